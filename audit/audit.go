@@ -0,0 +1,158 @@
+// Package audit records who did what to which resource, in append-only sinks,
+// so services meet compliance requirements uniformly.
+//
+//	auditor := audit.New(audit.NewPostgresSink(db), audit.WithRedaction("password", "token"))
+//
+//	router := middleware.NewRouter(audit.Middleware)
+//
+//	err := auditor.Record(ctx, audit.Event{
+//		Action:   "user.update",
+//		Resource: "user:42",
+//		Before:   map[string]any{"email": old.Email},
+//		After:    map[string]any{"email": user.Email},
+//	})
+//
+// The actor, the client IP and the request ID are filled in from the context
+// set by Middleware and WithActor.
+package audit
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// Event is an audited action.
+type Event struct {
+	// ID identifies the event; Record generates it if empty.
+	ID string `json:"id"`
+	// Time is when the action happened; Record sets it to now if zero.
+	Time time.Time `json:"time"`
+	// Actor is who performed the action, such as "user:42" or "service:billing".
+	Actor string `json:"actor"`
+	// Action is what was done, such as "user.update".
+	Action string `json:"action"`
+	// Resource is what the action was done to, such as "order:1001".
+	Resource string `json:"resource"`
+	// Before is the state of the resource before the action, if relevant.
+	Before map[string]any `json:"before,omitempty"`
+	// After is the state of the resource after the action, if relevant.
+	After map[string]any `json:"after,omitempty"`
+	// IP is the address of the client.
+	IP string `json:"ip,omitempty"`
+	// RequestID correlates the event with the logs of the request.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// ErrInvalidEvent is returned by Record for an event without an action.
+var ErrInvalidEvent = errors.New("audit: event has no action")
+
+// Redacted replaces the values of the fields redacted with WithRedaction.
+const Redacted = "[REDACTED]"
+
+type options struct {
+	redact  map[string]bool
+	samples map[string]float64
+	now     func() time.Time
+}
+
+// Option configures an Auditor.
+type Option func(*options)
+
+// WithRedaction replaces with Redacted the values of the Before and After fields
+// named one of fields, case-insensitively, including in nested maps.
+func WithRedaction(fields ...string) Option {
+	return func(opts *options) {
+		for _, field := range fields {
+			opts.redact[strings.ToLower(field)] = true
+		}
+	}
+}
+
+// WithSampling records only a fraction rate, between 0 and 1, of the events
+// of the actions, such as high-volume reads. Other actions are always recorded.
+func WithSampling(rate float64, actions ...string) Option {
+	return func(opts *options) {
+		for _, action := range actions {
+			opts.samples[action] = rate
+		}
+	}
+}
+
+// Auditor records events to a sink. It is safe for concurrent use.
+type Auditor struct {
+	sink Sink
+	opts options
+}
+
+// New returns an Auditor recording to sink; use MultiSink to record to several.
+func New(sink Sink, opts ...Option) *Auditor {
+	cfg := options{
+		redact:  map[string]bool{},
+		samples: map[string]float64{},
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Auditor{sink: sink, opts: cfg}
+}
+
+// Record completes event from ctx, redacts it and appends it to the sink,
+// unless it is sampled out. The fields already set are kept.
+func (a *Auditor) Record(ctx context.Context, event Event) error {
+	if event.Action == "" {
+		return ErrInvalidEvent
+	}
+	if rate, ok := a.opts.samples[event.Action]; ok && rand.Float64() >= rate { //nolint:gosec // sampling
+		return nil
+	}
+
+	if event.ID == "" {
+		event.ID = newID()
+	}
+	if event.Time.IsZero() {
+		event.Time = a.opts.now()
+	}
+	event.Time = event.Time.UTC()
+	fillFromContext(ctx, &event)
+
+	event.Before = a.redact(event.Before)
+	event.After = a.redact(event.After)
+
+	return a.sink.Append(ctx, event)
+}
+
+// redact returns a copy of state with the redacted fields replaced.
+func (a *Auditor) redact(state map[string]any) map[string]any {
+	if state == nil || len(a.opts.redact) == 0 {
+		return state
+	}
+
+	redacted := make(map[string]any, len(state))
+	for key, val := range state {
+		if a.opts.redact[strings.ToLower(key)] {
+			redacted[key] = Redacted
+
+			continue
+		}
+		if nested, ok := val.(map[string]any); ok {
+			val = a.redact(nested)
+		}
+		redacted[key] = val
+	}
+
+	return redacted
+}
+
+func newID() string {
+	var id [16]byte
+	_, _ = cryptorand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySink records the appended events.
+type memorySink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *memorySink) Append(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+
+	return nil
+}
+
+func TestRecord(t *testing.T) {
+	sink := &memorySink{}
+	auditor := New(sink)
+
+	ctx := WithActor(t.Context(), "user:1")
+	require.NoError(t, auditor.Record(ctx, Event{Action: "user.update", Resource: "user:42"}))
+	require.NoError(t, auditor.Record(ctx, Event{ID: "custom", Actor: "service:billing", Action: "order.refund"}))
+
+	require.Len(t, sink.events, 2)
+	first := sink.events[0]
+	assert.Len(t, first.ID, 32)
+	assert.Equal(t, "user:1", first.Actor)
+	assert.Equal(t, time.UTC, first.Time.Location())
+	assert.WithinDuration(t, time.Now(), first.Time, time.Minute)
+
+	assert.Equal(t, "custom", sink.events[1].ID)
+	assert.Equal(t, "service:billing", sink.events[1].Actor, "set fields are kept")
+
+	require.ErrorIs(t, auditor.Record(ctx, Event{}), ErrInvalidEvent)
+}
+
+func TestRecordRedaction(t *testing.T) {
+	sink := &memorySink{}
+	auditor := New(sink, WithRedaction("Password", "token"))
+
+	before := map[string]any{
+		"email":    "a@ezex.io",
+		"password": "old",
+		"oauth":    map[string]any{"Token": "t", "provider": "github"},
+	}
+	require.NoError(t, auditor.Record(t.Context(), Event{Action: "user.update", Before: before}))
+
+	assert.Equal(t, map[string]any{
+		"email":    "a@ezex.io",
+		"password": Redacted,
+		"oauth":    map[string]any{"Token": Redacted, "provider": "github"},
+	}, sink.events[0].Before)
+	assert.Nil(t, sink.events[0].After)
+	assert.Equal(t, "old", before["password"], "the state given is not modified")
+}
+
+func TestRecordSampling(t *testing.T) {
+	sink := &memorySink{}
+	auditor := New(sink, WithSampling(0, "user.read"), WithSampling(0.5, "order.read"))
+
+	for range 1000 {
+		require.NoError(t, auditor.Record(t.Context(), Event{Action: "user.read"}))
+		require.NoError(t, auditor.Record(t.Context(), Event{Action: "order.read"}))
+		require.NoError(t, auditor.Record(t.Context(), Event{Action: "user.update"}))
+	}
+
+	counts := map[string]int{}
+	for _, event := range sink.events {
+		counts[event.Action]++
+	}
+	assert.Zero(t, counts["user.read"])
+	assert.InDelta(t, 500, counts["order.read"], 100)
+	assert.Equal(t, 1000, counts["user.update"])
+}
@@ -0,0 +1,20 @@
+module github.com/ezex-io/gopkg/audit
+
+go 1.25.1
+
+require (
+	github.com/ezex-io/gopkg/logger v0.0.0-20261016204632-7089dcbf854e
+	github.com/jackc/pgx/v5 v5.11.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sync v0.17.0 // indirect
+	golang.org/x/text v0.29.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RequestIDHeader is the header Middleware reads the request ID from.
+const RequestIDHeader = "X-Request-ID"
+
+type (
+	requestKey struct{}
+	actorKey   struct{}
+)
+
+// requestInfo is the metadata of a request stored by Middleware.
+type requestInfo struct {
+	ip        string
+	requestID string
+}
+
+// Middleware stores the client IP and the request ID of each request in its context,
+// so the events recorded while handling it carry them. It is a middleware.Middleware
+// of the http-mdl package.
+//
+// The client IP is the first address of the X-Forwarded-For header, if any,
+// so the service must be behind a proxy that sets it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := requestInfo{
+			ip:        clientIP(r),
+			requestID: r.Header.Get(RequestIDHeader),
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestKey{}, info)))
+	})
+}
+
+// WithActor returns a copy of ctx carrying the actor of the events recorded with it,
+// typically set by the authentication middleware.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// fillFromContext sets the empty actor, IP and request ID of event from ctx.
+func fillFromContext(ctx context.Context, event *Event) {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && event.Actor == "" {
+		event.Actor = actor
+	}
+
+	info, _ := ctx.Value(requestKey{}).(requestInfo)
+	if event.IP == "" {
+		event.IP = info.ip
+	}
+	if event.RequestID == "" {
+		event.RequestID = info.requestID
+	}
+}
+
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+
+		return strings.TrimSpace(first)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	sink := &memorySink{}
+	auditor := New(sink)
+
+	handler := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx := WithActor(r.Context(), "user:1")
+		require.NoError(t, auditor.Record(ctx, Event{Action: "user.update"}))
+		require.NoError(t, auditor.Record(ctx, Event{Action: "user.update", IP: "10.0.0.9"}))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users/42", http.NoBody)
+	req.RemoteAddr = "192.0.2.1:5555"
+	req.Header.Set(RequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, sink.events, 2)
+	assert.Equal(t, "192.0.2.1", sink.events[0].IP)
+	assert.Equal(t, "req-1", sink.events[0].RequestID)
+	assert.Equal(t, "user:1", sink.events[0].Actor)
+	assert.Equal(t, "10.0.0.9", sink.events[1].IP)
+}
+
+func TestClientIP(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.RemoteAddr = "192.0.2.1:5555"
+	assert.Equal(t, "192.0.2.1", clientIP(req))
+
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+	assert.Equal(t, "203.0.113.7", clientIP(req))
+
+	req = httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.RemoteAddr = "@"
+	assert.Equal(t, "@", clientIP(req))
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// PostgresSchema creates the table used by the PostgresSink.
+// Revoke UPDATE and DELETE on it from the role of the service to make it append-only.
+const PostgresSchema = `
+CREATE TABLE IF NOT EXISTS audit_events (
+	id          TEXT        PRIMARY KEY,
+	time        TIMESTAMPTZ NOT NULL,
+	actor       TEXT        NOT NULL,
+	action      TEXT        NOT NULL,
+	resource    TEXT        NOT NULL,
+	before      JSONB,
+	after       JSONB,
+	ip          TEXT        NOT NULL,
+	request_id  TEXT        NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_resource_idx ON audit_events (resource, time);
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor, time);`
+
+// PostgresSink is a Sink inserting the events into the audit_events table.
+// It works with any database/sql driver for Postgres, such as pgx's stdlib driver.
+type PostgresSink struct {
+	db *sql.DB
+}
+
+// NewPostgresSink returns a sink on db. Call Migrate, or apply PostgresSchema
+// with your own migrations, before using it.
+func NewPostgresSink(db *sql.DB) *PostgresSink {
+	return &PostgresSink{db: db}
+}
+
+// Migrate creates the table of the sink if it doesn't exist.
+func (s *PostgresSink) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, PostgresSchema)
+
+	return err
+}
+
+func (s *PostgresSink) Append(ctx context.Context, event Event) error {
+	before, err := jsonColumn(event.Before)
+	if err != nil {
+		return err
+	}
+	after, err := jsonColumn(event.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO audit_events (id, time, actor, action, resource, before, after, ip, request_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		event.ID, event.Time, event.Actor, event.Action, event.Resource,
+		before, after, event.IP, event.RequestID)
+	if err != nil {
+		return fmt.Errorf("audit: failed to insert event: %w", err)
+	}
+
+	return nil
+}
+
+// jsonColumn encodes state for a JSONB column, NULL if it is nil.
+func jsonColumn(state map[string]any) (any, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("audit: failed to encode state: %w", err)
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresSink runs against the database in AUDIT_POSTGRES_DSN, if set.
+func TestPostgresSink(t *testing.T) {
+	dsn := os.Getenv("AUDIT_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("AUDIT_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	sink := NewPostgresSink(db)
+	require.NoError(t, sink.Migrate(t.Context()))
+
+	event := testEvent()
+	event.ID = fmt.Sprintf("test-%d", time.Now().UnixNano())
+	require.NoError(t, sink.Append(t.Context(), event))
+	require.Error(t, sink.Append(t.Context(), event), "IDs are unique")
+
+	var after string
+	require.NoError(t, db.QueryRowContext(t.Context(),
+		`SELECT after FROM audit_events WHERE id = $1`, event.ID).Scan(&after))
+	assert.JSONEq(t, `{"email": "a@ezex.io"}`, after)
+}
+
+func TestJSONColumn(t *testing.T) {
+	val, err := jsonColumn(nil)
+	require.NoError(t, err)
+	assert.Nil(t, val)
+
+	val, err = jsonColumn(map[string]any{"a": 1})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a": 1}`, val.(string))
+
+	_, err = jsonColumn(map[string]any{"a": make(chan int)})
+	require.Error(t, err)
+}
@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ezex-io/gopkg/logger"
+)
+
+// Sink appends events to an audit log. Implementations must be safe for concurrent use,
+// and must not allow the events appended to be modified.
+type Sink interface {
+	Append(ctx context.Context, event Event) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, event Event) error
+
+func (f SinkFunc) Append(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// MultiSink returns a Sink appending each event to all of sinks,
+// and returning their errors joined.
+func MultiSink(sinks ...Sink) Sink {
+	return SinkFunc(func(ctx context.Context, event Event) error {
+		var errs []error
+		for _, sink := range sinks {
+			if err := sink.Append(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// NewLoggerSink returns a Sink logging each event at info level to log,
+// under the message "audit", such as to ship them with the other logs.
+func NewLoggerSink(log logger.Logger) Sink {
+	return SinkFunc(func(_ context.Context, event Event) error {
+		log.Info("audit",
+			"id", event.ID,
+			"time", event.Time,
+			"actor", event.Actor,
+			"action", event.Action,
+			"resource", event.Resource,
+			"before", event.Before,
+			"after", event.After,
+			"ip", event.IP,
+			"request_id", event.RequestID,
+		)
+
+		return nil
+	})
+}
+
+// NewWriterSink returns a Sink writing each event as a line of JSON to w,
+// such as an append-only file.
+func NewWriterSink(w io.Writer) Sink {
+	var mu sync.Mutex
+
+	return SinkFunc(func(_ context.Context, event Event) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("audit: failed to encode event: %w", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		_, err = w.Write(append(data, '\n'))
+
+		return err
+	})
+}
+
+// BlobStore stores objects, such as an S3 or GCS bucket with object lock.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// NewBlobSink returns a Sink storing each event as a JSON object in store,
+// under prefix followed by "YYYY/MM/DD/" and the ID of the event, so they
+// can be listed by day.
+func NewBlobSink(store BlobStore, prefix string) Sink {
+	return SinkFunc(func(ctx context.Context, event Event) error {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("audit: failed to encode event: %w", err)
+		}
+
+		key := prefix + event.Time.Format("2006/01/02/") + event.ID + ".json"
+		if err := store.Put(ctx, key, data); err != nil {
+			return fmt.Errorf("audit: failed to store %s: %w", key, err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent() Event {
+	return Event{
+		ID:       "e1",
+		Time:     time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+		Actor:    "user:1",
+		Action:   "user.update",
+		Resource: "user:42",
+		After:    map[string]any{"email": "a@ezex.io"},
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+
+	require.NoError(t, sink.Append(t.Context(), testEvent()))
+	require.NoError(t, sink.Append(t.Context(), testEvent()))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var decoded Event
+	require.NoError(t, json.Unmarshal(lines[0], &decoded))
+	assert.Equal(t, testEvent(), decoded)
+}
+
+type blobStore map[string][]byte
+
+func (s blobStore) Put(_ context.Context, key string, data []byte) error {
+	s[key] = data
+
+	return nil
+}
+
+func TestBlobSink(t *testing.T) {
+	store := blobStore{}
+	require.NoError(t, NewBlobSink(store, "audit/").Append(t.Context(), testEvent()))
+
+	assert.Contains(t, store, "audit/2026/10/16/e1.json")
+}
+
+// recordingLogger records the messages and arguments logged at info level.
+type recordingLogger struct {
+	msgs []string
+	args [][]any
+}
+
+func (*recordingLogger) Debug(string, ...any) {}
+func (l *recordingLogger) Info(msg string, args ...any) {
+	l.msgs = append(l.msgs, msg)
+	l.args = append(l.args, args)
+}
+func (*recordingLogger) Warn(string, ...any)  {}
+func (*recordingLogger) Error(string, ...any) {}
+func (*recordingLogger) Fatal(string, ...any) {}
+
+func TestLoggerSink(t *testing.T) {
+	log := &recordingLogger{}
+	require.NoError(t, NewLoggerSink(log).Append(t.Context(), testEvent()))
+
+	assert.Equal(t, []string{"audit"}, log.msgs)
+	assert.Contains(t, fmt.Sprint(log.args[0]...), "user.update")
+}
+
+func TestMultiSink(t *testing.T) {
+	errFull := errors.New("disk full")
+	first, last := &memorySink{}, &memorySink{}
+	sink := MultiSink(first, SinkFunc(func(context.Context, Event) error { return errFull }), last)
+
+	require.ErrorIs(t, sink.Append(t.Context(), testEvent()), errFull)
+	assert.Len(t, first.events, 1)
+	assert.Len(t, last.events, 1)
+}
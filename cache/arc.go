@@ -0,0 +1,152 @@
+package cache
+
+import "container/list"
+
+// arcPolicy implements the Adaptive Replacement Cache of Megiddo and Modha.
+// Cached keys are split between t1 (seen once recently) and t2 (seen at least twice).
+// The ghost lists b1 and b2 remember keys recently evicted from t1 and t2; a hit in
+// a ghost list moves the target size of t1 toward the list that would have kept it.
+type arcPolicy[K comparable] struct {
+	capacity int
+	target   int // target size of t1
+	t1       *list.List
+	t2       *list.List
+	b1       *list.List
+	b2       *list.List
+	items    map[K]*arcItem
+}
+
+type arcItem struct {
+	list *list.List
+	elem *list.Element
+}
+
+func newARCPolicy[K comparable](capacity int) *arcPolicy[K] {
+	return &arcPolicy[K]{
+		capacity: capacity,
+		t1:       list.New(),
+		t2:       list.New(),
+		b1:       list.New(),
+		b2:       list.New(),
+		items:    make(map[K]*arcItem),
+	}
+}
+
+func (p *arcPolicy[K]) add(key K) (K, bool) {
+	var victim K
+	evict := false
+
+	item, ghost := p.items[key]
+	switch {
+	case ghost && item.list == p.b1:
+		p.target = min(p.capacity, p.target+max(p.b2.Len()/p.b1.Len(), 1))
+		victim, evict = p.replace(key)
+		p.unlink(key)
+		p.push(p.t2, key)
+
+		return victim, evict
+
+	case ghost && item.list == p.b2:
+		p.target = max(0, p.target-max(p.b1.Len()/p.b2.Len(), 1))
+		victim, evict = p.replace(key)
+		p.unlink(key)
+		p.push(p.t2, key)
+
+		return victim, evict
+	}
+
+	cached := p.t1.Len() + p.t2.Len()
+	switch {
+	case p.t1.Len()+p.b1.Len() >= p.capacity:
+		if p.t1.Len() < p.capacity {
+			p.dropBack(p.b1)
+			victim, evict = p.replace(key)
+		} else {
+			victim = p.dropBack(p.t1)
+			evict = true
+		}
+	case cached+p.b1.Len()+p.b2.Len() >= p.capacity:
+		if cached+p.b1.Len()+p.b2.Len() >= 2*p.capacity {
+			p.dropBack(p.b2)
+		}
+		victim, evict = p.replace(key)
+	}
+	p.push(p.t1, key)
+
+	return victim, evict
+}
+
+// replace evicts a key from t1 or t2 into its ghost list if the cache is full,
+// to make room for the incoming key.
+func (p *arcPolicy[K]) replace(incoming K) (K, bool) {
+	var victim K
+	if p.t1.Len()+p.t2.Len() < p.capacity {
+		return victim, false
+	}
+
+	item, ok := p.items[incoming]
+	inB2 := ok && item.list == p.b2
+
+	from, ghost := p.t2, p.b2
+	if p.t1.Len() > 0 && (p.t1.Len() > p.target || (inB2 && p.t1.Len() == p.target) || p.t2.Len() == 0) {
+		from, ghost = p.t1, p.b1
+	}
+
+	victim = from.Back().Value.(K)
+	p.unlink(victim)
+	p.push(ghost, victim)
+
+	return victim, true
+}
+
+// victim returns the key replace would evict, without moving it to a ghost list:
+// the cache removes it, which forgets it.
+func (p *arcPolicy[K]) victim() (K, bool) {
+	var victim K
+	switch {
+	case p.t1.Len() > 0 && (p.t1.Len() > p.target || p.t2.Len() == 0):
+		return p.t1.Back().Value.(K), true
+	case p.t2.Len() > 0:
+		return p.t2.Back().Value.(K), true
+	default:
+		return victim, false
+	}
+}
+
+func (p *arcPolicy[K]) touch(key K) {
+	item, ok := p.items[key]
+	if !ok || (item.list != p.t1 && item.list != p.t2) {
+		return
+	}
+
+	p.unlink(key)
+	p.push(p.t2, key)
+}
+
+func (p *arcPolicy[K]) remove(key K) {
+	if item, ok := p.items[key]; ok && (item.list == p.t1 || item.list == p.t2) {
+		p.unlink(key)
+	}
+}
+
+func (p *arcPolicy[K]) keys() []K {
+	return append(listKeys[K](p.t2), listKeys[K](p.t1)...)
+}
+
+func (p *arcPolicy[K]) push(l *list.List, key K) {
+	p.items[key] = &arcItem{list: l, elem: l.PushFront(key)}
+}
+
+func (p *arcPolicy[K]) unlink(key K) {
+	item := p.items[key]
+	item.list.Remove(item.elem)
+	delete(p.items, key)
+}
+
+// dropBack removes the least recent key of l and returns it.
+func (p *arcPolicy[K]) dropBack(l *list.List) K {
+	key := l.Back().Value.(K)
+	p.unlink(key)
+
+	return key
+}
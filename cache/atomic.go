@@ -0,0 +1,87 @@
+package cache
+
+import "time"
+
+// modifier is implemented by the caches of this package, which can skip the write of
+// an atomic update.
+type modifier[K comparable, V any] interface {
+	// modify atomically calls fn with the current item and stores the value it returns
+	// if write is true, with the expiry set like Upsert does. It returns the stored
+	// value, or the current one when nothing is written, and whether it wrote.
+	modify(key K, fn func(old V, exists bool) (value V, write bool), ttl time.Duration) (V, bool)
+}
+
+// alwaysWrite adapts an Upsert function to modify.
+func alwaysWrite[V any](fn func(old V, exists bool) V) func(old V, exists bool) (V, bool) {
+	return func(old V, exists bool) (V, bool) {
+		return fn(old, exists), true
+	}
+}
+
+// setIfAbsent is a modify function writing value only if the key is missing.
+func setIfAbsent[V any](value V) func(old V, exists bool) (V, bool) {
+	return func(old V, exists bool) (V, bool) {
+		if exists {
+			return old, false
+		}
+
+		return value, true
+	}
+}
+
+// modify runs fn atomically on the item of key. Caches of other packages only provide
+// Upsert, so when fn doesn't write, the current value is written back, and a missing
+// key is deleted again, which is not atomic.
+func modify[K comparable, V any](cache Cache[K, V], key K,
+	fn func(old V, exists bool) (V, bool), ttl time.Duration,
+) (V, bool) {
+	if m, ok := cache.(modifier[K, V]); ok {
+		return m.modify(key, fn, ttl)
+	}
+
+	var written, existed bool
+	value := cache.Upsert(key, func(old V, exists bool) V {
+		existed = exists
+		value, write := fn(old, exists)
+		if !write {
+			return old
+		}
+		written = true
+
+		return value
+	}, ttl)
+	if !written && !existed {
+		cache.Delete(key)
+	}
+
+	return value, written
+}
+
+// Increment atomically adds delta to the counter stored at key, keeping its expiry,
+// and returns the new value. It returns false, and stores nothing, if the key is
+// missing or expired, so a counter is created with the expiry of its choice:
+//
+//	if _, ok := cache.Increment(counters, key, 1); !ok {
+//		counters.Upsert(key, func(old int64, _ bool) int64 { return old + 1 }, time.Minute)
+//	}
+func Increment[K comparable](cache Cache[K, int64], key K, delta int64) (int64, bool) {
+	return modify(cache, key, func(old int64, exists bool) (int64, bool) {
+		return old + delta, exists
+	}, 0)
+}
+
+// Decrement atomically subtracts delta from the counter stored at key, like Increment.
+func Decrement[K comparable](cache Cache[K, int64], key K, delta int64) (int64, bool) {
+	return Increment(cache, key, -delta)
+}
+
+// CompareAndSwap atomically replaces the item of key with newValue, keeping its expiry,
+// if its current value is old. It reports whether the item was replaced, so optimistic
+// updates can read a value, compute the new one and retry when another writer won.
+func CompareAndSwap[K, V comparable](cache Cache[K, V], key K, old, newValue V) bool {
+	_, swapped := modify(cache, key, func(current V, exists bool) (V, bool) {
+		return newValue, exists && current == old
+	}, 0)
+
+	return swapped
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIncrement(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	caches := map[string]Cache[string, int64]{
+		"basic":     NewBasic[string, int64](t.Context()),
+		"bounded":   NewBounded[string, int64](t.Context(), 10),
+		"redis":     NewRedis[string, int64](client, JSONCodec[string, int64]{}),
+		"namespace": Namespace(NewBasic[string, int64](t.Context()), "tenant:"),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			if n, ok := Increment(cache, "hits", 1); ok || n != 0 {
+				t.Errorf("Increment(missing) = %d, %v, want 0, false", n, ok)
+			}
+			if cache.Exists("hits") {
+				t.Error("expected Increment not to create a missing counter")
+			}
+
+			cache.Add("hits", 10, time.Minute)
+			if n, ok := Increment(cache, "hits", 5); !ok || n != 15 {
+				t.Errorf("Increment = %d, %v, want 15, true", n, ok)
+			}
+			if n, ok := Decrement(cache, "hits", 20); !ok || n != -5 {
+				t.Errorf("Decrement = %d, %v, want -5, true", n, ok)
+			}
+			if got, _ := cache.Get("hits"); got != -5 {
+				t.Errorf("Get = %d, want -5", got)
+			}
+		})
+	}
+}
+
+func TestIncrementConcurrent(t *testing.T) {
+	cache := NewBasic[string, int64](t.Context())
+	cache.Add("hits", 0, 0)
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			for range 100 {
+				Increment(cache, "hits", 1)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got, _ := cache.Get("hits"); got != 5000 {
+		t.Errorf("expected no lost increment, got %d", got)
+	}
+}
+
+func TestIncrementKeepsExpiry(t *testing.T) {
+	cache := NewBasic[string, int64](t.Context())
+	cache.Add("hits", 1, 30*time.Millisecond)
+
+	Increment(cache, "hits", 1)
+	time.Sleep(40 * time.Millisecond)
+
+	if _, ok := cache.Get("hits"); ok {
+		t.Error("expected Increment to keep the expiry of the counter")
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	caches := map[string]Cache[string, string]{
+		"basic":     NewBasic[string, string](t.Context()),
+		"bounded":   NewBounded[string, string](t.Context(), 10),
+		"redis":     NewRedis[string, string](client, JSONCodec[string, string]{}),
+		"namespace": Namespace(NewBasic[string, string](t.Context()), "tenant:"),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			if CompareAndSwap(cache, "state", "", "open") {
+				t.Error("expected CompareAndSwap to fail on a missing key")
+			}
+			if cache.Exists("state") {
+				t.Error("expected CompareAndSwap not to create a missing key")
+			}
+
+			cache.Add("state", "open", 0)
+			if CompareAndSwap(cache, "state", "closed", "open") {
+				t.Error("expected CompareAndSwap to fail on a different value")
+			}
+			if !CompareAndSwap(cache, "state", "open", "closed") {
+				t.Error("expected CompareAndSwap to succeed on the current value")
+			}
+			if got, _ := cache.Get("state"); got != "closed" {
+				t.Errorf("Get = %q, want closed", got)
+			}
+		})
+	}
+}
+
+func TestCompareAndSwapNoEvictCallback(t *testing.T) {
+	var calls int
+	cache := NewBasic[string, int](t.Context(), WithOnEvict(func(string, int, Reason) { calls++ }))
+	cache.Add("a", 1, 0)
+
+	CompareAndSwap(cache, "a", 2, 3)
+	if calls != 0 {
+		t.Errorf("expected a failed swap not to replace the item, got %d callbacks", calls)
+	}
+
+	CompareAndSwap(cache, "a", 1, 3)
+	if calls != 1 {
+		t.Errorf("expected a swap to report the replaced value, got %d callbacks", calls)
+	}
+}
+
+// foreignCache is a Cache implemented outside of this package, which only provides Upsert.
+type foreignCache struct {
+	Cache[string, int64]
+}
+
+func TestIncrementForeignCache(t *testing.T) {
+	cache := foreignCache{NewBasic[string, int64](t.Context())}
+
+	if _, ok := Increment(cache, "hits", 1); ok || cache.Exists("hits") {
+		t.Error("expected Increment to leave a missing counter missing")
+	}
+
+	cache.Add("hits", 1, 0)
+	if n, ok := Increment(cache, "hits", 1); !ok || n != 2 {
+		t.Errorf("Increment = %d, %v, want 2, true", n, ok)
+	}
+}
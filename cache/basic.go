@@ -2,34 +2,61 @@ package cache
 
 import (
 	"context"
+	"io"
 	"sync"
 	"time"
 
+	"github.com/ezex-io/gopkg/retry"
 	"github.com/ezex-io/gopkg/scheduler"
 )
 
-type BasicCache[K any, V any] struct {
-	cache sync.Map
+type BasicCache[K comparable, V any] struct {
+	cache        sync.Map
+	upserts      keyLocks
+	loads        loadGroup[V]
+	stats        stats
+	tags         tagGenerations
+	slidingTTL   time.Duration
+	onEvict      func(key K, value V, reason Reason)
+	codec        Codec[K, V]
+	grace        time.Duration
+	refreshRetry []retry.Options
+	clock        Clock
 }
 
+// basicCacheEntry is never modified once stored, so entries can be swapped atomically.
 type basicCacheEntry[V any] struct {
 	Value  V
 	Expiry time.Time
+	tags   []tagStamp
 }
 
-func NewBasic[K any, V any](ctx context.Context, opts ...Option) Cache[K, V] {
+func (e *basicCacheEntry[V]) expired(now time.Time) bool {
+	return !e.Expiry.IsZero() && now.After(e.Expiry)
+}
+
+func NewBasic[K comparable, V any](ctx context.Context, opts ...Option) Cache[K, V] {
 	cfg := defaultConfig
 	for _, opt := range opts {
 		opt(&cfg)
 	}
 
 	cache := &BasicCache[K, V]{
-		cache: sync.Map{},
+		cache:        sync.Map{},
+		slidingTTL:   cfg.slidingTTL,
+		onEvict:      onEvictFunc[K, V](&cfg),
+		codec:        snapshotCodecOf[K, V](&cfg),
+		grace:        cfg.staleGrace,
+		refreshRetry: cfg.refreshRetry,
+		clock:        cfg.clock,
 	}
+	cache.stats.hook = cfg.statsHook
 
-	scheduler.Every(cfg.cleanUpInterval).Do(ctx, func(context.Context) {
-		cache.cleanupExpiredEntries()
-	})
+	if cfg.cleanUpInterval > 0 {
+		scheduler.Every(cfg.cleanUpInterval).Do(ctx, func(context.Context) {
+			cache.cleanupExpiredEntries()
+		})
+	}
 
 	return cache
 }
@@ -37,63 +64,181 @@ func NewBasic[K any, V any](ctx context.Context, opts ...Option) Cache[K, V] {
 // Add add new time to cache
 //
 //   - expiration: 0 for disable expire cache
-func (c *BasicCache[K, V]) Add(key K, value V, expiration time.Duration) bool {
+//   - opts: WithTags to tag the item
+func (c *BasicCache[K, V]) Add(key K, value V, expiration time.Duration, opts ...AddOption) bool {
 	var expiry time.Time
 	if expiration != 0 {
-		expiry = time.Now().Add(expiration)
+		expiry = c.clock.Now().Add(expiration)
 	}
 
-	entry := basicCacheEntry[V]{Value: value, Expiry: expiry}
-	c.cache.Store(key, entry)
+	entry := &basicCacheEntry[V]{Value: value, Expiry: expiry, tags: c.tags.stamp(addTags(opts))}
+	if previous, loaded := c.cache.Swap(key, entry); loaded {
+		c.replaced(key, previous.(*basicCacheEntry[V]))
+	}
 
 	return true
 }
 
+// Get returns the item, extending its expiry when the cache has a sliding TTL.
 func (c *BasicCache[K, V]) Get(key K) (V, bool) {
-	var zeroV V // zero Value of type V
-	value, ok := c.cache.Load(key)
+	value, _, ok := c.GetWithExpiry(key)
+
+	return value, ok
+}
+
+// GetWithExpiry returns the item like Get, with its expiry, the zero time if it never expires.
+func (c *BasicCache[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	entry, ok := c.lookup(key)
 	if !ok {
-		return zeroV, false
+		c.stats.record(Miss)
+
+		var zeroV V // zero Value of type V
+
+		return zeroV, time.Time{}, false
+	}
+	c.stats.record(Hit)
+
+	if c.slidingTTL > 0 && !entry.Expiry.IsZero() {
+		// A concurrent write wins over the extension.
+		extended := &basicCacheEntry[V]{Value: entry.Value, Expiry: c.clock.Now().Add(c.slidingTTL), tags: entry.tags}
+		if c.cache.CompareAndSwap(key, entry, extended) {
+			entry = extended
+		}
 	}
 
-	return value.(basicCacheEntry[V]).Value, true
+	return entry.Value, entry.Expiry, true
 }
 
-func (c *BasicCache[K, V]) Update(key K, newValue V, expiration time.Duration) bool {
-	// Check if the key exists in the cache
+// lookup returns the entry of key, removing it if it has expired
+// and is past the stale-while-revalidate grace period, or if one of its tags was invalidated.
+func (c *BasicCache[K, V]) lookup(key K) (*basicCacheEntry[V], bool) {
 	value, ok := c.cache.Load(key)
 	if !ok {
-		return false // Key not found, nothing to update
+		return nil, false
 	}
 
-	// Update the Value
-	entry, ok := value.(basicCacheEntry[V])
-	if !ok {
-		return false
+	entry := value.(*basicCacheEntry[V])
+	if !c.tags.valid(entry.tags) {
+		c.invalidated(key, entry)
+
+		return nil, false
 	}
-	entry.Value = newValue
 
-	// Update the expiration time if a new expiration is provided
-	if expiration != 0 {
-		entry.Expiry = time.Now().Add(expiration)
+	now := c.clock.Now()
+	if entry.expired(now) {
+		if entry.expired(now.Add(-c.grace)) {
+			c.expire(key, entry)
+		}
+
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// stale returns the value of an entry expired less than the grace period ago.
+func (c *BasicCache[K, V]) stale(key K) (V, bool) {
+	if value, ok := c.cache.Load(key); ok {
+		entry := value.(*basicCacheEntry[V])
+		now := c.clock.Now()
+		if entry.expired(now) && !entry.expired(now.Add(-c.grace)) && c.tags.valid(entry.tags) {
+			return entry.Value, true
+		}
+	}
+
+	var zeroV V
+
+	return zeroV, false
+}
+
+func (c *BasicCache[K, V]) refreshOptions() []retry.Options {
+	return c.refreshRetry
+}
+
+// expire removes an expired entry, unless it was replaced meanwhile.
+func (c *BasicCache[K, V]) expire(key any, entry *basicCacheEntry[V]) {
+	if c.cache.CompareAndDelete(key, entry) {
+		c.stats.record(Expiration)
+		c.evicted(key, entry.Value, ReasonExpired)
 	}
+}
 
-	// Store the updated entry back in the cache
-	c.cache.Store(key, entry)
+// invalidated removes an entry with an invalidated tag, unless it was replaced meanwhile,
+// and reports whether it did.
+func (c *BasicCache[K, V]) invalidated(key any, entry *basicCacheEntry[V]) bool {
+	if !c.cache.CompareAndDelete(key, entry) {
+		return false
+	}
+	c.evicted(key, entry.Value, ReasonDeleted)
 
 	return true
 }
 
+// replaced reports the value of an overwritten entry, or its expiry if it had expired.
+func (c *BasicCache[K, V]) replaced(key any, previous *basicCacheEntry[V]) {
+	if previous.expired(c.clock.Now()) {
+		c.stats.record(Expiration)
+		c.evicted(key, previous.Value, ReasonExpired)
+
+		return
+	}
+
+	c.evicted(key, previous.Value, ReasonReplaced)
+}
+
+func (c *BasicCache[K, V]) evicted(key any, value V, reason Reason) {
+	if c.onEvict != nil {
+		c.onEvict(key.(K), value, reason)
+	}
+}
+
+func (c *BasicCache[K, V]) Update(key K, newValue V, expiration time.Duration) bool {
+	for {
+		// Check if the key exists in the cache
+		value, ok := c.cache.Load(key)
+		if !ok {
+			return false // Key not found, nothing to update
+		}
+
+		// Update the Value
+		current, ok := value.(*basicCacheEntry[V])
+		if !ok {
+			return false
+		}
+		if !c.tags.valid(current.tags) {
+			c.invalidated(key, current)
+
+			return false
+		}
+		entry := &basicCacheEntry[V]{Value: newValue, Expiry: current.Expiry, tags: current.tags}
+
+		// Update the expiration time if a new expiration is provided
+		if expiration != 0 {
+			entry.Expiry = c.clock.Now().Add(expiration)
+		}
+
+		// Store the updated entry back in the cache, retrying if it changed meanwhile
+		if c.cache.CompareAndSwap(key, current, entry) {
+			c.evicted(key, current.Value, ReasonReplaced)
+
+			return true
+		}
+	}
+}
+
+// Exists reports whether the key is cached and unexpired, as Get would,
+// without counting a hit or a miss.
 func (c *BasicCache[K, V]) Exists(key K) bool {
-	_, ok := c.cache.Load(key)
+	_, ok := c.lookup(key)
 
 	return ok
 }
 
+// Keys returns the keys of the unexpired items.
 func (c *BasicCache[K, V]) Keys() []K {
 	keys := make([]K, 0)
-	c.cache.Range(func(key, _ any) bool {
-		keys = append(keys, key.(K))
+	c.Range(func(key K, _ V) bool {
+		keys = append(keys, key)
 
 		return true
 	})
@@ -101,29 +246,244 @@ func (c *BasicCache[K, V]) Keys() []K {
 	return keys
 }
 
+// Range calls fn for each unexpired item until fn returns false.
+// Like sync.Map.Range, it may or may not see the items changed while it runs.
+func (c *BasicCache[K, V]) Range(fn func(key K, value V) bool) {
+	now := c.clock.Now()
+	c.cache.Range(func(key, value any) bool {
+		entry := value.(*basicCacheEntry[V])
+		if entry.expired(now) || !c.tags.valid(entry.tags) {
+			return true
+		}
+
+		return fn(key.(K), entry.Value)
+	})
+}
+
+// Len returns the number of unexpired items. It walks the whole cache.
+func (c *BasicCache[K, V]) Len() int {
+	n := 0
+	c.Range(func(K, V) bool {
+		n++
+
+		return true
+	})
+
+	return n
+}
+
+// Clear deletes every item, calling the eviction callback for each.
+func (c *BasicCache[K, V]) Clear() {
+	c.cache.Range(func(key, _ any) bool {
+		if previous, loaded := c.cache.LoadAndDelete(key); loaded {
+			c.evicted(key, previous.(*basicCacheEntry[V]).Value, ReasonDeleted)
+		}
+
+		return true
+	})
+}
+
+// SetNX adds the item only if the key is missing or expired, and reports whether it did,
+// so the cache can hold locks.
+//
+//   - expiration: 0 for disable expire cache
+func (c *BasicCache[K, V]) SetNX(key K, value V, expiration time.Duration) bool {
+	_, written := c.modify(key, setIfAbsent(value), expiration)
+
+	return written
+}
+
 func (c *BasicCache[K, V]) Delete(key K) bool {
-	c.cache.Delete(key)
+	if previous, loaded := c.cache.LoadAndDelete(key); loaded {
+		c.evicted(key, previous.(*basicCacheEntry[V]).Value, ReasonDeleted)
+	}
 	_, ok := c.cache.Load(key)
 
 	return !ok
 }
 
-func (c *BasicCache[K, V]) cleanupExpiredEntries() {
+// Upsert atomically replaces the item with fn(old, exists) and returns the new value.
+// Concurrent upserts of the same key run one at a time, so no write is lost; fn runs
+// again only if Add, Update or Delete changed the item while it was running.
+// ttl sets the expiry; zero keeps the expiry of an existing item.
+func (c *BasicCache[K, V]) Upsert(key K, fn func(old V, exists bool) V, ttl time.Duration) V {
+	value, _ := c.modify(key, alwaysWrite(fn), ttl)
+
+	return value
+}
+
+func (c *BasicCache[K, V]) modify(key K, fn func(old V, exists bool) (V, bool), ttl time.Duration) (V, bool) {
+	unlock := lockKey(&c.upserts, key)
+	defer unlock()
+
+	for {
+		var old V
+		var expiry time.Time
+		var tags []tagStamp
+		current, exists := c.lookup(key)
+		if exists {
+			old = current.Value
+			expiry = current.Expiry
+			tags = current.tags
+		}
+		if ttl != 0 {
+			expiry = c.clock.Now().Add(ttl)
+		}
+
+		value, write := fn(old, exists)
+		if !write {
+			return old, false
+		}
+
+		entry := &basicCacheEntry[V]{Value: value, Expiry: expiry, tags: tags}
+		if !exists {
+			previous, loaded := c.cache.LoadOrStore(key, entry)
+			if !loaded {
+				return entry.Value, true
+			}
+
+			// An expired entry kept for stale-while-revalidate is replaced,
+			// unless it was replaced meanwhile.
+			stale := previous.(*basicCacheEntry[V])
+			if stale.expired(c.clock.Now()) && c.cache.CompareAndSwap(key, stale, entry) {
+				c.replaced(key, stale)
+
+				return entry.Value, true
+			}
+
+			continue
+		}
+
+		if c.cache.CompareAndSwap(key, current, entry) {
+			c.evicted(key, current.Value, ReasonReplaced)
+
+			return entry.Value, true
+		}
+	}
+}
+
+// GetMulti returns the items found among keys. Missing and expired keys are left out.
+func (c *BasicCache[K, V]) GetMulti(keys []K) map[K]V {
+	items := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, ok := c.Get(key); ok {
+			items[key] = value
+		}
+	}
+
+	return items
+}
+
+// AddMulti stores all the items with the same expiration.
+func (c *BasicCache[K, V]) AddMulti(items map[K]V, expiration time.Duration) bool {
+	for key, value := range items {
+		c.Add(key, value, expiration)
+	}
+
+	return true
+}
+
+// DeleteMulti deletes the items of keys.
+func (c *BasicCache[K, V]) DeleteMulti(keys []K) bool {
+	ok := true
+	for _, key := range keys {
+		ok = c.Delete(key) && ok
+	}
+
+	return ok
+}
+
+// GetOrLoad returns the cached item, or loads it with loader and stores it with ttl.
+// Concurrent calls missing the same key share a single load.
+func (c *BasicCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V], ttl time.Duration) (V, error) {
+	return getOrLoad(ctx, c, c.peek, &c.loads, key, loader, ttl)
+}
+
+// Stats returns the hit, miss, eviction and expiration counters.
+func (c *BasicCache[K, V]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// peek returns the item without counting a hit or a miss.
+func (c *BasicCache[K, V]) peek(key K) (V, bool) {
+	entry, ok := c.lookup(key)
+	if !ok {
+		var zeroV V
+
+		return zeroV, false
+	}
+
+	return entry.Value, true
+}
+
+// SaveSnapshot writes the unexpired items with their expiry to w,
+// encoded with the codec set by WithSnapshotCodec.
+func (c *BasicCache[K, V]) SaveSnapshot(w io.Writer) error {
+	return saveSnapshot[K, V](w, c)
+}
+
+// LoadSnapshot adds the unexpired items of a snapshot written by SaveSnapshot,
+// replacing the items with the same keys.
+func (c *BasicCache[K, V]) LoadSnapshot(r io.Reader) error {
+	return loadSnapshot[K, V](r, c, c.codec)
+}
+
+func (c *BasicCache[K, V]) snapshotEntries() ([]snapshotEntry[K, V], error) {
+	now := c.clock.Now()
+	entries := make([]snapshotEntry[K, V], 0)
 	c.cache.Range(func(key, value any) bool {
-		entry, ok := value.(basicCacheEntry[V])
-		if !ok {
-			return true
+		entry := value.(*basicCacheEntry[V])
+		if !entry.expired(now) && c.tags.valid(entry.tags) {
+			entries = append(entries, snapshotEntry[K, V]{key: key.(K), value: entry.Value, expiry: entry.Expiry})
 		}
 
-		// Skip entries with zero expiration time
-		if entry.Expiry.IsZero() {
+		return true
+	})
+
+	return entries, nil
+}
+
+func (c *BasicCache[K, V]) snapshotCodec() Codec[K, V] {
+	return c.codec
+}
+
+// InvalidateTag invalidates every item tagged with tag by WithTags, at once.
+// The items are removed on access and by the periodic clean-up.
+func (c *BasicCache[K, V]) InvalidateTag(tag string) {
+	c.tags.invalidate(tag)
+}
+
+// CleanupNow removes the expired and invalidated entries now, without waiting for the periodic clean-up.
+func (c *BasicCache[K, V]) CleanupNow() {
+	c.cleanupExpiredEntries()
+}
+
+func (c *BasicCache[K, V]) now() time.Time {
+	return c.clock.Now()
+}
+
+func (c *BasicCache[K, V]) cleanupExpiredEntries() {
+	horizon := c.tags.horizon()
+	removed := true
+	c.cache.Range(func(key, value any) bool {
+		entry, ok := value.(*basicCacheEntry[V])
+		if !ok {
 			return true
 		}
 
-		if time.Now().After(entry.Expiry) {
-			c.cache.Delete(key)
+		switch {
+		case !c.tags.valid(entry.tags):
+			// A replacement may have kept the tags of the entry, as Update does,
+			// so the invalidations are kept for the next clean-up to remove it.
+			removed = c.invalidated(key, entry) && removed
+		case entry.expired(c.clock.Now().Add(-c.grace)):
+			c.expire(key, entry)
 		}
 
 		return true
 	})
+
+	if removed {
+		c.tags.prune(horizon)
+	}
 }
@@ -96,6 +96,35 @@ func TestCacheWithExpiry(t *testing.T) {
 	})
 }
 
+func TestSlidingTTL(t *testing.T) {
+	cache := NewBasic[string, int](t.Context(), WithSlidingTTL(40*time.Millisecond))
+
+	cache.Add("session", 1, 40*time.Millisecond)
+	cache.Add("idle", 2, 40*time.Millisecond)
+	cache.Add("forever", 3, 0)
+
+	// Each read extends the expiry of session by another 40ms.
+	for range 4 {
+		time.Sleep(20 * time.Millisecond)
+		if _, ok := cache.Get("session"); !ok {
+			t.Fatal("expected session to be kept alive by reads")
+		}
+	}
+
+	if _, ok := cache.Get("idle"); ok {
+		t.Error("expected idle to be expired")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		t.Error("expected forever to never expire")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if _, ok := cache.Get("session"); ok {
+		t.Error("expected session to expire once idle")
+	}
+}
+
 func BenchmarkAdd(b *testing.B) {
 	b.ReportAllocs()
 	cache := NewBasic[int, int](b.Context()) // or NewBasic[int, int](0, WithCompressor())
@@ -0,0 +1,551 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ezex-io/gopkg/retry"
+	"github.com/ezex-io/gopkg/scheduler"
+)
+
+// BoundedCache is a cache bounded by a maximum number of entries.
+// When full, adding an entry evicts the one chosen by its eviction policy.
+type BoundedCache[K comparable, V any] struct {
+	sync.Mutex
+
+	maxEntries   int
+	entries      map[K]*boundedEntry[V]
+	policy       evictionPolicy[K]
+	loads        loadGroup[V]
+	stats        stats
+	tags         tagGenerations
+	slidingTTL   time.Duration
+	onEvict      func(key K, value V, reason Reason)
+	pending      []eviction[K, V]
+	codec        Codec[K, V]
+	maxBytes     int
+	sizer        func(key K, value V) int
+	bytes        int
+	grace        time.Duration
+	refreshRetry []retry.Options
+	clock        Clock
+}
+
+type boundedEntry[V any] struct {
+	value  V
+	expiry time.Time
+	size   int
+	tags   []tagStamp
+}
+
+func (e *boundedEntry[V]) expired(now time.Time) bool {
+	return !e.expiry.IsZero() && now.After(e.expiry)
+}
+
+// NewBounded creates a cache holding at most maxEntries entries (at least 1),
+// evicting according to the policy set with WithPolicy (LRU by default).
+// WithMaxBytes also bounds the estimated size of the entries.
+// Expired entries are removed on access and by a periodic cleanup.
+func NewBounded[K comparable, V any](ctx context.Context, maxEntries int, opts ...Option) Cache[K, V] {
+	cfg := defaultConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	sizer := sizerFunc[K, V](&cfg)
+	if maxEntries <= 0 && cfg.maxBytes > 0 && sizer != nil {
+		maxEntries = math.MaxInt / 4 // Bounded by size only
+	}
+	maxEntries = max(maxEntries, 1)
+	cache := &BoundedCache[K, V]{
+		maxEntries:   maxEntries,
+		entries:      make(map[K]*boundedEntry[V]),
+		policy:       newEvictionPolicy[K](cfg.policy, maxEntries),
+		slidingTTL:   cfg.slidingTTL,
+		onEvict:      onEvictFunc[K, V](&cfg),
+		codec:        snapshotCodecOf[K, V](&cfg),
+		maxBytes:     cfg.maxBytes,
+		sizer:        sizer,
+		grace:        cfg.staleGrace,
+		refreshRetry: cfg.refreshRetry,
+		clock:        cfg.clock,
+	}
+	cache.stats.hook = cfg.statsHook
+
+	if cfg.cleanUpInterval > 0 {
+		scheduler.Every(cfg.cleanUpInterval).Do(ctx, func(context.Context) {
+			cache.cleanupExpiredEntries()
+		})
+	}
+
+	return cache
+}
+
+// NewLRU creates a bounded cache with least-recently-used eviction.
+// It is NewBounded with the LRU policy.
+func NewLRU[K comparable, V any](ctx context.Context, maxEntries int, opts ...Option) Cache[K, V] {
+	return NewBounded[K, V](ctx, maxEntries, append(slices.Clip(opts), WithPolicy(LRU))...)
+}
+
+// Add stores the item, evicting an entry chosen by the policy if the cache is full.
+//
+//   - expiration: 0 for disable expire cache
+//   - opts: WithTags to tag the item
+func (c *BoundedCache[K, V]) Add(key K, value V, expiration time.Duration, opts ...AddOption) bool {
+	c.Lock()
+	defer c.unlock()
+
+	return c.add(key, value, expiration, c.tags.stamp(addTags(opts)))
+}
+
+// AddMulti stores all the items with the same expiration, under a single lock.
+func (c *BoundedCache[K, V]) AddMulti(items map[K]V, expiration time.Duration) bool {
+	c.Lock()
+	defer c.unlock()
+
+	added := true
+	for key, value := range items {
+		added = c.add(key, value, expiration, nil) && added
+	}
+
+	return added
+}
+
+// add stores the item with its tags and returns whether it is kept,
+// which it is not when it is larger than the byte budget.
+func (c *BoundedCache[K, V]) add(key K, value V, expiration time.Duration, tags []tagStamp) bool {
+	var expiry time.Time
+	if expiration != 0 {
+		expiry = c.clock.Now().Add(expiration)
+	}
+
+	if entry, ok := c.entries[key]; ok {
+		if entry.expired(c.clock.Now()) {
+			c.stats.record(Expiration)
+			c.evicted(key, entry.value, ReasonExpired)
+		} else {
+			c.evicted(key, entry.value, ReasonReplaced)
+		}
+		entry.value = value
+		entry.expiry = expiry
+		entry.tags = tags
+		c.policy.touch(key)
+
+		return c.resize(key, entry)
+	}
+
+	entry := &boundedEntry[V]{value: value, expiry: expiry, tags: tags}
+	c.entries[key] = entry
+	if victim, evict := c.policy.add(key); evict {
+		c.evicted(victim, c.entries[victim].value, ReasonEvicted)
+		c.bytes -= c.entries[victim].size
+		delete(c.entries, victim)
+		c.stats.record(Eviction)
+	}
+
+	return c.resize(key, entry)
+}
+
+// resize records the size of the value of entry, then evicts entries until
+// the cache fits its byte budget. It returns whether key is still cached.
+func (c *BoundedCache[K, V]) resize(key K, entry *boundedEntry[V]) bool {
+	if c.sizer == nil {
+		return true
+	}
+
+	size := c.sizer(key, entry.value)
+	c.bytes += size - entry.size
+	entry.size = size
+
+	for c.maxBytes > 0 && c.bytes > c.maxBytes {
+		victim, ok := c.policy.victim()
+		if !ok {
+			break
+		}
+		c.remove(victim, ReasonEvicted)
+		c.stats.record(Eviction)
+	}
+
+	_, ok := c.entries[key]
+
+	return ok
+}
+
+// Get returns the item and records its use,
+// extending its expiry when the cache has a sliding TTL.
+func (c *BoundedCache[K, V]) Get(key K) (V, bool) {
+	c.Lock()
+	defer c.unlock()
+
+	value, _, ok := c.get(key)
+
+	return value, ok
+}
+
+// GetWithExpiry returns the item like Get, with its expiry, the zero time if it never expires.
+func (c *BoundedCache[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	c.Lock()
+	defer c.unlock()
+
+	return c.get(key)
+}
+
+// GetMulti returns the items found among keys, under a single lock.
+// Missing and expired keys are left out.
+func (c *BoundedCache[K, V]) GetMulti(keys []K) map[K]V {
+	c.Lock()
+	defer c.unlock()
+
+	items := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, _, ok := c.get(key); ok {
+			items[key] = value
+		}
+	}
+
+	return items
+}
+
+func (c *BoundedCache[K, V]) get(key K) (V, time.Time, bool) {
+	entry, ok := c.lookup(key)
+	if !ok {
+		c.stats.record(Miss)
+
+		var zeroV V
+
+		return zeroV, time.Time{}, false
+	}
+	c.stats.record(Hit)
+	c.policy.touch(key)
+	if c.slidingTTL > 0 && !entry.expiry.IsZero() {
+		entry.expiry = c.clock.Now().Add(c.slidingTTL)
+	}
+
+	return entry.value, entry.expiry, true
+}
+
+// Update updates the value of an existing entry and records its use.
+// A zero expiration keeps the current expiry.
+func (c *BoundedCache[K, V]) Update(key K, newValue V, expiration time.Duration) bool {
+	c.Lock()
+	defer c.unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		return false
+	}
+
+	c.evicted(key, entry.value, ReasonReplaced)
+	entry.value = newValue
+	if expiration != 0 {
+		entry.expiry = c.clock.Now().Add(expiration)
+	}
+	c.policy.touch(key)
+
+	return c.resize(key, entry)
+}
+
+// Upsert atomically replaces the item with fn(old, exists) and returns the new value.
+// fn runs under the cache lock, so it must be fast and must not use the cache.
+// ttl sets the expiry; zero keeps the expiry of an existing item.
+func (c *BoundedCache[K, V]) Upsert(key K, fn func(old V, exists bool) V, ttl time.Duration) V {
+	value, _ := c.modify(key, alwaysWrite(fn), ttl)
+
+	return value
+}
+
+func (c *BoundedCache[K, V]) modify(key K, fn func(old V, exists bool) (V, bool), ttl time.Duration) (V, bool) {
+	c.Lock()
+	defer c.unlock()
+
+	entry, exists := c.lookup(key)
+	if !exists {
+		var zeroV V
+		value, write := fn(zeroV, false)
+		if !write {
+			return zeroV, false
+		}
+		c.add(key, value, ttl, nil)
+
+		return value, true
+	}
+
+	value, write := fn(entry.value, true)
+	if !write {
+		return entry.value, false
+	}
+
+	c.evicted(key, entry.value, ReasonReplaced)
+	entry.value = value
+	if ttl != 0 {
+		entry.expiry = c.clock.Now().Add(ttl)
+	}
+	c.policy.touch(key)
+	c.resize(key, entry)
+
+	return value, true
+}
+
+// Exists reports whether the key is cached, without recording a use.
+func (c *BoundedCache[K, V]) Exists(key K) bool {
+	c.Lock()
+	defer c.unlock()
+
+	_, ok := c.lookup(key)
+
+	return ok
+}
+
+// Keys returns the keys of the unexpired items, from the most to the least likely
+// to be kept by the policy; for LRU, from the most to the least recently used.
+func (c *BoundedCache[K, V]) Keys() []K {
+	c.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	keys := c.policy.keys()
+
+	return slices.DeleteFunc(keys, func(key K) bool {
+		entry := c.entries[key]
+
+		return entry.expired(now) || !c.tags.valid(entry.tags)
+	})
+}
+
+// Range calls fn for each unexpired item, in the order of Keys, until fn returns false.
+// It iterates over a copy taken under the lock, so fn may use the cache.
+func (c *BoundedCache[K, V]) Range(fn func(key K, value V) bool) {
+	entries, _ := c.snapshotEntries()
+	for _, entry := range entries {
+		if !fn(entry.key, entry.value) {
+			return
+		}
+	}
+}
+
+// Len returns the number of unexpired items.
+func (c *BoundedCache[K, V]) Len() int {
+	c.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	n := 0
+	for _, entry := range c.entries {
+		if !entry.expired(now) && c.tags.valid(entry.tags) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Clear deletes every item, calling the eviction callback for each.
+func (c *BoundedCache[K, V]) Clear() {
+	c.Lock()
+	defer c.unlock()
+
+	for key := range c.entries {
+		c.remove(key, ReasonDeleted)
+	}
+}
+
+// SetNX adds the item only if the key is missing or expired, and reports whether it did,
+// so the cache can hold locks.
+//
+//   - expiration: 0 for disable expire cache
+func (c *BoundedCache[K, V]) SetNX(key K, value V, expiration time.Duration) bool {
+	_, written := c.modify(key, setIfAbsent(value), expiration)
+
+	return written
+}
+
+func (c *BoundedCache[K, V]) Delete(key K) bool {
+	c.Lock()
+	defer c.unlock()
+
+	c.remove(key, ReasonDeleted)
+
+	return true
+}
+
+// DeleteMulti deletes the items of keys, under a single lock.
+func (c *BoundedCache[K, V]) DeleteMulti(keys []K) bool {
+	c.Lock()
+	defer c.unlock()
+
+	for _, key := range keys {
+		c.remove(key, ReasonDeleted)
+	}
+
+	return true
+}
+
+// GetOrLoad returns the cached item, or loads it with loader and stores it with ttl.
+// Concurrent calls missing the same key share a single load.
+func (c *BoundedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V], ttl time.Duration) (V, error) {
+	return getOrLoad(ctx, c, c.peek, &c.loads, key, loader, ttl)
+}
+
+// Stats returns the hit, miss, eviction and expiration counters.
+func (c *BoundedCache[K, V]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// SaveSnapshot writes the unexpired items with their expiry to w, in the order of
+// the eviction policy, encoded with the codec set by WithSnapshotCodec.
+func (c *BoundedCache[K, V]) SaveSnapshot(w io.Writer) error {
+	return saveSnapshot[K, V](w, c)
+}
+
+// LoadSnapshot adds the unexpired items of a snapshot written by SaveSnapshot,
+// restoring the order of the eviction policy as far as it allows.
+// If the snapshot holds more items than the cache, the last ones are evicted.
+func (c *BoundedCache[K, V]) LoadSnapshot(r io.Reader) error {
+	return loadSnapshot[K, V](r, c, c.codec)
+}
+
+func (c *BoundedCache[K, V]) snapshotEntries() ([]snapshotEntry[K, V], error) {
+	c.Lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	entries := make([]snapshotEntry[K, V], 0, len(c.entries))
+	for _, key := range c.policy.keys() {
+		entry := c.entries[key]
+		if !entry.expired(now) && c.tags.valid(entry.tags) {
+			entries = append(entries, snapshotEntry[K, V]{key: key, value: entry.value, expiry: entry.expiry})
+		}
+	}
+
+	return entries, nil
+}
+
+func (c *BoundedCache[K, V]) snapshotCodec() Codec[K, V] {
+	return c.codec
+}
+
+// peek returns the item without recording a use or counting a hit or a miss.
+func (c *BoundedCache[K, V]) peek(key K) (V, bool) {
+	c.Lock()
+	defer c.unlock()
+
+	entry, ok := c.lookup(key)
+	if !ok {
+		var zeroV V
+
+		return zeroV, false
+	}
+
+	return entry.value, true
+}
+
+// lookup returns the entry of key, removing it if it has expired
+// and is past the stale-while-revalidate grace period, or if one of its tags was invalidated.
+func (c *BoundedCache[K, V]) lookup(key K) (*boundedEntry[V], bool) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.tags.valid(entry.tags) {
+		c.remove(key, ReasonDeleted)
+
+		return nil, false
+	}
+
+	now := c.clock.Now()
+	if entry.expired(now) {
+		if entry.expired(now.Add(-c.grace)) {
+			c.remove(key, ReasonExpired)
+			c.stats.record(Expiration)
+		}
+
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// stale returns the value of an entry expired less than the grace period ago.
+func (c *BoundedCache[K, V]) stale(key K) (V, bool) {
+	c.Lock()
+	defer c.unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		now := c.clock.Now()
+		if entry.expired(now) && !entry.expired(now.Add(-c.grace)) && c.tags.valid(entry.tags) {
+			return entry.value, true
+		}
+	}
+
+	var zeroV V
+
+	return zeroV, false
+}
+
+func (c *BoundedCache[K, V]) refreshOptions() []retry.Options {
+	return c.refreshRetry
+}
+
+func (c *BoundedCache[K, V]) remove(key K, reason Reason) {
+	if entry, ok := c.entries[key]; ok {
+		delete(c.entries, key)
+		c.bytes -= entry.size
+		c.policy.remove(key)
+		c.evicted(key, entry.value, reason)
+	}
+}
+
+// evicted queues the eviction callback, called by unlock once the lock is released.
+func (c *BoundedCache[K, V]) evicted(key K, value V, reason Reason) {
+	if c.onEvict != nil {
+		c.pending = append(c.pending, eviction[K, V]{key: key, value: value, reason: reason})
+	}
+}
+
+// unlock releases the lock and calls the eviction callback for the queued entries.
+func (c *BoundedCache[K, V]) unlock() {
+	pending := c.pending
+	c.pending = nil
+	c.Unlock()
+
+	for _, e := range pending {
+		c.onEvict(e.key, e.value, e.reason)
+	}
+}
+
+// InvalidateTag invalidates every item tagged with tag by WithTags, at once.
+// The items are removed on access and by the periodic clean-up.
+func (c *BoundedCache[K, V]) InvalidateTag(tag string) {
+	c.tags.invalidate(tag)
+}
+
+// CleanupNow removes the expired and invalidated entries now, without waiting for the periodic clean-up.
+func (c *BoundedCache[K, V]) CleanupNow() {
+	c.cleanupExpiredEntries()
+}
+
+func (c *BoundedCache[K, V]) now() time.Time {
+	return c.clock.Now()
+}
+
+func (c *BoundedCache[K, V]) cleanupExpiredEntries() {
+	c.Lock()
+	defer c.unlock()
+
+	horizon := c.tags.horizon()
+	cutoff := c.clock.Now().Add(-c.grace)
+	for key, entry := range c.entries {
+		switch {
+		case !c.tags.valid(entry.tags):
+			c.remove(key, ReasonDeleted)
+		case entry.expired(cutoff):
+			c.remove(key, ReasonExpired)
+			c.stats.record(Expiration)
+		}
+	}
+	c.tags.prune(horizon)
+}
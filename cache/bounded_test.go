@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLRUEviction(t *testing.T) {
+	cache := NewLRU[string, int](t.Context(), 2)
+
+	cache.Add("one", 1, 0)
+	cache.Add("two", 2, 0)
+
+	// Using "one" makes "two" the least recently used entry.
+	if val, ok := cache.Get("one"); !ok || val != 1 {
+		t.Fatalf("expected one=1, got %v, %v", val, ok)
+	}
+
+	cache.Add("three", 3, 0)
+
+	if cache.Exists("two") {
+		t.Error("expected two to be evicted")
+	}
+	if !cache.Exists("one") || !cache.Exists("three") {
+		t.Error("expected one and three to stay cached")
+	}
+
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"three", "one"}) {
+		t.Errorf("unexpected keys order: %v", keys)
+	}
+}
+
+func TestLRUAddExisting(t *testing.T) {
+	cache := NewLRU[string, int](t.Context(), 2)
+
+	cache.Add("one", 1, 0)
+	cache.Add("two", 2, 0)
+	cache.Add("one", 10, 0)
+	cache.Add("three", 3, 0)
+
+	if val, _ := cache.Get("one"); val != 10 {
+		t.Errorf("expected one=10, got %v", val)
+	}
+	if cache.Exists("two") {
+		t.Error("expected two to be evicted")
+	}
+	if len(cache.Keys()) != 2 {
+		t.Errorf("expected 2 keys, got %v", cache.Keys())
+	}
+}
+
+func TestLRUUpdateAndDelete(t *testing.T) {
+	cache := NewLRU[int, string](t.Context(), 10)
+
+	if cache.Update(1, "missing", 0) {
+		t.Error("expected update of a missing key to fail")
+	}
+
+	cache.Add(1, "one", 0)
+	if !cache.Update(1, "uno", time.Minute) {
+		t.Error("expected update to succeed")
+	}
+	if val, _ := cache.Get(1); val != "uno" {
+		t.Errorf("expected uno, got %v", val)
+	}
+
+	if !cache.Delete(1) || cache.Exists(1) {
+		t.Error("expected 1 to be deleted")
+	}
+}
+
+func TestLRUExpiry(t *testing.T) {
+	cache := NewLRU[string, int](t.Context(), 10, WithCleanUpInterval(10*time.Millisecond))
+
+	cache.Add("short", 1, 20*time.Millisecond)
+	cache.Add("long", 2, time.Hour)
+	cache.Add("forever", 3, 0)
+
+	time.Sleep(30 * time.Millisecond)
+
+	if _, ok := cache.Get("short"); ok {
+		t.Error("expected short to be expired")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if keys := cache.Keys(); len(keys) != 2 {
+		t.Errorf("expected the cleanup to leave 2 keys, got %v", keys)
+	}
+}
+
+func TestBoundedSlidingTTL(t *testing.T) {
+	cache := NewBounded[string, int](t.Context(), 10, WithSlidingTTL(40*time.Millisecond))
+
+	cache.Add("session", 1, 40*time.Millisecond)
+	cache.Add("idle", 2, 40*time.Millisecond)
+	cache.Add("forever", 3, 0)
+
+	// Each read extends the expiry of session by another 40ms.
+	for range 4 {
+		time.Sleep(20 * time.Millisecond)
+		if _, ok := cache.Get("session"); !ok {
+			t.Fatal("expected session to be kept alive by reads")
+		}
+	}
+
+	if _, ok := cache.Get("idle"); ok {
+		t.Error("expected idle to be expired")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		t.Error("expected forever to never expire")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	if _, ok := cache.Get("session"); ok {
+		t.Error("expected session to expire once idle")
+	}
+}
+
+func BenchmarkLRUAdd(b *testing.B) {
+	b.ReportAllocs()
+	cache := NewLRU[int, int](b.Context(), 1000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		cache.Add(i, i, 0)
+	}
+}
@@ -1,20 +1,56 @@
 package cache
 
 import (
+	"context"
+	"io"
 	"time"
 )
 
-type Cache[K any, V any] interface {
-	// Add store your item to cache
-	Add(key K, value V, expiration time.Duration) bool
+type Cache[K comparable, V any] interface {
+	// Add store your item to cache; WithTags tags it
+	Add(key K, value V, expiration time.Duration, opts ...AddOption) bool
 	// Get load your item from cache
 	Get(key K) (V, bool)
+	// GetWithExpiry returns the item like Get, with its expiry, the zero time if it never expires
+	GetWithExpiry(key K) (V, time.Time, bool)
+	// SetNX adds the item only if the key is missing or expired, and reports whether it did
+	SetNX(key K, value V, expiration time.Duration) bool
 	// Update updates the Value of an existing entry in the cache.
 	Update(key K, newValue V, expiration time.Duration) bool
 	// Exists check your key exists in cache
 	Exists(key K) bool
 	// Keys return list of keys in cache
 	Keys() []K
+	// Range calls fn for each unexpired item until fn returns false, without allocating the keys
+	Range(fn func(key K, value V) bool)
+	// Len returns the number of unexpired items
+	Len() int
+	// Clear deletes every item
+	Clear()
+	// InvalidateTag invalidates every item tagged with tag, at once
+	InvalidateTag(tag string)
 	// Delete delete specific item from cache base on key
 	Delete(key K) bool
+	// Upsert atomically replaces the item with fn(old, exists) and returns the new value.
+	// ttl sets the expiry; zero keeps the expiry of an existing item.
+	Upsert(key K, fn func(old V, exists bool) V, ttl time.Duration) V
+	// GetMulti returns the items found among keys; missing keys are left out
+	GetMulti(keys []K) map[K]V
+	// AddMulti stores all the items with the same expiration
+	AddMulti(items map[K]V, expiration time.Duration) bool
+	// DeleteMulti deletes the items of keys
+	DeleteMulti(keys []K) bool
+	// GetOrLoad returns the cached item, or loads it with loader and stores it with ttl.
+	// Concurrent calls missing the same key share a single load.
+	GetOrLoad(ctx context.Context, key K, loader Loader[V], ttl time.Duration) (V, error)
+	// Stats returns the hit, miss, eviction and expiration counters
+	Stats() Stats
+	// CleanupNow removes the expired items now, without waiting for the periodic clean-up
+	CleanupNow()
+	// SaveSnapshot writes the unexpired items with their expiry to w,
+	// so a warm cache can be persisted on shutdown
+	SaveSnapshot(w io.Writer) error
+	// LoadSnapshot adds the unexpired items of a snapshot written by SaveSnapshot,
+	// so the cache is warm again on startup
+	LoadSnapshot(r io.Reader) error
 }
@@ -0,0 +1,318 @@
+// Package cachetest validates the caching behavior of integration tests:
+// it wraps a cache.Cache to record its reads and writes, and asserts on how stale
+// its reads were and how often they hit.
+//
+//	c, rec := cachetest.Wrap(cache.NewTiered(local, remote))
+//	service := NewService(c)
+//	... // exercise the service, update the data behind it
+//	rec.AssertNoStaleReadsBeyond(t, 5*time.Second)
+//	rec.AssertHitRatioAtLeast(t, 0.9)
+package cachetest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/cache"
+)
+
+// Read is a recorded read of a key.
+type Read[K comparable] struct {
+	Key  K
+	Hit  bool
+	Time time.Time
+	// Staleness is how long the value read had been superseded, by a write
+	// through a cache wrapped by the recorder or a change reported with SourceChanged.
+	// It is zero for a miss and for a current value.
+	Staleness time.Duration
+}
+
+// version is a recorded write of a key, or a change of its source without a value.
+type version struct {
+	time     time.Time
+	value    any
+	hasValue bool
+}
+
+type options struct {
+	clock cache.Clock
+}
+
+// Option configures a Recorder.
+type Option func(*options)
+
+// WithClock sets the clock the recorder reads the time from, such as the fake clock
+// given to the cache with cache.WithClock.
+func WithClock(clock cache.Clock) Option {
+	return func(opts *options) {
+		opts.clock = clock
+	}
+}
+
+// Recorder records the reads and writes of the caches it wraps.
+// It is safe for concurrent use.
+type Recorder[K comparable, V any] struct {
+	mu       sync.Mutex
+	clock    cache.Clock
+	versions map[K][]version
+	reads    []Read[K]
+}
+
+// NewRecorder returns a Recorder. Wrap several caches with the same recorder,
+// such as those of two instances of a service, to detect the stale reads
+// of one after a write through the other.
+func NewRecorder[K comparable, V any](opts ...Option) *Recorder[K, V] {
+	cfg := options{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Recorder[K, V]{clock: cfg.clock, versions: map[K][]version{}}
+}
+
+// Wrap returns c recording its operations to a new Recorder.
+func Wrap[K comparable, V any](c cache.Cache[K, V], opts ...Option) (cache.Cache[K, V], *Recorder[K, V]) {
+	rec := NewRecorder[K, V](opts...)
+
+	return rec.Wrap(c), rec
+}
+
+// Wrap returns c recording its operations to r.
+func (r *Recorder[K, V]) Wrap(c cache.Cache[K, V]) cache.Cache[K, V] {
+	return &recorded[K, V]{Cache: c, rec: r}
+}
+
+// SourceChanged records that the data cached under key changed, such as a row updated
+// in the database, so the values read from the cache afterwards are stale.
+func (r *Recorder[K, V]) SourceChanged(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.versions[key] = append(r.versions[key], version{time: r.clock.Now()})
+}
+
+// Reads returns the recorded reads, in order.
+func (r *Recorder[K, V]) Reads() []Read[K] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Read[K](nil), r.reads...)
+}
+
+// HitRatio returns the fraction of the recorded reads that hit, or 0 without reads.
+func (r *Recorder[K, V]) HitRatio() float64 {
+	reads := r.Reads()
+	if len(reads) == 0 {
+		return 0
+	}
+
+	hits := 0
+	for _, read := range reads {
+		if read.Hit {
+			hits++
+		}
+	}
+
+	return float64(hits) / float64(len(reads))
+}
+
+// Reset forgets the recorded reads and writes.
+func (r *Recorder[K, V]) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reads = nil
+	clear(r.versions)
+}
+
+// AssertNoStaleReadsBeyond fails t for each read of a value that had been superseded
+// for longer than bound, such as the TTL of a local cache in front of a shared one.
+// It reports whether there was none.
+func (r *Recorder[K, V]) AssertNoStaleReadsBeyond(t testing.TB, bound time.Duration) bool {
+	t.Helper()
+
+	ok := true
+	for _, read := range r.Reads() {
+		if read.Staleness > bound {
+			t.Errorf("cachetest: stale read of %v at %s: superseded %s ago, beyond %s",
+				read.Key, read.Time.Format(time.RFC3339Nano), read.Staleness, bound)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// AssertHitRatioAtLeast fails t if the hit ratio of the recorded reads is below minRatio,
+// and reports whether it is not.
+func (r *Recorder[K, V]) AssertHitRatioAtLeast(t testing.TB, minRatio float64) bool {
+	t.Helper()
+
+	if ratio := r.HitRatio(); ratio < minRatio {
+		t.Errorf("cachetest: hit ratio %.3f of %d reads, expected at least %.3f", ratio, len(r.Reads()), minRatio)
+
+		return false
+	}
+
+	return true
+}
+
+func (r *Recorder[K, V]) write(key K, value V) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.versions[key] = append(r.versions[key], version{time: r.clock.Now(), value: value, hasValue: true})
+}
+
+func (r *Recorder[K, V]) hit(key K, value V) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.clock.Now()
+	r.reads = append(r.reads, Read[K]{Key: key, Hit: true, Time: now, Staleness: r.staleness(key, value, now)})
+}
+
+func (r *Recorder[K, V]) miss(key K) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reads = append(r.reads, Read[K]{Key: key, Time: r.clock.Now()})
+}
+
+// staleness returns how long value had been superseded at now: the time elapsed
+// since the first version of key following the latest write of value.
+// A value never written through the recorder is not known to be stale.
+func (r *Recorder[K, V]) staleness(key K, value V, now time.Time) time.Duration {
+	versions := r.versions[key]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].hasValue || !reflect.DeepEqual(versions[i].value, value) {
+			continue
+		}
+		if i == len(versions)-1 {
+			return 0
+		}
+
+		return max(now.Sub(versions[i+1].time), 0)
+	}
+
+	return 0
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// recorded is a cache recording its operations to a Recorder.
+type recorded[K comparable, V any] struct {
+	cache.Cache[K, V]
+
+	rec *Recorder[K, V]
+}
+
+func (c *recorded[K, V]) Add(key K, value V, expiration time.Duration, opts ...cache.AddOption) bool {
+	added := c.Cache.Add(key, value, expiration, opts...)
+	if added {
+		c.rec.write(key, value)
+	}
+
+	return added
+}
+
+func (c *recorded[K, V]) SetNX(key K, value V, expiration time.Duration) bool {
+	added := c.Cache.SetNX(key, value, expiration)
+	if added {
+		c.rec.write(key, value)
+	}
+
+	return added
+}
+
+func (c *recorded[K, V]) Update(key K, value V, expiration time.Duration) bool {
+	updated := c.Cache.Update(key, value, expiration)
+	if updated {
+		c.rec.write(key, value)
+	}
+
+	return updated
+}
+
+func (c *recorded[K, V]) Upsert(key K, fn func(old V, exists bool) V, ttl time.Duration) V {
+	value := c.Cache.Upsert(key, fn, ttl)
+	c.rec.write(key, value)
+
+	return value
+}
+
+func (c *recorded[K, V]) AddMulti(items map[K]V, expiration time.Duration) bool {
+	added := c.Cache.AddMulti(items, expiration)
+	if added {
+		for key, value := range items {
+			c.rec.write(key, value)
+		}
+	}
+
+	return added
+}
+
+func (c *recorded[K, V]) Get(key K) (V, bool) {
+	value, ok := c.Cache.Get(key)
+	if ok {
+		c.rec.hit(key, value)
+	} else {
+		c.rec.miss(key)
+	}
+
+	return value, ok
+}
+
+func (c *recorded[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	value, expiry, ok := c.Cache.GetWithExpiry(key)
+	if ok {
+		c.rec.hit(key, value)
+	} else {
+		c.rec.miss(key)
+	}
+
+	return value, expiry, ok
+}
+
+func (c *recorded[K, V]) GetMulti(keys []K) map[K]V {
+	found := c.Cache.GetMulti(keys)
+	for _, key := range keys {
+		value, ok := found[key]
+		if ok {
+			c.rec.hit(key, value)
+		} else {
+			c.rec.miss(key)
+		}
+	}
+
+	return found
+}
+
+// GetOrLoad records a hit if the loader isn't called, and a miss and a write otherwise.
+func (c *recorded[K, V]) GetOrLoad(ctx context.Context, key K, loader cache.Loader[V], ttl time.Duration) (V, error) {
+	var loaded bool
+	value, err := c.Cache.GetOrLoad(ctx, key, func(ctx context.Context) (V, error) {
+		loaded = true
+
+		return loader(ctx)
+	}, ttl)
+	if err != nil {
+		return value, err
+	}
+
+	if loaded {
+		c.rec.miss(key)
+		c.rec.write(key, value)
+	} else {
+		c.rec.hit(key, value)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,128 @@
+package cachetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/cache"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// failures records the failures of the assertions under test.
+type failures struct {
+	testing.TB
+
+	errors int
+}
+
+func (*failures) Helper() {}
+
+func (f *failures) Errorf(string, ...any) {
+	f.errors++
+}
+
+func TestStaleReadAcrossInstances(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	rec := NewRecorder[string, int](WithClock(clock))
+	first := rec.Wrap(cache.NewBasic[string, int](t.Context(), cache.WithClock(clock)))
+	second := rec.Wrap(cache.NewBasic[string, int](t.Context(), cache.WithClock(clock)))
+
+	first.Add("price", 1, time.Hour)
+	clock.advance(time.Second)
+	second.Add("price", 2, time.Hour)
+	clock.advance(3 * time.Second)
+
+	if value, _ := first.Get("price"); value != 1 {
+		t.Fatalf("Get() = %d, want the stale 1", value)
+	}
+	if value, _ := second.Get("price"); value != 2 {
+		t.Fatalf("Get() = %d, want 2", value)
+	}
+
+	reads := rec.Reads()
+	if len(reads) != 2 || reads[0].Staleness != 3*time.Second || reads[1].Staleness != 0 {
+		t.Fatalf("Reads() = %+v, want staleness 3s then 0", reads)
+	}
+
+	if !rec.AssertNoStaleReadsBeyond(t, 5*time.Second) {
+		t.Error("expected reads 3s stale to pass a 5s bound")
+	}
+	tb := &failures{TB: t}
+	if rec.AssertNoStaleReadsBeyond(tb, 2*time.Second) || tb.errors != 1 {
+		t.Errorf("expected one failure for a 2s bound, got %d", tb.errors)
+	}
+}
+
+func TestSourceChanged(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	c, rec := Wrap(cache.NewBasic[string, int](t.Context(), cache.WithClock(clock)), WithClock(clock))
+
+	c.Add("price", 1, time.Hour)
+	c.Get("price")
+	rec.SourceChanged("price")
+	clock.advance(time.Minute)
+	c.Get("price")
+	c.Update("price", 2, time.Hour)
+	c.Get("price")
+
+	var staleness []time.Duration
+	for _, read := range rec.Reads() {
+		staleness = append(staleness, read.Staleness)
+	}
+	if len(staleness) != 3 || staleness[0] != 0 || staleness[1] != time.Minute || staleness[2] != 0 {
+		t.Errorf("staleness = %v, want [0 1m 0]", staleness)
+	}
+}
+
+func TestHitRatio(t *testing.T) {
+	c, rec := Wrap(cache.NewBasic[string, int](t.Context()))
+
+	if rec.HitRatio() != 0 {
+		t.Errorf("HitRatio() = %v before any read, want 0", rec.HitRatio())
+	}
+
+	loader := func(context.Context) (int, error) { return 1, nil }
+	for range 3 {
+		if _, err := c.GetOrLoad(t.Context(), "a", loader, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.GetMulti([]string{"a", "b"})
+
+	// Misses: the first load, then "b".
+	if ratio := rec.HitRatio(); ratio != 0.6 {
+		t.Errorf("HitRatio() = %v, want 0.6", ratio)
+	}
+	if !rec.AssertHitRatioAtLeast(t, 0.5) {
+		t.Error("expected a 0.6 ratio to pass 0.5")
+	}
+	tb := &failures{TB: t}
+	if rec.AssertHitRatioAtLeast(tb, 0.9) || tb.errors != 1 {
+		t.Errorf("expected one failure for 0.9, got %d", tb.errors)
+	}
+
+	rec.Reset()
+	if len(rec.Reads()) != 0 {
+		t.Errorf("Reads() = %v after Reset, want none", rec.Reads())
+	}
+}
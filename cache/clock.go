@@ -0,0 +1,45 @@
+package cache
+
+import "time"
+
+// Clock tells the current time to a cache.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the Clock of the system, used by default.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock sets the clock the cache reads the time from to compute and check
+// expiries, so tests can move a fake clock forward instead of sleeping:
+//
+//	clock := &fakeClock{now: time.Now()}
+//	c := cache.NewBasic[string, int](ctx, cache.WithClock(clock))
+//	c.Add("a", 1, time.Minute)
+//	clock.now = clock.now.Add(2 * time.Minute)
+//	c.CleanupNow() // "a" is removed
+//
+// NewRedis ignores it, since Redis expires entries on its own.
+func WithClock(clock Clock) Option {
+	return func(cfg *options) {
+		cfg.clock = clock
+	}
+}
+
+// clocked is implemented by the caches of this package, reading the time from a Clock.
+type clocked interface {
+	now() time.Time
+}
+
+// nowOf returns the current time of cache, from its clock when it has one.
+func nowOf(cache any) time.Time {
+	if c, ok := cache.(clocked); ok {
+		return c.now()
+	}
+
+	return time.Now()
+}
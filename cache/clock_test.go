@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"bytes"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock moved forward by the test.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestWithClock(t *testing.T) {
+	newCaches := map[string]func(opts ...Option) Cache[string, int]{
+		"basic":   func(opts ...Option) Cache[string, int] { return NewBasic[string, int](t.Context(), opts...) },
+		"bounded": func(opts ...Option) Cache[string, int] { return NewBounded[string, int](t.Context(), 10, opts...) },
+		"sharded": func(opts ...Option) Cache[string, int] { return NewSharded[string, int](t.Context(), 4, opts...) },
+	}
+
+	for name, newCache := range newCaches {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Now()}
+			var mu sync.Mutex
+			var expired []string
+			cache := newCache(WithClock(clock), WithCleanUpInterval(0),
+				WithOnEvict(func(key string, _ int, reason Reason) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					if reason == ReasonExpired {
+						expired = append(expired, key)
+					}
+				}))
+
+			cache.Add("a", 1, time.Minute)
+			cache.Add("b", 2, time.Hour)
+
+			clock.advance(2 * time.Minute)
+			if _, ok := cache.Get("a"); ok {
+				t.Error("expected a to be expired by the clock")
+			}
+			if _, ok := cache.Get("b"); !ok {
+				t.Error("expected b to be alive")
+			}
+
+			clock.advance(2 * time.Hour)
+			if got := cache.Keys(); len(got) != 0 {
+				t.Fatalf("expected Keys to skip the expired items, got %v", got)
+			}
+			cache.CleanupNow()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !slices.Contains(expired, "b") {
+				t.Errorf("expected CleanupNow to remove the expired items, got %v", expired)
+			}
+		})
+	}
+}
+
+func TestWithClockSnapshot(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	cache := NewBasic[string, int](t.Context(), WithClock(clock))
+	cache.Add("a", 1, time.Hour)
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.advance(30 * time.Minute)
+	restored := NewBasic[string, int](t.Context(), WithClock(clock))
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, ok := restored.(*BasicCache[string, int]).lookup("a")
+	if !ok {
+		t.Fatal("expected a to be restored")
+	}
+	if remaining := entry.Expiry.Sub(clock.Now()); remaining != 30*time.Minute {
+		t.Errorf("expected the remaining TTL on the clock, got %v", remaining)
+	}
+}
+
+func TestCleanupNowNamespace(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	shared := NewBasic[string, int](t.Context(), WithClock(clock), WithCleanUpInterval(0))
+	tenant := Namespace(shared, "tenant:")
+
+	tenant.Add("a", 1, time.Second)
+	clock.advance(time.Minute)
+	tenant.CleanupNow()
+
+	if shared.Exists("tenant:a") {
+		t.Error("expected CleanupNow to remove the expired items")
+	}
+}
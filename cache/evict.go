@@ -0,0 +1,81 @@
+package cache
+
+import "fmt"
+
+// Reason tells why an entry left the cache.
+type Reason int
+
+const (
+	// ReasonExpired is an entry removed after its expiry, on access or by the cleanup.
+	ReasonExpired Reason = iota
+	// ReasonReplaced is a value overwritten by Add or Update.
+	ReasonReplaced
+	// ReasonDeleted is an entry removed by Delete.
+	ReasonDeleted
+	// ReasonEvicted is an entry removed by the eviction policy to make room for another.
+	ReasonEvicted
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonExpired:
+		return "expired"
+	case ReasonReplaced:
+		return "replaced"
+	case ReasonDeleted:
+		return "deleted"
+	case ReasonEvicted:
+		return "evicted"
+	default:
+		return "unknown"
+	}
+}
+
+// WithOnEvict sets a function called with the key and the value of every entry
+// leaving the cache, and why, to release resources tied to the values.
+// It is called after the cache is updated, outside of its locks.
+//
+// K and V must match the types of the cache, or the constructor panics.
+// NewRedis ignores it, since Redis expires and evicts entries on its own.
+func WithOnEvict[K, V any](onEvict func(key K, value V, reason Reason)) Option {
+	return func(cfg *options) {
+		cfg.onEvict = onEvict
+	}
+}
+
+// onEvictFunc returns the eviction callback of cfg, also sending the expired
+// items to the WithExpirations pipeline, or nil when neither is set.
+func onEvictFunc[K, V any](cfg *options) func(key K, value V, reason Reason) {
+	expirations := expirationsOf[K, V](cfg)
+	if cfg.onEvict == nil && expirations == nil {
+		return nil
+	}
+
+	onEvict, ok := cfg.onEvict.(func(key K, value V, reason Reason))
+	if !ok && cfg.onEvict != nil {
+		var key K
+		var value V
+		panic(fmt.Sprintf("cache: WithOnEvict callback %T does not match the cache types %T and %T",
+			cfg.onEvict, key, value))
+	}
+
+	if expirations == nil {
+		return onEvict
+	}
+
+	return func(key K, value V, reason Reason) {
+		if onEvict != nil {
+			onEvict(key, value, reason)
+		}
+		if reason == ReasonExpired {
+			expirations.Send(ExpiredEntry[K, V]{Key: key, Value: value})
+		}
+	}
+}
+
+// eviction is an entry that left the cache, waiting for the callback.
+type eviction[K, V any] struct {
+	key    K
+	value  V
+	reason Reason
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type evictionLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *evictionLog) onEvict(key string, value int, reason Reason) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, fmt.Sprintf("%s=%d %s", key, value, reason))
+}
+
+func (l *evictionLog) sorted() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return slices.Sorted(slices.Values(l.events))
+}
+
+func TestOnEvict(t *testing.T) {
+	constructors := map[string]func(opt Option) Cache[string, int]{
+		"basic": func(opt Option) Cache[string, int] {
+			return NewBasic[string, int](t.Context(), opt)
+		},
+		"bounded": func(opt Option) Cache[string, int] {
+			return NewBounded[string, int](t.Context(), 10, opt)
+		},
+	}
+
+	for name, newCache := range constructors {
+		t.Run(name, func(t *testing.T) {
+			log := &evictionLog{}
+			cache := newCache(WithOnEvict(log.onEvict))
+
+			cache.Add("a", 1, 0)
+			cache.Add("a", 2, 0)
+			cache.Update("a", 3, 0)
+			cache.Delete("a")
+			cache.Delete("missing")
+			cache.Add("short", 4, time.Millisecond)
+			time.Sleep(5 * time.Millisecond)
+			cache.Get("short")
+
+			want := []string{"a=1 replaced", "a=2 replaced", "a=3 deleted", "short=4 expired"}
+			if got := log.sorted(); !slices.Equal(got, want) {
+				t.Errorf("evictions = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestOnEvictCapacity(t *testing.T) {
+	log := &evictionLog{}
+	cache := NewBounded[string, int](t.Context(), 2, WithOnEvict(log.onEvict))
+
+	cache.Add("a", 1, 0)
+	cache.Add("b", 2, 0)
+	cache.Add("c", 3, 0)
+
+	if got, want := log.sorted(), []string{"a=1 evicted"}; !slices.Equal(got, want) {
+		t.Errorf("evictions = %v, want %v", got, want)
+	}
+}
+
+func TestOnEvictCleanup(t *testing.T) {
+	log := &evictionLog{}
+	cache := NewBounded[string, int](t.Context(), 2,
+		WithOnEvict(log.onEvict), WithCleanUpInterval(5*time.Millisecond))
+
+	cache.Add("a", 1, time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	if got, want := log.sorted(), []string{"a=1 expired"}; !slices.Equal(got, want) {
+		t.Errorf("evictions = %v, want %v", got, want)
+	}
+}
+
+func TestOnEvictCallbackUsesCache(t *testing.T) {
+	var cache Cache[string, int]
+	cache = NewBounded[string, int](t.Context(), 1, WithOnEvict(func(key string, _ int, _ Reason) {
+		// The lock is released before the callback runs.
+		cache.Exists(key)
+	}))
+
+	cache.Add("a", 1, 0)
+	cache.Add("b", 2, 0)
+}
+
+func TestOnEvictTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for mismatched callback types")
+		}
+	}()
+
+	NewBasic[string, int](t.Context(), WithOnEvict(func(int, int, Reason) {}))
+}
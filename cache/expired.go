@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/ezex-io/gopkg/pipeline"
+)
+
+// ExpiredEntry is an item removed from the cache because it expired.
+type ExpiredEntry[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// WithExpirations sends every item removed from the cache because it expired to
+// expirations, so workflows can react to expiry, such as notifying a user that
+// a one-time password expired, without polling the cache. Any pipeline.Pipeline
+// of ExpiredEntry can be passed.
+//
+// Expired items are removed when accessed or by the periodic clean-up, so an event
+// may come up to the clean-up interval after the expiry. A full pipeline blocks the
+// clean-up until it has room.
+//
+// K and V must match the types of the cache, or the constructor panics.
+// NewRedis ignores it, since Redis expires entries on its own.
+func WithExpirations[K, V any](expirations pipeline.Sender[ExpiredEntry[K, V]]) Option {
+	return func(cfg *options) {
+		cfg.expirations = expirations
+	}
+}
+
+// expirationsOf returns the expiration sender of cfg, or nil when none is set.
+func expirationsOf[K, V any](cfg *options) pipeline.Sender[ExpiredEntry[K, V]] {
+	if cfg.expirations == nil {
+		return nil
+	}
+
+	expirations, ok := cfg.expirations.(pipeline.Sender[ExpiredEntry[K, V]])
+	if !ok {
+		var key K
+		var value V
+		panic(fmt.Sprintf("cache: WithExpirations pipeline %T does not match the cache types %T and %T",
+			cfg.expirations, key, value))
+	}
+
+	return expirations
+}
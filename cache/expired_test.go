@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/pipeline"
+)
+
+func TestWithExpirations(t *testing.T) {
+	for name, newCache := range map[string]func(opts ...Option) Cache[string, int]{
+		"basic":   func(opts ...Option) Cache[string, int] { return NewBasic[string, int](t.Context(), opts...) },
+		"bounded": func(opts ...Option) Cache[string, int] { return NewBounded[string, int](t.Context(), 10, opts...) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			expirations := pipeline.New[ExpiredEntry[string, int]](t.Context(), pipeline.WithBufferSize(10))
+			var evictions atomic.Int32
+			cache := newCache(
+				WithCleanUpInterval(5*time.Millisecond),
+				WithExpirations[string, int](expirations),
+				WithOnEvict(func(string, int, Reason) { evictions.Add(1) }),
+			)
+
+			cache.Add("otp", 123456, 10*time.Millisecond)
+			cache.Add("kept", 1, 0)
+			cache.Delete("kept")
+
+			select {
+			case entry := <-expirations.UnsafeGetChannel():
+				if entry.Key != "otp" || entry.Value != 123456 {
+					t.Errorf("expired entry = %+v, want otp", entry)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("expected the expired entry to be sent")
+			}
+
+			select {
+			case entry := <-expirations.UnsafeGetChannel():
+				t.Errorf("expected only expired entries, got %+v", entry)
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			if n := evictions.Load(); n != 2 {
+				t.Errorf("expected the eviction callback to still be called twice, got %d", n)
+			}
+		})
+	}
+}
+
+func TestWithExpirationsMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a pipeline of the wrong types to panic")
+		}
+	}()
+
+	expirations := pipeline.New[ExpiredEntry[int, int]](t.Context())
+	NewBasic[string, int](t.Context(), WithExpirations[int, int](expirations))
+}
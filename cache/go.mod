@@ -2,6 +2,20 @@ module github.com/ezex-io/gopkg/cache
 
 go 1.25.1
 
-require github.com/ezex-io/gopkg/scheduler v0.0.0-20260120175238-90dc637d8ae0
+require (
+	github.com/alicebob/miniredis/v2 v2.39.0
+	github.com/ezex-io/gopkg/pipeline v0.0.0-20261016204807-65fc131ade42
+	github.com/ezex-io/gopkg/retry v0.0.0-20261016204801-424d63a71d26
+	github.com/ezex-io/gopkg/scheduler v0.0.0-20261016204807-65fc131ade42
+	github.com/redis/go-redis/v9 v9.22.0
+)
 
-require golang.org/x/sync v0.19.0 // indirect
+require (
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/ezex-io/gopkg/errors v0.0.0-20261016204707-b2f46bf9ce1b // indirect
+	github.com/robfig/cron/v3 v3.0.1 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.uber.org/atomic v1.11.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+)
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// keyLocks serializes operations per key with a fixed set of striped mutexes,
+// so memory stays constant whatever the number of keys.
+// The zero value is ready to use.
+type keyLocks struct {
+	seed    maphash.Seed
+	once    sync.Once
+	stripes [64]sync.Mutex
+}
+
+// lockKey locks the stripe of key and returns the function unlocking it.
+func lockKey[K comparable](l *keyLocks, key K) func() {
+	l.once.Do(func() {
+		l.seed = maphash.MakeSeed()
+	})
+
+	stripe := &l.stripes[maphash.Comparable(l.seed, key)%uint64(len(l.stripes))]
+	stripe.Lock()
+
+	return stripe.Unlock
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// KeyOf returns a canonical key for a tuple of values, to key a cache by several
+// fields without joining them with separators that may also appear in the values:
+//
+//	c.Add(cache.KeyOf(userID, market, interval), candles, time.Minute)
+//
+// Each value is encoded with its kind and length before being hashed with SHA-256,
+// so distinct tuples get distinct keys. Signed integers of any size share an encoding,
+// and so do unsigned integers and floats; structs are encoded field by field, with
+// their type name, maps in key order, and pointers as the value they point to.
+// Values implementing encoding.BinaryMarshaler, such as time.Time, are encoded with it.
+// KeyOf panics on values that can't be encoded: channels, functions and unsafe pointers.
+func KeyOf(vals ...any) string {
+	enc := keyEncoder{hash: sha256.New()}
+	enc.writeLen(len(vals))
+	for _, val := range vals {
+		enc.encode(reflect.ValueOf(val))
+	}
+
+	return base64.RawURLEncoding.EncodeToString(enc.hash.Sum(nil))
+}
+
+// Kind tags of the key encoding.
+const (
+	keyNil byte = iota
+	keyBool
+	keyInt
+	keyUint
+	keyFloat
+	keyComplex
+	keyString
+	keyBytes
+	keyList
+	keyMap
+	keyStruct
+	keyMarshaled
+)
+
+var binaryMarshalerType = reflect.TypeFor[encoding.BinaryMarshaler]()
+
+type keyEncoder struct {
+	hash hash.Hash
+	buf  [binary.MaxVarintLen64]byte
+}
+
+func (e *keyEncoder) writeTag(tag byte) {
+	e.buf[0] = tag
+	_, _ = e.hash.Write(e.buf[:1])
+}
+
+func (e *keyEncoder) writeUint(n uint64) {
+	_, _ = e.hash.Write(binary.BigEndian.AppendUint64(e.buf[:0], n))
+}
+
+func (e *keyEncoder) writeLen(n int) {
+	_, _ = e.hash.Write(binary.AppendUvarint(e.buf[:0], uint64(n)))
+}
+
+func (e *keyEncoder) writeBytes(data []byte) {
+	e.writeLen(len(data))
+	_, _ = e.hash.Write(data)
+}
+
+func (e *keyEncoder) writeString(s string) {
+	e.writeLen(len(s))
+	_, _ = e.hash.Write([]byte(s))
+}
+
+func (e *keyEncoder) encode(val reflect.Value) {
+	if !val.IsValid() {
+		e.writeTag(keyNil)
+
+		return
+	}
+	if e.encodeMarshaler(val) {
+		return
+	}
+
+	switch val.Kind() {
+	case reflect.Bool:
+		e.writeTag(keyBool)
+		if val.Bool() {
+			e.writeUint(1)
+		} else {
+			e.writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeTag(keyInt)
+		e.writeUint(uint64(val.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeTag(keyUint)
+		e.writeUint(val.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.writeTag(keyFloat)
+		e.writeUint(math.Float64bits(val.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		e.writeTag(keyComplex)
+		e.writeUint(math.Float64bits(real(val.Complex())))
+		e.writeUint(math.Float64bits(imag(val.Complex())))
+	case reflect.String:
+		e.writeTag(keyString)
+		e.writeString(val.String())
+	case reflect.Slice, reflect.Array:
+		e.encodeList(val)
+	case reflect.Map:
+		e.encodeMap(val)
+	case reflect.Struct:
+		e.writeTag(keyStruct)
+		e.writeString(val.Type().String())
+		e.writeLen(val.NumField())
+		for i := range val.NumField() {
+			e.writeString(val.Type().Field(i).Name)
+			e.encode(val.Field(i))
+		}
+	case reflect.Pointer, reflect.Interface:
+		if val.IsNil() {
+			e.writeTag(keyNil)
+
+			return
+		}
+		e.encode(val.Elem())
+	default:
+		panic(fmt.Sprintf("cache: KeyOf can't encode a %s", val.Type()))
+	}
+}
+
+// encodeMarshaler encodes val with MarshalBinary, and reports whether it implements it.
+func (e *keyEncoder) encodeMarshaler(val reflect.Value) bool {
+	if !val.Type().Implements(binaryMarshalerType) || !val.CanInterface() ||
+		(val.Kind() == reflect.Pointer && val.IsNil()) {
+		return false
+	}
+
+	data, err := val.Interface().(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic(fmt.Sprintf("cache: KeyOf can't encode %s: %v", val.Type(), err))
+	}
+	e.writeTag(keyMarshaled)
+	e.writeString(val.Type().String())
+	e.writeBytes(data)
+
+	return true
+}
+
+func (e *keyEncoder) encodeList(val reflect.Value) {
+	if val.Kind() == reflect.Slice && val.Type().Elem().Kind() == reflect.Uint8 {
+		e.writeTag(keyBytes)
+		e.writeBytes(val.Bytes())
+
+		return
+	}
+
+	e.writeTag(keyList)
+	e.writeLen(val.Len())
+	for i := range val.Len() {
+		e.encode(val.Index(i))
+	}
+}
+
+// encodeMap encodes the entries of a map sorted by their encoded key,
+// so the encoding doesn't depend on the iteration order.
+func (e *keyEncoder) encodeMap(val reflect.Value) {
+	type entry struct {
+		key   []byte
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, val.Len())
+	iter := val.MapRange()
+	for iter.Next() {
+		sub := keyEncoder{hash: sha256.New()}
+		sub.encode(iter.Key())
+		entries = append(entries, entry{key: sub.hash.Sum(nil), value: iter.Value()})
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return cmp.Compare(string(a.key), string(b.key))
+	})
+
+	e.writeTag(keyMap)
+	e.writeLen(len(entries))
+	for _, entry := range entries {
+		_, _ = e.hash.Write(entry.key)
+		e.encode(entry.value)
+	}
+}
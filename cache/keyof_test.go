@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+type candleKey struct {
+	UserID   int
+	Market   string
+	Interval time.Duration
+}
+
+type otherKey struct {
+	UserID   int
+	Market   string
+	Interval time.Duration
+}
+
+func TestKeyOfStable(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	pairs := [][2][]any{
+		{{42, "btc-usd", time.Minute}, {42, "btc-usd", time.Minute}},
+		{{int8(1)}, {int64(1)}},
+		{{uint16(1)}, {uint(1)}},
+		{{float32(0.5)}, {0.5}},
+		{{candleKey{1, "a", 2}}, {&candleKey{1, "a", 2}}},
+		{{map[string]int{"a": 1, "b": 2, "c": 3}}, {map[string]int{"c": 3, "b": 2, "a": 1}}},
+		{{at}, {at}},
+		{{nil}, {(*candleKey)(nil)}},
+	}
+
+	for _, pair := range pairs {
+		if a, b := KeyOf(pair[0]...), KeyOf(pair[1]...); a != b {
+			t.Errorf("KeyOf(%v) = %s, KeyOf(%v) = %s, want equal keys", pair[0], a, pair[1], b)
+		}
+	}
+}
+
+func TestKeyOfDistinct(t *testing.T) {
+	pairs := [][2][]any{
+		{{"a:b", "c"}, {"a", "b:c"}},
+		{{"ab"}, {"a", "b"}},
+		{{1}, {uint(1)}},
+		{{1}, {"1"}},
+		{{1.0}, {1}},
+		{{[]byte("a")}, {"a"}},
+		{{[]string{"a", "b"}}, {[]string{"ab"}}},
+		{{[]int{}}, {nil}},
+		{{}, {nil}},
+		{{true}, {false}},
+		{{candleKey{1, "a", 2}}, {otherKey{1, "a", 2}}},
+		{{candleKey{1, "a", 2}}, {candleKey{1, "a", 3}}},
+		{{map[string]int{"a": 1}}, {map[string]int{"a": 2}}},
+	}
+
+	for _, pair := range pairs {
+		if a, b := KeyOf(pair[0]...), KeyOf(pair[1]...); a == b {
+			t.Errorf("KeyOf(%v) = KeyOf(%v) = %s, want distinct keys", pair[0], pair[1], a)
+		}
+	}
+}
+
+func TestKeyOfUnexportedFields(t *testing.T) {
+	type key struct {
+		id   int
+		name string
+	}
+
+	if KeyOf(key{1, "a"}) == KeyOf(key{1, "b"}) {
+		t.Error("expected unexported fields to be encoded")
+	}
+}
+
+func TestKeyOfPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected KeyOf to panic on a function")
+		}
+	}()
+
+	KeyOf(func() {})
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"container/list"
+	"slices"
+)
+
+// lfuPolicy keeps a use count per key and a list of keys per count,
+// so both uses and evictions are O(1).
+type lfuPolicy[K comparable] struct {
+	capacity int
+	items    map[K]*lfuItem[K]
+	buckets  map[int]*list.List // use count to keys, front is the most recently used
+	minCount int
+}
+
+type lfuItem[K comparable] struct {
+	count int
+	elem  *list.Element
+}
+
+func newLFUPolicy[K comparable](capacity int) *lfuPolicy[K] {
+	return &lfuPolicy[K]{
+		capacity: capacity,
+		items:    make(map[K]*lfuItem[K]),
+		buckets:  make(map[int]*list.List),
+	}
+}
+
+func (p *lfuPolicy[K]) add(key K) (K, bool) {
+	var victim K
+	evict := false
+	if len(p.items) >= p.capacity {
+		bucket := p.buckets[p.minCount]
+		victim = bucket.Back().Value.(K)
+		p.remove(victim)
+		evict = true
+	}
+
+	p.items[key] = &lfuItem[K]{count: 1, elem: p.bucket(1).PushFront(key)}
+	p.minCount = 1
+
+	return victim, evict
+}
+
+func (p *lfuPolicy[K]) touch(key K) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+
+	p.unlink(item)
+	if p.minCount == item.count && p.buckets[item.count] == nil {
+		p.minCount++
+	}
+	item.count++
+	item.elem = p.bucket(item.count).PushFront(key)
+}
+
+func (p *lfuPolicy[K]) remove(key K) {
+	item, ok := p.items[key]
+	if !ok {
+		return
+	}
+
+	p.unlink(item)
+	delete(p.items, key)
+
+	if p.buckets[p.minCount] == nil {
+		p.minCount = 0
+		for count := range p.buckets {
+			if p.minCount == 0 || count < p.minCount {
+				p.minCount = count
+			}
+		}
+	}
+}
+
+func (p *lfuPolicy[K]) victim() (K, bool) {
+	var victim K
+	bucket := p.buckets[p.minCount]
+	if bucket == nil {
+		return victim, false
+	}
+
+	return bucket.Back().Value.(K), true
+}
+
+func (p *lfuPolicy[K]) keys() []K {
+	counts := make([]int, 0, len(p.buckets))
+	for count := range p.buckets {
+		counts = append(counts, count)
+	}
+	slices.Sort(counts)
+	slices.Reverse(counts)
+
+	keys := make([]K, 0, len(p.items))
+	for _, count := range counts {
+		keys = append(keys, listKeys[K](p.buckets[count])...)
+	}
+
+	return keys
+}
+
+func (p *lfuPolicy[K]) bucket(count int) *list.List {
+	bucket, ok := p.buckets[count]
+	if !ok {
+		bucket = list.New()
+		p.buckets[count] = bucket
+	}
+
+	return bucket
+}
+
+// unlink removes the item from its bucket, dropping the bucket once empty.
+func (p *lfuPolicy[K]) unlink(item *lfuItem[K]) {
+	bucket := p.buckets[item.count]
+	bucket.Remove(item.elem)
+	if bucket.Len() == 0 {
+		delete(p.buckets, item.count)
+	}
+}
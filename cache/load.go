@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Loader loads the value of a missing key.
+type Loader[V any] func(ctx context.Context) (V, error)
+
+// loadGroup deduplicates concurrent loads of the same key (singleflight).
+// The zero value is ready to use.
+type loadGroup[V any] struct {
+	mu    sync.Mutex
+	calls map[any]*loadCall[V]
+}
+
+type loadCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// getOrLoad returns the cached value of key, or loads and stores it with ttl.
+// Concurrent callers missing the same key share a single call to loader, which runs
+// with the context of the first caller; the others stop waiting when their own ctx is done.
+// peek looks the key up again once the load is claimed, without counting it in the stats.
+func getOrLoad[K comparable, V any](ctx context.Context, cache Cache[K, V], peek func(key K) (V, bool),
+	group *loadGroup[V], key K, loader Loader[V], ttl time.Duration,
+) (V, error) {
+	if value, ok := cache.Get(key); ok {
+		return value, nil
+	}
+
+	if reader, ok := cache.(staleReader[K, V]); ok {
+		if value, ok := reader.stale(key); ok {
+			refresh(ctx, cache, group, key, loader, ttl, reader.refreshOptions())
+
+			return value, nil
+		}
+	}
+
+	group.mu.Lock()
+	if group.calls == nil {
+		group.calls = make(map[any]*loadCall[V])
+	}
+	if call, ok := group.calls[key]; ok {
+		group.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.value, call.err
+		case <-ctx.Done():
+			var zeroV V
+
+			return zeroV, ctx.Err()
+		}
+	}
+	call := &loadCall[V]{done: make(chan struct{})}
+	group.calls[key] = call
+	group.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			call.err = fmt.Errorf("cache: loader panicked: %v", r)
+			group.finish(key, call)
+
+			panic(r)
+		}
+		group.finish(key, call)
+	}()
+
+	// The previous load may have stored the value between the miss and the lock.
+	if value, ok := peek(key); ok {
+		call.value = value
+
+		return value, nil
+	}
+
+	call.value, call.err = loader(ctx)
+	if call.err == nil {
+		cache.Add(key, call.value, ttl)
+	}
+
+	return call.value, call.err
+}
+
+// tryStart claims the load of key, unless another load of it is running.
+func (g *loadGroup[V]) tryStart(key any) (*loadCall[V], bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.calls[key]; ok {
+		return nil, false
+	}
+	if g.calls == nil {
+		g.calls = make(map[any]*loadCall[V])
+	}
+	call := &loadCall[V]{done: make(chan struct{})}
+	g.calls[key] = call
+
+	return call, true
+}
+
+func (g *loadGroup[V]) finish(key any, call *loadCall[V]) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	close(call.done)
+}
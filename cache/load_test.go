@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	caches := map[string]Cache[string, int]{
+		"basic":   NewBasic[string, int](t.Context()),
+		"bounded": NewBounded[string, int](t.Context(), 10),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			var loads atomic.Int32
+			loader := func(context.Context) (int, error) {
+				loads.Add(1)
+
+				return 42, nil
+			}
+
+			for range 2 {
+				val, err := cache.GetOrLoad(t.Context(), "answer", loader, time.Minute)
+				if err != nil || val != 42 {
+					t.Fatalf("GetOrLoad = %d, %v; want 42, nil", val, err)
+				}
+			}
+
+			if n := loads.Load(); n != 1 {
+				t.Errorf("loader called %d times, want 1", n)
+			}
+			if val, ok := cache.Get("answer"); !ok || val != 42 {
+				t.Errorf("Get = %d, %v; want 42, true", val, ok)
+			}
+		})
+	}
+}
+
+func TestGetOrLoadDeduplicates(t *testing.T) {
+	cache := NewBasic[string, int](t.Context())
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	loader := func(context.Context) (int, error) {
+		loads.Add(1)
+		<-release
+
+		return 7, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := cache.GetOrLoad(t.Context(), "key", loader, 0)
+			if err != nil {
+				t.Errorf("GetOrLoad error: %v", err)
+			}
+			results[i] = val
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+	for i, val := range results {
+		if val != 7 {
+			t.Errorf("result %d = %d, want 7", i, val)
+		}
+	}
+}
+
+func TestGetOrLoadError(t *testing.T) {
+	cache := NewBounded[string, int](t.Context(), 10)
+	loadErr := errors.New("load failed")
+
+	_, err := cache.GetOrLoad(t.Context(), "key", func(context.Context) (int, error) {
+		return 0, loadErr
+	}, 0)
+	if !errors.Is(err, loadErr) {
+		t.Fatalf("GetOrLoad error = %v, want %v", err, loadErr)
+	}
+	if cache.Exists("key") {
+		t.Error("failed load must not be stored")
+	}
+
+	val, err := cache.GetOrLoad(t.Context(), "key", func(context.Context) (int, error) {
+		return 1, nil
+	}, 0)
+	if err != nil || val != 1 {
+		t.Errorf("GetOrLoad after failure = %d, %v; want 1, nil", val, err)
+	}
+}
+
+func TestGetOrLoadWaiterContext(t *testing.T) {
+	cache := NewBasic[string, int](t.Context())
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_, _ = cache.GetOrLoad(t.Context(), "key", func(context.Context) (int, error) {
+			close(started)
+			<-release
+
+			return 1, nil
+		}, 0)
+	}()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err := cache.GetOrLoad(ctx, "key", func(context.Context) (int, error) {
+		t.Error("second loader must not run")
+
+		return 2, nil
+	}, 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetOrLoad error = %v, want context.Canceled", err)
+	}
+}
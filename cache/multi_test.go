@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestMulti(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	caches := map[string]Cache[string, int]{
+		"basic":     NewBasic[string, int](t.Context()),
+		"bounded":   NewBounded[string, int](t.Context(), 10),
+		"redis":     NewRedis[string, int](client, JSONCodec[string, int]{}),
+		"namespace": Namespace(NewBasic[string, int](t.Context()), "tenant:"),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			items := map[string]int{"a": 1, "b": 2, "c": 3}
+			if !cache.AddMulti(items, time.Minute) {
+				t.Fatal("AddMulti failed")
+			}
+
+			got := cache.GetMulti([]string{"a", "b", "c", "missing"})
+			if !maps.Equal(got, items) {
+				t.Errorf("GetMulti = %v, want %v", got, items)
+			}
+
+			if !cache.DeleteMulti([]string{"a", "b", "missing"}) {
+				t.Error("DeleteMulti failed")
+			}
+
+			got = cache.GetMulti([]string{"a", "b", "c"})
+			if want := map[string]int{"c": 3}; !maps.Equal(got, want) {
+				t.Errorf("GetMulti after delete = %v, want %v", got, want)
+			}
+
+			if stats := cache.Stats(); stats.Hits != 4 || stats.Misses != 3 {
+				t.Errorf("Stats() = %+v, want 4 hits and 3 misses", stats)
+			}
+
+			if got := cache.GetMulti(nil); len(got) != 0 {
+				t.Errorf("GetMulti(nil) = %v", got)
+			}
+		})
+	}
+}
+
+func TestMultiEviction(t *testing.T) {
+	cache := NewBounded[int, int](t.Context(), 2)
+
+	cache.AddMulti(map[int]int{1: 1, 2: 2, 3: 3}, 0)
+
+	if n := len(cache.GetMulti([]int{1, 2, 3})); n != 2 {
+		t.Errorf("expected the bound to apply to AddMulti, got %d entries", n)
+	}
+}
@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+)
+
+// namespaced is a view of a cache restricted to the keys starting with a prefix.
+type namespaced[V any] struct {
+	cache  Cache[string, V]
+	prefix string
+}
+
+// Namespace returns a view of cache whose keys are stored with prefix prepended,
+// so several tenants can share one cache without key collisions. Keys only lists
+// the keys of the namespace, without the prefix. Stats are those of the whole cache.
+//
+// End the prefix with a separator, such as "tenant:42:", so that one namespace
+// is not a prefix of another. Namespaces can be nested.
+func Namespace[V any](cache Cache[string, V], prefix string) Cache[string, V] {
+	return &namespaced[V]{cache: cache, prefix: prefix}
+}
+
+// InvalidateNamespace deletes every key of cache starting with prefix,
+// wiping the data of the namespace created with the same prefix.
+// It returns the number of deleted keys.
+func InvalidateNamespace[V any](cache Cache[string, V], prefix string) int {
+	deleted := 0
+	for _, key := range cache.Keys() {
+		if strings.HasPrefix(key, prefix) && cache.Delete(key) {
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+// Add stores the item, with its tags prefixed like its key.
+func (n *namespaced[V]) Add(key string, value V, expiration time.Duration, opts ...AddOption) bool {
+	tags := addTags(opts)
+	if len(tags) == 0 {
+		return n.cache.Add(n.prefix+key, value, expiration)
+	}
+
+	prefixed := make([]string, len(tags))
+	for i, tag := range tags {
+		prefixed[i] = n.prefix + tag
+	}
+
+	return n.cache.Add(n.prefix+key, value, expiration, WithTags(prefixed...))
+}
+
+func (n *namespaced[V]) Get(key string) (V, bool) {
+	return n.cache.Get(n.prefix + key)
+}
+
+func (n *namespaced[V]) Update(key string, newValue V, expiration time.Duration) bool {
+	return n.cache.Update(n.prefix+key, newValue, expiration)
+}
+
+func (n *namespaced[V]) Exists(key string) bool {
+	return n.cache.Exists(n.prefix + key)
+}
+
+// Keys returns the keys of the namespace, without the prefix.
+func (n *namespaced[V]) Keys() []string {
+	keys := make([]string, 0)
+	for _, key := range n.cache.Keys() {
+		if trimmed, ok := strings.CutPrefix(key, n.prefix); ok {
+			keys = append(keys, trimmed)
+		}
+	}
+
+	return keys
+}
+
+// Range calls fn for each item of the namespace, without the prefix, until fn returns false.
+func (n *namespaced[V]) Range(fn func(key string, value V) bool) {
+	n.cache.Range(func(key string, value V) bool {
+		if trimmed, ok := strings.CutPrefix(key, n.prefix); ok {
+			return fn(trimmed, value)
+		}
+
+		return true
+	})
+}
+
+// Len returns the number of items of the namespace.
+func (n *namespaced[V]) Len() int {
+	count := 0
+	n.Range(func(string, V) bool {
+		count++
+
+		return true
+	})
+
+	return count
+}
+
+// Clear deletes the items of the namespace, leaving the rest of the cache alone.
+func (n *namespaced[V]) Clear() {
+	InvalidateNamespace(n.cache, n.prefix)
+}
+
+// InvalidateTag invalidates the items tagged with tag in the namespace.
+func (n *namespaced[V]) InvalidateTag(tag string) {
+	n.cache.InvalidateTag(n.prefix + tag)
+}
+
+func (n *namespaced[V]) Delete(key string) bool {
+	return n.cache.Delete(n.prefix + key)
+}
+
+func (n *namespaced[V]) Upsert(key string, fn func(old V, exists bool) V, ttl time.Duration) V {
+	return n.cache.Upsert(n.prefix+key, fn, ttl)
+}
+
+func (n *namespaced[V]) modify(key string, fn func(old V, exists bool) (V, bool), ttl time.Duration) (V, bool) {
+	return modify(n.cache, n.prefix+key, fn, ttl)
+}
+
+func (n *namespaced[V]) GetMulti(keys []string) map[string]V {
+	items := make(map[string]V, len(keys))
+	for key, value := range n.cache.GetMulti(n.prefixed(keys)) {
+		items[strings.TrimPrefix(key, n.prefix)] = value
+	}
+
+	return items
+}
+
+func (n *namespaced[V]) AddMulti(items map[string]V, expiration time.Duration) bool {
+	prefixed := make(map[string]V, len(items))
+	for key, value := range items {
+		prefixed[n.prefix+key] = value
+	}
+
+	return n.cache.AddMulti(prefixed, expiration)
+}
+
+func (n *namespaced[V]) DeleteMulti(keys []string) bool {
+	return n.cache.DeleteMulti(n.prefixed(keys))
+}
+
+func (n *namespaced[V]) prefixed(keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = n.prefix + key
+	}
+
+	return prefixed
+}
+
+func (n *namespaced[V]) GetWithExpiry(key string) (V, time.Time, bool) {
+	return n.cache.GetWithExpiry(n.prefix + key)
+}
+
+func (n *namespaced[V]) SetNX(key string, value V, expiration time.Duration) bool {
+	return n.cache.SetNX(n.prefix+key, value, expiration)
+}
+
+func (n *namespaced[V]) GetOrLoad(ctx context.Context, key string, loader Loader[V], ttl time.Duration) (V, error) {
+	return n.cache.GetOrLoad(ctx, n.prefix+key, loader, ttl)
+}
+
+func (n *namespaced[V]) Stats() Stats {
+	return n.cache.Stats()
+}
+
+// CleanupNow removes the expired items of the whole cache.
+func (n *namespaced[V]) CleanupNow() {
+	n.cache.CleanupNow()
+}
+
+func (n *namespaced[V]) now() time.Time {
+	return nowOf(n.cache)
+}
+
+// SaveSnapshot writes the items of the namespace, without the prefix, to w.
+// It returns ErrSnapshotUnsupported if the underlying cache is not one of this package.
+func (n *namespaced[V]) SaveSnapshot(w io.Writer) error {
+	return saveSnapshot[string, V](w, n)
+}
+
+// LoadSnapshot adds the unexpired items of a snapshot to the namespace.
+// It returns ErrSnapshotUnsupported if the underlying cache is not one of this package.
+func (n *namespaced[V]) LoadSnapshot(r io.Reader) error {
+	source, ok := n.cache.(snapshotter[string, V])
+	if !ok {
+		return ErrSnapshotUnsupported
+	}
+
+	return loadSnapshot[string, V](r, n, source.snapshotCodec())
+}
+
+func (n *namespaced[V]) snapshotEntries() ([]snapshotEntry[string, V], error) {
+	source, ok := n.cache.(snapshotter[string, V])
+	if !ok {
+		return nil, ErrSnapshotUnsupported
+	}
+
+	all, err := source.snapshotEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]snapshotEntry[string, V], 0, len(all))
+	for _, entry := range all {
+		if trimmed, ok := strings.CutPrefix(entry.key, n.prefix); ok {
+			entry.key = trimmed
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+func (n *namespaced[V]) snapshotCodec() Codec[string, V] {
+	if source, ok := n.cache.(snapshotter[string, V]); ok {
+		return source.snapshotCodec()
+	}
+
+	return JSONCodec[string, V]{}
+}
@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNamespace(t *testing.T) {
+	cache := NewBasic[string, int](t.Context())
+	tenantA := Namespace(cache, "tenant:a:")
+	tenantB := Namespace(cache, "tenant:b:")
+
+	tenantA.Add("users", 1, 0)
+	tenantA.Add("orders", 2, 0)
+	tenantB.Add("users", 3, 0)
+	cache.Add("global", 4, 0)
+
+	if val, ok := tenantA.Get("users"); !ok || val != 1 {
+		t.Errorf("tenantA.Get(users) = %d, %v; want 1, true", val, ok)
+	}
+	if val, ok := tenantB.Get("users"); !ok || val != 3 {
+		t.Errorf("tenantB.Get(users) = %d, %v; want 3, true", val, ok)
+	}
+	if !cache.Exists("tenant:a:users") {
+		t.Error("expected the prefixed key in the underlying cache")
+	}
+
+	keys := tenantA.Keys()
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"orders", "users"}) {
+		t.Errorf("tenantA.Keys() = %v", keys)
+	}
+
+	if n := InvalidateNamespace(cache, "tenant:a:"); n != 2 {
+		t.Errorf("InvalidateNamespace deleted %d keys, want 2", n)
+	}
+	if len(tenantA.Keys()) != 0 {
+		t.Errorf("expected tenant a to be empty, got %v", tenantA.Keys())
+	}
+	if !tenantB.Exists("users") || !cache.Exists("global") {
+		t.Error("expected other namespaces to be kept")
+	}
+}
+
+func TestNestedNamespace(t *testing.T) {
+	cache := NewBounded[string, int](t.Context(), 10)
+	users := Namespace(Namespace(cache, "tenant:a:"), "users:")
+
+	users.Add("42", 1, 0)
+
+	if !cache.Exists("tenant:a:users:42") {
+		t.Errorf("expected nested prefixes, got keys %v", cache.Keys())
+	}
+	if n := InvalidateNamespace(cache, "tenant:a:"); n != 1 || users.Exists("42") {
+		t.Error("expected the nested namespace to be invalidated with its parent")
+	}
+}
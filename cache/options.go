@@ -1,15 +1,39 @@
 package cache
 
-import "time"
+import (
+	"time"
+
+	"github.com/ezex-io/gopkg/retry"
+)
 
 var defaultConfig = options{
 	cleanUpInterval: 10 * time.Second,
+	localTTL:        time.Minute,
+	clock:           systemClock{},
 }
 
 type options struct {
 	cleanUpInterval time.Duration
+	policy          Policy
+	slidingTTL      time.Duration
+	statsHook       func(event StatsEvent)
+	keyPrefix       string
+	onError         func(err error)
+	onEvict         any
+	snapshotCodec   any
+	maxBytes        int
+	sizer           any
+	staleGrace      time.Duration
+	refreshRetry    []retry.Options
+	localTTL        time.Duration
+	invalidator     any
+	expirations     any
+	clock           Clock
 }
 
+// WithCleanUpInterval sets how often expired entries are removed in the background.
+// Zero or less disables the background clean-up, leaving it to CleanupNow and to
+// the removal of expired entries on access.
 func WithCleanUpInterval(interval time.Duration) Option {
 	return func(cfg *options) {
 		cfg.cleanUpInterval = interval
@@ -17,3 +41,12 @@ func WithCleanUpInterval(interval time.Duration) Option {
 }
 
 type Option func(*options)
+
+// WithSlidingTTL makes every successful Get extend the expiry of the entry to d from now,
+// so entries stay alive while they are in use and expire once idle for d.
+// Entries added without an expiration never expire and are not affected.
+func WithSlidingTTL(d time.Duration) Option {
+	return func(cfg *options) {
+		cfg.slidingTTL = d
+	}
+}
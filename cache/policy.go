@@ -0,0 +1,120 @@
+package cache
+
+import "container/list"
+
+// Policy selects which entry a bounded cache evicts when it is full.
+type Policy int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU Policy = iota
+
+	// LFU evicts the least frequently used entry; ties go to the least recently used.
+	LFU
+
+	// ARC (Adaptive Replacement Cache) balances recency and frequency, adapting to the
+	// workload by remembering recently evicted keys.
+	ARC
+)
+
+// WithPolicy sets the eviction policy of a bounded cache. Defaults to LRU.
+func WithPolicy(policy Policy) Option {
+	return func(cfg *options) {
+		cfg.policy = policy
+	}
+}
+
+// evictionPolicy tracks the keys of a bounded cache and chooses which one to evict.
+// The cache serializes the calls, so implementations don't need to be thread-safe.
+type evictionPolicy[K comparable] interface {
+	// add records a key new to the cache, and returns the key to evict
+	// if the cache now holds more keys than its capacity.
+	add(key K) (victim K, evict bool)
+
+	// touch records a use of a cached key.
+	touch(key K)
+
+	// remove forgets a key deleted from the cache.
+	remove(key K)
+
+	// keys returns the cached keys, from the most to the least likely to be kept.
+	keys() []K
+
+	// victim returns the cached key to evict next, if any.
+	victim() (K, bool)
+}
+
+func newEvictionPolicy[K comparable](policy Policy, capacity int) evictionPolicy[K] {
+	switch policy {
+	case LFU:
+		return newLFUPolicy[K](capacity)
+	case ARC:
+		return newARCPolicy[K](capacity)
+	default:
+		return newLRUPolicy[K](capacity)
+	}
+}
+
+type lruPolicy[K comparable] struct {
+	capacity int
+	order    *list.List // front is the most recently used
+	items    map[K]*list.Element
+}
+
+func newLRUPolicy[K comparable](capacity int) *lruPolicy[K] {
+	return &lruPolicy[K]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+func (p *lruPolicy[K]) add(key K) (K, bool) {
+	p.items[key] = p.order.PushFront(key)
+
+	var victim K
+	if p.order.Len() <= p.capacity {
+		return victim, false
+	}
+
+	victim = p.order.Remove(p.order.Back()).(K)
+	delete(p.items, victim)
+
+	return victim, true
+}
+
+func (p *lruPolicy[K]) touch(key K) {
+	if elem, ok := p.items[key]; ok {
+		p.order.MoveToFront(elem)
+	}
+}
+
+func (p *lruPolicy[K]) remove(key K) {
+	if elem, ok := p.items[key]; ok {
+		p.order.Remove(elem)
+		delete(p.items, key)
+	}
+}
+
+func (p *lruPolicy[K]) victim() (K, bool) {
+	var victim K
+	if p.order.Len() == 0 {
+		return victim, false
+	}
+
+	return p.order.Back().Value.(K), true
+}
+
+func (p *lruPolicy[K]) keys() []K {
+	return listKeys[K](p.order)
+}
+
+// listKeys returns the keys stored in l, from front to back.
+func listKeys[K any](l *list.List) []K {
+	keys := make([]K, 0, l.Len())
+	for elem := l.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Value.(K))
+	}
+
+	return keys
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestLFUEviction(t *testing.T) {
+	cache := NewBounded[string, int](t.Context(), 2, WithPolicy(LFU))
+
+	cache.Add("hot", 1, 0)
+	cache.Add("cold", 2, 0)
+	cache.Get("hot")
+	cache.Get("hot")
+	cache.Get("cold")
+
+	// "cold" is used less than "hot", even though it was used more recently.
+	cache.Add("new", 3, 0)
+
+	if cache.Exists("cold") {
+		t.Error("expected cold to be evicted")
+	}
+	if keys := cache.Keys(); !slices.Equal(keys, []string{"hot", "new"}) {
+		t.Errorf("unexpected keys order: %v", keys)
+	}
+
+	// Ties are broken by recency: "new" was used once, like "newer" will be.
+	cache.Add("newer", 4, 0)
+	if cache.Exists("new") || !cache.Exists("newer") || !cache.Exists("hot") {
+		t.Errorf("unexpected keys after tie: %v", cache.Keys())
+	}
+}
+
+func TestARCScanResistance(t *testing.T) {
+	cache := NewBounded[int, int](t.Context(), 4, WithPolicy(ARC))
+
+	// Keys 1 and 2 are used repeatedly and move to the frequency list.
+	for range 3 {
+		for key := 1; key <= 2; key++ {
+			if !cache.Exists(key) {
+				cache.Add(key, key, 0)
+			}
+			cache.Get(key)
+		}
+	}
+
+	// A one-off scan of many keys only churns the recency list.
+	for key := 100; key < 120; key++ {
+		cache.Add(key, key, 0)
+	}
+
+	if !cache.Exists(1) || !cache.Exists(2) {
+		t.Errorf("expected frequently used keys to survive a scan, got %v", cache.Keys())
+	}
+	if len(cache.Keys()) != 4 {
+		t.Errorf("expected 4 keys, got %v", cache.Keys())
+	}
+}
+
+func TestARCGhostHit(t *testing.T) {
+	policy := newARCPolicy[int](2)
+
+	policy.add(1)
+	policy.add(2)
+	policy.touch(1)
+
+	// 1 moved to the frequency list, so 2 is evicted into the recency ghost list.
+	victim, evict := policy.add(3)
+	if !evict || victim != 2 {
+		t.Fatalf("expected 2 to be evicted, got %v, %v", victim, evict)
+	}
+
+	// Adding 2 again is a ghost hit: the recency target grows, 2 goes to
+	// the frequency list and 1 is evicted to make room.
+	victim, evict = policy.add(2)
+	if !evict || victim != 1 {
+		t.Fatalf("expected 1 to be evicted, got %v, %v", victim, evict)
+	}
+	if policy.target != 1 {
+		t.Errorf("expected target 1, got %d", policy.target)
+	}
+	if keys := policy.keys(); !slices.Equal(keys, []int{2, 3}) {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+}
+
+func TestPoliciesStayBounded(t *testing.T) {
+	//nolint:gosec // deterministic sequence of operations
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, policy := range []Policy{LRU, LFU, ARC} {
+		cache := NewBounded[int, int](t.Context(), 8, WithPolicy(policy))
+		bounded := cache.(*BoundedCache[int, int])
+
+		for range 5000 {
+			key := rnd.Intn(32)
+			switch rnd.Intn(4) {
+			case 0:
+				cache.Delete(key)
+			case 1:
+				cache.Get(key)
+			default:
+				cache.Add(key, key, 0)
+			}
+
+			keys := cache.Keys()
+			if len(keys) > 8 || len(keys) != len(bounded.entries) {
+				t.Fatalf("policy %d: %d keys for %d entries", policy, len(keys), len(bounded.entries))
+			}
+			for _, k := range keys {
+				if _, ok := bounded.entries[k]; !ok {
+					t.Fatalf("policy %d: key %d is not cached", policy, k)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"maps"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRangeLenClear(t *testing.T) {
+	server, client := newTestRedis(t)
+
+	caches := map[string]Cache[string, int]{
+		"basic":     NewBasic[string, int](t.Context()),
+		"bounded":   NewBounded[string, int](t.Context(), 300),
+		"sharded":   NewSharded[string, int](t.Context(), 4),
+		"redis":     NewRedis[string, int](client, JSONCodec[string, int]{}, WithKeyPrefix("range:")),
+		"namespace": Namespace(NewBasic[string, int](t.Context()), "tenant:"),
+	}
+	server.Set("other", "kept") // Outside of the redis cache prefix
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			want := make(map[string]int)
+			for i := range 250 {
+				key := strconv.Itoa(i)
+				want[key] = i
+				cache.Add(key, i, time.Minute)
+			}
+
+			got := make(map[string]int)
+			cache.Range(func(key string, value int) bool {
+				got[key] = value
+
+				return true
+			})
+			if !maps.Equal(got, want) {
+				t.Errorf("Range visited %d items, want %d", len(got), len(want))
+			}
+
+			visited := 0
+			cache.Range(func(string, int) bool {
+				visited++
+
+				return visited < 3
+			})
+			if visited != 3 {
+				t.Errorf("expected Range to stop when fn returns false, visited %d", visited)
+			}
+
+			if n := cache.Len(); n != 250 {
+				t.Errorf("Len() = %d, want 250", n)
+			}
+
+			cache.Clear()
+			if n := cache.Len(); n != 0 {
+				t.Errorf("Len() after Clear = %d, want 0", n)
+			}
+			if cache.Exists("1") {
+				t.Error("expected Clear to delete every item")
+			}
+		})
+	}
+
+	if !server.Exists("other") {
+		t.Error("expected Clear to keep the keys outside of the prefix")
+	}
+}
+
+func TestRangeSkipsExpired(t *testing.T) {
+	for name, cache := range map[string]Cache[int, int]{
+		"basic":   NewBasic[int, int](t.Context()),
+		"bounded": NewBounded[int, int](t.Context(), 10),
+	} {
+		cache.Add(1, 1, time.Millisecond)
+		cache.Add(2, 2, 0)
+		time.Sleep(5 * time.Millisecond)
+
+		cache.Range(func(key, _ int) bool {
+			if key == 1 {
+				t.Errorf("%s: expected Range to skip expired items", name)
+			}
+
+			return true
+		})
+		if n := cache.Len(); n != 1 {
+			t.Errorf("%s: Len() = %d, want 1", name, n)
+		}
+	}
+}
+
+func TestRangeCanUseCache(t *testing.T) {
+	cache := NewBounded[int, int](t.Context(), 10)
+	cache.Add(1, 1, 0)
+
+	cache.Range(func(key, value int) bool {
+		cache.Add(key+1, value+1, 0) // Must not deadlock
+
+		return true
+	})
+
+	if !cache.Exists(2) {
+		t.Error("expected the item added during Range")
+	}
+}
+
+func TestClearEvictCallback(t *testing.T) {
+	var reasons []Reason
+	cache := NewBounded[int, int](t.Context(), 10, WithOnEvict(func(_, _ int, reason Reason) {
+		reasons = append(reasons, reason)
+	}))
+	cache.Add(1, 1, 0)
+	cache.Add(2, 2, 0)
+
+	cache.Clear()
+
+	if len(reasons) != 2 || reasons[0] != ReasonDeleted || reasons[1] != ReasonDeleted {
+		t.Errorf("expected a deleted callback per item, got %v", reasons)
+	}
+	if len(cache.Keys()) != 0 {
+		t.Error("expected the policy to forget the cleared keys")
+	}
+}
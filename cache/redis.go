@@ -0,0 +1,744 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Codec serializes the keys and values of a Redis cache.
+type Codec[K, V any] interface {
+	EncodeKey(key K) (string, error)
+	DecodeKey(key string) (K, error)
+	EncodeValue(value V) ([]byte, error)
+	DecodeValue(data []byte) (V, error)
+}
+
+// JSONCodec encodes values as JSON. String keys are used as they are,
+// other keys are encoded as JSON.
+type JSONCodec[K, V any] struct{}
+
+func (JSONCodec[K, V]) EncodeKey(key K) (string, error) {
+	if s, ok := any(key).(string); ok {
+		return s, nil
+	}
+
+	data, err := json.Marshal(key)
+
+	return string(data), err
+}
+
+func (JSONCodec[K, V]) DecodeKey(key string) (K, error) {
+	var decoded K
+	if ptr, ok := any(&decoded).(*string); ok {
+		*ptr = key
+
+		return decoded, nil
+	}
+
+	err := json.Unmarshal([]byte(key), &decoded)
+
+	return decoded, err
+}
+
+func (JSONCodec[K, V]) EncodeValue(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[K, V]) DecodeValue(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+
+	return value, err
+}
+
+// WithKeyPrefix prefixes every Redis key with prefix, so several caches can share
+// a Redis database. Keys only lists the keys with the prefix. Used by NewRedis only.
+func WithKeyPrefix(prefix string) Option {
+	return func(cfg *options) {
+		cfg.keyPrefix = prefix
+	}
+}
+
+// WithErrorHandler sets the function told about Redis and codec errors, which the
+// Cache methods can't return. By default they are logged.
+// Used by NewRedis and NewRedisInvalidator only.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(cfg *options) {
+		cfg.onError = handler
+	}
+}
+
+// RedisCache implements Cache on top of Redis, so the cache is shared by every
+// process using the same Redis. Expiry and eviction are left to Redis, so Stats
+// only counts hits and misses.
+type RedisCache[K comparable, V any] struct {
+	client     redis.UniversalClient
+	codec      Codec[K, V]
+	prefix     string
+	slidingTTL time.Duration
+	onError    func(err error)
+	upserts    keyLocks
+	loads      loadGroup[V]
+	stats      stats
+}
+
+// NewRedis creates a cache storing its entries in Redis, serialized with codec.
+// WithKeyPrefix, WithSlidingTTL, WithStatsHook and WithErrorHandler apply;
+// the clean-up interval and the eviction policy don't.
+func NewRedis[K comparable, V any](client redis.UniversalClient, codec Codec[K, V], opts ...Option) Cache[K, V] {
+	cfg := defaultConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cache := &RedisCache[K, V]{
+		client:     client,
+		codec:      codec,
+		prefix:     cfg.keyPrefix,
+		slidingTTL: cfg.slidingTTL,
+		onError:    cfg.onError,
+	}
+	if cache.onError == nil {
+		cache.onError = func(err error) {
+			log.Printf("redis cache error: %v", err)
+		}
+	}
+	cache.stats.hook = cfg.statsHook
+
+	return cache
+}
+
+// Add stores the item.
+//
+//   - expiration: 0 for disable expire cache
+//   - opts: WithTags to tag the item. Each tag is a Redis set of the keys carrying it,
+//     so a key stays tagged until the tag is invalidated, even if it is added again
+//     without the tag. The set expires with the last of its keys, or never once
+//     a key doesn't expire or the cache has a sliding TTL.
+func (c *RedisCache[K, V]) Add(key K, value V, expiration time.Duration, opts ...AddOption) bool {
+	redisKey, data, ok := c.encode(key, value)
+	if !ok {
+		return false
+	}
+
+	ctx := context.Background()
+	tags := addTags(opts)
+	if len(tags) == 0 {
+		return c.check(c.client.Set(ctx, redisKey, data, expiration).Err())
+	}
+
+	// A sliding TTL extends the keys past the expiration of their tag sets.
+	tagExpiration := expiration
+	if c.slidingTTL > 0 {
+		tagExpiration = 0
+	}
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redisKey, data, expiration)
+		for _, tag := range tags {
+			tagKeyScript.Eval(ctx, pipe, []string{c.tagKey(tag)}, redisKey, tagExpiration.Milliseconds())
+		}
+
+		return nil
+	})
+
+	return c.check(err)
+}
+
+// tagKeyScript adds a key to a tag set, keeping the set at least as long as the key
+// expiring in ARGV[2] milliseconds, never if 0: a new set takes the expiry of the key,
+// and an expiring set the later of both.
+var tagKeyScript = redis.NewScript(`
+local ttl = redis.call('PTTL', KEYS[1])
+redis.call('SADD', KEYS[1], ARGV[1])
+local expiration = tonumber(ARGV[2])
+if expiration == 0 then
+	redis.call('PERSIST', KEYS[1])
+elseif ttl == -2 or (ttl >= 0 and ttl < expiration) then
+	redis.call('PEXPIRE', KEYS[1], expiration)
+end
+return 1
+`)
+
+// tagKeyPrefix starts the keys of the tag sets, which scan skips.
+const tagKeyPrefix = "{tag}:"
+
+func (c *RedisCache[K, V]) tagKey(tag string) string {
+	return tagKeyPrefix + c.prefix + tag
+}
+
+// invalidateTagScript deletes the keys of a tag set, and the set.
+var invalidateTagScript = redis.NewScript(`
+local keys = redis.call('SMEMBERS', KEYS[1])
+for i = 1, #keys, 1000 do
+	redis.call('DEL', unpack(keys, i, math.min(i + 999, #keys)))
+end
+redis.call('DEL', KEYS[1])
+return #keys
+`)
+
+// InvalidateTag deletes every item tagged with tag by WithTags, atomically,
+// with a Lua script. The script deletes keys it is not given as arguments,
+// so it is not supported by Redis Cluster.
+func (c *RedisCache[K, V]) InvalidateTag(tag string) {
+	err := invalidateTagScript.Run(context.Background(), c.client, []string{c.tagKey(tag)}).Err()
+	c.check(err)
+}
+
+// Get returns the item, extending its expiry when the cache has a sliding TTL.
+func (c *RedisCache[K, V]) Get(key K) (V, bool) {
+	value, ok := c.get(key, c.slidingTTL, nil)
+	if ok {
+		c.stats.record(Hit)
+	} else {
+		c.stats.record(Miss)
+	}
+
+	return value, ok
+}
+
+// GetWithExpiry returns the item like Get, with its expiry, the zero time if it never expires.
+// The expiry is computed from the remaining time to live told by Redis.
+func (c *RedisCache[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	var expiry time.Time
+	value, ok := c.get(key, c.slidingTTL, &expiry)
+	if ok {
+		c.stats.record(Hit)
+	} else {
+		c.stats.record(Miss)
+	}
+
+	return value, expiry, ok
+}
+
+// SetNX adds the item only if the key is missing, and reports whether it did,
+// so the cache can hold locks shared by every process using the same Redis.
+//
+//   - expiration: 0 for disable expire cache
+func (c *RedisCache[K, V]) SetNX(key K, value V, expiration time.Duration) bool {
+	redisKey, data, ok := c.encode(key, value)
+	if !ok {
+		return false
+	}
+
+	set, err := c.client.SetNX(context.Background(), redisKey, data, expiration).Result()
+
+	return c.check(err) && set
+}
+
+// Update updates the value of an existing entry.
+// A zero expiration keeps the current expiry.
+func (c *RedisCache[K, V]) Update(key K, newValue V, expiration time.Duration) bool {
+	redisKey, data, ok := c.encode(key, newValue)
+	if !ok {
+		return false
+	}
+
+	args := redis.SetArgs{Mode: "XX", TTL: expiration, KeepTTL: expiration == 0}
+	err := c.client.SetArgs(context.Background(), redisKey, data, args).Err()
+	if errors.Is(err, redis.Nil) {
+		return false
+	}
+
+	return c.check(err)
+}
+
+// maxUpsertAttempts bounds the retries of an Upsert transaction.
+const maxUpsertAttempts = 10
+
+// Upsert atomically replaces the item with fn(old, exists) and returns the new value.
+// It uses an optimistic WATCH transaction, run again when another client changes the
+// key meanwhile, so fn may run more than once; upserts of this process run one at a time.
+// ttl sets the expiry; zero keeps the expiry of an existing item.
+func (c *RedisCache[K, V]) Upsert(key K, fn func(old V, exists bool) V, ttl time.Duration) V {
+	value, _ := c.modify(key, alwaysWrite(fn), ttl)
+
+	return value
+}
+
+func (c *RedisCache[K, V]) modify(key K, fn func(old V, exists bool) (V, bool), ttl time.Duration) (V, bool) {
+	var result V
+	var written bool
+
+	redisKey, ok := c.key(key)
+	if !ok {
+		var zeroV V
+		value, _ := fn(zeroV, false)
+
+		return value, false
+	}
+
+	unlock := lockKey(&c.upserts, key)
+	defer unlock()
+
+	ctx := context.Background()
+	upsert := func(tx *redis.Tx) error {
+		var old V
+		data, err := tx.Get(ctx, redisKey).Bytes()
+		exists := err == nil
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if exists {
+			if old, err = c.codec.DecodeValue(data); err != nil {
+				return err
+			}
+		}
+
+		result, written = fn(old, exists)
+		if !written {
+			result = old
+
+			return nil
+		}
+
+		encoded, err := c.codec.EncodeValue(result)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetArgs(ctx, redisKey, encoded, redis.SetArgs{TTL: ttl, KeepTTL: ttl == 0})
+
+			return nil
+		})
+
+		return err
+	}
+
+	for range maxUpsertAttempts {
+		err := c.client.Watch(ctx, upsert, redisKey)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return result, c.check(err) && written
+		}
+	}
+	c.check(fmt.Errorf("upsert %q: %w", redisKey, redis.TxFailedErr))
+
+	return result, false
+}
+
+// Exists reports whether the key is cached.
+func (c *RedisCache[K, V]) Exists(key K) bool {
+	redisKey, ok := c.key(key)
+	if !ok {
+		return false
+	}
+
+	n, err := c.client.Exists(context.Background(), redisKey).Result()
+
+	return c.check(err) && n > 0
+}
+
+// Keys returns the keys with the cache prefix. Keys the codec can't decode are skipped.
+func (c *RedisCache[K, V]) Keys() []K {
+	ctx := context.Background()
+	keys := make([]K, 0)
+
+	iter := c.client.Scan(ctx, 0, escapePattern(c.prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		if strings.HasPrefix(iter.Val(), tagKeyPrefix) {
+			continue
+		}
+		key, err := c.codec.DecodeKey(strings.TrimPrefix(iter.Val(), c.prefix))
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	c.check(iter.Err())
+
+	return keys
+}
+
+// scanBatch is the number of keys asked for per SCAN call by Range, Len and Clear.
+const scanBatch = 100
+
+// scan calls fn with each batch of Redis keys having the cache prefix until fn returns false.
+func (c *RedisCache[K, V]) scan(ctx context.Context, fn func(redisKeys []string) bool) {
+	var cursor uint64
+	for {
+		redisKeys, next, err := c.client.Scan(ctx, cursor, escapePattern(c.prefix)+"*", scanBatch).Result()
+		if !c.check(err) {
+			return
+		}
+		redisKeys = slices.DeleteFunc(redisKeys, func(redisKey string) bool {
+			return strings.HasPrefix(redisKey, tagKeyPrefix)
+		})
+		if len(redisKeys) > 0 && !fn(redisKeys) {
+			return
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return
+		}
+	}
+}
+
+// Range calls fn for each item with the cache prefix until fn returns false,
+// reading the values in batches. Items the codec can't decode are skipped.
+func (c *RedisCache[K, V]) Range(fn func(key K, value V) bool) {
+	ctx := context.Background()
+	c.scan(ctx, func(redisKeys []string) bool {
+		values, err := c.client.MGet(ctx, redisKeys...).Result()
+		if !c.check(err) {
+			return false
+		}
+
+		for i, raw := range values {
+			data, ok := raw.(string)
+			if !ok {
+				continue // Deleted since the scan
+			}
+
+			key, err := c.codec.DecodeKey(strings.TrimPrefix(redisKeys[i], c.prefix))
+			if err != nil {
+				continue
+			}
+			value, err := c.codec.DecodeValue([]byte(data))
+			if err != nil {
+				continue
+			}
+
+			if !fn(key, value) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Len returns the number of keys with the cache prefix. It scans the keys.
+func (c *RedisCache[K, V]) Len() int {
+	n := 0
+	c.scan(context.Background(), func(redisKeys []string) bool {
+		n += len(redisKeys)
+
+		return true
+	})
+
+	return n
+}
+
+// Clear deletes the keys with the cache prefix, leaving the rest of the database alone.
+func (c *RedisCache[K, V]) Clear() {
+	ctx := context.Background()
+	c.scan(ctx, func(redisKeys []string) bool {
+		return c.check(c.client.Del(ctx, redisKeys...).Err())
+	})
+}
+
+// Delete removes the item.
+func (c *RedisCache[K, V]) Delete(key K) bool {
+	redisKey, ok := c.key(key)
+	if !ok {
+		return false
+	}
+
+	return c.check(c.client.Del(context.Background(), redisKey).Err())
+}
+
+// GetMulti returns the items found among keys with a single MGET.
+// Missing keys, and keys or values the codec can't handle, are left out.
+func (c *RedisCache[K, V]) GetMulti(keys []K) map[K]V {
+	return c.getMulti(keys, nil)
+}
+
+// getMultiWithExpiry is GetMulti with the expiries of the items, read in the same round trip.
+func (c *RedisCache[K, V]) getMultiWithExpiry(keys []K) (map[K]V, map[K]time.Time) {
+	expiries := make(map[K]time.Time, len(keys))
+
+	return c.getMulti(keys, expiries), expiries
+}
+
+// getMulti reads the items, and their expiries into expiries unless it is nil.
+func (c *RedisCache[K, V]) getMulti(keys []K, expiries map[K]time.Time) map[K]V {
+	items := make(map[K]V, len(keys))
+
+	valid := make([]K, 0, len(keys))
+	redisKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if redisKey, ok := c.key(key); ok {
+			valid = append(valid, key)
+			redisKeys = append(redisKeys, redisKey)
+		}
+	}
+	if len(redisKeys) == 0 {
+		return items
+	}
+
+	ctx := context.Background()
+	var getCmd *redis.SliceCmd
+	var ttlCmds []*redis.DurationCmd
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		getCmd = pipe.MGet(ctx, redisKeys...)
+		if c.slidingTTL > 0 {
+			for _, redisKey := range redisKeys {
+				pipe.Do(ctx, "pexpire", redisKey, c.slidingTTL.Milliseconds(), "xx")
+			}
+		}
+		if expiries != nil {
+			for _, redisKey := range redisKeys {
+				ttlCmds = append(ttlCmds, pipe.PTTL(ctx, redisKey))
+			}
+		}
+
+		return nil
+	})
+	if !c.check(err) {
+		return items
+	}
+
+	for i, data := range getCmd.Val() {
+		str, ok := data.(string)
+		if !ok {
+			c.stats.record(Miss)
+
+			continue
+		}
+
+		value, err := c.codec.DecodeValue([]byte(str))
+		if !c.check(err) {
+			c.stats.record(Miss)
+
+			continue
+		}
+		c.stats.record(Hit)
+		items[valid[i]] = value
+
+		if expiries != nil {
+			if ttl := ttlCmds[i].Val(); ttl > 0 {
+				expiries[valid[i]] = time.Now().Add(ttl)
+			}
+		}
+	}
+
+	return items
+}
+
+// AddMulti stores all the items with the same expiration in a single pipeline.
+func (c *RedisCache[K, V]) AddMulti(items map[K]V, expiration time.Duration) bool {
+	ok := true
+	ctx := context.Background()
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range items {
+			redisKey, data, encoded := c.encode(key, value)
+			if !encoded {
+				ok = false
+
+				continue
+			}
+			pipe.Set(ctx, redisKey, data, expiration)
+		}
+
+		return nil
+	})
+
+	return c.check(err) && ok
+}
+
+// DeleteMulti deletes the items of keys with a single DEL.
+func (c *RedisCache[K, V]) DeleteMulti(keys []K) bool {
+	ok := true
+	redisKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		redisKey, encoded := c.key(key)
+		if !encoded {
+			ok = false
+
+			continue
+		}
+		redisKeys = append(redisKeys, redisKey)
+	}
+	if len(redisKeys) == 0 {
+		return ok
+	}
+
+	return c.check(c.client.Del(context.Background(), redisKeys...).Err()) && ok
+}
+
+// GetOrLoad returns the cached item, or loads it with loader and stores it with ttl.
+// Concurrent calls of this process missing the same key share a single load;
+// other processes may load the key at the same time.
+func (c *RedisCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V], ttl time.Duration) (V, error) {
+	return getOrLoad(ctx, c, c.peek, &c.loads, key, loader, ttl)
+}
+
+// Stats returns the hit and miss counters; evictions and expirations are done by Redis.
+func (c *RedisCache[K, V]) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// CleanupNow does nothing, since Redis removes expired keys on its own.
+func (*RedisCache[K, V]) CleanupNow() {}
+
+// SaveSnapshot writes the items with the cache prefix and their expiry to w,
+// encoded with the codec of the cache. Items the codec can't decode are skipped.
+func (c *RedisCache[K, V]) SaveSnapshot(w io.Writer) error {
+	return saveSnapshot[K, V](w, c)
+}
+
+// LoadSnapshot adds the unexpired items of a snapshot written by SaveSnapshot.
+func (c *RedisCache[K, V]) LoadSnapshot(r io.Reader) error {
+	return loadSnapshot[K, V](r, c, c.codec)
+}
+
+func (c *RedisCache[K, V]) snapshotEntries() ([]snapshotEntry[K, V], error) {
+	ctx := context.Background()
+
+	var redisKeys []string
+	iter := c.client.Scan(ctx, 0, escapePattern(c.prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		if !strings.HasPrefix(iter.Val(), tagKeyPrefix) {
+			redisKeys = append(redisKeys, iter.Val())
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	getCmds := make([]*redis.StringCmd, len(redisKeys))
+	ttlCmds := make([]*redis.DurationCmd, len(redisKeys))
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, redisKey := range redisKeys {
+			getCmds[i] = pipe.Get(ctx, redisKey)
+			ttlCmds[i] = pipe.PTTL(ctx, redisKey)
+		}
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries := make([]snapshotEntry[K, V], 0, len(redisKeys))
+	for i, redisKey := range redisKeys {
+		data, err := getCmds[i].Bytes()
+		if err != nil {
+			continue // Expired or deleted since the scan
+		}
+
+		key, err := c.codec.DecodeKey(strings.TrimPrefix(redisKey, c.prefix))
+		if err != nil {
+			continue
+		}
+		value, err := c.codec.DecodeValue(data)
+		if err != nil {
+			continue
+		}
+
+		entry := snapshotEntry[K, V]{key: key, value: value}
+		if ttl := ttlCmds[i].Val(); ttl > 0 {
+			entry.expiry = now.Add(ttl)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (c *RedisCache[K, V]) snapshotCodec() Codec[K, V] {
+	return c.codec
+}
+
+func (c *RedisCache[K, V]) peek(key K) (V, bool) {
+	return c.get(key, 0, nil)
+}
+
+// get reads the item, extending its expiry to slidingTTL from now if it has one.
+// get returns the item, and sets expiry to its expiry unless expiry is nil.
+func (c *RedisCache[K, V]) get(key K, slidingTTL time.Duration, expiry *time.Time) (V, bool) {
+	var zeroV V
+
+	redisKey, ok := c.key(key)
+	if !ok {
+		return zeroV, false
+	}
+
+	ctx := context.Background()
+	var getCmd *redis.StringCmd
+	var ttlCmd *redis.DurationCmd
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		getCmd = pipe.Get(ctx, redisKey)
+		if slidingTTL > 0 {
+			// XX only extends keys that already expire.
+			pipe.Do(ctx, "pexpire", redisKey, slidingTTL.Milliseconds(), "xx")
+		}
+		if expiry != nil {
+			ttlCmd = pipe.PTTL(ctx, redisKey)
+		}
+
+		return nil
+	})
+	if errors.Is(err, redis.Nil) || !c.check(err) {
+		return zeroV, false
+	}
+
+	data, err := getCmd.Bytes()
+	if !c.check(err) {
+		return zeroV, false
+	}
+
+	value, err := c.codec.DecodeValue(data)
+	if !c.check(err) {
+		return zeroV, false
+	}
+
+	if expiry != nil {
+		if ttl := ttlCmd.Val(); ttl > 0 {
+			*expiry = time.Now().Add(ttl)
+		}
+	}
+
+	return value, true
+}
+
+func (c *RedisCache[K, V]) key(key K) (string, bool) {
+	encoded, err := c.codec.EncodeKey(key)
+	if !c.check(err) {
+		return "", false
+	}
+
+	return c.prefix + encoded, true
+}
+
+func (c *RedisCache[K, V]) encode(key K, value V) (string, []byte, bool) {
+	redisKey, ok := c.key(key)
+	if !ok {
+		return "", nil, false
+	}
+
+	data, err := c.codec.EncodeValue(value)
+	if !c.check(err) {
+		return "", nil, false
+	}
+
+	return redisKey, data, true
+}
+
+// check reports err to the error handler and returns whether it is nil.
+func (c *RedisCache[K, V]) check(err error) bool {
+	if err != nil {
+		c.onError(err)
+
+		return false
+	}
+
+	return true
+}
+
+// escapePattern escapes the glob characters of a SCAN pattern.
+func escapePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(s)
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type redisPoint struct {
+	X, Y int
+}
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return server, client
+}
+
+func TestRedisCache(t *testing.T) {
+	_, client := newTestRedis(t)
+	cache := NewRedis[string, redisPoint](client, JSONCodec[string, redisPoint]{}, WithKeyPrefix("points:"))
+
+	if !cache.Add("a", redisPoint{1, 2}, 0) {
+		t.Fatal("failed to add a")
+	}
+
+	if val, ok := cache.Get("a"); !ok || val != (redisPoint{1, 2}) {
+		t.Errorf("Get(a) = %v, %v", val, ok)
+	}
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("expected missing to be absent")
+	}
+	if !cache.Exists("a") || cache.Exists("missing") {
+		t.Error("unexpected Exists result")
+	}
+
+	if cache.Update("missing", redisPoint{}, 0) {
+		t.Error("Update must not create missing keys")
+	}
+	if !cache.Update("a", redisPoint{3, 4}, 0) {
+		t.Error("failed to update a")
+	}
+	if val, _ := cache.Get("a"); val != (redisPoint{3, 4}) {
+		t.Errorf("Get(a) after update = %v", val)
+	}
+
+	// Keys outside the prefix are not listed.
+	client.Set(t.Context(), "other", "x", 0)
+	cache.Add("b", redisPoint{}, 0)
+	keys := cache.Keys()
+	slices.Sort(keys)
+	if !slices.Equal(keys, []string{"a", "b"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+
+	if !cache.Delete("a") || cache.Exists("a") {
+		t.Error("failed to delete a")
+	}
+
+	if got, want := cache.Stats(), (Stats{Hits: 2, Misses: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRedisCacheExpiry(t *testing.T) {
+	server, client := newTestRedis(t)
+	cache := NewRedis[int, string](client, JSONCodec[int, string]{})
+
+	cache.Add(1, "one", time.Minute)
+	cache.Add(2, "two", 0)
+	cache.Update(1, "uno", 0)
+
+	server.FastForward(30 * time.Second)
+	if val, ok := cache.Get(1); !ok || val != "uno" {
+		t.Errorf("Get(1) = %q, %v; want the TTL kept by Update", val, ok)
+	}
+
+	server.FastForward(31 * time.Second)
+	if cache.Exists(1) {
+		t.Error("expected 1 to be expired")
+	}
+	if !cache.Exists(2) {
+		t.Error("expected 2 to never expire")
+	}
+}
+
+func TestRedisCacheSlidingTTL(t *testing.T) {
+	server, client := newTestRedis(t)
+	cache := NewRedis[string, int](client, JSONCodec[string, int]{}, WithSlidingTTL(time.Minute))
+
+	cache.Add("session", 1, time.Minute)
+	cache.Add("forever", 2, 0)
+
+	for range 3 {
+		server.FastForward(40 * time.Second)
+		if _, ok := cache.Get("session"); !ok {
+			t.Fatal("expected session to be kept alive by reads")
+		}
+	}
+
+	cache.Get("forever")
+	if ttl := server.TTL("forever"); ttl != 0 {
+		t.Errorf("TTL(forever) = %v, want none", ttl)
+	}
+}
+
+func TestRedisCacheErrors(t *testing.T) {
+	server, client := newTestRedis(t)
+
+	var errs []error
+	cache := NewRedis[string, int](client, JSONCodec[string, int]{}, WithErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	server.Set("bad", "not json")
+	if _, ok := cache.Get("bad"); ok {
+		t.Error("expected undecodable value to be a miss")
+	}
+
+	server.Close()
+	if cache.Add("a", 1, 0) {
+		t.Error("expected Add to fail without a server")
+	}
+
+	if len(errs) != 2 {
+		t.Errorf("got %d errors, want 2: %v", len(errs), errs)
+	}
+}
+
+func TestRedisCacheGetOrLoad(t *testing.T) {
+	_, client := newTestRedis(t)
+	cache := NewRedis[string, int](client, JSONCodec[string, int]{})
+
+	loads := 0
+	for range 2 {
+		val, err := cache.GetOrLoad(t.Context(), "key", func(context.Context) (int, error) {
+			loads++
+
+			return 7, nil
+		}, time.Minute)
+		if err != nil || val != 7 {
+			t.Fatalf("GetOrLoad = %d, %v", val, err)
+		}
+	}
+
+	if loads != 1 {
+		t.Errorf("loader called %d times, want 1", loads)
+	}
+}
+
+func TestJSONCodecKeys(t *testing.T) {
+	codec := JSONCodec[redisPoint, int]{}
+
+	encoded, err := codec.EncodeKey(redisPoint{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := codec.DecodeKey(encoded)
+	if err != nil || decoded != (redisPoint{1, 2}) {
+		t.Errorf("DecodeKey(%q) = %v, %v", encoded, decoded, err)
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetNX(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	for name, cache := range map[string]Cache[string, int]{
+		"basic":     NewBasic[string, int](t.Context()),
+		"bounded":   NewBounded[string, int](t.Context(), 10),
+		"redis":     NewRedis[string, int](client, JSONCodec[string, int]{}, WithKeyPrefix("setnx:")),
+		"sharded":   NewSharded[string, int](t.Context(), 4),
+		"namespace": Namespace(NewBasic[string, int](t.Context()), "ns:"),
+		"tiered":    NewTiered(NewBasic[string, int](t.Context()), NewBasic[string, int](t.Context())),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if !cache.SetNX("lock", 1, 20*time.Millisecond) {
+				t.Fatal("expected SetNX to set a missing key")
+			}
+			if cache.SetNX("lock", 2, 0) {
+				t.Error("expected SetNX to leave an existing key alone")
+			}
+			if got, _ := cache.Get("lock"); got != 1 {
+				t.Errorf("Get(lock) = %d, want 1", got)
+			}
+
+			if name == "redis" {
+				return // miniredis only expires keys when its clock is moved forward
+			}
+			time.Sleep(30 * time.Millisecond)
+			if !cache.SetNX("lock", 3, 0) {
+				t.Error("expected SetNX to replace an expired key")
+			}
+		})
+	}
+}
+
+func TestGetWithExpiry(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	for name, cache := range map[string]Cache[string, int]{
+		"basic":   NewBasic[string, int](t.Context()),
+		"bounded": NewBounded[string, int](t.Context(), 10),
+		"redis":   NewRedis[string, int](client, JSONCodec[string, int]{}, WithKeyPrefix("expiry:")),
+		"sharded": NewSharded[string, int](t.Context(), 4),
+		"tiered":  NewTiered(NewBasic[string, int](t.Context()), NewBasic[string, int](t.Context())),
+	} {
+		t.Run(name, func(t *testing.T) {
+			cache.Add("hour", 1, time.Hour)
+			cache.Add("forever", 2, 0)
+
+			value, expiry, ok := cache.GetWithExpiry("hour")
+			if !ok || value != 1 {
+				t.Fatalf("GetWithExpiry(hour) = %d, %v", value, ok)
+			}
+			if remaining := time.Until(expiry); remaining <= 59*time.Minute || remaining > time.Hour {
+				t.Errorf("expected an expiry in about an hour, got %v", remaining)
+			}
+
+			if _, expiry, ok := cache.GetWithExpiry("forever"); !ok || !expiry.IsZero() {
+				t.Errorf("expected the zero expiry for an item without one, got %v, %v", expiry, ok)
+			}
+
+			if _, _, ok := cache.GetWithExpiry("missing"); ok {
+				t.Error("expected a miss for a missing key")
+			}
+		})
+	}
+}
+
+func TestGetWithExpirySliding(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	cache := NewBasic[string, int](t.Context(), WithClock(clock), WithSlidingTTL(time.Hour))
+	cache.Add("a", 1, time.Minute)
+
+	clock.advance(time.Second)
+	if _, expiry, _ := cache.GetWithExpiry("a"); !expiry.Equal(clock.Now().Add(time.Hour)) {
+		t.Errorf("expected the extended expiry, got %v", expiry)
+	}
+}
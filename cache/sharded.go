@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"context"
+	"hash/maphash"
+	"io"
+	"runtime"
+	"time"
+)
+
+// ShardedCache spreads its keys over independent basic caches by hash, so concurrent
+// writers and the periodic cleanups of the shards don't contend with each other.
+type ShardedCache[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []*BasicCache[K, V]
+}
+
+// NewSharded creates a cache of shards basic caches (runtime.GOMAXPROCS when not positive),
+// each with its own cleanup. The options apply to every shard, and a stats hook
+// or eviction callback is shared by them, so it must be safe for concurrent use.
+func NewSharded[K comparable, V any](ctx context.Context, shards int, opts ...Option) Cache[K, V] {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+
+	cache := &ShardedCache[K, V]{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*BasicCache[K, V], shards),
+	}
+	for i := range cache.shards {
+		cache.shards[i] = NewBasic[K, V](ctx, opts...).(*BasicCache[K, V])
+	}
+
+	return cache
+}
+
+func (c *ShardedCache[K, V]) shard(key K) *BasicCache[K, V] {
+	return c.shards[maphash.Comparable(c.seed, key)%uint64(len(c.shards))]
+}
+
+// Add stores the item in the shard of key.
+//
+//   - expiration: 0 for disable expire cache
+func (c *ShardedCache[K, V]) Add(key K, value V, expiration time.Duration, opts ...AddOption) bool {
+	return c.shard(key).Add(key, value, expiration, opts...)
+}
+
+func (c *ShardedCache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *ShardedCache[K, V]) Update(key K, newValue V, expiration time.Duration) bool {
+	return c.shard(key).Update(key, newValue, expiration)
+}
+
+func (c *ShardedCache[K, V]) Exists(key K) bool {
+	return c.shard(key).Exists(key)
+}
+
+// Keys returns the keys of every shard.
+func (c *ShardedCache[K, V]) Keys() []K {
+	keys := make([]K, 0)
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+
+	return keys
+}
+
+// Range calls fn for each unexpired item of every shard until fn returns false.
+func (c *ShardedCache[K, V]) Range(fn func(key K, value V) bool) {
+	for _, shard := range c.shards {
+		stopped := false
+		shard.Range(func(key K, value V) bool {
+			stopped = !fn(key, value)
+
+			return !stopped
+		})
+		if stopped {
+			return
+		}
+	}
+}
+
+// Len returns the number of unexpired items of every shard.
+func (c *ShardedCache[K, V]) Len() int {
+	n := 0
+	for _, shard := range c.shards {
+		n += shard.Len()
+	}
+
+	return n
+}
+
+// Clear deletes the items of every shard.
+func (c *ShardedCache[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+// InvalidateTag invalidates the items tagged with tag in every shard.
+func (c *ShardedCache[K, V]) InvalidateTag(tag string) {
+	for _, shard := range c.shards {
+		shard.InvalidateTag(tag)
+	}
+}
+
+func (c *ShardedCache[K, V]) Delete(key K) bool {
+	return c.shard(key).Delete(key)
+}
+
+func (c *ShardedCache[K, V]) Upsert(key K, fn func(old V, exists bool) V, ttl time.Duration) V {
+	return c.shard(key).Upsert(key, fn, ttl)
+}
+
+func (c *ShardedCache[K, V]) modify(key K, fn func(old V, exists bool) (V, bool), ttl time.Duration) (V, bool) {
+	return c.shard(key).modify(key, fn, ttl)
+}
+
+func (c *ShardedCache[K, V]) GetMulti(keys []K) map[K]V {
+	items := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, ok := c.Get(key); ok {
+			items[key] = value
+		}
+	}
+
+	return items
+}
+
+func (c *ShardedCache[K, V]) AddMulti(items map[K]V, expiration time.Duration) bool {
+	for key, value := range items {
+		c.Add(key, value, expiration)
+	}
+
+	return true
+}
+
+func (c *ShardedCache[K, V]) DeleteMulti(keys []K) bool {
+	for _, key := range keys {
+		c.Delete(key)
+	}
+
+	return true
+}
+
+// GetWithExpiry returns the item like Get, with its expiry, the zero time if it never expires.
+func (c *ShardedCache[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	return c.shard(key).GetWithExpiry(key)
+}
+
+// SetNX adds the item only if the key is missing or expired, and reports whether it did.
+func (c *ShardedCache[K, V]) SetNX(key K, value V, expiration time.Duration) bool {
+	return c.shard(key).SetNX(key, value, expiration)
+}
+
+// GetOrLoad returns the cached item, or loads it with loader and stores it with ttl.
+// Concurrent calls missing the same key share a single load.
+func (c *ShardedCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V], ttl time.Duration) (V, error) {
+	return c.shard(key).GetOrLoad(ctx, key, loader, ttl)
+}
+
+// Stats returns the sum of the counters of the shards.
+func (c *ShardedCache[K, V]) Stats() Stats {
+	var total Stats
+	for _, shard := range c.shards {
+		stats := shard.Stats()
+		total.Hits += stats.Hits
+		total.Misses += stats.Misses
+		total.Evictions += stats.Evictions
+		total.Expired += stats.Expired
+	}
+
+	return total
+}
+
+// CleanupNow removes the expired items of every shard now.
+func (c *ShardedCache[K, V]) CleanupNow() {
+	for _, shard := range c.shards {
+		shard.CleanupNow()
+	}
+}
+
+func (c *ShardedCache[K, V]) now() time.Time {
+	return c.shards[0].now()
+}
+
+// SaveSnapshot writes the unexpired items of every shard with their expiry to w.
+// A snapshot can be loaded into a cache with another number of shards, or another kind of cache.
+func (c *ShardedCache[K, V]) SaveSnapshot(w io.Writer) error {
+	return saveSnapshot[K, V](w, c)
+}
+
+// LoadSnapshot adds the unexpired items of a snapshot written by SaveSnapshot.
+func (c *ShardedCache[K, V]) LoadSnapshot(r io.Reader) error {
+	return loadSnapshot[K, V](r, c, c.snapshotCodec())
+}
+
+func (c *ShardedCache[K, V]) snapshotEntries() ([]snapshotEntry[K, V], error) {
+	entries := make([]snapshotEntry[K, V], 0)
+	for _, shard := range c.shards {
+		shardEntries, err := shard.snapshotEntries()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, shardEntries...)
+	}
+
+	return entries, nil
+}
+
+func (c *ShardedCache[K, V]) snapshotCodec() Codec[K, V] {
+	return c.shards[0].snapshotCodec()
+}
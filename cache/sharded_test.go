@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSharded(t *testing.T) {
+	cache := NewSharded[string, int](t.Context(), 8)
+
+	for i := range 100 {
+		cache.Add(strconv.Itoa(i), i, 0)
+	}
+
+	keys := cache.Keys()
+	if len(keys) != 100 {
+		t.Fatalf("expected 100 keys, got %d", len(keys))
+	}
+
+	used := 0
+	for _, shard := range cache.(*ShardedCache[string, int]).shards {
+		if len(shard.Keys()) > 0 {
+			used++
+		}
+	}
+	if used < 2 {
+		t.Errorf("expected the keys to be spread over the shards, %d used", used)
+	}
+
+	if value, ok := cache.Get("42"); !ok || value != 42 {
+		t.Errorf("Get(42) = %d, %v", value, ok)
+	}
+	if !cache.Update("42", 43, 0) {
+		t.Error("Update failed")
+	}
+	if !cache.Delete("42") || cache.Exists("42") {
+		t.Error("expected 42 to be deleted")
+	}
+	cache.Get("42")
+
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Stats() = %+v, want the sum of the shards", stats)
+	}
+
+	got := cache.GetMulti([]string{"1", "2", "missing"})
+	if len(got) != 2 || got["1"] != 1 || got["2"] != 2 {
+		t.Errorf("GetMulti = %v", got)
+	}
+}
+
+func TestShardedDefaults(t *testing.T) {
+	cache := NewSharded[int, int](t.Context(), 0).(*ShardedCache[int, int])
+	if len(cache.shards) < 1 {
+		t.Error("expected at least one shard")
+	}
+}
+
+func TestShardedOptions(t *testing.T) {
+	var mu sync.Mutex
+	var evicted []int
+	cache := NewSharded[int, int](t.Context(), 4, WithCleanUpInterval(5*time.Millisecond),
+		WithOnEvict(func(key, _ int, reason Reason) {
+			mu.Lock()
+			defer mu.Unlock()
+			if reason == ReasonExpired {
+				evicted = append(evicted, key)
+			}
+		}))
+
+	for i := range 10 {
+		cache.Add(i, i, time.Millisecond)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(evicted)
+		mu.Unlock()
+		if n == 10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the cleanup of every shard to expire the entries, got %d", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestShardedGetOrLoadAndAtomic(t *testing.T) {
+	cache := NewSharded[string, int64](t.Context(), 4)
+
+	value, err := cache.GetOrLoad(t.Context(), "k", func(context.Context) (int64, error) { return 1, nil }, 0)
+	if err != nil || value != 1 {
+		t.Fatalf("GetOrLoad = %d, %v", value, err)
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() { Increment(cache, "k", 1) })
+	}
+	wg.Wait()
+
+	if got, _ := cache.Get("k"); got != 11 {
+		t.Errorf("Get = %d, want 11", got)
+	}
+}
+
+func TestShardedSnapshot(t *testing.T) {
+	cache := NewSharded[int, int](t.Context(), 4)
+	for i := range 20 {
+		cache.Add(i, i, time.Hour)
+	}
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewSharded[int, int](t.Context(), 3)
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := restored.Keys()
+	slices.Sort(keys)
+	if len(keys) != 20 || keys[0] != 0 || keys[19] != 19 {
+		t.Errorf("restored keys = %v", keys)
+	}
+}
+
+func BenchmarkShardedParallel(b *testing.B) {
+	benchmarks := map[string]Cache[int, int]{
+		"basic":   NewBasic[int, int](b.Context()),
+		"sharded": NewSharded[int, int](b.Context(), 0),
+	}
+
+	for name, cache := range benchmarks {
+		b.Run(name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					cache.Add(i%1024, i, time.Minute)
+					cache.Get((i + 7) % 1024)
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package cache
+
+import "fmt"
+
+// WithMaxBytes bounds a bounded cache by the estimated memory footprint of its entries,
+// as reported by sizer, in addition to its number of entries. Whenever the total exceeds
+// n bytes, entries are evicted in the order of the eviction policy until it fits;
+// an entry larger than n on its own is not kept.
+// With a non-positive maxEntries, NewBounded then bounds the cache by size only.
+//
+// sizer is called under the cache lock each time a value is stored, so it must be fast.
+// K and V must match the types of the cache, or the constructor panics.
+// Used by NewBounded and NewLRU only.
+func WithMaxBytes[K, V any](n int, sizer func(key K, value V) int) Option {
+	return func(cfg *options) {
+		cfg.maxBytes = n
+		cfg.sizer = sizer
+	}
+}
+
+// sizerFunc returns the sizer of cfg, or nil when none is set.
+func sizerFunc[K, V any](cfg *options) func(key K, value V) int {
+	if cfg.sizer == nil {
+		return nil
+	}
+
+	sizer, ok := cfg.sizer.(func(key K, value V) int)
+	if !ok {
+		var key K
+		var value V
+		panic(fmt.Sprintf("cache: WithMaxBytes sizer %T does not match the cache types %T and %T",
+			cfg.sizer, key, value))
+	}
+
+	return sizer
+}
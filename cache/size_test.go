@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"slices"
+	"testing"
+)
+
+func byteSize(_ string, value []byte) int {
+	return len(value)
+}
+
+func TestMaxBytes(t *testing.T) {
+	for _, policy := range []Policy{LRU, LFU, ARC} {
+		cache := NewBounded[string, []byte](t.Context(), 100,
+			WithPolicy(policy), WithMaxBytes(10, byteSize))
+
+		cache.Add("a", make([]byte, 4), 0)
+		cache.Add("b", make([]byte, 4), 0)
+		cache.Get("b")
+		cache.Add("c", make([]byte, 4), 0)
+
+		if cache.Exists("a") {
+			t.Errorf("%d: expected a to be evicted once the budget is exceeded", policy)
+		}
+		if !cache.Exists("b") || !cache.Exists("c") {
+			t.Errorf("%d: expected b and c to be kept, got %v", policy, cache.Keys())
+		}
+		if evictions := cache.Stats().Evictions; evictions != 1 {
+			t.Errorf("%d: Evictions = %d, want 1", policy, evictions)
+		}
+	}
+}
+
+func TestMaxBytesTracksUpdates(t *testing.T) {
+	cache := NewLRU[string, []byte](t.Context(), 100, WithMaxBytes(10, byteSize))
+
+	cache.Add("a", make([]byte, 4), 0)
+	cache.Add("b", make([]byte, 4), 0)
+
+	// Growing b evicts a, the least recently used.
+	cache.Update("b", make([]byte, 8), 0)
+	if got := cache.Keys(); !slices.Equal(got, []string{"b"}) {
+		t.Fatalf("Keys() = %v, want [b]", got)
+	}
+
+	// Shrinking b and deleting entries frees room.
+	cache.Add("b", make([]byte, 2), 0)
+	cache.Add("c", make([]byte, 8), 0)
+	if got := cache.Keys(); !slices.Equal(got, []string{"c", "b"}) {
+		t.Fatalf("Keys() = %v, want [c b]", got)
+	}
+
+	cache.Delete("c")
+	cache.Upsert("d", func([]byte, bool) []byte { return make([]byte, 8) }, 0)
+	if got := cache.Keys(); !slices.Equal(got, []string{"d", "b"}) {
+		t.Errorf("Keys() = %v, want [d b]", got)
+	}
+}
+
+func TestMaxBytesOversizedEntry(t *testing.T) {
+	cache := NewLRU[string, []byte](t.Context(), 100, WithMaxBytes(10, byteSize))
+	cache.Add("small", make([]byte, 2), 0)
+
+	if cache.Add("huge", make([]byte, 11), 0) {
+		t.Error("expected an entry larger than the budget to be rejected")
+	}
+	if cache.Exists("huge") {
+		t.Error("expected the oversized entry not to be kept")
+	}
+}
+
+func TestMaxBytesOnly(t *testing.T) {
+	cache := NewBounded[int, []byte](t.Context(), 0, WithMaxBytes(1000, func(_ int, value []byte) int {
+		return len(value)
+	}))
+
+	for i := range 500 {
+		cache.Add(i, []byte{1}, 0)
+	}
+	if n := len(cache.Keys()); n != 500 {
+		t.Errorf("expected no entry limit without maxEntries, got %d entries", n)
+	}
+}
+
+func TestMaxBytesSizerMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a sizer of the wrong types to panic")
+		}
+	}()
+
+	NewLRU[int, int](t.Context(), 1, WithMaxBytes(10, byteSize))
+}
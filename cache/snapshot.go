@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// snapshotVersion is the version of the snapshot format written by SaveSnapshot.
+const snapshotVersion = 1
+
+var (
+	// ErrSnapshotVersion is returned by LoadSnapshot for a snapshot in an unknown format.
+	ErrSnapshotVersion = errors.New("cache: unsupported snapshot version")
+
+	// ErrSnapshotUnsupported is returned by a namespace whose cache can't be snapshotted.
+	ErrSnapshotUnsupported = errors.New("cache: snapshot not supported")
+)
+
+// WithSnapshotCodec sets the codec serializing the keys and values written by SaveSnapshot
+// and read by LoadSnapshot. By default keys and values are encoded with JSONCodec.
+// NewRedis uses its own codec instead.
+//
+// K and V must match the types of the cache, or the constructor panics.
+func WithSnapshotCodec[K, V any](codec Codec[K, V]) Option {
+	return func(cfg *options) {
+		cfg.snapshotCodec = codec
+	}
+}
+
+// snapshotCodecOf returns the snapshot codec of cfg, or JSONCodec when none is set.
+func snapshotCodecOf[K, V any](cfg *options) Codec[K, V] {
+	if cfg.snapshotCodec == nil {
+		return JSONCodec[K, V]{}
+	}
+
+	codec, ok := cfg.snapshotCodec.(Codec[K, V])
+	if !ok {
+		var key K
+		var value V
+		panic(fmt.Sprintf("cache: WithSnapshotCodec codec %T does not match the cache types %T and %T",
+			cfg.snapshotCodec, key, value))
+	}
+
+	return codec
+}
+
+// snapshotEntry is an entry of the cache as written to a snapshot.
+type snapshotEntry[K, V any] struct {
+	key    K
+	value  V
+	expiry time.Time
+}
+
+// snapshotter is implemented by the caches able to list their entries with their expiry.
+type snapshotter[K, V any] interface {
+	snapshotEntries() ([]snapshotEntry[K, V], error)
+	snapshotCodec() Codec[K, V]
+}
+
+// snapshotHeader is the first line of a snapshot.
+type snapshotHeader struct {
+	Version int `json:"version"`
+}
+
+// snapshotRecord is one line of a snapshot after the header.
+type snapshotRecord struct {
+	Key    string    `json:"key"`
+	Value  []byte    `json:"value"`
+	Expiry time.Time `json:"expiry,omitzero"`
+}
+
+// writeSnapshot writes a header line then one JSON line per entry,
+// with the key and value serialized by codec.
+func writeSnapshot[K, V any](w io.Writer, codec Codec[K, V], entries []snapshotEntry[K, V]) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(snapshotHeader{Version: snapshotVersion}); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		key, err := codec.EncodeKey(entry.key)
+		if err != nil {
+			return fmt.Errorf("cache: encode snapshot key: %w", err)
+		}
+		value, err := codec.EncodeValue(entry.value)
+		if err != nil {
+			return fmt.Errorf("cache: encode snapshot value of %q: %w", key, err)
+		}
+
+		if err := enc.Encode(snapshotRecord{Key: key, Value: value, Expiry: entry.expiry}); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// readSnapshot reads the entries written by writeSnapshot, skipping those expired by now.
+func readSnapshot[K, V any](r io.Reader, codec Codec[K, V], now time.Time) ([]snapshotEntry[K, V], error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	var header snapshotHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("cache: read snapshot header: %w", err)
+	}
+	if header.Version != snapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, header.Version)
+	}
+
+	entries := make([]snapshotEntry[K, V], 0)
+	for {
+		var record snapshotRecord
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cache: read snapshot: %w", err)
+		}
+
+		if !record.Expiry.IsZero() && !now.Before(record.Expiry) {
+			continue
+		}
+
+		key, err := codec.DecodeKey(record.Key)
+		if err != nil {
+			return nil, fmt.Errorf("cache: decode snapshot key %q: %w", record.Key, err)
+		}
+		value, err := codec.DecodeValue(record.Value)
+		if err != nil {
+			return nil, fmt.Errorf("cache: decode snapshot value of %q: %w", record.Key, err)
+		}
+
+		entries = append(entries, snapshotEntry[K, V]{key: key, value: value, expiry: record.Expiry})
+	}
+}
+
+// saveSnapshot writes the entries of cache to w.
+func saveSnapshot[K, V any](w io.Writer, cache snapshotter[K, V]) error {
+	entries, err := cache.snapshotEntries()
+	if err != nil {
+		return err
+	}
+
+	return writeSnapshot(w, cache.snapshotCodec(), entries)
+}
+
+// loadSnapshot reads the entries from r and adds them to cache with their remaining time to live.
+// The entries are added in reverse order, so the first entry of the snapshot is the most recent one.
+// Nothing is added if the snapshot can't be read.
+func loadSnapshot[K comparable, V any](r io.Reader, cache Cache[K, V], codec Codec[K, V]) error {
+	now := nowOf(cache)
+	entries, err := readSnapshot(r, codec, now)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range slices.Backward(entries) {
+		var ttl time.Duration
+		if !entry.expiry.IsZero() {
+			ttl = entry.expiry.Sub(now)
+			if ttl <= 0 {
+				continue
+			}
+		}
+		cache.Add(entry.key, entry.value, ttl)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	newCaches := map[string]func(prefix string) Cache[string, int]{
+		"basic": func(string) Cache[string, int] {
+			return NewBasic[string, int](t.Context())
+		},
+		"bounded": func(string) Cache[string, int] {
+			return NewBounded[string, int](t.Context(), 10)
+		},
+		"redis": func(prefix string) Cache[string, int] {
+			return NewRedis[string, int](client, JSONCodec[string, int]{}, WithKeyPrefix(prefix))
+		},
+		"namespace": func(prefix string) Cache[string, int] {
+			return Namespace(NewBasic[string, int](t.Context()), prefix)
+		},
+	}
+
+	for name, newCache := range newCaches {
+		t.Run(name, func(t *testing.T) {
+			saved := newCache(name + ":saved:")
+			saved.Add("forever", 1, 0)
+			saved.Add("minute", 2, time.Minute)
+
+			var buf bytes.Buffer
+			if err := saved.SaveSnapshot(&buf); err != nil {
+				t.Fatalf("SaveSnapshot: %v", err)
+			}
+
+			loaded := newCache(name + ":loaded:")
+			if err := loaded.LoadSnapshot(&buf); err != nil {
+				t.Fatalf("LoadSnapshot: %v", err)
+			}
+
+			got := loaded.GetMulti([]string{"forever", "minute"})
+			if want := map[string]int{"forever": 1, "minute": 2}; !maps.Equal(got, want) {
+				t.Errorf("loaded items = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSnapshotKeepsExpiry(t *testing.T) {
+	cache := NewBasic[string, int](t.Context())
+	cache.Add("short", 1, 50*time.Millisecond)
+	cache.Add("long", 2, time.Hour)
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	restored := NewBasic[string, int](t.Context())
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.Exists("short") {
+		t.Error("expected the entry expired since the snapshot to be skipped")
+	}
+
+	entry, ok := restored.(*BasicCache[string, int]).lookup("long")
+	if !ok {
+		t.Fatal("expected long to be restored")
+	}
+	if remaining := time.Until(entry.Expiry); remaining <= 59*time.Minute || remaining > time.Hour {
+		t.Errorf("expected the remaining TTL to be kept, got %v", remaining)
+	}
+}
+
+func TestSnapshotSkipsExpired(t *testing.T) {
+	cache := NewBasic[string, int](t.Context())
+	cache.Add("expired", 1, time.Nanosecond)
+	cache.Add("alive", 2, 0)
+	time.Sleep(time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "expired") {
+		t.Errorf("expected expired entries to be left out, got %s", buf.String())
+	}
+}
+
+func TestSnapshotBoundedOrder(t *testing.T) {
+	cache := NewLRU[int, int](t.Context(), 3)
+	for i := range 3 {
+		cache.Add(i, i, 0)
+	}
+	cache.Get(0) // 0 is now the most recently used
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewLRU[int, int](t.Context(), 3)
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := restored.Keys(), cache.Keys(); !slices.Equal(got, want) {
+		t.Errorf("restored LRU order = %v, want %v", got, want)
+	}
+
+	// A smaller cache keeps the most recently used entries.
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	small := NewLRU[int, int](t.Context(), 2)
+	if err := small.LoadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := small.Keys(), []int{0, 2}; !slices.Equal(got, want) {
+		t.Errorf("small cache keys = %v, want %v", got, want)
+	}
+}
+
+type upperCodec struct {
+	JSONCodec[string, string]
+}
+
+func (upperCodec) EncodeValue(value string) ([]byte, error) {
+	return []byte(strings.ToUpper(value)), nil
+}
+
+func (upperCodec) DecodeValue(data []byte) (string, error) {
+	return strings.ToLower(string(data)), nil
+}
+
+func TestSnapshotCodec(t *testing.T) {
+	cache := NewBasic[string, string](t.Context(), WithSnapshotCodec[string, string](upperCodec{}))
+	cache.Add("greeting", "hello", 0)
+
+	var buf bytes.Buffer
+	if err := cache.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "SEVMTE8") { // base64 of HELLO
+		t.Errorf("expected the codec to encode the value, got %s", buf.String())
+	}
+
+	restored := NewBasic[string, string](t.Context(), WithSnapshotCodec[string, string](upperCodec{}))
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := restored.Get("greeting"); got != "hello" {
+		t.Errorf("Get(greeting) = %q, want hello", got)
+	}
+}
+
+func TestSnapshotCodecMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a codec of the wrong types to panic")
+		}
+	}()
+
+	NewBasic[int, int](t.Context(), WithSnapshotCodec[string, string](upperCodec{}))
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	cache := NewBasic[int, int](t.Context())
+
+	err := cache.LoadSnapshot(strings.NewReader(`{"version":99}`))
+	if !errors.Is(err, ErrSnapshotVersion) {
+		t.Errorf("expected ErrSnapshotVersion, got %v", err)
+	}
+
+	if err := cache.LoadSnapshot(strings.NewReader("")); err == nil {
+		t.Error("expected an error for an empty snapshot")
+	}
+
+	snapshot := `{"version":1}` + "\n" + `{"key":"1","value":"MQ=="}` + "\n" + `{"key":"x","value":"MQ=="}`
+	if err := cache.LoadSnapshot(strings.NewReader(snapshot)); err == nil {
+		t.Error("expected an error for an undecodable key")
+	}
+	if cache.Exists(1) {
+		t.Error("expected nothing to be loaded from an invalid snapshot")
+	}
+}
+
+func TestNamespaceSnapshot(t *testing.T) {
+	shared := NewBasic[string, int](t.Context())
+	tenant := Namespace(shared, "tenant:1:")
+	tenant.Add("a", 1, 0)
+	shared.Add("other", 2, 0)
+
+	var buf bytes.Buffer
+	if err := tenant.SaveSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := NewBasic[string, int](t.Context())
+	if err := restored.LoadSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.Keys(); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("restored keys = %v, want only the namespace keys without prefix", got)
+	}
+
+	buf.Reset()
+	if err := Namespace[int](stubCache{}, "x:").SaveSnapshot(&buf); !errors.Is(err, ErrSnapshotUnsupported) {
+		t.Errorf("expected ErrSnapshotUnsupported, got %v", err)
+	}
+}
+
+// stubCache is a Cache of another package, which can't be snapshotted by a namespace.
+type stubCache struct {
+	Cache[string, int]
+}
+
+func BenchmarkSaveSnapshot(b *testing.B) {
+	cache := NewBasic[string, int](b.Context())
+	for i := range 1000 {
+		cache.Add(strconv.Itoa(i), i, time.Hour)
+	}
+
+	var buf bytes.Buffer
+	for b.Loop() {
+		buf.Reset()
+		_ = cache.SaveSnapshot(&buf)
+	}
+}
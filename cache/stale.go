@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ezex-io/gopkg/retry"
+)
+
+// WithStaleWhileRevalidate keeps entries for grace after they expire. Meanwhile, GetOrLoad
+// serves the stale value at once and refreshes it in the background with its loader,
+// retried according to opts, so callers don't wait for a slow upstream.
+// Concurrent calls share a single refresh. A refresh that still fails leaves the stale value
+// in place, and once grace has passed GetOrLoad loads the value synchronously again.
+//
+// The other methods treat expired entries as missing, as without the option.
+// Used by NewBasic and NewBounded only; Redis removes expired entries itself.
+func WithStaleWhileRevalidate(grace time.Duration, opts ...retry.Options) Option {
+	return func(cfg *options) {
+		cfg.staleGrace = grace
+		cfg.refreshRetry = opts
+	}
+}
+
+// staleReader is implemented by the caches supporting stale-while-revalidate.
+type staleReader[K comparable, V any] interface {
+	// stale returns the value of an entry expired less than the grace period ago.
+	stale(key K) (V, bool)
+
+	// refreshOptions returns the retry options of the background refresh.
+	refreshOptions() []retry.Options
+}
+
+// refresh reloads key in the background with loader, unless a load of key is already running.
+// The refresh runs with the values of ctx but not its cancellation, since the caller
+// doesn't wait for it.
+func refresh[K comparable, V any](ctx context.Context, cache Cache[K, V], group *loadGroup[V],
+	key K, loader Loader[V], ttl time.Duration, opts []retry.Options,
+) {
+	call, started := group.tryStart(key)
+	if !started {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				// Nobody is there to re-panic to: report it to the waiters, if any.
+				call.err = fmt.Errorf("cache: loader panicked: %v", r)
+			}
+			group.finish(key, call)
+		}()
+
+		call.value, call.err = retry.ExecuteSyncT(ctx, func() (V, error) {
+			return loader(ctx)
+		}, opts...)
+		if call.err == nil {
+			cache.Add(key, call.value, ttl)
+		}
+	}()
+}
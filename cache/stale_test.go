@@ -0,0 +1,198 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/retry"
+)
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	caches := map[string]Cache[string, int64]{
+		"basic":   NewBasic[string, int64](t.Context(), WithStaleWhileRevalidate(time.Hour)),
+		"bounded": NewBounded[string, int64](t.Context(), 10, WithStaleWhileRevalidate(time.Hour)),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			var loads atomic.Int64
+			release := make(chan struct{})
+			loader := func(context.Context) (int64, error) {
+				if loads.Add(1) > 1 {
+					<-release // The refresh is slow
+				}
+
+				return loads.Load(), nil
+			}
+
+			if v, err := cache.GetOrLoad(t.Context(), "k", loader, 10*time.Millisecond); err != nil || v != 1 {
+				t.Fatalf("GetOrLoad = %d, %v, want 1", v, err)
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			if _, ok := cache.Get("k"); ok {
+				t.Error("expected Get to treat the stale entry as missing")
+			}
+
+			// Served stale at once, while the refresh is blocked.
+			for range 3 {
+				v, err := cache.GetOrLoad(t.Context(), "k", loader, time.Minute)
+				if err != nil || v != 1 {
+					t.Fatalf("GetOrLoad = %d, %v, want the stale 1", v, err)
+				}
+			}
+			close(release)
+
+			deadline := time.Now().Add(time.Second)
+			for {
+				if v, ok := cache.Get("k"); ok {
+					if v != 2 {
+						t.Errorf("refreshed value = %d, want 2", v)
+					}
+
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("expected the entry to be refreshed in the background")
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			if n := loads.Load(); n != 2 {
+				t.Errorf("expected concurrent callers to share one refresh, got %d loads", n)
+			}
+		})
+	}
+}
+
+func TestStaleWhileRevalidateGraceOver(t *testing.T) {
+	cache := NewBasic[string, int](t.Context(), WithStaleWhileRevalidate(10*time.Millisecond))
+	cache.Add("k", 1, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	v, err := cache.GetOrLoad(t.Context(), "k", func(context.Context) (int, error) { return 2, nil }, time.Minute)
+	if err != nil || v != 2 {
+		t.Errorf("GetOrLoad = %d, %v, want a synchronous load once the grace is over", v, err)
+	}
+}
+
+func TestStaleWhileRevalidateRetries(t *testing.T) {
+	cache := NewBounded[string, int](t.Context(), 10,
+		WithStaleWhileRevalidate(time.Hour, retry.WithSyncMaxRetries(3), retry.WithSyncRetryDelay(time.Millisecond)))
+	cache.Add("k", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	var attempts atomic.Int32
+	loader := func(context.Context) (int, error) {
+		if attempts.Add(1) < 3 {
+			return 0, errors.New("upstream down")
+		}
+
+		return 2, nil
+	}
+
+	if v, _ := cache.GetOrLoad(t.Context(), "k", loader, time.Minute); v != 1 {
+		t.Errorf("GetOrLoad = %d, want the stale 1", v)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if v, ok := cache.Get("k"); ok && v == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the refresh to succeed after retries, %d attempts", attempts.Load())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStaleWhileRevalidateCleanup(t *testing.T) {
+	cache := NewBasic[string, int](t.Context(), WithCleanUpInterval(5*time.Millisecond),
+		WithStaleWhileRevalidate(time.Hour))
+	cache.Add("k", 1, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.(*BasicCache[string, int]).stale("k"); !ok {
+		t.Error("expected the cleanup to keep entries within the grace period")
+	}
+}
+
+func TestStaleWhileRevalidateSetNX(t *testing.T) {
+	caches := map[string]func(clock Clock) Cache[string, int]{
+		"basic": func(clock Clock) Cache[string, int] {
+			return NewBasic[string, int](t.Context(), WithClock(clock), WithStaleWhileRevalidate(time.Hour))
+		},
+		"bounded": func(clock Clock) Cache[string, int] {
+			return NewBounded[string, int](t.Context(), 10, WithClock(clock), WithStaleWhileRevalidate(time.Hour))
+		},
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Now()}
+			cache := newCache(clock)
+			cache.Add("lock", 1, time.Second)
+			cache.Add("counter", 1, time.Second)
+			clock.advance(2 * time.Second)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+
+				if !cache.SetNX("lock", 2, time.Minute) {
+					t.Error("expected SetNX to replace the stale item")
+				}
+				if v := cache.Upsert("counter", func(old int, _ bool) int { return old + 10 }, 0); v != 10 {
+					t.Errorf("Upsert = %d, want 10: the stale item doesn't exist", v)
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("SetNX and Upsert spin on a stale item")
+			}
+
+			if v, ok := cache.Get("lock"); !ok || v != 2 {
+				t.Errorf("Get = %d, %v, want 2", v, ok)
+			}
+		})
+	}
+}
+
+func TestStaleWhileRevalidateExists(t *testing.T) {
+	caches := map[string]func(clock Clock) Cache[string, int]{
+		"basic": func(clock Clock) Cache[string, int] {
+			return NewBasic[string, int](t.Context(), WithClock(clock), WithStaleWhileRevalidate(time.Hour))
+		},
+		"bounded": func(clock Clock) Cache[string, int] {
+			return NewBounded[string, int](t.Context(), 10, WithClock(clock), WithStaleWhileRevalidate(time.Hour))
+		},
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Now()}
+			cache := newCache(clock)
+			cache.Add("stale", 1, time.Second)
+			cache.Add("fresh", 1, time.Hour)
+			clock.advance(2 * time.Second)
+
+			if _, ok := cache.Get("stale"); ok {
+				t.Fatal("expected Get to miss the stale item")
+			}
+			if cache.Exists("stale") {
+				t.Error("expected Exists to agree with Get on the stale item")
+			}
+			if !cache.Exists("fresh") {
+				t.Error("expected the fresh item to exist")
+			}
+			if keys := cache.Keys(); len(keys) != 1 || keys[0] != "fresh" {
+				t.Errorf("Keys = %v, want [fresh]", keys)
+			}
+		})
+	}
+}
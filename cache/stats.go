@@ -0,0 +1,93 @@
+package cache
+
+import "sync/atomic"
+
+// StatsEvent identifies what a stats hook is notified of.
+type StatsEvent int
+
+const (
+	// Hit is a Get that found the key.
+	Hit StatsEvent = iota
+	// Miss is a Get that did not find the key, or found it expired.
+	Miss
+	// Eviction is an entry removed to make room for another.
+	Eviction
+	// Expiration is an expired entry removed on access or by the cleanup.
+	Expiration
+)
+
+func (e StatsEvent) String() string {
+	switch e {
+	case Hit:
+		return "hit"
+	case Miss:
+		return "miss"
+	case Eviction:
+		return "eviction"
+	case Expiration:
+		return "expiration"
+	default:
+		return "unknown"
+	}
+}
+
+// Stats is a snapshot of the counters of a cache.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Expired   uint64
+}
+
+// HitRatio returns the share of Get calls that found the key, or 0 before any call.
+func (s Stats) HitRatio() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(total)
+}
+
+// WithStatsHook sets a function called on every counted event,
+// for exporting metrics as they happen. It must be fast and must not use the cache.
+func WithStatsHook(hook func(event StatsEvent)) Option {
+	return func(cfg *options) {
+		cfg.statsHook = hook
+	}
+}
+
+// stats maintains the counters of a cache atomically.
+type stats struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	expired   atomic.Uint64
+	hook      func(event StatsEvent)
+}
+
+func (s *stats) record(event StatsEvent) {
+	switch event {
+	case Hit:
+		s.hits.Add(1)
+	case Miss:
+		s.misses.Add(1)
+	case Eviction:
+		s.evictions.Add(1)
+	case Expiration:
+		s.expired.Add(1)
+	}
+
+	if s.hook != nil {
+		s.hook(event)
+	}
+}
+
+func (s *stats) snapshot() Stats {
+	return Stats{
+		Hits:      s.hits.Load(),
+		Misses:    s.misses.Load(),
+		Evictions: s.evictions.Load(),
+		Expired:   s.expired.Load(),
+	}
+}
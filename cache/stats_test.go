@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	var mu sync.Mutex
+	events := make(map[StatsEvent]int)
+	hook := WithStatsHook(func(event StatsEvent) {
+		mu.Lock()
+		events[event]++
+		mu.Unlock()
+	})
+
+	caches := map[string]Cache[string, int]{
+		"basic":   NewBasic[string, int](t.Context(), hook),
+		"bounded": NewBounded[string, int](t.Context(), 2, hook),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			clear(events)
+
+			cache.Add("a", 1, 0)
+			cache.Add("short", 2, time.Millisecond)
+			cache.Get("a")
+			cache.Get("a")
+			cache.Get("missing")
+
+			time.Sleep(5 * time.Millisecond)
+			cache.Get("short")
+
+			want := Stats{Hits: 2, Misses: 2, Expired: 1}
+			if got := cache.Stats(); got != want {
+				t.Errorf("Stats() = %+v, want %+v", got, want)
+			}
+			if ratio := cache.Stats().HitRatio(); ratio != 0.5 {
+				t.Errorf("HitRatio() = %v, want 0.5", ratio)
+			}
+			if events[Hit] != 2 || events[Miss] != 2 || events[Expiration] != 1 {
+				t.Errorf("hook events = %v", events)
+			}
+		})
+	}
+}
+
+func TestStatsEvictions(t *testing.T) {
+	cache := NewBounded[int, int](t.Context(), 2)
+
+	for i := range 5 {
+		cache.Add(i, i, 0)
+	}
+
+	if got := cache.Stats().Evictions; got != 3 {
+		t.Errorf("Evictions = %d, want 3", got)
+	}
+}
+
+func TestStatsGetOrLoad(t *testing.T) {
+	cache := NewBasic[string, int](t.Context())
+
+	for range 3 {
+		_, _ = cache.GetOrLoad(t.Context(), "key", func(context.Context) (int, error) { return 1, nil }, 0)
+	}
+
+	if got, want := cache.Stats(), (Stats{Hits: 2, Misses: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type addOptions struct {
+	tags []string
+}
+
+// AddOption configures a single Add.
+type AddOption func(*addOptions)
+
+// WithTags attaches tags to the added item, so InvalidateTag can invalidate it
+// together with every other item carrying one of the tags, such as all the views
+// derived from the same entity:
+//
+//	c.Add("user:42:profile", profile, time.Hour, cache.WithTags("user:42"))
+//	c.Add("user:42:orders", orders, time.Hour, cache.WithTags("user:42"))
+//	c.InvalidateTag("user:42") // both are gone
+//
+// The tags are replaced by the next Add of the key, and kept by Update and Upsert.
+func WithTags(tags ...string) AddOption {
+	return func(opt *addOptions) {
+		opt.tags = append(opt.tags, tags...)
+	}
+}
+
+// addTags returns the tags set by opts.
+func addTags(opts []AddOption) []string {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	var cfg addOptions
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg.tags
+}
+
+// tagStamp is a tag of an item with the generation of the tags when the item was added.
+type tagStamp struct {
+	tag        string
+	generation uint64
+}
+
+// tagGenerations records the invalidations of tags. Each invalidation gives its tag
+// the next generation of a counter, and items are stamped with the current generation
+// when added, so an item is invalidated once one of its tags has a later generation,
+// and invalidating a tag is a single store whatever the number of items carrying it.
+//
+// The clean-up prunes the invalidations once the items they invalidated are removed,
+// so tags used once, such as per request or per user, don't accumulate.
+type tagGenerations struct {
+	counter     atomic.Uint64
+	generations sync.Map // tag -> uint64
+}
+
+func (g *tagGenerations) current(tag string) uint64 {
+	if generation, ok := g.generations.Load(tag); ok {
+		return generation.(uint64)
+	}
+
+	return 0
+}
+
+// invalidate invalidates every item carrying tag.
+func (g *tagGenerations) invalidate(tag string) {
+	generation := g.counter.Add(1)
+	for {
+		previous, loaded := g.generations.LoadOrStore(tag, generation)
+		if !loaded || previous.(uint64) >= generation || g.generations.CompareAndSwap(tag, previous, generation) {
+			return
+		}
+	}
+}
+
+// stamp returns the tags with the current generation, to be stored with an item.
+func (g *tagGenerations) stamp(tags []string) []tagStamp {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	generation := g.counter.Load()
+	stamps := make([]tagStamp, len(tags))
+	for i, tag := range tags {
+		stamps[i] = tagStamp{tag: tag, generation: generation}
+	}
+
+	return stamps
+}
+
+// valid reports whether none of the tags of an item has been invalidated since it was added.
+func (g *tagGenerations) valid(stamps []tagStamp) bool {
+	for _, stamp := range stamps {
+		if g.current(stamp.tag) > stamp.generation {
+			return false
+		}
+	}
+
+	return true
+}
+
+// horizon returns the generation of the last invalidation, read by a clean-up
+// before it removes the invalidated items.
+func (g *tagGenerations) horizon() uint64 {
+	return g.counter.Load()
+}
+
+// prune forgets the invalidations up to horizon, once the clean-up has removed
+// the items they invalidated: the remaining items were added after them.
+func (g *tagGenerations) prune(horizon uint64) {
+	g.generations.Range(func(tag, generation any) bool {
+		if generation.(uint64) <= horizon {
+			g.generations.CompareAndDelete(tag, generation)
+		}
+
+		return true
+	})
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func testInvalidateTag(t *testing.T, cache Cache[string, int]) {
+	t.Helper()
+
+	cache.Add("profile", 1, 0, WithTags("user:42"))
+	cache.Add("orders", 2, 0, WithTags("user:42", "orders"))
+	cache.Add("other", 3, 0, WithTags("user:7"))
+	cache.Add("plain", 4, 0)
+
+	cache.InvalidateTag("user:42")
+
+	for _, key := range []string{"profile", "orders"} {
+		if _, ok := cache.Get(key); ok {
+			t.Errorf("expected %s to be invalidated", key)
+		}
+		if cache.Exists(key) {
+			t.Errorf("expected %s not to exist", key)
+		}
+	}
+	for _, key := range []string{"other", "plain"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+
+	keys := cache.Keys()
+	slices.Sort(keys)
+	if want := []string{"other", "plain"}; !slices.Equal(keys, want) {
+		t.Errorf("Keys = %v, want %v", keys, want)
+	}
+	if got := cache.Len(); got != 2 {
+		t.Errorf("Len = %d, want 2", got)
+	}
+
+	cache.Add("profile", 5, 0, WithTags("user:42"))
+	if got, ok := cache.Get("profile"); !ok || got != 5 {
+		t.Errorf("Get(profile) = %d, %v, want the item added after the invalidation", got, ok)
+	}
+
+	cache.InvalidateTag("user:42")
+	if cache.Exists("profile") {
+		t.Error("expected a second invalidation to invalidate the new item")
+	}
+}
+
+func TestBasicInvalidateTag(t *testing.T) {
+	testInvalidateTag(t, NewBasic[string, int](t.Context()))
+}
+
+func TestBoundedInvalidateTag(t *testing.T) {
+	testInvalidateTag(t, NewBounded[string, int](t.Context(), 10))
+}
+
+func TestRedisInvalidateTag(t *testing.T) {
+	_, client := newTestRedis(t)
+	testInvalidateTag(t, NewRedis[string, int](client, JSONCodec[string, int]{}))
+}
+
+func TestShardedInvalidateTag(t *testing.T) {
+	testInvalidateTag(t, NewSharded[string, int](t.Context(), 4))
+}
+
+func TestTieredInvalidateTag(t *testing.T) {
+	local := NewBasic[string, int](t.Context())
+	remote := NewBasic[string, int](t.Context())
+	testInvalidateTag(t, NewTiered(local, remote))
+}
+
+func TestNamespaceInvalidateTag(t *testing.T) {
+	shared := NewBasic[string, int](t.Context())
+	users := Namespace[int](shared, "users:")
+	orders := Namespace[int](shared, "orders:")
+
+	users.Add("a", 1, 0, WithTags("tenant"))
+	orders.Add("a", 2, 0, WithTags("tenant"))
+
+	users.InvalidateTag("tenant")
+	if users.Exists("a") {
+		t.Error("expected the tagged item of the namespace to be invalidated")
+	}
+	if !orders.Exists("a") {
+		t.Error("expected the same tag of another namespace to be kept")
+	}
+
+	testInvalidateTag(t, Namespace[int](NewBasic[string, int](t.Context()), "ns:"))
+}
+
+func TestUpdateKeepsTags(t *testing.T) {
+	cache := NewBasic[string, int](t.Context())
+
+	cache.Add("a", 1, 0, WithTags("t"))
+	cache.Update("a", 2, 0)
+	cache.Upsert("a", func(old int, _ bool) int { return old + 1 }, 0)
+
+	cache.InvalidateTag("t")
+	if cache.Exists("a") {
+		t.Error("expected Update and Upsert to keep the tags of the item")
+	}
+}
+
+func tagCount(tags *tagGenerations) int {
+	count := 0
+	tags.generations.Range(func(_, _ any) bool {
+		count++
+
+		return true
+	})
+
+	return count
+}
+
+func TestInvalidateTagPrunes(t *testing.T) {
+	basic := NewBasic[string, int](t.Context(), WithCleanUpInterval(0)).(*BasicCache[string, int])
+	bounded := NewBounded[string, int](t.Context(), 10, WithCleanUpInterval(0)).(*BoundedCache[string, int])
+	caches := map[string]struct {
+		cache Cache[string, int]
+		tags  *tagGenerations
+	}{
+		"basic":   {basic, &basic.tags},
+		"bounded": {bounded, &bounded.tags},
+	}
+
+	for name, tc := range caches {
+		t.Run(name, func(t *testing.T) {
+			cache := tc.cache
+			cache.Add("old", 1, 0, WithTags("request:1", "user:42"))
+			for i := range 100 {
+				cache.InvalidateTag(fmt.Sprintf("request:%d", i))
+			}
+			cache.Add("new", 2, 0, WithTags("user:42"))
+
+			cache.CleanupNow()
+			if got := tagCount(tc.tags); got != 0 {
+				t.Errorf("expected the invalidations to be pruned, %d kept", got)
+			}
+			if cache.Exists("old") {
+				t.Error("expected the invalidated item to stay removed")
+			}
+			if !cache.Exists("new") {
+				t.Error("expected the item added after the invalidations to be kept")
+			}
+
+			cache.InvalidateTag("user:42")
+			if cache.Exists("new") {
+				t.Error("expected the tag to be invalidated after the pruning")
+			}
+		})
+	}
+}
+
+func TestRedisTagExpiry(t *testing.T) {
+	server, client := newTestRedis(t)
+	cache := NewRedis[string, int](client, JSONCodec[string, int]{})
+	tagKey := cache.(*RedisCache[string, int]).tagKey
+
+	cache.Add("a", 1, time.Minute, WithTags("t"))
+	cache.Add("b", 2, time.Hour, WithTags("t"))
+	cache.Add("c", 3, time.Second, WithTags("t"))
+	if ttl := server.TTL(tagKey("t")); ttl != time.Hour {
+		t.Errorf("expected the tag set to expire with its last key, got %v", ttl)
+	}
+
+	cache.Add("d", 4, 0, WithTags("t"))
+	cache.Add("e", 5, time.Minute, WithTags("t"))
+	if ttl := server.TTL(tagKey("t")); ttl != 0 {
+		t.Errorf("expected the tag set of a key without expiry to be kept, got %v", ttl)
+	}
+
+	cache.Add("f", 6, time.Minute, WithTags("once"))
+	server.FastForward(2 * time.Minute)
+	if server.Exists(tagKey("once")) {
+		t.Error("expected the tag set to expire with its keys")
+	}
+
+	sliding := NewRedis[string, int](client, JSONCodec[string, int]{}, WithSlidingTTL(time.Minute))
+	sliding.Add("g", 7, time.Minute, WithTags("sliding"))
+	if ttl := server.TTL(tagKey("sliding")); ttl != 0 {
+		t.Errorf("expected the tag set of a sliding cache to be kept, got %v", ttl)
+	}
+}
@@ -0,0 +1,513 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Invalidation tells the replicas of a tiered cache to drop local items.
+type Invalidation[K any] struct {
+	// Origin identifies the cache that sent it, which ignores its own invalidations.
+	Origin string `json:"origin"`
+	// Keys are the keys to drop.
+	Keys []K `json:"keys,omitempty"`
+	// All drops every local item, after a Clear.
+	All bool `json:"all,omitempty"`
+}
+
+// Invalidator carries invalidations between the replicas of a tiered cache.
+// Every receiver must get every invalidation sent by any replica.
+//
+// A pipeline.Pipeline[cache.Invalidation[K]] is an Invalidator for replicas
+// in one process; RedisInvalidator connects replicas through Redis pub/sub.
+type Invalidator[K any] interface {
+	// Send publishes msg to the receivers of every replica.
+	Send(msg Invalidation[K])
+	// RegisterReceiver registers a function called with each published invalidation.
+	RegisterReceiver(receiver func(msg Invalidation[K]))
+}
+
+// WithLocalTTL caps how long a tiered cache keeps an item in its local tier,
+// which bounds how stale a local item can be if an invalidation is lost.
+// Defaults to one minute; zero keeps local items as long as the remote ones.
+// Used by NewTiered only.
+func WithLocalTTL(d time.Duration) Option {
+	return func(cfg *options) {
+		cfg.localTTL = d
+	}
+}
+
+// WithInvalidator makes a tiered cache tell the other replicas, through invalidator,
+// to drop the local items it writes or deletes, and drop the items they write or delete.
+// Its key type must match the one of the cache, or NewTiered panics.
+// Used by NewTiered only.
+func WithInvalidator[K any](invalidator Invalidator[K]) Option {
+	return func(cfg *options) {
+		cfg.invalidator = invalidator
+	}
+}
+
+func invalidatorOf[K any](cfg *options) Invalidator[K] {
+	if cfg.invalidator == nil {
+		return nil
+	}
+
+	invalidator, ok := cfg.invalidator.(Invalidator[K])
+	if !ok {
+		panic(fmt.Sprintf("cache: invalidator %T does not match the key type %T", cfg.invalidator, *new(K)))
+	}
+
+	return invalidator
+}
+
+// TieredCache reads through a fast local cache to a shared remote cache, and writes
+// through both. Local items live at most the local TTL, and are dropped on every
+// replica when another one changes them, if an invalidator is set.
+//
+// A replica filling its local tier from the remote one may race with an invalidation
+// of the same key, and keep the old item until its local TTL ends.
+type TieredCache[K comparable, V any] struct {
+	local       Cache[K, V]
+	remote      Cache[K, V]
+	localTTL    time.Duration
+	invalidator Invalidator[K]
+	origin      string
+	loads       loadGroup[V]
+	stats       stats
+}
+
+// NewTiered creates a cache using local as a first level in front of remote,
+// for instance a bounded in-memory cache in front of a Redis cache.
+// WithLocalTTL, WithInvalidator and WithStatsHook apply.
+// Stats counts a hit when either level has the item, and the evictions
+// and expirations of the local level.
+func NewTiered[K comparable, V any](local, remote Cache[K, V], opts ...Option) Cache[K, V] {
+	cfg := defaultConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cache := &TieredCache[K, V]{
+		local:       local,
+		remote:      remote,
+		localTTL:    cfg.localTTL,
+		invalidator: invalidatorOf[K](&cfg),
+		origin:      rand.Text(),
+	}
+	cache.stats.hook = cfg.statsHook
+
+	if cache.invalidator != nil {
+		cache.invalidator.RegisterReceiver(cache.invalidated)
+	}
+
+	return cache
+}
+
+// Add stores the item in both levels.
+//
+//   - expiration: 0 for disable expire cache
+func (c *TieredCache[K, V]) Add(key K, value V, expiration time.Duration, opts ...AddOption) bool {
+	defer c.invalidate(key)
+
+	if !c.remote.Add(key, value, expiration, opts...) {
+		c.local.Delete(key)
+
+		return false
+	}
+
+	return c.local.Add(key, value, c.localExpiration(expiration), opts...)
+}
+
+// Get returns the local item, or the remote one, which is then kept locally.
+func (c *TieredCache[K, V]) Get(key K) (V, bool) {
+	value, ok := c.peek(key)
+	if ok {
+		c.stats.record(Hit)
+	} else {
+		c.stats.record(Miss)
+	}
+
+	return value, ok
+}
+
+// peek returns the item without counting a hit or a miss.
+func (c *TieredCache[K, V]) peek(key K) (V, bool) {
+	if value, ok := c.local.Get(key); ok {
+		return value, true
+	}
+
+	value, expiry, ok := c.remote.GetWithExpiry(key)
+	if ok {
+		c.keep(key, value, expiry)
+	}
+
+	return value, ok
+}
+
+// keep stores locally an item read from the remote level, with the local TTL
+// but expiring no later than the remote item. An expired item is not kept.
+func (c *TieredCache[K, V]) keep(key K, value V, expiry time.Time) {
+	var expiration time.Duration
+	if !expiry.IsZero() {
+		expiration = time.Until(expiry)
+		if expiration <= 0 {
+			return
+		}
+	}
+
+	c.local.Add(key, value, c.localExpiration(expiration))
+}
+
+// GetWithExpiry returns the remote item with its expiry, the zero time if it never
+// expires, and keeps it locally. It always reads the remote level, since the local
+// items expire with the local TTL.
+func (c *TieredCache[K, V]) GetWithExpiry(key K) (V, time.Time, bool) {
+	value, expiry, ok := c.remote.GetWithExpiry(key)
+	if !ok {
+		c.stats.record(Miss)
+
+		return value, expiry, false
+	}
+	c.stats.record(Hit)
+	c.keep(key, value, expiry)
+
+	return value, expiry, true
+}
+
+// SetNX adds the item to both levels only if the key is missing from the remote level,
+// and reports whether it did.
+//
+//   - expiration: 0 for disable expire cache
+func (c *TieredCache[K, V]) SetNX(key K, value V, expiration time.Duration) bool {
+	if !c.remote.SetNX(key, value, expiration) {
+		return false
+	}
+
+	c.local.Add(key, value, c.localExpiration(expiration))
+	c.invalidate(key)
+
+	return true
+}
+
+// Update updates the remote item and drops the local one, since its new expiry is unknown.
+func (c *TieredCache[K, V]) Update(key K, newValue V, expiration time.Duration) bool {
+	defer c.invalidate(key)
+
+	ok := c.remote.Update(key, newValue, expiration)
+	c.local.Delete(key)
+
+	return ok
+}
+
+func (c *TieredCache[K, V]) Exists(key K) bool {
+	return c.local.Exists(key) || c.remote.Exists(key)
+}
+
+// Keys returns the keys of the remote level.
+func (c *TieredCache[K, V]) Keys() []K {
+	return c.remote.Keys()
+}
+
+// Range calls fn for each item of the remote level until fn returns false.
+func (c *TieredCache[K, V]) Range(fn func(key K, value V) bool) {
+	c.remote.Range(fn)
+}
+
+// Len returns the number of items of the remote level.
+func (c *TieredCache[K, V]) Len() int {
+	return c.remote.Len()
+}
+
+// Clear deletes every item of both levels, and of the local level of the other replicas.
+func (c *TieredCache[K, V]) Clear() {
+	c.remote.Clear()
+	c.local.Clear()
+
+	if c.invalidator != nil {
+		c.invalidator.Send(Invalidation[K]{Origin: c.origin, All: true})
+	}
+}
+
+// InvalidateTag invalidates the remote items tagged with tag, and drops every local item,
+// here and on the other replicas, since the items read from the remote level are kept
+// locally without their tags.
+func (c *TieredCache[K, V]) InvalidateTag(tag string) {
+	c.remote.InvalidateTag(tag)
+	c.local.Clear()
+
+	if c.invalidator != nil {
+		c.invalidator.Send(Invalidation[K]{Origin: c.origin, All: true})
+	}
+}
+
+func (c *TieredCache[K, V]) Delete(key K) bool {
+	defer c.invalidate(key)
+
+	ok := c.remote.Delete(key)
+	c.local.Delete(key)
+
+	return ok
+}
+
+// Upsert atomically replaces the remote item with fn(old, exists) and returns
+// the new value. The local item is dropped, and read again on the next Get.
+// ttl sets the expiry; zero keeps the expiry of an existing item.
+func (c *TieredCache[K, V]) Upsert(key K, fn func(old V, exists bool) V, ttl time.Duration) V {
+	value, _ := c.modify(key, alwaysWrite(fn), ttl)
+
+	return value
+}
+
+func (c *TieredCache[K, V]) modify(key K, fn func(old V, exists bool) (V, bool), ttl time.Duration) (V, bool) {
+	value, written := modify(c.remote, key, fn, ttl)
+	if written {
+		c.local.Delete(key)
+		c.invalidate(key)
+	}
+
+	return value, written
+}
+
+// GetMulti returns the items found among keys, reading from the remote level
+// only the keys missing locally.
+func (c *TieredCache[K, V]) GetMulti(keys []K) map[K]V {
+	items := c.local.GetMulti(keys)
+
+	missing := make([]K, 0, len(keys)-len(items))
+	for _, key := range keys {
+		if _, ok := items[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		fetched, expiries := c.remoteGetMulti(missing)
+		for key, value := range fetched {
+			c.keep(key, value, expiries[key])
+			items[key] = value
+		}
+	}
+
+	for _, key := range keys {
+		if _, ok := items[key]; ok {
+			c.stats.record(Hit)
+		} else {
+			c.stats.record(Miss)
+		}
+	}
+
+	return items
+}
+
+// expiryMultiGetter is implemented by the caches reading several items with their
+// expiries at once, such as RedisCache in a single round trip.
+type expiryMultiGetter[K comparable, V any] interface {
+	getMultiWithExpiry(keys []K) (map[K]V, map[K]time.Time)
+}
+
+// remoteGetMulti returns the remote items found among keys, with their expiries.
+func (c *TieredCache[K, V]) remoteGetMulti(keys []K) (map[K]V, map[K]time.Time) {
+	if remote, ok := c.remote.(expiryMultiGetter[K, V]); ok {
+		return remote.getMultiWithExpiry(keys)
+	}
+
+	items := make(map[K]V, len(keys))
+	expiries := make(map[K]time.Time, len(keys))
+	for _, key := range keys {
+		if value, expiry, ok := c.remote.GetWithExpiry(key); ok {
+			items[key] = value
+			expiries[key] = expiry
+		}
+	}
+
+	return items, expiries
+}
+
+// AddMulti stores all the items with the same expiration in both levels.
+func (c *TieredCache[K, V]) AddMulti(items map[K]V, expiration time.Duration) bool {
+	keys := make([]K, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	defer c.invalidate(keys...)
+
+	if !c.remote.AddMulti(items, expiration) {
+		c.local.DeleteMulti(keys)
+
+		return false
+	}
+
+	return c.local.AddMulti(items, c.localExpiration(expiration))
+}
+
+// DeleteMulti deletes the items of keys from both levels.
+func (c *TieredCache[K, V]) DeleteMulti(keys []K) bool {
+	defer c.invalidate(keys...)
+
+	ok := c.remote.DeleteMulti(keys)
+	c.local.DeleteMulti(keys)
+
+	return ok
+}
+
+// GetOrLoad returns the cached item, or loads it with loader and stores it with ttl.
+// Concurrent calls missing the same key share a single load within this replica.
+func (c *TieredCache[K, V]) GetOrLoad(ctx context.Context, key K, loader Loader[V], ttl time.Duration) (V, error) {
+	return getOrLoad(ctx, c, c.peek, &c.loads, key, loader, ttl)
+}
+
+// Stats returns the hits and misses of the cache, and the evictions and
+// expirations of its local level.
+func (c *TieredCache[K, V]) Stats() Stats {
+	stats := c.stats.snapshot()
+	local := c.local.Stats()
+	stats.Evictions = local.Evictions
+	stats.Expired = local.Expired
+
+	return stats
+}
+
+// CleanupNow removes the expired items of both levels.
+func (c *TieredCache[K, V]) CleanupNow() {
+	c.local.CleanupNow()
+	c.remote.CleanupNow()
+}
+
+// SaveSnapshot writes the items of the remote level to w.
+func (c *TieredCache[K, V]) SaveSnapshot(w io.Writer) error {
+	return c.remote.SaveSnapshot(w)
+}
+
+// LoadSnapshot adds the items of a snapshot to the remote level,
+// and drops the local items, here and on the other replicas.
+func (c *TieredCache[K, V]) LoadSnapshot(r io.Reader) error {
+	err := c.remote.LoadSnapshot(r)
+	c.local.Clear()
+
+	if c.invalidator != nil {
+		c.invalidator.Send(Invalidation[K]{Origin: c.origin, All: true})
+	}
+
+	return err
+}
+
+// localExpiration returns the expiration of an item added locally, capped by the local TTL.
+func (c *TieredCache[K, V]) localExpiration(expiration time.Duration) time.Duration {
+	if c.localTTL > 0 && (expiration == 0 || expiration > c.localTTL) {
+		return c.localTTL
+	}
+
+	return expiration
+}
+
+// invalidate tells the other replicas to drop their local items of keys.
+func (c *TieredCache[K, V]) invalidate(keys ...K) {
+	if c.invalidator != nil && len(keys) > 0 {
+		c.invalidator.Send(Invalidation[K]{Origin: c.origin, Keys: keys})
+	}
+}
+
+// invalidated drops the local items invalidated by another replica.
+func (c *TieredCache[K, V]) invalidated(msg Invalidation[K]) {
+	if msg.Origin == c.origin {
+		return
+	}
+
+	if msg.All {
+		c.local.Clear()
+
+		return
+	}
+	c.local.DeleteMulti(msg.Keys)
+}
+
+// RedisInvalidator is an Invalidator publishing invalidations on a Redis pub/sub
+// channel, encoded as JSON, so keys must be JSON-encodable.
+// Invalidations published while a replica is disconnected are lost to it,
+// so also bound the local TTL of the tiered caches.
+type RedisInvalidator[K any] struct {
+	ctx     context.Context
+	client  redis.UniversalClient
+	channel string
+	onError func(err error)
+}
+
+// NewRedisInvalidator creates an invalidator publishing on channel.
+// Receivers stop when ctx is done. WithErrorHandler applies.
+func NewRedisInvalidator[K any](ctx context.Context, client redis.UniversalClient, channel string,
+	opts ...Option,
+) *RedisInvalidator[K] {
+	cfg := defaultConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	invalidator := &RedisInvalidator[K]{
+		ctx:     ctx,
+		client:  client,
+		channel: channel,
+		onError: cfg.onError,
+	}
+	if invalidator.onError == nil {
+		invalidator.onError = func(err error) {
+			log.Printf("redis invalidator error: %v", err)
+		}
+	}
+
+	return invalidator
+}
+
+// Send publishes msg on the channel.
+func (i *RedisInvalidator[K]) Send(msg Invalidation[K]) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		i.onError(err)
+
+		return
+	}
+
+	if err := i.client.Publish(i.ctx, i.channel, data).Err(); err != nil {
+		i.onError(err)
+	}
+}
+
+// RegisterReceiver subscribes to the channel and calls receiver with each invalidation,
+// from a goroutine, until the context of the invalidator is done.
+// It returns once the subscription is active.
+func (i *RedisInvalidator[K]) RegisterReceiver(receiver func(msg Invalidation[K])) {
+	sub := i.client.Subscribe(i.ctx, i.channel)
+	if _, err := sub.Receive(i.ctx); err != nil {
+		i.onError(err)
+	}
+
+	go func() {
+		defer func() { _ = sub.Close() }()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-i.ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var msg Invalidation[K]
+				if err := json.Unmarshal([]byte(message.Payload), &msg); err != nil {
+					i.onError(err)
+
+					continue
+				}
+				receiver(msg)
+			}
+		}
+	}()
+}
@@ -0,0 +1,249 @@
+package cache
+
+import (
+	"maps"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBus is an in-process Invalidator delivering every invalidation synchronously.
+type testBus[K any] struct {
+	mu        sync.Mutex
+	receivers []func(msg Invalidation[K])
+}
+
+func (b *testBus[K]) Send(msg Invalidation[K]) {
+	b.mu.Lock()
+	receivers := b.receivers
+	b.mu.Unlock()
+
+	for _, receiver := range receivers {
+		receiver(msg)
+	}
+}
+
+func (b *testBus[K]) RegisterReceiver(receiver func(msg Invalidation[K])) {
+	b.mu.Lock()
+	b.receivers = append(b.receivers, receiver)
+	b.mu.Unlock()
+}
+
+func TestTieredReadThrough(t *testing.T) {
+	local := NewBasic[string, int](t.Context())
+	remote := NewBasic[string, int](t.Context())
+	cache := NewTiered(local, remote)
+
+	remote.Add("a", 1, 0)
+
+	if got, ok := cache.Get("a"); !ok || got != 1 {
+		t.Fatalf("Get(a) = %d, %v, want 1, true", got, ok)
+	}
+	if got, ok := local.Get("a"); !ok || got != 1 {
+		t.Errorf("expected the remote item to be kept locally, got %d, %v", got, ok)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Error("expected a miss for a missing key")
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("stats = %+v, want 1 hit and 1 miss", stats)
+	}
+}
+
+func TestTieredWriteThrough(t *testing.T) {
+	local := NewBasic[string, int](t.Context())
+	remote := NewBasic[string, int](t.Context())
+	cache := NewTiered(local, remote)
+
+	cache.Add("a", 1, 0)
+	if !local.Exists("a") || !remote.Exists("a") {
+		t.Fatal("expected Add to write both levels")
+	}
+
+	if !cache.Update("a", 2, 0) {
+		t.Fatal("expected Update to succeed")
+	}
+	if local.Exists("a") {
+		t.Error("expected Update to drop the local item")
+	}
+	if got, _ := cache.Get("a"); got != 2 {
+		t.Errorf("Get(a) = %d, want 2", got)
+	}
+
+	if got := cache.Upsert("a", func(old int, _ bool) int { return old + 1 }, 0); got != 3 {
+		t.Errorf("Upsert = %d, want 3", got)
+	}
+	if got, _ := remote.Get("a"); got != 3 {
+		t.Errorf("remote a = %d, want 3", got)
+	}
+
+	cache.Delete("a")
+	if local.Exists("a") || remote.Exists("a") {
+		t.Error("expected Delete to delete both levels")
+	}
+}
+
+func TestTieredLocalTTL(t *testing.T) {
+	local := NewBasic[string, int](t.Context())
+	remote := NewBasic[string, int](t.Context())
+	cache := NewTiered(local, remote, WithLocalTTL(20*time.Millisecond))
+
+	cache.Add("a", 1, 0)
+	remote.Add("a", 2, 0) // changed behind the back of the tiered cache
+
+	if got, _ := cache.Get("a"); got != 1 {
+		t.Errorf("Get(a) = %d, want the local 1", got)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	if got, _ := cache.Get("a"); got != 2 {
+		t.Errorf("Get(a) = %d, want the remote 2 once the local item expired", got)
+	}
+}
+
+func TestTieredRemoteExpiry(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"default local TTL": nil,
+		"no local TTL":      {WithLocalTTL(0)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			local := NewBasic[string, int](t.Context())
+			remote := NewBasic[string, int](t.Context())
+			cache := NewTiered(local, remote, opts...)
+
+			remote.Add("a", 1, 50*time.Millisecond)
+			remote.Add("b", 2, 50*time.Millisecond)
+			if _, ok := cache.Get("a"); !ok {
+				t.Fatal("expected the remote item")
+			}
+			if got := cache.GetMulti([]string{"b"}); len(got) != 1 {
+				t.Fatalf("GetMulti = %v, want the remote item", got)
+			}
+
+			time.Sleep(80 * time.Millisecond)
+
+			if _, ok := cache.Get("a"); ok {
+				t.Error("expected Get to miss once the remote item expired")
+			}
+			if got := cache.GetMulti([]string{"b"}); len(got) != 0 {
+				t.Errorf("GetMulti = %v, want no item once the remote item expired", got)
+			}
+		})
+	}
+}
+
+func TestTieredRedisRemoteExpiry(t *testing.T) {
+	_, client := newTestRedis(t)
+	local := NewBasic[string, int](t.Context())
+	remote := NewRedis[string, int](client, JSONCodec[string, int]{})
+	cache := NewTiered(local, remote)
+
+	remote.Add("a", 1, 10*time.Second)
+	if got := cache.GetMulti([]string{"a"}); len(got) != 1 {
+		t.Fatalf("GetMulti = %v, want the remote item", got)
+	}
+
+	_, expiry, ok := local.GetWithExpiry("a")
+	if !ok || expiry.IsZero() || time.Until(expiry) > 10*time.Second {
+		t.Errorf("expected the local item to expire with the remote one, got %v, %v", expiry, ok)
+	}
+}
+
+func TestTieredInvalidation(t *testing.T) {
+	remote := NewBasic[string, int](t.Context())
+	bus := &testBus[string]{}
+
+	localA := NewBasic[string, int](t.Context())
+	localB := NewBasic[string, int](t.Context())
+	replicaA := NewTiered(localA, remote, WithInvalidator[string](bus))
+	replicaB := NewTiered(localB, remote, WithInvalidator[string](bus))
+
+	replicaA.Add("a", 1, 0)
+	if got, _ := replicaB.Get("a"); got != 1 {
+		t.Fatalf("replica B Get(a) = %d, want 1", got)
+	}
+
+	replicaA.Add("a", 2, 0)
+	if localB.Exists("a") {
+		t.Error("expected replica B to drop its local item")
+	}
+	if !localA.Exists("a") {
+		t.Error("expected replica A to ignore its own invalidation")
+	}
+	if got, _ := replicaB.Get("a"); got != 2 {
+		t.Errorf("replica B Get(a) = %d, want 2", got)
+	}
+
+	replicaB.GetMulti([]string{"a"})
+	replicaA.Clear()
+	if localB.Len() != 0 {
+		t.Error("expected Clear to clear the local level of replica B")
+	}
+}
+
+func TestTieredMulti(t *testing.T) {
+	local := NewBasic[string, int](t.Context())
+	remote := NewBasic[string, int](t.Context())
+	cache := NewTiered(local, remote)
+
+	local.Add("a", 1, 0)
+	remote.Add("b", 2, 0)
+
+	got := cache.GetMulti([]string{"a", "b", "c"})
+	if want := map[string]int{"a": 1, "b": 2}; !maps.Equal(got, want) {
+		t.Errorf("GetMulti = %v, want %v", got, want)
+	}
+	if !local.Exists("b") {
+		t.Error("expected the remote items to be kept locally")
+	}
+
+	cache.AddMulti(map[string]int{"c": 3, "d": 4}, 0)
+	if !local.Exists("c") || !remote.Exists("d") {
+		t.Error("expected AddMulti to write both levels")
+	}
+
+	cache.DeleteMulti([]string{"b", "c"})
+	if local.Exists("b") || remote.Exists("c") {
+		t.Error("expected DeleteMulti to delete both levels")
+	}
+}
+
+func TestRedisInvalidator(t *testing.T) {
+	_, client := newTestRedis(t)
+	remote := NewRedis[string, int](client, JSONCodec[string, int]{})
+
+	localA := NewBasic[string, int](t.Context())
+	localB := NewBasic[string, int](t.Context())
+	replicaA := NewTiered(localA, remote,
+		WithInvalidator[string](NewRedisInvalidator[string](t.Context(), client, "invalidations")))
+	replicaB := NewTiered(localB, remote,
+		WithInvalidator[string](NewRedisInvalidator[string](t.Context(), client, "invalidations")))
+
+	replicaA.Add("a", 1, 0)
+	replicaB.Get("a")
+
+	replicaA.Delete("a")
+
+	deadline := time.Now().Add(time.Second)
+	for localB.Exists("a") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected replica B to drop its local item")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestInvalidatorKeyMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected an invalidator of the wrong key type to panic")
+		}
+	}()
+
+	NewTiered(NewBasic[string, int](t.Context()), NewBasic[string, int](t.Context()),
+		WithInvalidator[int](&testBus[int]{}))
+}
@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func increment(old int, _ bool) int {
+	return old + 1
+}
+
+func TestUpsert(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	caches := map[string]Cache[string, int]{
+		"basic":     NewBasic[string, int](t.Context()),
+		"bounded":   NewBounded[string, int](t.Context(), 10),
+		"redis":     NewRedis[string, int](client, JSONCodec[string, int]{}),
+		"namespace": Namespace(NewBasic[string, int](t.Context()), "tenant:"),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			var seen []bool
+			fn := func(old int, exists bool) int {
+				seen = append(seen, exists)
+
+				return old + 10
+			}
+
+			if got := cache.Upsert("key", fn, 0); got != 10 {
+				t.Errorf("first Upsert = %d, want 10", got)
+			}
+			if got := cache.Upsert("key", fn, 0); got != 20 {
+				t.Errorf("second Upsert = %d, want 20", got)
+			}
+			if len(seen) != 2 || seen[0] || !seen[1] {
+				t.Errorf("exists = %v, want [false true]", seen)
+			}
+			if val, ok := cache.Get("key"); !ok || val != 20 {
+				t.Errorf("Get = %d, %v; want 20, true", val, ok)
+			}
+		})
+	}
+}
+
+func TestUpsertConcurrent(t *testing.T) {
+	_, client := newTestRedis(t)
+
+	caches := map[string]Cache[string, int]{
+		"basic":   NewBasic[string, int](t.Context()),
+		"bounded": NewBounded[string, int](t.Context(), 10),
+		"redis":   NewRedis[string, int](client, JSONCodec[string, int]{}),
+	}
+
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			var wg sync.WaitGroup
+			for range 50 {
+				wg.Go(func() {
+					cache.Upsert("counter", increment, 0)
+				})
+			}
+			wg.Wait()
+
+			if val, _ := cache.Get("counter"); val != 50 {
+				t.Errorf("counter = %d, want 50", val)
+			}
+		})
+	}
+}
+
+func TestUpsertTTL(t *testing.T) {
+	cache := NewBounded[string, int](t.Context(), 10)
+
+	cache.Upsert("key", increment, 10*time.Millisecond)
+	// A zero ttl keeps the current expiry.
+	cache.Upsert("key", increment, 0)
+
+	time.Sleep(20 * time.Millisecond)
+	if cache.Exists("key") {
+		t.Error("expected the key to expire")
+	}
+}
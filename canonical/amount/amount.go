@@ -0,0 +1,144 @@
+// Package amount provides the canonical representation of monetary amounts used by ezex services.
+//
+// An Amount is an exact integer number of base units (satoshi, wei...) of a registered coin,
+// so no precision is lost in arithmetic or serialization. Conversions to decimals and
+// rationals are provided for interop; the shopspring subpackage converts to decimal.Decimal.
+package amount
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrCoinMismatch is returned when combining amounts of different coins.
+	ErrCoinMismatch = errors.New("coin mismatch")
+
+	// ErrPrecision is returned when a value has more decimals than the coin.
+	ErrPrecision = errors.New("value exceeds the coin precision")
+)
+
+// Amount is an exact amount of a coin. The zero value is not valid;
+// amounts are created with FromUnits, Parse or the conversion functions.
+// Amounts are immutable.
+type Amount struct {
+	units *big.Int
+	coin  Coin
+}
+
+// FromUnits returns the amount of units base units of the coin.
+func FromUnits(units *big.Int, symbol string) (Amount, error) {
+	coin, err := Lookup(symbol)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	return Amount{units: new(big.Int).Set(units), coin: coin}, nil
+}
+
+// Parse parses a decimal string such as "1.5" as an amount of the coin.
+func Parse(value, symbol string) (Amount, error) {
+	rat, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || strings.ContainsAny(value, "/eE") {
+		return Amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+
+	return FromRat(rat, symbol)
+}
+
+// Units returns a copy of the number of base units.
+func (a Amount) Units() *big.Int {
+	if a.units == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(a.units)
+}
+
+// Coin returns the coin of the amount.
+func (a Amount) Coin() Coin {
+	return a.coin
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (a Amount) Sign() int {
+	if a.units == nil {
+		return 0
+	}
+
+	return a.units.Sign()
+}
+
+// IsZero reports whether the amount is zero.
+func (a Amount) IsZero() bool {
+	return a.Sign() == 0
+}
+
+// Add returns a + b. Both amounts must be of the same coin.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if err := a.sameCoin(b); err != nil {
+		return Amount{}, err
+	}
+
+	return Amount{units: new(big.Int).Add(a.Units(), b.Units()), coin: a.coin}, nil
+}
+
+// Sub returns a - b. Both amounts must be of the same coin.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if err := a.sameCoin(b); err != nil {
+		return Amount{}, err
+	}
+
+	return Amount{units: new(big.Int).Sub(a.Units(), b.Units()), coin: a.coin}, nil
+}
+
+// Cmp compares a and b, returning -1, 0 or +1. Both amounts must be of the same coin.
+func (a Amount) Cmp(b Amount) (int, error) {
+	if err := a.sameCoin(b); err != nil {
+		return 0, err
+	}
+
+	return a.Units().Cmp(b.Units()), nil
+}
+
+func (a Amount) sameCoin(b Amount) error {
+	if a.coin.Symbol != b.coin.Symbol {
+		return fmt.Errorf("%w: %s and %s", ErrCoinMismatch, a.coin.Symbol, b.coin.Symbol)
+	}
+
+	return nil
+}
+
+// Text returns the amount as a decimal string without trailing zeros, such as "1.5".
+func (a Amount) Text() string {
+	units := a.Units()
+	negative := units.Sign() < 0
+	digits := units.Abs(units).String()
+
+	decimals := int(a.coin.Decimals)
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	text := digits
+	if decimals > 0 {
+		whole, frac := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+		text = whole
+		if frac != "" {
+			text += "." + frac
+		}
+	}
+
+	if negative {
+		return "-" + text
+	}
+
+	return text
+}
+
+// String returns the amount followed by its coin, such as "1.5 BTC".
+func (a Amount) String() string {
+	return a.Text() + " " + a.coin.Symbol
+}
@@ -0,0 +1,89 @@
+package amount
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustParse(t *testing.T, value, symbol string) Amount {
+	t.Helper()
+
+	a, err := Parse(value, symbol)
+	require.NoError(t, err)
+
+	return a
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value  string
+		symbol string
+		units  string
+		text   string
+	}{
+		{"1.5", "BTC", "150000000", "1.5"},
+		{"0.00000001", "BTC", "1", "0.00000001"},
+		{"-2", "USDT", "-2000000", "-2"},
+		{"1.000000000000000001", "ETH", "1000000000000000001", "1.000000000000000001"},
+		{"0", "BTC", "0", "0"},
+	}
+
+	for _, tt := range tests {
+		a := mustParse(t, tt.value, tt.symbol)
+		assert.Equal(t, tt.units, a.Units().String(), tt.value)
+		assert.Equal(t, tt.text, a.Text(), tt.value)
+	}
+
+	assert.Equal(t, "1.5 BTC", mustParse(t, "1.50", "btc").String())
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("0.000000001", "BTC")
+	require.ErrorIs(t, err, ErrPrecision)
+
+	_, err = Parse("1", "DOGE")
+	require.ErrorIs(t, err, ErrUnknownCoin)
+
+	for _, value := range []string{"", "abc", "1/2", "1e3"} {
+		_, err = Parse(value, "BTC")
+		require.Error(t, err, value)
+	}
+}
+
+func TestFromUnits(t *testing.T) {
+	units := big.NewInt(42)
+	a, err := FromUnits(units, "BTC")
+	require.NoError(t, err)
+
+	units.SetInt64(7)
+	assert.Equal(t, int64(42), a.Units().Int64(), "FromUnits must copy its input")
+
+	a.Units().SetInt64(7)
+	assert.Equal(t, int64(42), a.Units().Int64(), "Units must return a copy")
+}
+
+func TestArithmetic(t *testing.T) {
+	a := mustParse(t, "1.5", "BTC")
+	b := mustParse(t, "0.25", "BTC")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	assert.Equal(t, "1.75", sum.Text())
+
+	diff, err := b.Sub(a)
+	require.NoError(t, err)
+	assert.Equal(t, "-1.25", diff.Text())
+	assert.Equal(t, -1, diff.Sign())
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cmp)
+
+	_, err = a.Add(mustParse(t, "1", "ETH"))
+	require.ErrorIs(t, err, ErrCoinMismatch)
+
+	assert.True(t, Amount{}.IsZero())
+}
@@ -0,0 +1,92 @@
+package amount
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownCoin is returned for a coin that is not registered.
+	ErrUnknownCoin = errors.New("unknown coin")
+
+	// ErrInvalidCoin is returned when registering a coin without a symbol, with negative decimals,
+	// a negative dust threshold or a minimum unit that is not positive.
+	ErrInvalidCoin = errors.New("invalid coin")
+)
+
+// Coin describes an asset whose amounts are counted in integer base units.
+type Coin struct {
+	// Symbol identifies the coin, such as "BTC". Symbols are case-insensitive.
+	Symbol string
+
+	// Decimals is the number of decimal places of one base unit: 8 for BTC, 18 for ETH.
+	Decimals int32
+
+	// Dust is the amount, in base units, below which an amount is not worth transferring,
+	// such as an output costing more in fees than it carries; nil for none. See Amount.IsDust.
+	Dust *big.Int
+
+	// MinUnit is the smallest amount, in base units, that can be transferred,
+	// amounts being multiples of it; nil for one base unit. See Amount.RoundToMinUnit.
+	MinUnit *big.Int
+}
+
+var registry = struct {
+	sync.RWMutex
+	coins map[string]Coin
+}{
+	coins: map[string]Coin{
+		"BTC":  {Symbol: "BTC", Decimals: 8, Dust: big.NewInt(546)}, // the dust limit of P2PKH outputs
+		"ETH":  {Symbol: "ETH", Decimals: 18},
+		"USDT": {Symbol: "USDT", Decimals: 6},
+		"USDC": {Symbol: "USDC", Decimals: 6},
+	},
+}
+
+// Register adds coin to the registry, replacing the coin with the same symbol.
+// BTC, ETH, USDT and USDC are registered by default.
+func Register(coin Coin) error {
+	symbol := strings.ToUpper(strings.TrimSpace(coin.Symbol))
+	invalidDust := coin.Dust != nil && coin.Dust.Sign() < 0
+	invalidMinUnit := coin.MinUnit != nil && coin.MinUnit.Sign() <= 0
+	if symbol == "" || coin.Decimals < 0 || invalidDust || invalidMinUnit {
+		return fmt.Errorf("%w: %+v", ErrInvalidCoin, coin)
+	}
+	coin.Symbol = symbol
+	coin.Dust = cloneInt(coin.Dust)
+	coin.MinUnit = cloneInt(coin.MinUnit)
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.coins[symbol] = coin
+
+	return nil
+}
+
+// Lookup returns the registered coin with the given symbol.
+func Lookup(symbol string) (Coin, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	coin, ok := registry.coins[strings.ToUpper(strings.TrimSpace(symbol))]
+	if !ok {
+		return Coin{}, fmt.Errorf("%w: %q", ErrUnknownCoin, symbol)
+	}
+	coin.Dust = cloneInt(coin.Dust)
+	coin.MinUnit = cloneInt(coin.MinUnit)
+
+	return coin, nil
+}
+
+// cloneInt returns a copy of n, so the registry doesn't share its thresholds.
+func cloneInt(n *big.Int) *big.Int {
+	if n == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(n)
+}
@@ -0,0 +1,36 @@
+package amount
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	coin, err := Lookup("btc")
+	require.NoError(t, err)
+	assert.Equal(t, Coin{Symbol: "BTC", Decimals: 8, Dust: big.NewInt(546)}, coin)
+
+	coin.Dust.SetInt64(0)
+	coin, err = Lookup("BTC")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(546), coin.Dust, "the registry is not modified through a lookup")
+
+	_, err = Lookup("DOGE")
+	require.ErrorIs(t, err, ErrUnknownCoin)
+}
+
+func TestRegister(t *testing.T) {
+	require.NoError(t, Register(Coin{Symbol: " tst ", Decimals: 3}))
+
+	coin, err := Lookup("TST")
+	require.NoError(t, err)
+	assert.Equal(t, Coin{Symbol: "TST", Decimals: 3}, coin)
+
+	require.ErrorIs(t, Register(Coin{Symbol: "", Decimals: 3}), ErrInvalidCoin)
+	require.ErrorIs(t, Register(Coin{Symbol: "NEG", Decimals: -1}), ErrInvalidCoin)
+	require.ErrorIs(t, Register(Coin{Symbol: "NEG", Decimals: 3, Dust: big.NewInt(-1)}), ErrInvalidCoin)
+	require.ErrorIs(t, Register(Coin{Symbol: "NEG", Decimals: 3, MinUnit: big.NewInt(0)}), ErrInvalidCoin)
+}
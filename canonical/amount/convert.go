@@ -0,0 +1,71 @@
+package amount
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Decimal is implemented by decimal types representing coefficient × 10^exponent,
+// such as shopspring's decimal.Decimal, so they convert without this package depending on them.
+type Decimal interface {
+	Coefficient() *big.Int
+	Exponent() int32
+}
+
+// FromDecimal converts a decimal value to an amount of the coin.
+// It fails with ErrPrecision if the value has more decimals than the coin.
+func FromDecimal(value Decimal, symbol string) (Amount, error) {
+	coin, err := Lookup(symbol)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	units := new(big.Int).Set(value.Coefficient())
+	shift := int64(value.Exponent()) + int64(coin.Decimals)
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(shift)), nil)
+	if shift >= 0 {
+		units.Mul(units, pow)
+	} else {
+		var rem big.Int
+		units.QuoRem(units, pow, &rem)
+		if rem.Sign() != 0 {
+			return Amount{}, fmt.Errorf("%w: %s has %d decimals", ErrPrecision, coin.Symbol, coin.Decimals)
+		}
+	}
+
+	return Amount{units: units, coin: coin}, nil
+}
+
+// FromRat converts a rational value to an amount of the coin.
+// It fails with ErrPrecision if the value is not a whole number of base units.
+func FromRat(value *big.Rat, symbol string) (Amount, error) {
+	coin, err := Lookup(symbol)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(unitsPerCoin(coin)))
+	if !scaled.IsInt() {
+		return Amount{}, fmt.Errorf("%w: %s has %d decimals", ErrPrecision, coin.Symbol, coin.Decimals)
+	}
+
+	return Amount{units: new(big.Int).Set(scaled.Num()), coin: coin}, nil
+}
+
+// ToRat returns the amount in whole coins as an exact rational.
+func (a Amount) ToRat() *big.Rat {
+	return new(big.Rat).SetFrac(a.Units(), unitsPerCoin(a.coin))
+}
+
+// unitsPerCoin returns 10^decimals.
+func unitsPerCoin(coin Coin) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(coin.Decimals)), nil)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,47 @@
+package amount
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDecimal is coefficient × 10^exponent.
+type testDecimal struct {
+	coefficient int64
+	exponent    int32
+}
+
+func (d testDecimal) Coefficient() *big.Int { return big.NewInt(d.coefficient) }
+func (d testDecimal) Exponent() int32       { return d.exponent }
+
+func TestFromDecimal(t *testing.T) {
+	a, err := FromDecimal(testDecimal{15, -1}, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, "150000000", a.Units().String())
+
+	a, err = FromDecimal(testDecimal{3, 2}, "USDT")
+	require.NoError(t, err)
+	assert.Equal(t, "300", a.Text())
+
+	a, err = FromDecimal(testDecimal{1000, -11}, "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, "0.00000001", a.Text())
+
+	_, err = FromDecimal(testDecimal{1, -9}, "BTC")
+	require.ErrorIs(t, err, ErrPrecision)
+}
+
+func TestRat(t *testing.T) {
+	a := mustParse(t, "1.25", "ETH")
+	assert.Equal(t, big.NewRat(5, 4), a.ToRat())
+
+	back, err := FromRat(a.ToRat(), "ETH")
+	require.NoError(t, err)
+	assert.Equal(t, a, back)
+
+	_, err = FromRat(big.NewRat(1, 3), "BTC")
+	require.ErrorIs(t, err, ErrPrecision)
+}
@@ -0,0 +1,30 @@
+package amount
+
+import "math/big"
+
+// IsDust reports whether the amount is below the dust threshold of its coin,
+// so it should not be transferred or swept: withdrawals of dust are rejected,
+// outputs holding dust are left unspent. Negative amounts are compared by magnitude.
+// It is false for coins without a dust threshold.
+func (a Amount) IsDust() bool {
+	if a.coin.Dust == nil {
+		return false
+	}
+
+	units := a.Units()
+
+	return units.Abs(units).Cmp(a.coin.Dust) < 0
+}
+
+// RoundToMinUnit returns the amount rounded toward zero to a multiple of the minimum unit
+// of its coin, the largest part of it that can be transferred.
+// The amount is returned as is for coins without a minimum unit.
+func (a Amount) RoundToMinUnit() Amount {
+	if a.coin.MinUnit == nil {
+		return a
+	}
+
+	units := new(big.Int).Quo(a.Units(), a.coin.MinUnit)
+
+	return Amount{units: units.Mul(units, a.coin.MinUnit), coin: a.coin}
+}
@@ -0,0 +1,44 @@
+package amount
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDust(t *testing.T) {
+	tests := []struct {
+		value  string
+		symbol string
+		dust   bool
+	}{
+		{"0.00000545", "BTC", true},
+		{"0.00000546", "BTC", false},
+		{"-0.00000545", "BTC", true},
+		{"0", "BTC", true},
+		{"0.000000000000000001", "ETH", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.dust, mustParse(t, tt.value, tt.symbol).IsDust(), "%s %s", tt.value, tt.symbol)
+	}
+}
+
+func TestRoundToMinUnit(t *testing.T) {
+	require.NoError(t, Register(Coin{Symbol: "LOT", Decimals: 3, MinUnit: big.NewInt(10)}))
+
+	tests := map[string]string{
+		"1.234":  "1.23",
+		"1.23":   "1.23",
+		"0.009":  "0",
+		"-1.239": "-1.23",
+	}
+	for value, want := range tests {
+		assert.Equal(t, want, mustParse(t, value, "LOT").RoundToMinUnit().Text(), value)
+	}
+
+	btc := mustParse(t, "0.12345678", "BTC")
+	assert.Equal(t, btc, btc.RoundToMinUnit())
+}
@@ -0,0 +1,20 @@
+// Package shopspring converts canonical amounts to and from shopspring's decimal.Decimal,
+// so services using that library can adopt amount.Amount incrementally.
+// It is a separate package so that the amount package does not depend on the library.
+package shopspring
+
+import (
+	"github.com/ezex-io/gopkg/canonical/amount"
+	"github.com/shopspring/decimal"
+)
+
+// FromDecimal converts d to an amount of the coin.
+// It fails with amount.ErrPrecision if d has more decimals than the coin.
+func FromDecimal(d decimal.Decimal, symbol string) (amount.Amount, error) {
+	return amount.FromDecimal(d, symbol)
+}
+
+// ToDecimal converts a to a decimal in whole coins.
+func ToDecimal(a amount.Amount) decimal.Decimal {
+	return decimal.NewFromBigInt(a.Units(), -a.Coin().Decimals)
+}
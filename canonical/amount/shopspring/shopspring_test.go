@@ -0,0 +1,21 @@
+package shopspring
+
+import (
+	"testing"
+
+	"github.com/ezex-io/gopkg/canonical/amount"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalRoundTrip(t *testing.T) {
+	a, err := FromDecimal(decimal.RequireFromString("12.3456"), "USDC")
+	require.NoError(t, err)
+	assert.Equal(t, "12345600", a.Units().String())
+
+	assert.True(t, decimal.RequireFromString("12.3456").Equal(ToDecimal(a)))
+
+	_, err = FromDecimal(decimal.RequireFromString("0.0000001"), "USDC")
+	require.ErrorIs(t, err, amount.ErrPrecision)
+}
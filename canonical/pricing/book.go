@@ -0,0 +1,156 @@
+package pricing
+
+import (
+	"slices"
+
+	"github.com/shopspring/decimal"
+)
+
+// Side is the side of an order.
+type Side int
+
+const (
+	// Buy takes liquidity from the asks.
+	Buy Side = iota
+
+	// Sell takes liquidity from the bids.
+	Sell
+)
+
+// Level is the total quantity offered at a price.
+type Level struct {
+	Price    decimal.Decimal
+	Quantity decimal.Decimal
+}
+
+// Book is a snapshot of an order book.
+// Bids are sorted by descending price and asks by ascending price.
+type Book struct {
+	Bids []Level
+	Asks []Level
+}
+
+// NewBook creates a book from unsorted levels. The levels are copied and sorted.
+func NewBook(bids, asks []Level) Book {
+	book := Book{
+		Bids: slices.Clone(bids),
+		Asks: slices.Clone(asks),
+	}
+	slices.SortStableFunc(book.Bids, func(a, b Level) int { return b.Price.Cmp(a.Price) })
+	slices.SortStableFunc(book.Asks, func(a, b Level) int { return a.Price.Cmp(b.Price) })
+
+	return book
+}
+
+// BestBid returns the highest bid, or false if there are no bids.
+func (b Book) BestBid() (Level, bool) {
+	if len(b.Bids) == 0 {
+		return Level{}, false
+	}
+
+	return b.Bids[0], true
+}
+
+// BestAsk returns the lowest ask, or false if there are no asks.
+func (b Book) BestAsk() (Level, bool) {
+	if len(b.Asks) == 0 {
+		return Level{}, false
+	}
+
+	return b.Asks[0], true
+}
+
+// Mid returns the price halfway between the best bid and the best ask.
+func (b Book) Mid() (decimal.Decimal, error) {
+	bid, ask, err := b.top()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return Mid(bid, ask)
+}
+
+// Spread returns the best ask minus the best bid.
+func (b Book) Spread() (decimal.Decimal, error) {
+	bid, ask, err := b.top()
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return Spread(bid, ask)
+}
+
+func (b Book) top() (bid, ask decimal.Decimal, err error) {
+	bestBid, hasBid := b.BestBid()
+	bestAsk, hasAsk := b.BestAsk()
+	if !hasBid || !hasAsk {
+		return decimal.Decimal{}, decimal.Decimal{}, ErrEmptyBook
+	}
+
+	return bestBid.Price, bestAsk.Price, nil
+}
+
+// Fill is the estimated outcome of a market order walking the book.
+type Fill struct {
+	// Filled is the quantity that the book can fill.
+	Filled decimal.Decimal
+
+	// Cost is the sum of price times quantity over the filled levels.
+	Cost decimal.Decimal
+
+	// AvgPrice is Cost divided by Filled.
+	AvgPrice decimal.Decimal
+
+	// Slippage is how much worse AvgPrice is than the best price; never negative.
+	Slippage decimal.Decimal
+
+	// SlippageBps is Slippage in basis points of the best price.
+	SlippageBps decimal.Decimal
+}
+
+// EstimateFill estimates a market order of quantity on side against the book.
+// If the book can't fill the whole quantity, the partial fill is returned
+// together with ErrInsufficientLiquidity.
+func (b Book) EstimateFill(side Side, quantity decimal.Decimal) (Fill, error) {
+	levels := b.Asks
+	if side == Sell {
+		levels = b.Bids
+	}
+	if len(levels) == 0 {
+		return Fill{}, ErrEmptyBook
+	}
+
+	fill := Fill{Filled: decimal.Zero, Cost: decimal.Zero}
+	remaining := quantity
+	for _, level := range levels {
+		if !remaining.IsPositive() {
+			break
+		}
+
+		taken := decimal.Min(remaining, level.Quantity)
+		fill.Filled = fill.Filled.Add(taken)
+		fill.Cost = fill.Cost.Add(taken.Mul(level.Price))
+		remaining = remaining.Sub(taken)
+	}
+
+	if fill.Filled.IsZero() {
+		return fill, ErrInsufficientLiquidity
+	}
+
+	best := levels[0].Price
+	fill.AvgPrice = fill.Cost.DivRound(fill.Filled, divisionPrecision)
+	fill.Slippage = fill.AvgPrice.Sub(best)
+	if side == Sell {
+		fill.Slippage = best.Sub(fill.AvgPrice)
+	}
+	fill.SlippageBps = decimal.Zero
+	if !best.IsZero() {
+		fill.SlippageBps = fill.Slippage.Mul(basisPoints).DivRound(best, divisionPrecision)
+	}
+
+	if remaining.IsPositive() {
+		return fill, ErrInsufficientLiquidity
+	}
+
+	return fill, nil
+}
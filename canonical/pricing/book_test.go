@@ -0,0 +1,78 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBook() Book {
+	return NewBook(
+		[]Level{
+			{Price: dec("99"), Quantity: dec("2")},
+			{Price: dec("100"), Quantity: dec("1")},
+		},
+		[]Level{
+			{Price: dec("102"), Quantity: dec("2")},
+			{Price: dec("101"), Quantity: dec("1")},
+		},
+	)
+}
+
+func TestBook_Top(t *testing.T) {
+	book := testBook()
+
+	bid, ok := book.BestBid()
+	require.True(t, ok)
+	assert.Equal(t, "100", bid.Price.String())
+
+	ask, ok := book.BestAsk()
+	require.True(t, ok)
+	assert.Equal(t, "101", ask.Price.String())
+
+	mid, err := book.Mid()
+	require.NoError(t, err)
+	assert.Equal(t, "100.5", mid.String())
+
+	spread, err := book.Spread()
+	require.NoError(t, err)
+	assert.Equal(t, "1", spread.String())
+
+	_, err = Book{}.Mid()
+	require.ErrorIs(t, err, ErrEmptyBook)
+	_, err = Book{}.Spread()
+	require.ErrorIs(t, err, ErrEmptyBook)
+}
+
+func TestBook_EstimateFill(t *testing.T) {
+	book := testBook()
+
+	fill, err := book.EstimateFill(Buy, dec("2"))
+	require.NoError(t, err)
+	assert.Equal(t, "2", fill.Filled.String())
+	assert.Equal(t, "203", fill.Cost.String())
+	assert.Equal(t, "101.5", fill.AvgPrice.String())
+	assert.Equal(t, "0.5", fill.Slippage.String())
+	assert.True(t, fill.SlippageBps.Round(4).Equal(dec("49.505")), fill.SlippageBps.String())
+
+	fill, err = book.EstimateFill(Sell, dec("3"))
+	require.NoError(t, err)
+	assert.Equal(t, "298", fill.Cost.String())
+	assert.True(t, fill.Slippage.Round(6).Equal(dec("0.666667")), fill.Slippage.String())
+
+	fill, err = book.EstimateFill(Buy, dec("0.5"))
+	require.NoError(t, err)
+	assert.True(t, fill.Slippage.IsZero())
+
+	fill, err = book.EstimateFill(Buy, dec("5"))
+	require.ErrorIs(t, err, ErrInsufficientLiquidity)
+	assert.Equal(t, "3", fill.Filled.String())
+
+	_, err = book.EstimateFill(Buy, decimal.Zero)
+	require.ErrorIs(t, err, ErrInsufficientLiquidity)
+
+	_, err = Book{}.EstimateFill(Sell, dec("1"))
+	require.ErrorIs(t, err, ErrEmptyBook)
+}
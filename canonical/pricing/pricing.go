@@ -0,0 +1,105 @@
+// Package pricing provides exact decimal price math for order books:
+// mid and spread calculations, slippage estimation and rounding to tick and lot sizes.
+//
+// Prices and quantities are shopspring decimals, never float64.
+package pricing
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrInvalidStep is returned when a tick or lot size is not positive.
+	ErrInvalidStep = errors.New("pricing: step must be positive")
+
+	// ErrCrossedPrices is returned when the bid is above the ask.
+	ErrCrossedPrices = errors.New("pricing: bid is above ask")
+
+	// ErrEmptyBook is returned when a side of the book has no levels.
+	ErrEmptyBook = errors.New("pricing: empty book")
+
+	// ErrInsufficientLiquidity is returned when the book can't fill the whole quantity.
+	ErrInsufficientLiquidity = errors.New("pricing: insufficient liquidity")
+)
+
+// basisPoints is the number of basis points in one.
+var basisPoints = decimal.NewFromInt(10_000)
+
+// divisionPrecision is the number of decimal places kept by divisions that don't terminate.
+const divisionPrecision = 18
+
+// Rounding selects how a value is rounded to a multiple of a step.
+type Rounding int
+
+const (
+	// RoundDown rounds toward negative infinity.
+	RoundDown Rounding = iota
+
+	// RoundUp rounds toward positive infinity.
+	RoundUp
+
+	// RoundNearest rounds to the nearest multiple, halves away from zero.
+	RoundNearest
+)
+
+// Mid returns the price halfway between bid and ask.
+func Mid(bid, ask decimal.Decimal) (decimal.Decimal, error) {
+	if bid.GreaterThan(ask) {
+		return decimal.Decimal{}, ErrCrossedPrices
+	}
+
+	return bid.Add(ask).Div(decimal.NewFromInt(2)), nil
+}
+
+// Spread returns ask minus bid.
+func Spread(bid, ask decimal.Decimal) (decimal.Decimal, error) {
+	if bid.GreaterThan(ask) {
+		return decimal.Decimal{}, ErrCrossedPrices
+	}
+
+	return ask.Sub(bid), nil
+}
+
+// SpreadBps returns the spread in basis points of the mid price.
+func SpreadBps(bid, ask decimal.Decimal) (decimal.Decimal, error) {
+	mid, err := Mid(bid, ask)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	if mid.IsZero() {
+		return decimal.Zero, nil
+	}
+
+	return ask.Sub(bid).Mul(basisPoints).DivRound(mid, divisionPrecision), nil
+}
+
+// RoundToTick rounds price to a multiple of tick.
+func RoundToTick(price, tick decimal.Decimal, rounding Rounding) (decimal.Decimal, error) {
+	return roundToStep(price, tick, rounding)
+}
+
+// RoundToLot rounds quantity down to a multiple of lot,
+// so an order never exceeds the quantity it was sized for.
+func RoundToLot(quantity, lot decimal.Decimal) (decimal.Decimal, error) {
+	return roundToStep(quantity, lot, RoundDown)
+}
+
+func roundToStep(value, step decimal.Decimal, rounding Rounding) (decimal.Decimal, error) {
+	if !step.IsPositive() {
+		return decimal.Decimal{}, ErrInvalidStep
+	}
+
+	steps := value.Div(step)
+	switch rounding {
+	case RoundUp:
+		steps = steps.Ceil()
+	case RoundNearest:
+		steps = steps.Round(0)
+	default:
+		steps = steps.Floor()
+	}
+
+	return steps.Mul(step), nil
+}
@@ -0,0 +1,73 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestMidAndSpread(t *testing.T) {
+	mid, err := Mid(dec("100.1"), dec("100.2"))
+	require.NoError(t, err)
+	assert.Equal(t, "100.15", mid.String())
+
+	spread, err := Spread(dec("100.1"), dec("100.2"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", spread.String())
+
+	bps, err := SpreadBps(dec("99"), dec("101"))
+	require.NoError(t, err)
+	assert.Equal(t, "200", bps.String())
+
+	_, err = Mid(dec("2"), dec("1"))
+	require.ErrorIs(t, err, ErrCrossedPrices)
+	_, err = Spread(dec("2"), dec("1"))
+	require.ErrorIs(t, err, ErrCrossedPrices)
+	_, err = SpreadBps(dec("2"), dec("1"))
+	require.ErrorIs(t, err, ErrCrossedPrices)
+
+	bps, err = SpreadBps(decimal.Zero, decimal.Zero)
+	require.NoError(t, err)
+	assert.True(t, bps.IsZero())
+}
+
+func TestRoundToTick(t *testing.T) {
+	tests := []struct {
+		price    string
+		tick     string
+		rounding Rounding
+		expected string
+	}{
+		{"100.237", "0.01", RoundDown, "100.23"},
+		{"100.237", "0.01", RoundUp, "100.24"},
+		{"100.235", "0.01", RoundNearest, "100.24"},
+		{"100.234", "0.01", RoundNearest, "100.23"},
+		{"100.25", "0.05", RoundDown, "100.25"},
+		{"100.26", "0.05", RoundUp, "100.3"},
+		{"-1.5", "1", RoundDown, "-2"},
+	}
+
+	for _, tt := range tests {
+		got, err := RoundToTick(dec(tt.price), dec(tt.tick), tt.rounding)
+		require.NoError(t, err)
+		assert.True(t, dec(tt.expected).Equal(got), "%s to %s: got %s", tt.price, tt.tick, got)
+	}
+
+	_, err := RoundToTick(dec("1"), decimal.Zero, RoundDown)
+	require.ErrorIs(t, err, ErrInvalidStep)
+}
+
+func TestRoundToLot(t *testing.T) {
+	got, err := RoundToLot(dec("1.2399"), dec("0.001"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.239", got.String())
+
+	_, err = RoundToLot(dec("1"), dec("-0.1"))
+	require.ErrorIs(t, err, ErrInvalidStep)
+}
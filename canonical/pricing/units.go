@@ -0,0 +1,19 @@
+package pricing
+
+import (
+	"math/big"
+
+	"github.com/shopspring/decimal"
+)
+
+// FromUnits converts an integer amount of base units, such as wei or satoshi,
+// into a decimal with the given number of decimals.
+func FromUnits(units *big.Int, decimals int32) decimal.Decimal {
+	return decimal.NewFromBigInt(units, -decimals)
+}
+
+// ToUnits converts a decimal into an integer amount of base units with the given
+// number of decimals. Digits beyond the precision of the unit are truncated.
+func ToUnits(value decimal.Decimal, decimals int32) *big.Int {
+	return value.Shift(decimals).Truncate(0).BigInt()
+}
@@ -0,0 +1,18 @@
+package pricing
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnits(t *testing.T) {
+	wei, _ := new(big.Int).SetString("1500000000000000000", 10)
+
+	value := FromUnits(wei, 18)
+	assert.Equal(t, "1.5", value.String())
+	assert.Equal(t, wei, ToUnits(value, 18))
+
+	assert.Equal(t, big.NewInt(123), ToUnits(dec("1.239"), 2))
+}
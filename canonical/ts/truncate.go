@@ -0,0 +1,50 @@
+package ts
+
+import "time"
+
+// Candlestick intervals commonly used by market data.
+const (
+	Interval1m  = time.Minute
+	Interval5m  = 5 * time.Minute
+	Interval15m = 15 * time.Minute
+	Interval1h  = time.Hour
+	Interval4h  = 4 * time.Hour
+	Interval1d  = 24 * time.Hour
+)
+
+// Truncate rounds t down to a multiple of d since the Unix epoch,
+// which is how fixed-size candlestick intervals are aligned.
+// If d is less than a millisecond, t is returned unchanged.
+func (t Timestamp) Truncate(d time.Duration) Timestamp {
+	step := Timestamp(d.Milliseconds())
+	if step <= 0 {
+		return t
+	}
+
+	rem := t % step
+	if rem < 0 {
+		rem += step
+	}
+
+	return t - rem
+}
+
+// StartOfDay returns midnight UTC of t's day.
+func (t Timestamp) StartOfDay() Timestamp {
+	return t.Truncate(Interval1d)
+}
+
+// StartOfWeek returns midnight UTC of the Monday starting t's week (ISO 8601 weeks).
+func (t Timestamp) StartOfWeek() Timestamp {
+	day := t.StartOfDay()
+	offset := (int(day.Time().Weekday()) + 6) % 7 // days since Monday
+
+	return day.Add(-time.Duration(offset) * Interval1d)
+}
+
+// StartOfMonth returns midnight UTC of the first day of t's month.
+func (t Timestamp) StartOfMonth() Timestamp {
+	tm := t.Time()
+
+	return FromTime(time.Date(tm.Year(), tm.Month(), 1, 0, 0, 0, 0, time.UTC))
+}
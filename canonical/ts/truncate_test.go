@@ -0,0 +1,44 @@
+package ts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	stamp := FromTime(time.Date(2025, 3, 5, 14, 37, 42, 500000000, time.UTC)) // Wednesday
+
+	tests := []struct {
+		interval time.Duration
+		expected time.Time
+	}{
+		{Interval1m, time.Date(2025, 3, 5, 14, 37, 0, 0, time.UTC)},
+		{Interval5m, time.Date(2025, 3, 5, 14, 35, 0, 0, time.UTC)},
+		{Interval15m, time.Date(2025, 3, 5, 14, 30, 0, 0, time.UTC)},
+		{Interval1h, time.Date(2025, 3, 5, 14, 0, 0, 0, time.UTC)},
+		{Interval4h, time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC)},
+		{Interval1d, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, stamp.Truncate(tt.interval).Time(), tt.interval.String())
+	}
+
+	assert.Equal(t, stamp, stamp.Truncate(0))
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), stamp.StartOfWeek().Time())
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), stamp.StartOfMonth().Time())
+}
+
+func TestTruncateBeforeEpoch(t *testing.T) {
+	stamp := FromTime(time.Date(1969, 12, 31, 23, 59, 30, 0, time.UTC))
+
+	assert.Equal(t, time.Date(1969, 12, 31, 23, 59, 0, 0, time.UTC), stamp.Truncate(Interval1m).Time())
+}
+
+func TestStartOfWeekOnMonday(t *testing.T) {
+	monday := FromTime(time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), monday.StartOfWeek().Time())
+}
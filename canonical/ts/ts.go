@@ -0,0 +1,177 @@
+// Package ts provides the canonical timestamp used by ezex services.
+//
+// A Timestamp is a UTC instant with millisecond precision, stored as milliseconds
+// since the Unix epoch. Every service serializes it the same way:
+// - JSON and text use RFC 3339 with exactly three fractional digits ("2006-01-02T15:04:05.000Z")
+// - SQL uses a UTC time.Time truncated to milliseconds
+//
+// Timestamps carry no monotonic clock reading, so comparisons are plain integer comparisons.
+package ts
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Layout is the canonical text representation of a Timestamp.
+const Layout = "2006-01-02T15:04:05.000Z07:00"
+
+// Timestamp is a UTC instant with millisecond precision.
+type Timestamp int64
+
+// Now returns the current time as a Timestamp.
+func Now() Timestamp {
+	return FromTime(time.Now())
+}
+
+// FromTime converts t to a Timestamp, dropping sub-millisecond precision.
+func FromTime(t time.Time) Timestamp {
+	return Timestamp(t.UnixMilli())
+}
+
+// FromUnixMilli returns the Timestamp for the given milliseconds since the Unix epoch.
+func FromUnixMilli(ms int64) Timestamp {
+	return Timestamp(ms)
+}
+
+// Parse parses an RFC 3339 string, dropping sub-millisecond precision.
+func Parse(s string) (Timestamp, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timestamp %q: %w", s, err)
+	}
+
+	return FromTime(t), nil
+}
+
+// Time returns the Timestamp as a UTC time.Time.
+func (t Timestamp) Time() time.Time {
+	return time.UnixMilli(int64(t)).UTC()
+}
+
+// UnixMilli returns the number of milliseconds since the Unix epoch.
+func (t Timestamp) UnixMilli() int64 {
+	return int64(t)
+}
+
+// IsZero reports whether t is the Unix epoch, the zero value.
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
+// Before reports whether t is before u.
+func (t Timestamp) Before(u Timestamp) bool {
+	return t < u
+}
+
+// After reports whether t is after u.
+func (t Timestamp) After(u Timestamp) bool {
+	return t > u
+}
+
+// Equal reports whether t and u represent the same instant.
+func (t Timestamp) Equal(u Timestamp) bool {
+	return t == u
+}
+
+// Compare returns -1 if t is before u, +1 if t is after u, and 0 if they are equal.
+func (t Timestamp) Compare(u Timestamp) int {
+	switch {
+	case t < u:
+		return -1
+	case t > u:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Add returns t+d, dropping sub-millisecond precision of d.
+func (t Timestamp) Add(d time.Duration) Timestamp {
+	return t + Timestamp(d.Milliseconds())
+}
+
+// Sub returns the duration t-u.
+func (t Timestamp) Sub(u Timestamp) time.Duration {
+	return time.Duration(t-u) * time.Millisecond
+}
+
+// String returns t in the canonical layout.
+func (t Timestamp) String() string {
+	return t.Time().Format(Layout)
+}
+
+// MarshalText implements encoding.TextMarshaler using the canonical layout.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting any RFC 3339 string.
+func (t *Timestamp) UnmarshalText(data []byte) error {
+	parsed, err := Parse(string(data))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler using the canonical layout.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	return strconv.AppendQuote(nil, t.String()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// It accepts an RFC 3339 string, milliseconds since the Unix epoch as a number, or null.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		str, err := strconv.Unquote(string(data))
+		if err != nil {
+			return fmt.Errorf("invalid timestamp %s: %w", data, err)
+		}
+
+		return t.UnmarshalText([]byte(str))
+	}
+
+	ms, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp %s: %w", data, err)
+	}
+	*t = FromUnixMilli(ms)
+
+	return nil
+}
+
+// Value implements driver.Valuer, storing t as a UTC time.Time.
+func (t Timestamp) Value() (driver.Value, error) {
+	return t.Time(), nil
+}
+
+// Scan implements sql.Scanner.
+// It accepts time.Time, milliseconds since the Unix epoch, and RFC 3339 strings.
+func (t *Timestamp) Scan(src any) error {
+	switch val := src.(type) {
+	case time.Time:
+		*t = FromTime(val)
+	case int64:
+		*t = FromUnixMilli(val)
+	case string:
+		return t.UnmarshalText([]byte(val))
+	case []byte:
+		return t.UnmarshalText(val)
+	case nil:
+		*t = 0
+	default:
+		return fmt.Errorf("cannot scan %T into timestamp", src)
+	}
+
+	return nil
+}
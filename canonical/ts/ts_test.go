@@ -0,0 +1,81 @@
+package ts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromTimeDropsSubMillisecond(t *testing.T) {
+	tm := time.Date(2025, 3, 1, 10, 30, 15, 123456789, time.FixedZone("CET", 3600))
+	stamp := FromTime(tm)
+
+	assert.Equal(t, time.Date(2025, 3, 1, 9, 30, 15, 123000000, time.UTC), stamp.Time())
+	assert.Equal(t, "2025-03-01T09:30:15.123Z", stamp.String())
+}
+
+func TestComparison(t *testing.T) {
+	first := FromUnixMilli(1_000)
+	second := first.Add(time.Second)
+
+	assert.True(t, first.Before(second))
+	assert.True(t, second.After(first))
+	assert.True(t, first.Equal(FromUnixMilli(1_000)))
+	assert.Equal(t, -1, first.Compare(second))
+	assert.Equal(t, 1, second.Compare(first))
+	assert.Equal(t, 0, first.Compare(first))
+	assert.Equal(t, time.Second, second.Sub(first))
+}
+
+func TestJSON(t *testing.T) {
+	stamp := FromUnixMilli(1_740_825_015_123)
+
+	data, err := json.Marshal(struct {
+		At Timestamp `json:"at"`
+	}{At: stamp})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"at":"2025-03-01T10:30:15.123Z"}`, string(data))
+
+	for _, input := range []string{
+		`"2025-03-01T10:30:15.123Z"`,
+		`"2025-03-01T11:30:15.123456+01:00"`,
+		`1740825015123`,
+	} {
+		var decoded Timestamp
+		require.NoError(t, json.Unmarshal([]byte(input), &decoded), input)
+		assert.Equal(t, stamp, decoded, input)
+	}
+
+	var decoded Timestamp
+	require.NoError(t, json.Unmarshal([]byte(`null`), &decoded))
+	assert.True(t, decoded.IsZero())
+
+	require.Error(t, json.Unmarshal([]byte(`"yesterday"`), &decoded))
+	require.Error(t, json.Unmarshal([]byte(`true`), &decoded))
+}
+
+func TestSQL(t *testing.T) {
+	stamp := FromUnixMilli(1_740_825_015_123)
+
+	val, err := stamp.Value()
+	require.NoError(t, err)
+	assert.Equal(t, stamp.Time(), val)
+
+	sources := []any{
+		stamp.Time(),
+		int64(1_740_825_015_123),
+		"2025-03-01T10:30:15.123Z",
+		[]byte("2025-03-01T10:30:15.123Z"),
+	}
+	for _, src := range sources {
+		var scanned Timestamp
+		require.NoError(t, scanned.Scan(src))
+		assert.Equal(t, stamp, scanned)
+	}
+
+	var scanned Timestamp
+	require.Error(t, scanned.Scan(3.14))
+}
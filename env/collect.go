@@ -0,0 +1,61 @@
+package env
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	collectMu  sync.Mutex
+	collecting bool
+	collected  []error
+)
+
+// CollectErrors makes GetEnv and MustGetEnv record their errors, returning the zero value,
+// instead of panicking, until Finalize returns them all at once. A misconfigured
+// deployment then reports every bad variable in one startup failure:
+//
+//	func main() {
+//		env.CollectErrors()
+//		cfg := Config{
+//			Port:    env.GetEnv[int]("HTTP_PORT"),
+//			DBURL:   env.MustGetEnv[*url.URL]("DATABASE_URL"),
+//			Timeout: env.GetEnv[time.Duration]("TIMEOUT", env.WithDefault("5s")),
+//		}
+//		if err := env.Finalize(); err != nil {
+//			log.Fatal(err)
+//		}
+//		...
+//	}
+//
+// Errors recorded before are discarded.
+func CollectErrors() {
+	collectMu.Lock()
+	collecting = true
+	collected = nil
+	collectMu.Unlock()
+}
+
+// Finalize ends the mode started by CollectErrors and returns the errors recorded
+// since, joined, or nil if there were none. GetEnv and MustGetEnv panic again afterwards.
+func Finalize() error {
+	collectMu.Lock()
+	defer collectMu.Unlock()
+
+	err := errors.Join(collected...)
+	collecting = false
+	collected = nil
+
+	return err
+}
+
+// fail records err if errors are collected, and panics with it otherwise.
+func fail(err error) {
+	collectMu.Lock()
+	defer collectMu.Unlock()
+
+	if !collecting {
+		panic(err)
+	}
+	collected = append(collected, err)
+}
@@ -0,0 +1,40 @@
+package env_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectErrors(t *testing.T) {
+	t.Setenv("COLLECT_PORT", "not a number")
+	t.Setenv("COLLECT_TIMEOUT", "5s")
+	t.Setenv("COLLECT_URL", "")
+
+	env.CollectErrors()
+	port := env.GetEnv[int]("COLLECT_PORT")
+	timeout := env.GetEnv[time.Duration]("COLLECT_TIMEOUT")
+	url := env.MustGetEnv[string]("COLLECT_URL")
+	err := env.Finalize()
+
+	assert.Zero(t, port)
+	assert.Equal(t, 5*time.Second, timeout)
+	assert.Empty(t, url)
+	require.ErrorIs(t, err, env.ErrNotSet)
+	assert.ErrorContains(t, err, "COLLECT_PORT")
+	assert.ErrorContains(t, err, "COLLECT_URL")
+
+	require.NoError(t, env.Finalize(), "the errors are returned once")
+	assert.Panics(t, func() { env.GetEnv[int]("COLLECT_PORT") }, "GetEnv panics again after Finalize")
+}
+
+func TestCollectErrorsNone(t *testing.T) {
+	t.Setenv("COLLECT_PORT", "8080")
+
+	env.CollectErrors()
+	assert.Equal(t, 8080, env.GetEnv[int]("COLLECT_PORT"))
+	require.NoError(t, env.Finalize())
+}
@@ -0,0 +1,170 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFromYAML loads the YAML document at path into the process environment,
+// flattening its nested keys into variable names, so services read config files
+// and variables alike with GetEnv:
+//
+//	# config.yaml
+//	server:
+//	  port: 8080         # SERVER_PORT=8080
+//	  allowed-origins:   # SERVER_ALLOWED_ORIGINS=a.example,b.example
+//	    - a.example
+//	    - b.example
+//	databases:
+//	  - host: primary    # DATABASES_0_HOST=primary
+//
+// Keys are joined with '_' and upper-cased, and their '.', '-' and spaces
+// become '_'. Lists of scalars are joined with commas, as GetEnv splits []string
+// values, and other lists are flattened by index. Null values are skipped.
+//
+// Like LoadLayered, the document overrides the variables already set; use
+// WithoutOverride to let the deployment override the file, and WithConflictError
+// to reject keys flattening to the same variable with different values.
+func LoadFromYAML(path string, opts ...LoadOption) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("env: failed to read %s: %w", path, err)
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("env: failed to parse YAML in %s: %w", path, err)
+	}
+
+	return loadDocument(path, doc, opts)
+}
+
+// LoadFromJSON loads the JSON document at path into the process environment,
+// flattening it like LoadFromYAML.
+func LoadFromJSON(path string, opts ...LoadOption) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("env: failed to read %s: %w", path, err)
+	}
+
+	// Numbers are kept as written, so large integers don't lose precision.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("env: failed to parse JSON in %s: %w", path, err)
+	}
+
+	return loadDocument(path, doc, opts)
+}
+
+func loadDocument(path string, doc any, opts []LoadOption) error {
+	cfg := loadOptions{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if doc == nil {
+		return nil
+	}
+	if !isComposite(doc) {
+		return fmt.Errorf("env: %s is not a mapping or a list", path)
+	}
+
+	vars := map[string]string{}
+	var conflicts []error
+	flatten("", doc, func(key, val string) {
+		if prev, ok := vars[key]; ok && prev != val {
+			conflicts = append(conflicts, fmt.Errorf("%w: %s in %s", ErrConflict, key, path))
+		}
+		vars[key] = val
+	})
+
+	if cfg.conflictError && len(conflicts) > 0 {
+		return errors.Join(conflicts...)
+	}
+
+	origins := make(map[string]string, len(vars))
+	for key := range vars {
+		origins[key] = path
+	}
+
+	return setVars(vars, origins, cfg)
+}
+
+// flatten calls set with the variable of each scalar of node, named after
+// its path from prefix. Mapping keys are visited in order, so the last of keys
+// flattening to the same variable wins.
+func flatten(prefix string, node any, set func(key, val string)) {
+	switch node := node.(type) {
+	case nil:
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(node)) {
+			flatten(joinKey(prefix, key), node[key], set)
+		}
+	case map[any]any: // YAML mappings with keys other than strings
+		named := make(map[string]any, len(node))
+		for key, val := range node {
+			named[fmt.Sprint(key)] = val
+		}
+		flatten(prefix, named, set)
+	case []any:
+		if slices.ContainsFunc(node, isComposite) {
+			for i, item := range node {
+				flatten(joinKey(prefix, strconv.Itoa(i)), item, set)
+			}
+
+			return
+		}
+
+		items := make([]string, 0, len(node))
+		for _, item := range node {
+			if item != nil {
+				items = append(items, formatScalar(item))
+			}
+		}
+		set(prefix, strings.Join(items, ","))
+	default:
+		set(prefix, formatScalar(node))
+	}
+}
+
+func isComposite(node any) bool {
+	switch node.(type) {
+	case map[string]any, map[any]any, []any:
+		return true
+	default:
+		return false
+	}
+}
+
+func formatScalar(val any) string {
+	switch val := val.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// joinKey appends the mapping key or list index name to prefix, as a variable name.
+func joinKey(prefix, name string) string {
+	name = strings.ToUpper(strings.NewReplacer(".", "_", "-", "_", " ", "_").Replace(name))
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "_" + name
+}
@@ -0,0 +1,103 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDocument(t *testing.T, name, content string, keys ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	// Restore the variables set by the tests.
+	for _, key := range keys {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+
+	return path
+}
+
+func TestLoadFromYAML(t *testing.T) {
+	path := writeDocument(t, "config.yaml", `
+doc:
+  server:
+    port: 8080
+    timeout: 5s
+    allowed-origins: [a.example, b.example]
+    debug: true
+  databases:
+    - host: primary
+      port: 5432
+    - host: replica
+  unset: null
+`, "DOC_SERVER_PORT", "DOC_SERVER_TIMEOUT", "DOC_SERVER_ALLOWED_ORIGINS", "DOC_SERVER_DEBUG",
+		"DOC_DATABASES_0_HOST", "DOC_DATABASES_0_PORT", "DOC_DATABASES_1_HOST", "DOC_UNSET")
+
+	require.NoError(t, env.LoadFromYAML(path))
+
+	assert.Equal(t, 8080, env.GetEnv[int]("DOC_SERVER_PORT"))
+	assert.Equal(t, 5*time.Second, env.GetEnv[time.Duration]("DOC_SERVER_TIMEOUT"))
+	assert.Equal(t, []string{"a.example", "b.example"}, env.GetEnv[[]string]("DOC_SERVER_ALLOWED_ORIGINS"))
+	assert.True(t, env.GetEnv[bool]("DOC_SERVER_DEBUG"))
+	assert.Equal(t, "primary", os.Getenv("DOC_DATABASES_0_HOST"))
+	assert.Equal(t, "5432", os.Getenv("DOC_DATABASES_0_PORT"))
+	assert.Equal(t, "replica", os.Getenv("DOC_DATABASES_1_HOST"))
+
+	_, set := os.LookupEnv("DOC_UNSET")
+	assert.False(t, set, "null values are skipped")
+}
+
+func TestLoadFromJSON(t *testing.T) {
+	path := writeDocument(t, "config.json", `{"doc": {"server.port": 8080, "id": 12345678901234567890}}`,
+		"DOC_SERVER_PORT", "DOC_ID")
+
+	require.NoError(t, env.LoadFromJSON(path))
+
+	assert.Equal(t, "8080", os.Getenv("DOC_SERVER_PORT"))
+	assert.Equal(t, "12345678901234567890", os.Getenv("DOC_ID"), "numbers are kept as written")
+}
+
+func TestLoadFromYAMLOverride(t *testing.T) {
+	path := writeDocument(t, "config.yaml", "doc:\n  host: file\n  port: 1\n", "DOC_HOST", "DOC_PORT")
+	t.Setenv("DOC_HOST", "deployment")
+
+	require.NoError(t, env.LoadFromYAML(path, env.WithoutOverride()))
+	assert.Equal(t, "deployment", os.Getenv("DOC_HOST"))
+	assert.Equal(t, "1", os.Getenv("DOC_PORT"))
+
+	require.NoError(t, env.LoadFromYAML(path))
+	assert.Equal(t, "file", os.Getenv("DOC_HOST"))
+}
+
+func TestLoadFromYAMLConflict(t *testing.T) {
+	path := writeDocument(t, "config.yaml", "doc:\n  server:\n    port: 1\n  server_port: 2\n", "DOC_SERVER_PORT")
+
+	err := env.LoadFromYAML(path, env.WithConflictError())
+	require.ErrorIs(t, err, env.ErrConflict)
+	_, set := os.LookupEnv("DOC_SERVER_PORT")
+	assert.False(t, set)
+
+	require.NoError(t, env.LoadFromYAML(path))
+	assert.Equal(t, "2", os.Getenv("DOC_SERVER_PORT"), "the last key in order wins")
+}
+
+func TestLoadFromDocumentErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	require.ErrorIs(t, env.LoadFromYAML(filepath.Join(dir, "missing.yaml")), os.ErrNotExist)
+
+	scalar := writeDocument(t, "scalar.yaml", "just a string\n")
+	require.Error(t, env.LoadFromYAML(scalar))
+
+	invalid := writeDocument(t, "invalid.json", "{")
+	require.Error(t, env.LoadFromJSON(invalid))
+}
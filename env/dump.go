@@ -0,0 +1,49 @@
+package env
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Masked replaces the values of secrets in Dump.
+const Masked = "[REDACTED]"
+
+// secretPatterns are the patterns of the variables masked by Dump.
+var secretPatterns = []string{"*_KEY", "*_PASSWORD", "*_TOKEN", "*_SECRET"}
+
+// Dump returns the effective configuration, the variables read so far with
+// their values, defaults applied, so it can be logged at startup:
+//
+//	log.Info("config loaded", "config", env.Dump("*_DSN"))
+//
+// The non-empty values of the variables matching a secret pattern are Masked.
+// The patterns *_KEY, *_PASSWORD, *_TOKEN and *_SECRET always apply, and
+// redactPatterns adds to them. Patterns have the syntax of path.Match and
+// are matched case-insensitively.
+func Dump(redactPatterns ...string) map[string]string {
+	readsMu.Lock()
+	dump := maps.Clone(values)
+	readsMu.Unlock()
+
+	patterns := slices.Concat(secretPatterns, redactPatterns)
+	for key, val := range dump {
+		if val != "" && isSecret(key, patterns) {
+			dump[key] = Masked
+		}
+	}
+
+	return dump
+}
+
+func isSecret(key string, patterns []string) bool {
+	key = strings.ToUpper(key)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToUpper(pattern), key); matched {
+			return true
+		}
+	}
+
+	return false
+}
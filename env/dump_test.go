@@ -0,0 +1,38 @@
+package env_test
+
+import (
+	"testing"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	env.ResetReport()
+	t.Cleanup(env.ResetReport)
+	t.Setenv("DUMP_PORT", "8080")
+	t.Setenv("DUMP_API_KEY", "sk-123")
+	t.Setenv("DUMP_DB_PASSWORD", "hunter2")
+	t.Setenv("DUMP_DSN", "postgres://user:pass@db")
+	t.Setenv("DUMP_access_token", "t")
+
+	env.GetEnv[int]("DUMP_PORT")
+	env.GetEnv[string]("DUMP_HOST", env.WithDefault("localhost"))
+	env.GetEnv[string]("DUMP_API_KEY")
+	env.GetEnv[string]("DUMP_DB_PASSWORD")
+	env.GetEnv[string]("DUMP_DSN")
+	env.GetEnv[string]("DUMP_access_token")
+	env.GetEnv[string]("DUMP_JWT_SECRET")
+
+	assert.Equal(t, map[string]string{
+		"DUMP_PORT":         "8080",
+		"DUMP_HOST":         "localhost",
+		"DUMP_API_KEY":      env.Masked,
+		"DUMP_DB_PASSWORD":  env.Masked,
+		"DUMP_DSN":          env.Masked,
+		"DUMP_access_token": env.Masked,
+		"DUMP_JWT_SECRET":   "",
+	}, env.Dump("*_DSN"))
+
+	assert.Equal(t, "postgres://user:pass@db", env.Dump()["DUMP_DSN"])
+}
@@ -2,7 +2,8 @@ package env
 
 import (
 	"fmt"
-	"os"
+	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -11,85 +12,325 @@ import (
 )
 
 type SupportedTypes interface {
-	~string | ~int | ~float64 | ~bool | ~[]string | time.Duration
+	~string | ~int | ~float64 | ~bool | ~[]string | time.Duration | time.Time |
+		int64 | uint | []int | map[string]string | *url.URL | net.IP | ByteSize | []byte
 }
 
-// Option defines a function type that modifies a string value,
-// typically used for environment variable customization.
-type Option func(value *string)
+type options struct {
+	key         string
+	value       string
+	found       bool
+	defVal      string
+	hasDefault  bool
+	defTyped    any // set by WithDefaultT
+	reader      *Reader
+	err         error
+	timeLayouts []string
+	validators  []func(val string) error
+	transforms  []func(value *string)
+	base64      bool
+}
+
+// Option defines a function type that customizes how an environment variable is read.
+type Option func(opts *options)
 
 // WithDefault returns an Option that sets a default value
 // if the environment variable is not set or is empty.
 func WithDefault(defVal string) Option {
-	return func(val *string) {
-		if *val == "" {
-			*val = defVal
+	return func(opts *options) {
+		if !opts.hasDefault {
+			opts.defVal = defVal
+			opts.hasDefault = true
+		}
+	}
+}
+
+// WithDefaultT returns an Option that sets a default value of the type read,
+// so it is checked by the compiler rather than parsed at runtime:
+//
+//	timeout := env.GetEnv[time.Duration]("TIMEOUT", env.WithDefaultT(5*time.Second))
+//
+// It applies to GetEnv, LookupEnv, MustGetEnv and GetEnvJSON. They fail,
+// naming the key, if T is not the type read. The default is not validated,
+// and like with WithDefault, the first default given wins.
+func WithDefaultT[T SupportedTypes](val T) Option {
+	return func(opts *options) {
+		if !opts.hasDefault {
+			opts.defTyped = val
+			opts.hasDefault = true
+		}
+	}
+}
+
+// WithValueFunc returns an Option that applies fn to the value read, after the default.
+// Option was a func(value *string) before it gained settings of its own,
+// so custom options written that way keep working wrapped:
+//
+//	trim := func(val *string) { *val = strings.TrimSpace(*val) }
+//	name := env.GetEnv[string]("NAME", env.WithValueFunc(trim))
+func WithValueFunc(fn func(value *string)) Option {
+	return func(opts *options) {
+		opts.transforms = append(opts.transforms, fn)
+	}
+}
+
+// WithTimeLayout returns an Option that sets the layouts tried, in order,
+// when parsing a time.Time value. The default layout is time.RFC3339,
+// also kept when no layout is given.
+func WithTimeLayout(layouts ...string) Option {
+	return func(opts *options) {
+		if len(layouts) > 0 {
+			opts.timeLayouts = layouts
 		}
 	}
 }
 
 // GetEnv retrieves an environment variable by key,
 // applies the provided options, and converts it to the desired type T.
+// The read is recorded in the Report, like those of the other functions.
 //
-// Panics if the conversion fails or the type is unsupported.
-func GetEnv[T SupportedTypes](key string, options ...Option) T {
-	val := os.Getenv(key)
-	for _, opt := range options {
-		opt(&val)
+// Panics, naming the key, if the conversion or a validation fails or the type is unsupported,
+// unless errors are collected with CollectErrors. Use LookupEnv to handle these errors instead.
+func GetEnv[T SupportedTypes](key string, opts ...Option) T {
+	cfg := newOptions(key, opts)
+	cfg.record(false)
+
+	v, err := read[T](cfg)
+	if err != nil {
+		fail(err)
+
+		var zero T
+
+		return zero
+	}
+
+	return v
+}
+
+// LookupEnv retrieves an environment variable by key, like GetEnv,
+// but returns an error instead of panicking.
+// It returns an error wrapping ErrNotSet if the variable is empty and there is no default.
+func LookupEnv[T SupportedTypes](key string, opts ...Option) (T, error) {
+	cfg := newOptions(key, opts)
+	cfg.record(true)
+
+	if cfg.value == "" && cfg.err == nil && !cfg.typedDefault() {
+		var zero T
+
+		return zero, fmt.Errorf("%w: %s", ErrNotSet, cfg.key)
+	}
+
+	return read[T](cfg)
+}
+
+// MustGetEnv retrieves a required environment variable by key, like LookupEnv,
+// and panics with its error, unless errors are collected with CollectErrors.
+// Unlike GetEnv, it panics on an empty string.
+func MustGetEnv[T SupportedTypes](key string, opts ...Option) T {
+	v, err := LookupEnv[T](key, opts...)
+	if err != nil {
+		fail(err)
+
+		var zero T
+
+		return zero
+	}
+
+	return v
+}
+
+func newOptions(key string, opts []Option) *options {
+	cfg := &options{
+		timeLayouts: []string{time.RFC3339},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	lookup := lookupProcess
+	cfg.key = key
+	if cfg.reader != nil {
+		lookup = cfg.reader.lookup
+		cfg.key = cfg.reader.prefix + key
+	}
+	if cfg.value, _, cfg.err = lookup(cfg.key); cfg.err != nil {
+		cfg.err = fmt.Errorf("env: failed to look up %s: %w", cfg.key, cfg.err)
+	}
+	if cfg.base64 && cfg.value != "" && cfg.err == nil {
+		if cfg.value, cfg.err = decodeBase64(cfg.value); cfg.err != nil {
+			cfg.err = fmt.Errorf("env: invalid %s: %w", cfg.key, cfg.err)
+		}
+	}
+	cfg.found = cfg.value != ""
+	if !cfg.found && cfg.hasDefault {
+		cfg.value = cfg.defVal
+	}
+	for _, fn := range cfg.transforms {
+		fn(&cfg.value)
+	}
+
+	return cfg
+}
+
+// typedDefault reports whether the variable is empty and has a default set by WithDefaultT.
+func (o *options) typedDefault() bool {
+	return !o.found && o.defTyped != nil
+}
+
+// defaultT returns the default of cfg set by WithDefaultT, which must be a T.
+func defaultT[T any](cfg *options) (T, error) {
+	v, ok := cfg.defTyped.(T)
+	if !ok {
+		return v, fmt.Errorf("env: invalid %s: default of type %T, expected %T", cfg.key, cfg.defTyped, v)
+	}
+
+	return v, nil
+}
+
+// read converts the value of cfg and validates it.
+func read[T SupportedTypes](cfg *options) (T, error) {
+	if cfg.err != nil {
+		var zero T
+
+		return zero, cfg.err
+	}
+	if cfg.typedDefault() {
+		return defaultT[T](cfg)
 	}
 
+	v, err := parse[T](cfg)
+	if err == nil {
+		err = cfg.validate()
+	}
+	if err != nil {
+		return v, fmt.Errorf("env: invalid %s: %w", cfg.key, err)
+	}
+
+	return v, nil
+}
+
+func parse[T SupportedTypes](cfg *options) (T, error) {
+	val := cfg.value
+
 	var result T
 	switch any(result).(type) {
 	case int:
 		v, err := strconv.Atoi(val)
 		if err != nil {
-			panic(fmt.Errorf("failed to convert %q to int: %w", val, err))
+			return result, fmt.Errorf("failed to convert %q to int: %w", val, err)
 		}
 
-		return any(v).(T)
+		return any(v).(T), nil
 
 	case bool:
 		v, err := strconv.ParseBool(val)
 		if err != nil {
-			panic(fmt.Errorf("failed to convert %q to bool: %w", val, err))
+			return result, fmt.Errorf("failed to convert %q to bool: %w", val, err)
 		}
 
-		return any(v).(T)
+		return any(v).(T), nil
 
 	case float64:
 		v, err := strconv.ParseFloat(val, 64)
 		if err != nil {
-			panic(fmt.Errorf("failed to convert %q to float64: %w", val, err))
+			return result, fmt.Errorf("failed to convert %q to float64: %w", val, err)
 		}
 
-		return any(v).(T)
+		return any(v).(T), nil
 
 	case string:
-		return any(val).(T)
+		return any(val).(T), nil
 
 	case []string:
 		if val == "" {
-			return any([]string{}).(T)
+			return any([]string{}).(T), nil
 		}
 		parts := strings.Split(val, ",")
 
-		return any(parts).(T)
+		return any(parts).(T), nil
 
 	case time.Duration:
 		dur, err := time.ParseDuration(val)
 		if err != nil {
-			panic(fmt.Errorf("failed to parse duration %q: %w", val, err))
+			return result, fmt.Errorf("failed to parse duration %q: %w", val, err)
+		}
+
+		return any(dur).(T), nil
+
+	case time.Time:
+		t, err := parseTime(val, cfg.timeLayouts)
+
+		return any(t).(T), err
+
+	case int64:
+		v, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return result, fmt.Errorf("failed to convert %q to int64: %w", val, err)
+		}
+
+		return any(v).(T), nil
+
+	case uint:
+		v, err := strconv.ParseUint(val, 10, 0)
+		if err != nil {
+			return result, fmt.Errorf("failed to convert %q to uint: %w", val, err)
+		}
+
+		return any(uint(v)).(T), nil
+
+	case []int:
+		ints, err := parseInts(val)
+
+		return any(ints).(T), err
+
+	case map[string]string:
+		m, err := parseMap(val)
+
+		return any(m).(T), err
+
+	case *url.URL:
+		u, err := parseURL(val)
+
+		return any(u).(T), err
+
+	case net.IP:
+		ip := net.ParseIP(val)
+		if ip == nil {
+			return result, fmt.Errorf("failed to parse IP address %q", val)
 		}
 
-		return any(dur).(T)
+		return any(ip).(T), nil
+
+	case ByteSize:
+		size, err := parseByteSize(val)
+
+		return any(size).(T), err
+
+	case []byte:
+		return any([]byte(val)).(T), nil
 
 	default:
-		panic(fmt.Sprintf("unsupported type: %T", result))
+		return result, fmt.Errorf("unsupported type: %T", result)
 	}
 }
 
+func parseTime(val string, layouts []string) (time.Time, error) {
+	var err error
+	for _, layout := range layouts {
+		var parsed time.Time
+		parsed, err = time.Parse(layout, val)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("failed to parse time %q: %w", val, err)
+}
+
 // LoadEnvsFromFile loads environment variables from the specified file(s).
 // If a file is not found, it returns without an error.
+// Variables already set are kept, and earlier files win; use LoadLayered
+// to control the precedence.
 func LoadEnvsFromFile(envFile ...string) error {
 	return godotenv.Load(envFile...)
 }
@@ -3,10 +3,11 @@ package env_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/ezex-io/gopkg/env"
+	"github.com/ezex-io/gopkg/env/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -47,6 +48,47 @@ func TestGetEnvWithDefault(t *testing.T) {
 	assert.Equal(t, time.Second*5, env.GetEnv[time.Duration]("MY_DURATION", env.WithDefault("5s")))
 }
 
+// TestGetEnvWithValueFunc verifies that options written as func(*string) still apply, after the default.
+func TestGetEnvWithValueFunc(t *testing.T) {
+	upper := func(val *string) { *val = strings.ToUpper(*val) }
+	fallback := func(val *string) {
+		if *val == "" {
+			*val = "fallback"
+		}
+	}
+
+	t.Setenv("MY_STRING", "str")
+	assert.Equal(t, "STR", env.GetEnv[string]("MY_STRING", env.WithValueFunc(upper)))
+	assert.Equal(t, "DEF", env.GetEnv[string]("MY_UNSET", env.WithValueFunc(upper), env.WithDefault("def")))
+
+	val, err := env.LookupEnv[string]("MY_UNSET", env.WithValueFunc(fallback))
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", val)
+}
+
+// TestGetEnvWithDefaultT verifies that typed defaults are used as they are when variables are not set.
+func TestGetEnvWithDefaultT(t *testing.T) {
+	assert.Equal(t, 5*time.Second, env.GetEnv[time.Duration]("MY_DURATION", env.WithDefaultT(5*time.Second)))
+	assert.Equal(t, []string{"a,b"}, env.GetEnv[[]string]("MY_STRING_LIST", env.WithDefaultT([]string{"a,b"})))
+	assert.Equal(t, 2*env.MiB, env.GetEnv[env.ByteSize]("MY_SIZE", env.WithDefaultT(2*env.MiB)))
+	assert.Equal(t, 1, env.GetEnv[int]("MY_INT", env.WithDefaultT(1), env.WithDefault("2")), "the first default wins")
+
+	t.Setenv("MY_DURATION", "1m")
+	assert.Equal(t, time.Minute, env.GetEnv[time.Duration]("MY_DURATION", env.WithDefaultT(5*time.Second)))
+
+	val, err := env.LookupEnv[int]("MY_INT", env.WithDefaultT(8080))
+	require.NoError(t, err)
+	assert.Equal(t, 8080, val)
+
+	_, err = env.LookupEnv[int]("MY_INT", env.WithDefaultT("8080"))
+	require.ErrorContains(t, err, "MY_INT")
+	assert.Panics(t, func() { env.GetEnv[int]("MY_INT", env.WithDefaultT(int64(1))) })
+
+	list, err := env.GetEnvJSON[[]string]("MY_JSON", env.WithDefaultT([]string{"a"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, list)
+}
+
 // TestGetEnvNotSet ensures that calling GetEnv without a default on an unset variable panics.
 func TestGetEnvNotSet(t *testing.T) {
 	assert.Panics(t, func() {
@@ -106,3 +148,62 @@ func TestLoadEnvsFromFileEmptyPath(t *testing.T) {
 	err := env.LoadEnvsFromFile()
 	assert.Error(t, err)
 }
+
+// TestGetEnvTime verifies time.Time parsing with default and custom layouts.
+func TestGetEnvTime(t *testing.T) {
+	t.Setenv("MY_TIME", "2025-03-01T10:30:00Z")
+	assert.Equal(t, time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC), env.GetEnv[time.Time]("MY_TIME"))
+
+	t.Setenv("MY_DATE", "2025-03-01")
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		env.GetEnv[time.Time]("MY_DATE", env.WithTimeLayout(time.RFC3339, time.DateOnly)))
+	assert.Equal(t, time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC),
+		env.GetEnv[time.Time]("MY_TIME", env.WithTimeLayout()), "no layout keeps the default")
+
+	assert.Panics(t, func() {
+		env.GetEnv[time.Time]("MY_DATE")
+	})
+	assert.Panics(t, func() {
+		env.GetEnv[time.Time]("MY_UNSET_TIME")
+	})
+}
+
+// TestLookupEnv verifies that LookupEnv returns errors instead of panicking.
+func TestLookupEnv(t *testing.T) {
+	t.Setenv("MY_INT", "1")
+	t.Setenv("MY_BAD_INT", "one")
+
+	v, err := env.LookupEnv[int]("MY_INT")
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	v, err = env.LookupEnv[int]("MY_UNSET_INT", env.WithDefault("2"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, v)
+
+	_, err = env.LookupEnv[string]("MY_UNSET_STRING")
+	require.ErrorIs(t, err, env.ErrNotSet)
+	assert.ErrorContains(t, err, "MY_UNSET_STRING")
+
+	_, err = env.LookupEnv[int]("MY_BAD_INT")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, env.ErrNotSet)
+	assert.ErrorContains(t, err, "MY_BAD_INT")
+
+	_, err = env.LookupEnv[time.Time]("MY_BAD_INT")
+	assert.Error(t, err)
+}
+
+// TestMustGetEnv verifies that MustGetEnv panics on a missing or invalid variable.
+func TestMustGetEnv(t *testing.T) {
+	t.Setenv("MY_STRING", "str")
+	t.Setenv("MY_BAD_DURATION", "two seconds")
+
+	assert.Equal(t, "str", env.MustGetEnv[string]("MY_STRING"))
+	assert.Panics(t, func() {
+		env.MustGetEnv[string]("MY_UNSET_STRING")
+	})
+	assert.Panics(t, func() {
+		env.MustGetEnv[time.Duration]("MY_BAD_DURATION")
+	})
+}
@@ -0,0 +1,172 @@
+package env
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Schema declares a configuration for which Generate emits a typed Config struct
+// and a Load function. It can be decoded from JSON.
+type Schema struct {
+	// Package is the package clause of the generated file.
+	Package string `json:"package"`
+
+	// Struct is the name of the generated struct. Defaults to "Config".
+	Struct string `json:"struct,omitempty"`
+
+	// Fields are the struct fields, in order.
+	Fields []Field `json:"fields"`
+}
+
+// Field declares one environment variable of a Schema.
+type Field struct {
+	// Name is the exported Go field name, such as "HTTPPort".
+	Name string `json:"name"`
+
+	// Env is the environment variable key, such as "HTTP_PORT".
+	Env string `json:"env"`
+
+	// Type is the Go type of the field, one of the SupportedTypes:
+	// string, int, int64, uint, float64, bool, []string, []int, map[string]string,
+	// time.Duration, time.Time, *url.URL, net.IP, env.ByteSize or []byte.
+	Type string `json:"type"`
+
+	// Default is the value used when the variable is not set or is empty.
+	Default string `json:"default,omitempty"`
+
+	// Doc is the comment of the field.
+	Doc string `json:"doc,omitempty"`
+}
+
+// generatedTypes are the field types Generate accepts, with the package they need, if any.
+var generatedTypes = map[string]string{
+	"string":            "",
+	"int":               "",
+	"int64":             "",
+	"uint":              "",
+	"float64":           "",
+	"bool":              "",
+	"[]string":          "",
+	"[]int":             "",
+	"map[string]string": "",
+	"time.Duration":     "time",
+	"time.Time":         "time",
+	"*url.URL":          "net/url",
+	"net.IP":            "net",
+	"env.ByteSize":      "",
+	"[]byte":            "",
+}
+
+// Generate writes Go source declaring a struct with one field per schema field,
+// and a Load function reading them with GetEnv, so services get compile-time
+// field access instead of string keys scattered through the code.
+// Like GetEnv, the generated Load panics when a value can't be converted.
+func Generate(schema Schema, w io.Writer) error {
+	if err := validateSchema(&schema); err != nil {
+		return err
+	}
+
+	var imports []string
+	for _, field := range schema.Fields {
+		if pkg := generatedTypes[field.Type]; pkg != "" && !slices.Contains(imports, pkg) {
+			imports = append(imports, pkg)
+		}
+	}
+	slices.Sort(imports)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by env.Generate. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", schema.Package)
+	buf.WriteString("import (\n")
+	for _, pkg := range imports {
+		fmt.Fprintf(&buf, "%q\n", pkg)
+	}
+	if len(imports) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\"github.com/ezex-io/gopkg/env/v2\"\n)\n\n")
+
+	fmt.Fprintf(&buf, "// %s holds the configuration read from the environment.\n", schema.Struct)
+	fmt.Fprintf(&buf, "type %s struct {\n", schema.Struct)
+	for _, field := range schema.Fields {
+		doc := field.Doc
+		if doc == "" {
+			doc = "is read from " + field.Env + "."
+		}
+		for line := range strings.SplitSeq(field.Name+" "+doc, "\n") {
+			fmt.Fprintf(&buf, "// %s\n", line)
+		}
+		fmt.Fprintf(&buf, "%s %s\n", field.Name, field.Type)
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&buf, "// Load reads %s from the environment.\n", schema.Struct)
+	buf.WriteString("// It panics if a variable can't be converted to the type of its field.\n")
+	fmt.Fprintf(&buf, "func Load() *%s {\n", schema.Struct)
+	fmt.Fprintf(&buf, "return &%s{\n", schema.Struct)
+	for _, field := range schema.Fields {
+		fmt.Fprintf(&buf, "%s: env.GetEnv[%s](%s", field.Name, field.Type, strconv.Quote(field.Env))
+		if field.Default != "" {
+			fmt.Fprintf(&buf, ", env.WithDefault(%s)", strconv.Quote(field.Default))
+		}
+		buf.WriteString("),\n")
+	}
+	buf.WriteString("}\n}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("env: format generated code: %w", err)
+	}
+
+	_, err = w.Write(src)
+
+	return err
+}
+
+// validateSchema checks the schema and fills in the defaults.
+func validateSchema(schema *Schema) error {
+	if !token.IsIdentifier(schema.Package) {
+		return fmt.Errorf("env: invalid package name %q", schema.Package)
+	}
+
+	if schema.Struct == "" {
+		schema.Struct = "Config"
+	}
+	if !token.IsIdentifier(schema.Struct) || !token.IsExported(schema.Struct) {
+		return fmt.Errorf("env: invalid struct name %q", schema.Struct)
+	}
+
+	if len(schema.Fields) == 0 {
+		return errors.New("env: schema has no fields")
+	}
+
+	names := make(map[string]bool, len(schema.Fields))
+	for _, field := range schema.Fields {
+		switch {
+		case !token.IsIdentifier(field.Name) || !token.IsExported(field.Name):
+			return fmt.Errorf("env: invalid field name %q", field.Name)
+		case names[field.Name]:
+			return fmt.Errorf("env: duplicate field %q", field.Name)
+		case field.Env == "":
+			return fmt.Errorf("env: field %q has no environment variable", field.Name)
+		case !isGeneratedType(field.Type):
+			return fmt.Errorf("env: field %q has unsupported type %q", field.Name, field.Type)
+		}
+		names[field.Name] = true
+	}
+
+	return nil
+}
+
+func isGeneratedType(typ string) bool {
+	_, ok := generatedTypes[typ]
+
+	return ok
+}
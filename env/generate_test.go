@@ -0,0 +1,114 @@
+package env_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	schema := env.Schema{
+		Package: "config",
+		Fields: []env.Field{
+			{Name: "HTTPPort", Env: "HTTP_PORT", Type: "int", Default: "8080", Doc: "is the port to listen on."},
+			{Name: "Timeout", Env: "TIMEOUT", Type: "time.Duration", Default: "5s"},
+			{Name: "Peers", Env: "PEERS", Type: "[]string"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, env.Generate(schema, &buf))
+
+	want := `// Code generated by env.Generate. DO NOT EDIT.
+
+package config
+
+import (
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+)
+
+// Config holds the configuration read from the environment.
+type Config struct {
+	// HTTPPort is the port to listen on.
+	HTTPPort int
+	// Timeout is read from TIMEOUT.
+	Timeout time.Duration
+	// Peers is read from PEERS.
+	Peers []string
+}
+
+// Load reads Config from the environment.
+// It panics if a variable can't be converted to the type of its field.
+func Load() *Config {
+	return &Config{
+		HTTPPort: env.GetEnv[int]("HTTP_PORT", env.WithDefault("8080")),
+		Timeout:  env.GetEnv[time.Duration]("TIMEOUT", env.WithDefault("5s")),
+		Peers:    env.GetEnv[[]string]("PEERS"),
+	}
+}
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestGenerateWithoutTime(t *testing.T) {
+	var buf bytes.Buffer
+	err := env.Generate(env.Schema{
+		Package: "config",
+		Struct:  "Settings",
+		Fields:  []env.Field{{Name: "Name", Env: "NAME", Type: "string"}},
+	}, &buf)
+
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), `"time"`)
+	assert.Contains(t, buf.String(), "func Load() *Settings {")
+}
+
+func TestGenerateImports(t *testing.T) {
+	var buf bytes.Buffer
+	err := env.Generate(env.Schema{
+		Package: "config",
+		Fields: []env.Field{
+			{Name: "Endpoint", Env: "ENDPOINT", Type: "*url.URL"},
+			{Name: "Bind", Env: "BIND", Type: "net.IP"},
+			{Name: "Timeout", Env: "TIMEOUT", Type: "time.Duration"},
+			{Name: "MaxBody", Env: "MAX_BODY", Type: "env.ByteSize"},
+		},
+	}, &buf)
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "import (\n\t\"net\"\n\t\"net/url\"\n\t\"time\"\n\n")
+	assert.Contains(t, buf.String(), `MaxBody:  env.GetEnv[env.ByteSize]("MAX_BODY"),`)
+}
+
+func TestGenerateInvalidSchema(t *testing.T) {
+	field := env.Field{Name: "Port", Env: "PORT", Type: "int"}
+	withField := func(f env.Field) env.Schema {
+		return env.Schema{Package: "config", Fields: []env.Field{f}}
+	}
+
+	tests := []struct {
+		name   string
+		schema env.Schema
+	}{
+		{"no package", env.Schema{Fields: []env.Field{field}}},
+		{"unexported struct", env.Schema{Package: "config", Struct: "config", Fields: []env.Field{field}}},
+		{"no fields", env.Schema{Package: "config"}},
+		{"unexported field", withField(env.Field{Name: "port", Env: "PORT", Type: "int"})},
+		{"duplicate field", env.Schema{Package: "config", Fields: []env.Field{field, field}}},
+		{"no env", withField(env.Field{Name: "Port", Type: "int"})},
+		{"unsupported type", withField(env.Field{Name: "Port", Env: "PORT", Type: "uint8"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.Error(t, env.Generate(tt.schema, &buf))
+			assert.Zero(t, buf.Len())
+		})
+	}
+}
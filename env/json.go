@@ -0,0 +1,58 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotSet is returned when a required environment variable is not set or is empty.
+var ErrNotSet = errors.New("env: variable not set")
+
+// GetEnvJSON reads a JSON or YAML document from the environment variable key
+// and decodes it into T, for structured configuration such as a list of endpoints
+// injected as a single variable or secret. WithDefault and WithDefaultT apply.
+//
+// The document is decoded as JSON when it starts with '{' or '[', and as YAML otherwise.
+// Both use the `json` struct tags, so one struct serves both formats.
+// It returns an error wrapping ErrNotSet if the variable is empty and there is no default.
+func GetEnvJSON[T any](key string, opts ...Option) (T, error) {
+	cfg := newOptions(key, opts)
+	cfg.record(true)
+	key = cfg.key
+
+	var result T
+	if cfg.err != nil {
+		return result, cfg.err
+	}
+
+	if cfg.typedDefault() {
+		return defaultT[T](cfg)
+	}
+
+	data := bytes.TrimSpace([]byte(cfg.value))
+	if len(data) == 0 {
+		return result, fmt.Errorf("%w: %s", ErrNotSet, key)
+	}
+
+	if data[0] != '{' && data[0] != '[' {
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return result, fmt.Errorf("env: failed to parse YAML in %s: %w", key, err)
+		}
+
+		var err error
+		if data, err = json.Marshal(doc); err != nil {
+			return result, fmt.Errorf("env: failed to convert YAML in %s: %w", key, err)
+		}
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("env: failed to parse JSON in %s: %w", key, err)
+	}
+
+	return result, nil
+}
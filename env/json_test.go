@@ -0,0 +1,73 @@
+package env_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type chainConfig struct {
+	Name      string   `json:"name"`
+	ChainID   int      `json:"chain_id"`
+	Endpoints []string `json:"endpoints"`
+	Timeout   string   `json:"timeout,omitempty"`
+}
+
+// TestGetEnvJSON verifies that a JSON document is decoded into a struct.
+func TestGetEnvJSON(t *testing.T) {
+	t.Setenv("CHAINS", `[
+		{"name": "ethereum", "chain_id": 1, "endpoints": ["https://a", "https://b"]},
+		{"name": "polygon", "chain_id": 137, "endpoints": ["https://c"], "timeout": "5s"}
+	]`)
+
+	chains, err := env.GetEnvJSON[[]chainConfig]("CHAINS")
+	require.NoError(t, err)
+	assert.Equal(t, []chainConfig{
+		{Name: "ethereum", ChainID: 1, Endpoints: []string{"https://a", "https://b"}},
+		{Name: "polygon", ChainID: 137, Endpoints: []string{"https://c"}, Timeout: "5s"},
+	}, chains)
+}
+
+// TestGetEnvJSONYAML verifies that a YAML document is decoded with the json tags.
+func TestGetEnvJSONYAML(t *testing.T) {
+	t.Setenv("CHAIN", "name: ethereum\nchain_id: 1\nendpoints:\n  - https://a\n")
+
+	chain, err := env.GetEnvJSON[chainConfig]("CHAIN")
+	require.NoError(t, err)
+	assert.Equal(t, chainConfig{Name: "ethereum", ChainID: 1, Endpoints: []string{"https://a"}}, chain)
+}
+
+// TestGetEnvJSONMap verifies decoding into maps and builtin types.
+func TestGetEnvJSONMap(t *testing.T) {
+	t.Setenv("LIMITS", `{"read": 100, "write": 10}`)
+
+	limits, err := env.GetEnvJSON[map[string]int]("LIMITS")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"read": 100, "write": 10}, limits)
+
+	timeouts, err := env.GetEnvJSON[map[string]time.Duration]("UNSET_TIMEOUTS", env.WithDefault(`{"rpc": 5}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{"rpc": 5}, timeouts)
+}
+
+// TestGetEnvJSONErrors verifies that missing and malformed documents are reported.
+func TestGetEnvJSONErrors(t *testing.T) {
+	_, err := env.GetEnvJSON[chainConfig]("UNSET_CHAIN")
+	require.ErrorIs(t, err, env.ErrNotSet)
+	assert.Contains(t, err.Error(), "UNSET_CHAIN")
+
+	t.Setenv("BAD_JSON", `{"name": `)
+	_, err = env.GetEnvJSON[chainConfig]("BAD_JSON")
+	require.ErrorContains(t, err, "BAD_JSON")
+
+	t.Setenv("BAD_YAML", "name: [unclosed")
+	_, err = env.GetEnvJSON[chainConfig]("BAD_YAML")
+	require.ErrorContains(t, err, "YAML")
+
+	t.Setenv("WRONG_TYPE", `{"chain_id": "one"}`)
+	_, err = env.GetEnvJSON[chainConfig]("WRONG_TYPE")
+	require.Error(t, err)
+}
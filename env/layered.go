@@ -0,0 +1,108 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+)
+
+// ErrConflict is returned by LoadLayered, with WithConflictError, for a variable
+// given different values by several files.
+var ErrConflict = errors.New("env: conflicting values")
+
+type loadOptions struct {
+	conflictError bool
+	keepProcess   bool
+}
+
+// LoadOption customizes how LoadLayered, LoadFromYAML and LoadFromJSON load files.
+type LoadOption func(opts *loadOptions)
+
+// WithConflictError returns a LoadOption that makes LoadLayered fail, without
+// setting any variable, if files give different values to the same variable.
+func WithConflictError() LoadOption {
+	return func(opts *loadOptions) {
+		opts.conflictError = true
+	}
+}
+
+// WithoutOverride returns a LoadOption that keeps the variables already set
+// in the process environment, as LoadEnvsFromFile does, so the files only provide defaults.
+func WithoutOverride() LoadOption {
+	return func(opts *loadOptions) {
+		opts.keepProcess = true
+	}
+}
+
+// LoadLayered loads the env files into the process environment with
+// the precedence of their order, such as:
+//
+//	env.LoadLayered(".env", ".env.local", ".env."+os.Getenv("APP_ENV"))
+//
+// Each file overrides the variables of the files before it, and the files
+// override the variables already set in the process environment.
+// Missing files, and empty names, are skipped, so optional layers such as .env.local
+// don't have to exist. Use LoadLayeredWith for more control.
+func LoadLayered(files ...string) error {
+	return LoadLayeredWith(files)
+}
+
+// LoadLayeredWith loads the env files like LoadLayered, customized by opts:
+//
+//	// Variables set by the deployment win over the files.
+//	err := env.LoadLayeredWith([]string{".env", ".env.production"}, env.WithoutOverride())
+func LoadLayeredWith(files []string, opts ...LoadOption) error {
+	cfg := loadOptions{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	vars := map[string]string{}
+	origins := map[string]string{}
+	var conflicts []error
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+
+		parsed, err := ParseFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("env: failed to read %s: %w", file, err)
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(parsed)) {
+			if prev, ok := vars[key]; ok && prev != parsed[key] {
+				conflicts = append(conflicts, fmt.Errorf("%w: %s in %s and %s", ErrConflict, key, origins[key], file))
+			}
+			vars[key] = parsed[key]
+			origins[key] = file
+		}
+	}
+
+	if cfg.conflictError && len(conflicts) > 0 {
+		return errors.Join(conflicts...)
+	}
+
+	return setVars(vars, origins, cfg)
+}
+
+// setVars sets vars in the process environment, keeping the variables already set
+// with WithoutOverride. origins names the file of each variable, for errors.
+func setVars(vars, origins map[string]string, cfg loadOptions) error {
+	for key, val := range vars {
+		if _, set := os.LookupEnv(key); set && cfg.keepProcess {
+			continue
+		}
+		if err := os.Setenv(key, val); err != nil {
+			return fmt.Errorf("env: failed to set %s from %s: %w", key, origins[key], err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLayers(t *testing.T) (base, local, production string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	base = filepath.Join(dir, ".env")
+	local = filepath.Join(dir, ".env.local")
+	production = filepath.Join(dir, ".env.production")
+	require.NoError(t, os.WriteFile(base, []byte("LAYER_HOST=localhost\nLAYER_PORT=8080\nLAYER_NAME=app\n"), 0o600))
+	require.NoError(t, os.WriteFile(production, []byte("LAYER_HOST=db.prod\nLAYER_NAME=app\n"), 0o600))
+
+	// Restore the variables set by the tests.
+	for _, key := range []string{"LAYER_HOST", "LAYER_PORT", "LAYER_NAME"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+
+	return base, local, production
+}
+
+func TestLoadLayered(t *testing.T) {
+	base, local, production := writeLayers(t)
+	t.Setenv("LAYER_PORT", "9090")
+
+	require.NoError(t, env.LoadLayered(base, local, production, ""))
+
+	assert.Equal(t, "db.prod", os.Getenv("LAYER_HOST"))
+	assert.Equal(t, "8080", os.Getenv("LAYER_PORT"), "files override the process environment")
+	assert.Equal(t, "app", os.Getenv("LAYER_NAME"))
+}
+
+func TestLoadLayeredWithoutOverride(t *testing.T) {
+	base, local, production := writeLayers(t)
+	t.Setenv("LAYER_PORT", "9090")
+
+	require.NoError(t, env.LoadLayeredWith([]string{base, local, production}, env.WithoutOverride()))
+
+	assert.Equal(t, "db.prod", os.Getenv("LAYER_HOST"))
+	assert.Equal(t, "9090", os.Getenv("LAYER_PORT"))
+}
+
+func TestLoadLayeredConflictError(t *testing.T) {
+	base, local, production := writeLayers(t)
+
+	err := env.LoadLayeredWith([]string{base, local, production}, env.WithConflictError())
+	require.ErrorIs(t, err, env.ErrConflict)
+	assert.ErrorContains(t, err, "LAYER_HOST in "+base+" and "+production)
+	assert.NotContains(t, err.Error(), "LAYER_NAME", "equal values don't conflict")
+
+	_, set := os.LookupEnv("LAYER_HOST")
+	assert.False(t, set, "nothing is set on conflicts")
+}
+
+func TestLoadLayeredInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("INVALID LINE"), 0o600))
+
+	require.ErrorContains(t, env.LoadLayered(path), path)
+}
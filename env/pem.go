@@ -0,0 +1,154 @@
+package env
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WithBase64Decode returns an Option that decodes the value of the variable
+// from base64 before converting it, so binary secrets such as keys can be passed
+// as variables:
+//
+//	key := env.MustGetEnv[[]byte]("SIGNING_KEY", env.WithBase64Decode())
+//
+// Standard and URL-safe encodings are accepted, padded or not, and whitespace is ignored.
+// The default is used as is.
+func WithBase64Decode() Option {
+	return func(opts *options) {
+		opts.base64 = true
+	}
+}
+
+func decodeBase64(val string) (string, error) {
+	val = strings.Join(strings.Fields(val), "")
+
+	var firstErr error
+	for _, encoding := range []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding,
+	} {
+		decoded, err := encoding.DecodeString(val)
+		if err == nil {
+			return string(decoded), nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return "", fmt.Errorf("failed to decode base64: %w", firstErr)
+}
+
+// LookupPrivateKey reads a PEM-encoded private key, in PKCS #8, PKCS #1 or SEC 1 form,
+// from the variable key, such as a signing key. The key is a crypto.Signer,
+// such as an *ecdsa.PrivateKey, *rsa.PrivateKey or ed25519.PrivateKey.
+//
+// Escaped newlines ("\n") are accepted in place of line breaks, as variables often
+// hold them, and WithBase64Decode reads a base64-encoded PEM document.
+// It returns an error wrapping ErrNotSet if the variable is empty and there is no default.
+func LookupPrivateKey(key string, opts ...Option) (crypto.Signer, error) {
+	blocks, cfg, err := lookupPEM(key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks {
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue
+		}
+
+		signer, err := parsePrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("env: invalid %s: %w", cfg.key, err)
+		}
+
+		return signer, nil
+	}
+
+	return nil, fmt.Errorf("env: invalid %s: no PEM private key", cfg.key)
+}
+
+// LookupCertificates reads the PEM-encoded certificates of the variable key,
+// such as a certificate chain or a set of CA roots, in their order.
+// It accepts the values LookupPrivateKey accepts, and ignores the other PEM blocks.
+func LookupCertificates(key string, opts ...Option) ([]*x509.Certificate, error) {
+	blocks, cfg, err := lookupPEM(key, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for _, block := range blocks {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("env: invalid %s: %w", cfg.key, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("env: invalid %s: no PEM certificate", cfg.key)
+	}
+
+	return certs, nil
+}
+
+func lookupPEM(key string, opts []Option) ([]*pem.Block, *options, error) {
+	cfg := newOptions(key, opts)
+	cfg.record(true)
+	if cfg.err != nil {
+		return nil, cfg, cfg.err
+	}
+	if cfg.value == "" {
+		return nil, cfg, fmt.Errorf("%w: %s", ErrNotSet, cfg.key)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, cfg, fmt.Errorf("env: invalid %s: %w", cfg.key, err)
+	}
+
+	data := []byte(cfg.value)
+	if !strings.Contains(cfg.value, "\n") {
+		data = []byte(strings.ReplaceAll(cfg.value, `\n`, "\n"))
+	}
+
+	var blocks []*pem.Block
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	if len(blocks) == 0 {
+		return nil, cfg, fmt.Errorf("env: invalid %s: no PEM data", cfg.key)
+	}
+
+	return blocks, cfg, nil
+}
+
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("failed to parse private key: not PKCS #8, PKCS #1 or SEC 1")
+}
@@ -0,0 +1,125 @@
+package env_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64Decode(t *testing.T) {
+	secret := []byte{0x00, 0xff, 0x10, 'k', 'e', 'y'}
+
+	for _, encoded := range []string{
+		base64.StdEncoding.EncodeToString(secret),
+		base64.RawURLEncoding.EncodeToString(secret),
+		"AP8Qa2V5\n",
+	} {
+		t.Setenv("BASE64_KEY", encoded)
+		assert.Equal(t, secret, env.MustGetEnv[[]byte]("BASE64_KEY", env.WithBase64Decode()), encoded)
+	}
+
+	t.Setenv("BASE64_KEY", "aGVsbG8=")
+	assert.Equal(t, "hello", env.GetEnv[string]("BASE64_KEY", env.WithBase64Decode()))
+	assert.Equal(t, []byte("aGVsbG8="), env.GetEnv[[]byte]("BASE64_KEY"), "values are raw without the option")
+
+	t.Setenv("BASE64_KEY", "not base64!")
+	_, err := env.LookupEnv[[]byte]("BASE64_KEY", env.WithBase64Decode())
+	require.ErrorContains(t, err, "BASE64_KEY")
+
+	t.Setenv("BASE64_KEY", "")
+	assert.Equal(t, []byte("raw"), env.GetEnv[[]byte]("BASE64_KEY", env.WithBase64Decode(), env.WithDefault("raw")))
+}
+
+func TestUnmarshalBase64(t *testing.T) {
+	t.Setenv("UNMARSHAL_KEY", "aGVsbG8=")
+	t.Setenv("UNMARSHAL_RAW", "aGVsbG8=")
+
+	var cfg struct {
+		Key []byte `env:"UNMARSHAL_KEY,base64"`
+		Raw []byte `env:"UNMARSHAL_RAW"`
+	}
+	require.NoError(t, env.Unmarshal(&cfg))
+	assert.Equal(t, []byte("hello"), cfg.Key)
+	assert.Equal(t, []byte("aGVsbG8="), cfg.Raw)
+}
+
+func testPEM(t *testing.T) (keyPEM, certPEM string, key *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "env test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+
+	return keyPEM, certPEM, key
+}
+
+func TestLookupPrivateKey(t *testing.T) {
+	keyPEM, certPEM, key := testPEM(t)
+
+	for name, val := range map[string]string{
+		"pem":     keyPEM,
+		"escaped": strings.ReplaceAll(keyPEM, "\n", `\n`),
+		"bundle":  certPEM + keyPEM,
+	} {
+		t.Setenv("PEM_KEY", val)
+		signer, err := env.LookupPrivateKey("PEM_KEY")
+		require.NoError(t, err, name)
+		assert.True(t, key.Equal(signer), name)
+	}
+
+	t.Setenv("PEM_KEY", base64.StdEncoding.EncodeToString([]byte(keyPEM)))
+	signer, err := env.LookupPrivateKey("PEM_KEY", env.WithBase64Decode())
+	require.NoError(t, err)
+	assert.True(t, key.Equal(signer))
+
+	t.Setenv("PEM_KEY", certPEM)
+	_, err = env.LookupPrivateKey("PEM_KEY")
+	require.ErrorContains(t, err, "no PEM private key")
+
+	t.Setenv("PEM_KEY", "not pem")
+	_, err = env.LookupPrivateKey("PEM_KEY")
+	require.ErrorContains(t, err, "no PEM data")
+
+	t.Setenv("PEM_KEY", "")
+	_, err = env.LookupPrivateKey("PEM_KEY")
+	require.ErrorIs(t, err, env.ErrNotSet)
+}
+
+func TestLookupCertificates(t *testing.T) {
+	keyPEM, certPEM, _ := testPEM(t)
+
+	t.Setenv("PEM_CERTS", certPEM+keyPEM+certPEM)
+	certs, err := env.LookupCertificates("PEM_CERTS")
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+	assert.Equal(t, "env test", certs[0].Subject.CommonName)
+
+	t.Setenv("PEM_CERTS", keyPEM)
+	_, err = env.LookupCertificates("PEM_CERTS")
+	require.ErrorContains(t, err, "no PEM certificate")
+}
@@ -0,0 +1,126 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Provider resolves variables from a source beyond the process environment,
+// such as HashiCorp Vault, AWS Secrets Manager or Docker secrets files,
+// so production secrets don't have to be injected as plain variables.
+type Provider interface {
+	// Lookup returns the value of the variable key, and whether the provider has it.
+	// An error, such as the store being unreachable, is reported by GetEnv
+	// and the other functions instead of treating the variable as unset.
+	Lookup(key string) (value string, ok bool, err error)
+}
+
+// ProviderFunc adapts a function to a Provider.
+type ProviderFunc func(key string) (string, bool, error)
+
+func (f ProviderFunc) Lookup(key string) (string, bool, error) {
+	return f(key)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   []Provider
+)
+
+// RegisterProvider adds p to the providers consulted, in registration order,
+// for the variables that are empty in the process environment, which therefore
+// still overrides them, as do the files named by the variables with FileSuffix.
+// Readers created with WithReader and NewFromMap don't consult the providers.
+func RegisterProvider(p Provider) {
+	providersMu.Lock()
+	providers = append(providers, p)
+	providersMu.Unlock()
+}
+
+// ResetProviders removes the registered providers, typically in test cleanups:
+//
+//	t.Cleanup(env.ResetProviders)
+func ResetProviders() {
+	providersMu.Lock()
+	providers = nil
+	providersMu.Unlock()
+}
+
+// FileSuffix marks a variable naming the file that holds the value of another,
+// as Docker and Kubernetes pass secrets: with MY_SECRET_FILE=/run/secrets/my_secret
+// set and MY_SECRET empty, GetEnv[string]("MY_SECRET") returns the contents of the file,
+// with a single trailing newline trimmed. A file that can't be read is reported
+// as an error, not as an unset variable.
+const FileSuffix = "_FILE"
+
+// lookupProcess looks key up in the process environment, then in the file
+// named by key with FileSuffix, then in the providers.
+func lookupProcess(key string) (string, bool, error) {
+	if val := os.Getenv(key); val != "" {
+		return val, true, nil
+	}
+
+	if path := os.Getenv(key + FileSuffix); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("%s%s: %w", key, FileSuffix, err)
+		}
+
+		return trimNewline(string(data)), true, nil
+	}
+
+	providersMu.RLock()
+	registered := providers
+	providersMu.RUnlock()
+
+	for _, provider := range registered {
+		val, ok, err := provider.Lookup(key)
+		if err != nil || ok {
+			return val, ok, err
+		}
+	}
+
+	_, ok := os.LookupEnv(key)
+
+	return "", ok, nil
+}
+
+// NewFileProvider returns a Provider reading each variable from the file of the same
+// name in dir, or of its lowercase name, as Docker and Kubernetes mount secrets:
+//
+//	env.RegisterProvider(env.NewFileProvider("/run/secrets"))
+//	password := env.GetEnv[string]("DB_PASSWORD") // reads /run/secrets/db_password
+//
+// A single trailing newline is trimmed from the contents.
+func NewFileProvider(dir string) Provider {
+	return ProviderFunc(func(key string) (string, bool, error) {
+		if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+			return "", false, nil
+		}
+
+		for _, name := range []string{key, strings.ToLower(key)} {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return "", false, err
+			}
+
+			return trimNewline(string(data)), true, nil
+		}
+
+		return "", false, nil
+	})
+}
+
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+
+	return strings.TrimSuffix(s, "\r")
+}
@@ -0,0 +1,116 @@
+package env_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mapProvider(vars map[string]string) env.Provider {
+	return env.ProviderFunc(func(key string) (string, bool, error) {
+		val, ok := vars[key]
+
+		return val, ok, nil
+	})
+}
+
+func TestProviderFallback(t *testing.T) {
+	t.Cleanup(env.ResetProviders)
+	env.RegisterProvider(mapProvider(map[string]string{"PROVIDER_SECRET": "from-vault", "PROVIDER_PORT": "1"}))
+	env.RegisterProvider(mapProvider(map[string]string{"PROVIDER_SECRET": "shadowed", "PROVIDER_NAME": "second"}))
+	t.Setenv("PROVIDER_PORT", "8080")
+
+	assert.Equal(t, "from-vault", env.GetEnv[string]("PROVIDER_SECRET"))
+	assert.Equal(t, "second", env.GetEnv[string]("PROVIDER_NAME"))
+	assert.Equal(t, 8080, env.GetEnv[int]("PROVIDER_PORT"), "the environment overrides providers")
+
+	_, err := env.LookupEnv[string]("PROVIDER_MISSING")
+	require.ErrorIs(t, err, env.ErrNotSet)
+	assert.Equal(t, "def", env.GetEnv[string]("PROVIDER_MISSING", env.WithDefault("def")))
+}
+
+func TestProviderError(t *testing.T) {
+	t.Cleanup(env.ResetProviders)
+	errUnreachable := errors.New("vault unreachable")
+	env.RegisterProvider(env.ProviderFunc(func(string) (string, bool, error) {
+		return "", false, errUnreachable
+	}))
+
+	_, err := env.LookupEnv[string]("PROVIDER_SECRET", env.WithDefault("def"))
+	require.ErrorIs(t, err, errUnreachable)
+	require.ErrorContains(t, err, "PROVIDER_SECRET")
+
+	var cfg struct {
+		Secret string `env:"PROVIDER_SECRET"`
+	}
+	require.ErrorIs(t, env.Unmarshal(&cfg), errUnreachable)
+
+	assert.Panics(t, func() { env.GetEnv[string]("PROVIDER_SECRET") })
+
+	t.Setenv("PROVIDER_SECRET", "set")
+	assert.Equal(t, "set", env.GetEnv[string]("PROVIDER_SECRET"), "providers are not consulted")
+}
+
+func TestProviderNotUsedByReaders(t *testing.T) {
+	t.Cleanup(env.ResetProviders)
+	env.RegisterProvider(mapProvider(map[string]string{"PROVIDER_SECRET": "from-vault"}))
+
+	_, err := env.LookupEnv[string]("PROVIDER_SECRET", env.WithReader(env.NewFromMap(nil)))
+	require.ErrorIs(t, err, env.ErrNotSet)
+
+	val, ok, err := env.WithPrefix("PROVIDER_").Lookup("SECRET")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "from-vault", val)
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Cleanup(env.ResetProviders)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("s3cret\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "API_KEY"), []byte("key\r\n"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "DIR"), 0o700))
+
+	provider := env.NewFileProvider(dir)
+	env.RegisterProvider(provider)
+
+	assert.Equal(t, "s3cret", env.GetEnv[string]("DB_PASSWORD"))
+	assert.Equal(t, "key", env.GetEnv[string]("API_KEY"))
+
+	_, ok, err := provider.Lookup("MISSING")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = provider.Lookup("../" + filepath.Base(dir) + "/API_KEY")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = provider.Lookup("DIR")
+	require.Error(t, err)
+}
+
+func TestFileIndirection(t *testing.T) {
+	t.Cleanup(env.ResetProviders)
+	env.RegisterProvider(mapProvider(map[string]string{"FILE_SECRET": "from-vault"}))
+
+	path := filepath.Join(t.TempDir(), "file_secret")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+	t.Setenv("FILE_SECRET_FILE", path)
+
+	assert.Equal(t, "from-file", env.GetEnv[string]("FILE_SECRET"), "the file wins over the providers")
+	assert.Equal(t, "from-file", env.GetEnv[string]("SECRET", env.WithReader(env.WithPrefix("FILE_"))))
+
+	t.Setenv("FILE_SECRET", "from-env")
+	assert.Equal(t, "from-env", env.GetEnv[string]("FILE_SECRET"), "the variable wins over the file")
+
+	t.Setenv("FILE_SECRET", "")
+	t.Setenv("FILE_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err := env.LookupEnv[string]("FILE_SECRET")
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Contains(t, err.Error(), "FILE_SECRET_FILE")
+}
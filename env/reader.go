@@ -0,0 +1,106 @@
+package env
+
+import (
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Reader looks up variables in a source, such as a parsed env file,
+// optionally scoped to a prefix. Read from it with the WithReader option:
+//
+//	vars, err := env.ParseFile(".env.staging")
+//	...
+//	port, err := env.LookupEnv[int]("HTTP_PORT", env.WithReader(env.NewFromMap(vars)))
+type Reader struct {
+	lookup func(key string) (string, bool, error)
+	keys   func() []string
+	prefix string
+}
+
+// NewFromMap returns a Reader looking up variables in a copy of m.
+func NewFromMap(m map[string]string) *Reader {
+	vars := maps.Clone(m)
+
+	return &Reader{
+		lookup: func(key string) (string, bool, error) {
+			val, ok := vars[key]
+
+			return val, ok, nil
+		},
+		keys: func() []string {
+			return slices.Collect(maps.Keys(vars))
+		},
+	}
+}
+
+// WithPrefix returns a Reader of the process environment, and of the registered
+// providers, scoped to prefix,
+// so a component reads its configuration without repeating the prefix:
+//
+//	payments := env.WithPrefix("PAYMENTS_")
+//	url := env.GetEnv[string]("API_URL", env.WithReader(payments)) // reads PAYMENTS_API_URL
+func WithPrefix(prefix string) *Reader {
+	process := &Reader{
+		lookup: lookupProcess,
+		keys: func() []string {
+			keys := make([]string, 0, len(os.Environ()))
+			for _, kv := range os.Environ() {
+				key, _, _ := strings.Cut(kv, "=")
+				keys = append(keys, key)
+			}
+
+			return keys
+		},
+	}
+
+	return process.WithPrefix(prefix)
+}
+
+// WithPrefix returns a Reader of the same source scoped to the prefix
+// of r followed by prefix.
+func (r *Reader) WithPrefix(prefix string) *Reader {
+	return &Reader{
+		lookup: r.lookup,
+		keys:   r.keys,
+		prefix: r.prefix + prefix,
+	}
+}
+
+// Lookup returns the value of the variable key, with the prefix of r, and whether it is set.
+// It returns the error of a provider that failed to look the variable up.
+func (r *Reader) Lookup(key string) (string, bool, error) {
+	return r.lookup(r.prefix + key)
+}
+
+// Keys returns the sorted keys of the variables set under the prefix of r, without it.
+// Providers can't be listed, so their variables are left out.
+func (r *Reader) Keys() []string {
+	var keys []string
+	for _, key := range r.keys() {
+		if rest, ok := strings.CutPrefix(key, r.prefix); ok && rest != "" {
+			keys = append(keys, rest)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// WithReader returns an Option that reads the variable from r
+// instead of the process environment.
+func WithReader(r *Reader) Option {
+	return func(opts *options) {
+		opts.reader = r
+	}
+}
+
+// ParseFile parses the env file at path, in the format LoadEnvsFromFile reads,
+// and returns its variables without setting them in the process environment,
+// so env files can be inspected, validated or compared.
+func ParseFile(path string) (map[string]string, error) {
+	return godotenv.Read(path)
+}
@@ -0,0 +1,84 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# comment\nHTTP_PORT=9090\nexport TIMEOUT=5s\nNAME=\"quoted value\"\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	vars, err := env.ParseFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"HTTP_PORT": "9090", "TIMEOUT": "5s", "NAME": "quoted value"}, vars)
+
+	_, set := os.LookupEnv("HTTP_PORT")
+	assert.False(t, set, "expected the process environment to be left alone")
+
+	_, err = env.ParseFile(filepath.Join(t.TempDir(), "missing.env"))
+	require.Error(t, err)
+}
+
+func TestNewFromMap(t *testing.T) {
+	t.Setenv("HTTP_PORT", "8080")
+
+	vars := map[string]string{"HTTP_PORT": "9090", "TIMEOUT": "5s"}
+	reader := env.NewFromMap(vars)
+	vars["TIMEOUT"] = "changed"
+
+	assert.Equal(t, 9090, env.GetEnv[int]("HTTP_PORT", env.WithReader(reader)))
+	assert.Equal(t, 5*time.Second, env.GetEnv[time.Duration]("TIMEOUT", env.WithReader(reader)))
+	assert.Equal(t, 1, env.GetEnv[int]("WORKERS", env.WithReader(reader), env.WithDefault("1")))
+
+	_, err := env.LookupEnv[string]("NAME", env.WithReader(reader))
+	require.ErrorIs(t, err, env.ErrNotSet)
+
+	val, ok, err := reader.Lookup("HTTP_PORT")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "9090", val)
+
+	var cfg struct {
+		Port int `env:"HTTP_PORT"`
+	}
+	require.NoError(t, env.Unmarshal(&cfg, env.WithReader(reader)))
+	assert.Equal(t, 9090, cfg.Port)
+}
+
+func TestWithPrefix(t *testing.T) {
+	t.Setenv("PAYMENTS_API_URL", "https://pay.ezex.io")
+	t.Setenv("PAYMENTS_RETRIES", "x")
+	t.Setenv("PAYMENTS_STRIPE_KEY", "sk")
+
+	payments := env.WithPrefix("PAYMENTS_")
+	assert.Equal(t, "https://pay.ezex.io", env.GetEnv[string]("API_URL", env.WithReader(payments)))
+	assert.Equal(t, []string{"API_URL", "RETRIES", "STRIPE_KEY"}, payments.Keys())
+
+	_, err := env.LookupEnv[int]("RETRIES", env.WithReader(payments))
+	require.ErrorContains(t, err, "env: invalid PAYMENTS_RETRIES")
+	_, err = env.LookupEnv[int]("TIMEOUT", env.WithReader(payments))
+	require.ErrorContains(t, err, "PAYMENTS_TIMEOUT")
+
+	stripe := payments.WithPrefix("STRIPE_")
+	val, ok, err := stripe.Lookup("KEY")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "sk", val)
+	assert.Equal(t, []string{"KEY"}, stripe.Keys())
+}
+
+func TestReaderKeys(t *testing.T) {
+	reader := env.NewFromMap(map[string]string{"DB_HOST": "h", "DB_PORT": "1", "HTTP_PORT": "2"})
+
+	assert.Equal(t, []string{"DB_HOST", "DB_PORT", "HTTP_PORT"}, reader.Keys())
+	assert.Equal(t, []string{"HOST", "PORT"}, reader.WithPrefix("DB_").Keys())
+	assert.Empty(t, reader.WithPrefix("CACHE_").Keys())
+}
@@ -0,0 +1,131 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// KeyRead describes how a variable was read.
+type KeyRead struct {
+	// Key is the variable, with the prefix of the reader if any.
+	Key string
+	// Set reports whether the variable had a value.
+	Set bool
+	// Default reports whether the variable was empty and its default was used.
+	Default bool
+	// Required reports whether the variable was read as required, by LookupEnv,
+	// MustGetEnv, GetEnvJSON or a `required` field of Unmarshal.
+	Required bool
+}
+
+// Missing reports whether the variable is required but had neither a value nor a default.
+func (k KeyRead) Missing() bool {
+	return k.Required && !k.Set && !k.Default
+}
+
+// ReadReport lists the variables read so far, sorted by key.
+type ReadReport []KeyRead
+
+// Defaults returns the variables whose default was used.
+func (r ReadReport) Defaults() []string {
+	return r.keys(func(k KeyRead) bool { return k.Default })
+}
+
+// Missing returns the required variables that had neither a value nor a default.
+func (r ReadReport) Missing() []string {
+	return r.keys(KeyRead.Missing)
+}
+
+func (r ReadReport) keys(match func(KeyRead) bool) []string {
+	var keys []string
+	for _, read := range r {
+		if match(read) {
+			keys = append(keys, read.Key)
+		}
+	}
+
+	return keys
+}
+
+var (
+	readsMu sync.Mutex
+	reads   = map[string]KeyRead{}
+	values  = map[string]string{} // the values read, for Dump
+)
+
+// Report returns the variables read so far, by any function of the package, so
+// a misconfigured deployment can be reported at boot rather than at the first use
+// of a variable:
+//
+//	cfg := loadConfig()
+//	report := env.Report()
+//	log.Info("config loaded", "defaults", report.Defaults(), "missing", report.Missing())
+//
+// A variable read several times is reported once, as of its last read,
+// and required if any of its reads was.
+func Report() ReadReport {
+	readsMu.Lock()
+	defer readsMu.Unlock()
+
+	report := make(ReadReport, 0, len(reads))
+	for _, key := range slices.Sorted(maps.Keys(reads)) {
+		report = append(report, reads[key])
+	}
+
+	return report
+}
+
+// ResetReport forgets the variables read so far, typically in test cleanups.
+func ResetReport() {
+	readsMu.Lock()
+	clear(reads)
+	clear(values)
+	readsMu.Unlock()
+}
+
+// ValidateRequired checks that the variables keys are set in the process environment,
+// or by a registered provider, and returns an error joining one error wrapping
+// ErrNotSet per missing variable:
+//
+//	if err := env.ValidateRequired("DATABASE_URL", "JWT_SECRET"); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The variables are recorded as required in the Report.
+func ValidateRequired(keys ...string) error {
+	var errs []error
+	for _, key := range keys {
+		cfg := newOptions(key, nil)
+		cfg.record(true)
+
+		switch {
+		case cfg.err != nil:
+			errs = append(errs, cfg.err)
+		case cfg.value == "":
+			errs = append(errs, fmt.Errorf("%w: %s", ErrNotSet, key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// record adds the read of cfg to the Report.
+func (cfg *options) record(required bool) {
+	readsMu.Lock()
+	defer readsMu.Unlock()
+
+	read := KeyRead{
+		Key:      cfg.key,
+		Set:      cfg.found,
+		Default:  !cfg.found && cfg.hasDefault && (cfg.defVal != "" || cfg.defTyped != nil),
+		Required: required || reads[cfg.key].Required,
+	}
+	reads[cfg.key] = read
+	values[cfg.key] = cfg.value
+	if cfg.typedDefault() {
+		values[cfg.key] = fmt.Sprint(cfg.defTyped)
+	}
+}
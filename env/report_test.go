@@ -0,0 +1,71 @@
+package env_test
+
+import (
+	"testing"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	env.ResetReport()
+	t.Cleanup(env.ResetReport)
+	t.Setenv("REPORT_PORT", "8080")
+
+	env.GetEnv[int]("REPORT_PORT")
+	env.GetEnv[string]("REPORT_HOST", env.WithDefault("localhost"))
+	env.GetEnv[string]("REPORT_OPTIONAL")
+	_, _ = env.LookupEnv[string]("REPORT_SECRET")
+	_, _ = env.LookupEnv[string]("REPORT_SECRET", env.WithReader(env.NewFromMap(nil)))
+	stripe := env.NewFromMap(map[string]string{"STRIPE_KEY": "sk"}).WithPrefix("STRIPE_")
+	_, _ = env.LookupEnv[string]("KEY", env.WithReader(stripe))
+
+	var cfg struct {
+		Token string `env:"REPORT_TOKEN,required"`
+	}
+	_ = env.Unmarshal(&cfg)
+
+	assert.Equal(t, env.ReadReport{
+		{Key: "REPORT_HOST", Default: true},
+		{Key: "REPORT_OPTIONAL"},
+		{Key: "REPORT_PORT", Set: true},
+		{Key: "REPORT_SECRET", Required: true},
+		{Key: "REPORT_TOKEN", Required: true},
+		{Key: "STRIPE_KEY", Set: true, Required: true},
+	}, env.Report())
+
+	report := env.Report()
+	assert.Equal(t, []string{"REPORT_HOST"}, report.Defaults())
+	assert.Equal(t, []string{"REPORT_SECRET", "REPORT_TOKEN"}, report.Missing())
+
+	env.ResetReport()
+	assert.Empty(t, env.Report())
+}
+
+func TestReportRequiredSticks(t *testing.T) {
+	env.ResetReport()
+	t.Cleanup(env.ResetReport)
+
+	_, _ = env.LookupEnv[string]("REPORT_SECRET")
+	env.GetEnv[string]("REPORT_SECRET")
+
+	assert.Equal(t, []string{"REPORT_SECRET"}, env.Report().Missing())
+}
+
+func TestValidateRequired(t *testing.T) {
+	env.ResetReport()
+	t.Cleanup(env.ResetReport)
+	t.Setenv("REPORT_DATABASE_URL", "postgres://db")
+	t.Setenv("REPORT_EMPTY", "")
+
+	require.NoError(t, env.ValidateRequired("REPORT_DATABASE_URL"))
+
+	err := env.ValidateRequired("REPORT_DATABASE_URL", "REPORT_JWT_SECRET", "REPORT_EMPTY")
+	require.ErrorIs(t, err, env.ErrNotSet)
+	assert.ErrorContains(t, err, "REPORT_JWT_SECRET")
+	assert.ErrorContains(t, err, "REPORT_EMPTY")
+	assert.NotContains(t, err.Error(), "REPORT_DATABASE_URL")
+
+	assert.Equal(t, []string{"REPORT_EMPTY", "REPORT_JWT_SECRET"}, env.Report().Missing())
+}
@@ -0,0 +1,118 @@
+package env
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes, read from values such as "512MB" or "1.5GiB".
+// KB, MB, GB and TB are powers of 1000, KiB, MiB, GiB and TiB powers of 1024,
+// and a plain number is a number of bytes. Units are case-insensitive.
+type ByteSize int64
+
+// Byte size units.
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"kb":  KB,
+	"mb":  MB,
+	"gb":  GB,
+	"tb":  TB,
+	"kib": KiB,
+	"mib": MiB,
+	"gib": GiB,
+	"tib": TiB,
+}
+
+func parseByteSize(val string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(val)
+	split := strings.IndexFunc(trimmed, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split < 0 {
+		split = len(trimmed)
+	}
+
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(trimmed[split:]))]
+	if !ok {
+		return 0, fmt.Errorf("failed to parse byte size %q: unknown unit", val)
+	}
+
+	number, err := strconv.ParseFloat(trimmed[:split], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse byte size %q: %w", val, err)
+	}
+
+	size := number * float64(unit)
+	if size > math.MaxInt64 {
+		return 0, fmt.Errorf("failed to parse byte size %q: out of range", val)
+	}
+
+	return ByteSize(size), nil
+}
+
+func parseInts(val string) ([]int, error) {
+	if val == "" {
+		return []int{}, nil
+	}
+
+	parts := strings.Split(val, ",")
+	ints := make([]int, len(parts))
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %q to int: %w", part, err)
+		}
+		ints[i] = v
+	}
+
+	return ints, nil
+}
+
+// parseMap parses "k=v,k=v" pairs. Values may contain '=' but not ','.
+func parseMap(val string) (map[string]string, error) {
+	m := make(map[string]string)
+	if val == "" {
+		return m, nil
+	}
+
+	for pair := range strings.SplitSeq(val, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("failed to parse %q as key=value", pair)
+		}
+		m[key] = value
+	}
+
+	return m, nil
+}
+
+// parseURL parses an absolute URL, so a missing scheme is caught early.
+func parseURL(val string) (*url.URL, error) {
+	u, err := url.Parse(val)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL %q: %w", val, err)
+	}
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("failed to parse URL %q: missing scheme", val)
+	}
+
+	return u, nil
+}
@@ -0,0 +1,79 @@
+package env_test
+
+import (
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEnvMoreTypes(t *testing.T) {
+	t.Setenv("MY_INT64", "9000000000")
+	t.Setenv("MY_UINT", "42")
+	t.Setenv("MY_INTS", "1,2,3")
+	t.Setenv("MY_MAP", "region=eu,tier=gold=1")
+	t.Setenv("MY_URL", "https://api.ezex.io/v1?x=1")
+	t.Setenv("MY_IP", "10.0.0.1")
+	t.Setenv("MY_SIZE", "512MB")
+
+	assert.Equal(t, int64(9000000000), env.GetEnv[int64]("MY_INT64"))
+	assert.Equal(t, uint(42), env.GetEnv[uint]("MY_UINT"))
+	assert.Equal(t, []int{1, 2, 3}, env.GetEnv[[]int]("MY_INTS"))
+	assert.Equal(t, map[string]string{"region": "eu", "tier": "gold=1"}, env.GetEnv[map[string]string]("MY_MAP"))
+	assert.Equal(t, "api.ezex.io", env.GetEnv[*url.URL]("MY_URL").Host)
+	assert.True(t, net.IPv4(10, 0, 0, 1).Equal(env.GetEnv[net.IP]("MY_IP")))
+	assert.Equal(t, 512*env.MB, env.GetEnv[env.ByteSize]("MY_SIZE"))
+
+	assert.Equal(t, []int{}, env.GetEnv[[]int]("MY_UNSET_INTS"))
+	assert.Equal(t, map[string]string{}, env.GetEnv[map[string]string]("MY_UNSET_MAP"))
+}
+
+func TestGetEnvMoreTypesInvalid(t *testing.T) {
+	t.Setenv("MY_BAD", "-1,x")
+
+	_, err := env.LookupEnv[uint]("MY_BAD")
+	require.Error(t, err)
+	_, err = env.LookupEnv[int64]("MY_BAD")
+	require.Error(t, err)
+	_, err = env.LookupEnv[[]int]("MY_BAD")
+	require.ErrorContains(t, err, `"x"`)
+	_, err = env.LookupEnv[map[string]string]("MY_BAD")
+	require.ErrorContains(t, err, "key=value")
+	_, err = env.LookupEnv[*url.URL]("MY_BAD")
+	require.ErrorContains(t, err, "missing scheme")
+	_, err = env.LookupEnv[net.IP]("MY_BAD")
+	require.ErrorContains(t, err, "IP address")
+	_, err = env.LookupEnv[env.ByteSize]("MY_BAD")
+	require.Error(t, err)
+}
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  env.ByteSize
+	}{
+		{"1024", 1024},
+		{"10B", 10},
+		{"1kb", env.KB},
+		{"1.5GiB", env.GiB + env.GiB/2},
+		{"2 TiB", 2 * env.TiB},
+		{"3MiB", 3 * env.MiB},
+	}
+	for _, tt := range tests {
+		t.Setenv("MY_SIZE", tt.value)
+		assert.Equal(t, tt.want, env.GetEnv[env.ByteSize]("MY_SIZE"), tt.value)
+	}
+
+	for _, value := range []string{"1XB", "MB", "1e3MB", "99999999TiB"} {
+		t.Setenv("MY_SIZE", value)
+		_, err := env.LookupEnv[env.ByteSize]("MY_SIZE")
+		require.Error(t, err, value)
+	}
+
+	t.Setenv("MY_SIZE", "2GB")
+	_, err := env.LookupEnv[env.ByteSize]("MY_SIZE", env.WithRange(env.Byte, env.GB))
+	require.ErrorContains(t, err, "out of range")
+}
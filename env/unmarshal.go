@@ -0,0 +1,225 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Unmarshal populates the struct pointed to by v from environment variables,
+// following the `env` tags of its fields:
+//
+//	type Config struct {
+//		HTTPPort int           `env:"HTTP_PORT,default=8080"`
+//		Timeout  time.Duration `env:"TIMEOUT,default=5s"`
+//		Secret   string        `env:"SECRET,required"`
+//		DB       DBConfig      `env:",prefix=DB_"` // reads DB_HOST, DB_PORT...
+//	}
+//
+// The tag names the variable, followed by options:
+//
+//   - default=value: the value used when the variable is not set or is empty.
+//     It runs to the next known option, so it may contain commas, as []string values do.
+//   - required: the variable must be set, or have a default.
+//   - prefix=PREFIX: on a struct field, prefixes the variables of its fields.
+//   - base64: the value is base64-encoded, as with WithBase64Decode.
+//
+// Fields are converted like GetEnv does; named types whose underlying type
+// is supported are accepted too. Nested structs, and pointers to structs,
+// are populated recursively, with the prefix of their tag if any.
+// Fields without a tag, and variables that are empty without a default, are left untouched,
+// so the struct can hold defaults of its own.
+//
+// Unmarshal returns every error it finds joined, those of missing required
+// variables wrapping ErrNotSet. opts apply to every field, such as WithReader
+// to read from an env file rather than the process environment.
+func Unmarshal(v any, opts ...Option) error {
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Pointer || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("env: Unmarshal needs a non-nil pointer to a struct, got %T", v)
+	}
+
+	return unmarshalStruct(val.Elem(), "", opts)
+}
+
+// fieldTag is a parsed `env` tag.
+type fieldTag struct {
+	key        string
+	defVal     string
+	hasDefault bool
+	required   bool
+	prefix     string
+	base64     bool
+}
+
+func parseFieldTag(tag string) (fieldTag, error) {
+	parts := strings.Split(tag, ",")
+	parsed := fieldTag{key: parts[0]}
+
+	inDefault := false
+	for _, part := range parts[1:] {
+		switch {
+		case part == "required":
+			parsed.required = true
+			inDefault = false
+		case part == "base64":
+			parsed.base64 = true
+			inDefault = false
+		case strings.HasPrefix(part, "default="):
+			parsed.defVal = strings.TrimPrefix(part, "default=")
+			parsed.hasDefault = true
+			inDefault = true
+		case strings.HasPrefix(part, "prefix="):
+			parsed.prefix = strings.TrimPrefix(part, "prefix=")
+			inDefault = false
+		case inDefault:
+			parsed.defVal += "," + part
+		default:
+			return parsed, fmt.Errorf("unknown tag option %q", part)
+		}
+	}
+
+	return parsed, nil
+}
+
+func unmarshalStruct(val reflect.Value, prefix string, opts []Option) error {
+	var errs []error
+
+	typ := val.Type()
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, hasTag := field.Tag.Lookup("env")
+		parsed, err := parseFieldTag(tag)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("env: field %s: %w", field.Name, err))
+
+			continue
+		}
+
+		fieldVal := val.Field(i)
+		if nested, ok := nestedStruct(fieldVal); ok {
+			if err := unmarshalStruct(nested, prefix+parsed.prefix, opts); err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		if !hasTag || parsed.key == "" {
+			continue
+		}
+
+		if err := unmarshalField(fieldVal, prefix+parsed.key, parsed, opts); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// nestedStruct returns the struct held by a struct field, allocating it if it is a nil pointer.
+// A time.Time or a *url.URL is a value, not a nested struct.
+func nestedStruct(val reflect.Value) (reflect.Value, bool) {
+	typ := val.Type()
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == reflect.TypeFor[time.Time]() || typ == reflect.TypeFor[url.URL]() {
+		return reflect.Value{}, false
+	}
+
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			val.Set(reflect.New(typ))
+		}
+		val = val.Elem()
+	}
+
+	return val, true
+}
+
+func unmarshalField(val reflect.Value, key string, tag fieldTag, opts []Option) error {
+	if tag.hasDefault {
+		opts = append([]Option{WithDefault(tag.defVal)}, opts...)
+	}
+	if tag.base64 {
+		opts = append(slices.Clip(opts), WithBase64Decode())
+	}
+	cfg := newOptions(key, opts)
+	cfg.record(tag.required)
+	if cfg.err != nil {
+		return cfg.err
+	}
+
+	if cfg.value == "" {
+		if tag.required {
+			return fmt.Errorf("%w: %s", ErrNotSet, cfg.key)
+		}
+
+		return nil
+	}
+
+	parsed, err := parseValue(val.Type(), cfg)
+	if err != nil {
+		return fmt.Errorf("env: invalid %s: %w", cfg.key, err)
+	}
+	val.Set(parsed)
+
+	return nil
+}
+
+// parseValue converts the value of cfg to typ, one of the SupportedTypes
+// or a named type with one of them as underlying type.
+func parseValue(typ reflect.Type, cfg *options) (reflect.Value, error) {
+	var parsed any
+	var err error
+
+	switch {
+	case typ == reflect.TypeFor[time.Duration]():
+		parsed, err = parse[time.Duration](cfg)
+	case typ == reflect.TypeFor[time.Time]():
+		parsed, err = parse[time.Time](cfg)
+	case typ == reflect.TypeFor[ByteSize]():
+		parsed, err = parse[ByteSize](cfg)
+	case typ == reflect.TypeFor[*url.URL]():
+		parsed, err = parse[*url.URL](cfg)
+	case typ == reflect.TypeFor[net.IP]():
+		parsed, err = parse[net.IP](cfg)
+	case typ.Kind() == reflect.Int64:
+		parsed, err = parse[int64](cfg)
+	case typ.Kind() == reflect.Uint:
+		parsed, err = parse[uint](cfg)
+	case typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+		parsed, err = parse[[]byte](cfg)
+	case typ.Kind() == reflect.Slice && typ.Elem() == reflect.TypeFor[int]():
+		parsed, err = parse[[]int](cfg)
+	case typ.Kind() == reflect.Map && typ.Key() == reflect.TypeFor[string]() && typ.Elem() == reflect.TypeFor[string]():
+		parsed, err = parse[map[string]string](cfg)
+	case typ.Kind() == reflect.String:
+		parsed, err = parse[string](cfg)
+	case typ.Kind() == reflect.Int:
+		parsed, err = parse[int](cfg)
+	case typ.Kind() == reflect.Float64:
+		parsed, err = parse[float64](cfg)
+	case typ.Kind() == reflect.Bool:
+		parsed, err = parse[bool](cfg)
+	case typ.Kind() == reflect.Slice && typ.Elem() == reflect.TypeFor[string]():
+		parsed, err = parse[[]string](cfg)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type: %s", typ)
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return reflect.ValueOf(parsed).Convert(typ), nil
+}
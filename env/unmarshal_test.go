@@ -0,0 +1,115 @@
+package env_test
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Mode string
+
+type DBConfig struct {
+	Host string `env:"HOST,default=localhost"`
+	Port int    `env:"PORT,required"`
+}
+
+type Config struct {
+	HTTPPort int           `env:"HTTP_PORT,default=8080"`
+	Timeout  time.Duration `env:"TIMEOUT,default=5s"`
+	Peers    []string      `env:"PEERS,default=a,b,required"`
+	Debug    bool          `env:"DEBUG"`
+	Ratio    float64       `env:"RATIO"`
+	Mode     Mode          `env:"MODE,default=dev"`
+	Started  time.Time     `env:"STARTED"`
+	Name     string        `env:"NAME"`
+	DB       DBConfig      `env:",prefix=DB_"`
+	Replica  *DBConfig     `env:",prefix=REPLICA_"`
+	Ignored  string
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Setenv("HTTP_PORT", "9090")
+	t.Setenv("DEBUG", "true")
+	t.Setenv("RATIO", "0.5")
+	t.Setenv("STARTED", "2025-03-01T10:30:00Z")
+	t.Setenv("DB_PORT", "5432")
+	t.Setenv("REPLICA_HOST", "replica")
+	t.Setenv("REPLICA_PORT", "5433")
+	t.Setenv("IGNORED", "set")
+
+	cfg := Config{Name: "kept"}
+	require.NoError(t, env.Unmarshal(&cfg))
+
+	assert.Equal(t, Config{
+		HTTPPort: 9090,
+		Timeout:  5 * time.Second,
+		Peers:    []string{"a", "b"},
+		Debug:    true,
+		Ratio:    0.5,
+		Mode:     "dev",
+		Started:  time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC),
+		Name:     "kept",
+		DB:       DBConfig{Host: "localhost", Port: 5432},
+		Replica:  &DBConfig{Host: "replica", Port: 5433},
+	}, cfg)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	t.Setenv("HTTP_PORT", "http")
+	t.Setenv("REPLICA_PORT", "1")
+
+	var cfg Config
+	err := env.Unmarshal(&cfg)
+
+	require.ErrorIs(t, err, env.ErrNotSet)
+	assert.ErrorContains(t, err, "DB_PORT")
+	assert.ErrorContains(t, err, "invalid HTTP_PORT")
+}
+
+func TestUnmarshalInvalidTarget(t *testing.T) {
+	var cfg Config
+
+	require.Error(t, env.Unmarshal(cfg))
+	require.Error(t, env.Unmarshal((*Config)(nil)))
+
+	var bad struct {
+		Field int `env:"FIELD,optional"`
+	}
+	require.ErrorContains(t, env.Unmarshal(&bad), `unknown tag option "optional"`)
+
+	var unsupported struct {
+		Field int32 `env:"FIELD"`
+	}
+	t.Setenv("FIELD", "1")
+	require.ErrorContains(t, env.Unmarshal(&unsupported), "unsupported type")
+}
+
+func TestUnmarshalMoreTypes(t *testing.T) {
+	t.Setenv("ENDPOINT", "https://api.ezex.io")
+	t.Setenv("MAX_BODY", "1MiB")
+	t.Setenv("LABELS", "a=1")
+
+	var cfg struct {
+		Endpoint *url.URL          `env:"ENDPOINT,required"`
+		MaxBody  env.ByteSize      `env:"MAX_BODY"`
+		Labels   map[string]string `env:"LABELS"`
+		Ports    []int             `env:"PORTS,default=80,443"`
+		Bind     net.IP            `env:"BIND,default=127.0.0.1"`
+		Offset   int64             `env:"OFFSET,default=-1"`
+		Workers  uint              `env:"WORKERS,default=4"`
+	}
+	require.NoError(t, env.Unmarshal(&cfg))
+
+	assert.Equal(t, "api.ezex.io", cfg.Endpoint.Host)
+	assert.Equal(t, env.MiB, cfg.MaxBody)
+	assert.Equal(t, map[string]string{"a": "1"}, cfg.Labels)
+	assert.Equal(t, []int{80, 443}, cfg.Ports)
+	assert.Equal(t, "127.0.0.1", cfg.Bind.String())
+	assert.Equal(t, int64(-1), cfg.Offset)
+	assert.Equal(t, uint(4), cfg.Workers)
+}
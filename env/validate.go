@@ -0,0 +1,57 @@
+package env
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WithValidator returns an Option that checks the value read, or its default,
+// with fn once it is converted. GetEnv panics and LookupEnv returns an error
+// naming the key when fn returns an error.
+func WithValidator(fn func(val string) error) Option {
+	return func(opts *options) {
+		opts.validators = append(opts.validators, fn)
+	}
+}
+
+// WithRange returns an Option that rejects values outside [minVal, maxVal]:
+//
+//	port := env.GetEnv[int]("HTTP_PORT", env.WithDefault("8080"), env.WithRange(1, 65535))
+func WithRange[N int | int64 | uint | float64 | time.Duration | ByteSize](minVal, maxVal N) Option {
+	return WithValidator(func(val string) error {
+		v, err := parse[N](&options{value: val})
+		if err != nil {
+			return err
+		}
+		if v < minVal || v > maxVal {
+			return fmt.Errorf("%v is out of range [%v, %v]", v, minVal, maxVal)
+		}
+
+		return nil
+	})
+}
+
+// WithOneOf returns an Option that rejects values other than the given ones:
+//
+//	stage := env.GetEnv[string]("STAGE", env.WithOneOf("dev", "staging", "prod"))
+func WithOneOf(values ...string) Option {
+	return WithValidator(func(val string) error {
+		if !slices.Contains(values, val) {
+			return fmt.Errorf("%q is not one of %s", val, strings.Join(values, ", "))
+		}
+
+		return nil
+	})
+}
+
+func (o *options) validate() error {
+	for _, validator := range o.validators {
+		if err := validator(o.value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
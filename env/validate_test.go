@@ -0,0 +1,68 @@
+package env_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRange(t *testing.T) {
+	t.Setenv("HTTP_PORT", "8080")
+	t.Setenv("BAD_PORT", "70000")
+	t.Setenv("RATIO", "1.5")
+	t.Setenv("TIMEOUT", "2m")
+
+	assert.Equal(t, 8080, env.GetEnv[int]("HTTP_PORT", env.WithRange(1, 65535)))
+
+	_, err := env.LookupEnv[int]("BAD_PORT", env.WithRange(1, 65535))
+	require.ErrorContains(t, err, "env: invalid BAD_PORT: 70000 is out of range [1, 65535]")
+
+	_, err = env.LookupEnv[float64]("RATIO", env.WithRange(0.0, 1.0))
+	require.ErrorContains(t, err, "RATIO")
+
+	_, err = env.LookupEnv[time.Duration]("TIMEOUT", env.WithRange(time.Second, time.Minute))
+	require.ErrorContains(t, err, "2m0s is out of range [1s, 1m0s]")
+
+	assert.Equal(t, 30*time.Second,
+		env.GetEnv[time.Duration]("UNSET_TIMEOUT", env.WithDefault("30s"), env.WithRange(time.Second, time.Minute)))
+
+	assert.PanicsWithError(t, "env: invalid BAD_PORT: 70000 is out of range [1, 65535]", func() {
+		env.GetEnv[int]("BAD_PORT", env.WithRange(1, 65535))
+	})
+}
+
+func TestWithOneOf(t *testing.T) {
+	t.Setenv("STAGE", "qa")
+
+	_, err := env.LookupEnv[string]("STAGE", env.WithOneOf("dev", "staging", "prod"))
+	require.ErrorContains(t, err, `env: invalid STAGE: "qa" is not one of dev, staging, prod`)
+
+	stage, err := env.LookupEnv[string]("UNSET_STAGE", env.WithDefault("dev"), env.WithOneOf("dev", "prod"))
+	require.NoError(t, err)
+	assert.Equal(t, "dev", stage)
+}
+
+func TestWithValidator(t *testing.T) {
+	t.Setenv("URL", "ftp://example.com")
+
+	errScheme := errors.New("not an https URL")
+	validator := env.WithValidator(func(val string) error {
+		if !strings.HasPrefix(val, "https://") {
+			return errScheme
+		}
+
+		return nil
+	})
+
+	_, err := env.LookupEnv[string]("URL", validator)
+	require.ErrorIs(t, err, errScheme)
+
+	assert.Panics(t, func() {
+		env.GetEnv[string]("URL", validator)
+	})
+}
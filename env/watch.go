@@ -0,0 +1,132 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce groups the events of a file being written in several steps into a single reload.
+const watchDebounce = 100 * time.Millisecond
+
+// Watch reloads the env files when they change, until ctx is done, so credentials
+// can be rotated without restarting the service:
+//
+//	err := env.Watch(ctx, []string{"/etc/app/secrets.env"}, func(changed map[string]string) {
+//		db.SetPassword(changed["DB_PASSWORD"])
+//	})
+//
+// Variables of later files override those of earlier ones. On a change, the changed
+// variables are set in the process environment, so GetEnv reads the new values,
+// and onChange is called with them; the removed ones are unset and given as empty.
+// A file that fails to parse, such as one being written, is reloaded on its next change.
+//
+// The directories of the files are watched rather than the files, so replacing
+// a file, as editors and Kubernetes do, is detected too.
+// Watch returns an error if a file can't be read or watched; it watches in the background.
+func Watch(ctx context.Context, files []string, onChange func(changed map[string]string)) error {
+	vars, err := readFiles(files)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("env: failed to watch: %w", err)
+	}
+
+	for _, file := range files {
+		if err := watcher.Add(filepath.Dir(file)); err != nil {
+			_ = watcher.Close()
+
+			return fmt.Errorf("env: failed to watch %s: %w", file, err)
+		}
+	}
+
+	go watchFiles(ctx, watcher, files, vars, onChange)
+
+	return nil
+}
+
+func watchFiles(ctx context.Context, watcher *fsnotify.Watcher, files []string,
+	vars map[string]string, onChange func(changed map[string]string),
+) {
+	defer func() { _ = watcher.Close() }()
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			debounce.Reset(watchDebounce)
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-debounce.C:
+			reloaded, err := readFiles(files)
+			if err != nil {
+				continue
+			}
+
+			changed := diffVars(vars, reloaded)
+			if len(changed) == 0 {
+				continue
+			}
+			vars = reloaded
+
+			for key, val := range changed {
+				if _, ok := reloaded[key]; ok {
+					_ = os.Setenv(key, val)
+				} else {
+					_ = os.Unsetenv(key)
+				}
+			}
+			onChange(changed)
+		}
+	}
+}
+
+// readFiles parses the env files, later files overriding earlier ones.
+func readFiles(files []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, file := range files {
+		parsed, err := ParseFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("env: failed to read %s: %w", file, err)
+		}
+		maps.Copy(vars, parsed)
+	}
+
+	return vars, nil
+}
+
+// diffVars returns the variables of next that differ from prev,
+// and those of prev missing from next, as empty.
+func diffVars(prev, next map[string]string) map[string]string {
+	changed := map[string]string{}
+	for key, val := range next {
+		if old, ok := prev[key]; !ok || old != val {
+			changed[key] = val
+		}
+	}
+	for key := range prev {
+		if _, ok := next[key]; !ok {
+			changed[key] = ""
+		}
+	}
+
+	return changed
+}
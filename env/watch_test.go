@@ -0,0 +1,56 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/env/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.env")
+	secrets := filepath.Join(dir, "secrets.env")
+	require.NoError(t, os.WriteFile(base, []byte("WATCH_HOST=db\nWATCH_PASSWORD=base\n"), 0o600))
+	require.NoError(t, os.WriteFile(secrets, []byte("WATCH_PASSWORD=old\nWATCH_TOKEN=t\n"), 0o600))
+	t.Setenv("WATCH_PASSWORD", "old")
+	t.Setenv("WATCH_TOKEN", "t")
+
+	changes := make(chan map[string]string, 1)
+	require.NoError(t, env.Watch(t.Context(), []string{base, secrets}, func(changed map[string]string) {
+		changes <- changed
+	}))
+
+	// Replace the file, as editors and Kubernetes do.
+	tmp := filepath.Join(dir, "secrets.env.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte("WATCH_PASSWORD=new\n"), 0o600))
+	require.NoError(t, os.Rename(tmp, secrets))
+
+	select {
+	case changed := <-changes:
+		assert.Equal(t, map[string]string{"WATCH_PASSWORD": "new", "WATCH_TOKEN": ""}, changed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the change")
+	}
+
+	assert.Equal(t, "new", env.GetEnv[string]("WATCH_PASSWORD"))
+	_, set := os.LookupEnv("WATCH_TOKEN")
+	assert.False(t, set)
+
+	// Rewriting the same variables is not a change.
+	require.NoError(t, os.WriteFile(base, []byte("WATCH_HOST=db\nWATCH_PASSWORD=base\n"), 0o600))
+	select {
+	case changed := <-changes:
+		t.Fatalf("unexpected change %v", changed)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestWatchMissingFile(t *testing.T) {
+	err := env.Watch(t.Context(), []string{filepath.Join(t.TempDir(), "missing.env")}, func(map[string]string) {})
+	require.Error(t, err)
+}
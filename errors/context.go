@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// StatusClientClosedRequest is the non-standard HTTP status (popularized by nginx)
+// used when the client gave up before the server responded.
+const StatusClientClosedRequest = 499
+
+var (
+	// ErrCanceled marks work abandoned because the caller gave up (context canceled).
+	ErrCanceled = New("client canceled")
+
+	// ErrTimeout marks work abandoned because the server ran out of time (deadline exceeded).
+	ErrTimeout = New("server timeout")
+)
+
+// FromContext classifies err by the context error ctxErr, so handlers can tell
+// "client gave up" (ErrCanceled) from "server timed out" (ErrTimeout).
+//
+// If ctxErr is nil, err is returned unchanged. Otherwise the returned error
+// matches the sentinel, ctxErr and err (when err is not nil).
+func FromContext(err, ctxErr error) error {
+	var sentinel error
+	switch {
+	case Is(ctxErr, context.Canceled):
+		sentinel = ErrCanceled
+	case Is(ctxErr, context.DeadlineExceeded):
+		sentinel = ErrTimeout
+	default:
+		return err
+	}
+
+	if err == nil || Is(err, ctxErr) {
+		return fmt.Errorf("%w: %w", sentinel, ctxErr)
+	}
+
+	return fmt.Errorf("%w: %w: %w", sentinel, ctxErr, err)
+}
+
+// HTTPStatus maps an error to the HTTP status code that should be returned for it:
+// StatusClientClosedRequest (499) for cancellations, 504 for timeouts,
+// 500 for any other error and 200 for nil.
+//
+// Bare context errors are classified the same way as FromContext would.
+func HTTPStatus(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case Is(err, ErrCanceled), Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	case Is(err, ErrTimeout), Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
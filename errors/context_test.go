@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	errDial := New("dial failed")
+
+	t.Run("no context error", func(t *testing.T) {
+		assert.Equal(t, errDial, FromContext(errDial, nil))
+		assert.NoError(t, FromContext(nil, nil))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		err := FromContext(errDial, context.Canceled)
+		require.ErrorIs(t, err, ErrCanceled)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, errDial)
+		assert.NotErrorIs(t, err, ErrTimeout)
+		assert.Equal(t, "client canceled: context canceled: dial failed", err.Error())
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		err := FromContext(nil, context.DeadlineExceeded)
+		require.ErrorIs(t, err, ErrTimeout)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, "server timeout: context deadline exceeded", err.Error())
+	})
+
+	t.Run("error already wraps context error", func(t *testing.T) {
+		err := FromContext(context.Canceled, context.Canceled)
+		assert.Equal(t, "client canceled: context canceled", err.Error())
+	})
+
+	t.Run("cause error", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(t.Context())
+		cancel(New("shutdown"))
+
+		err := FromContext(errDial, ctx.Err())
+		require.ErrorIs(t, err, ErrCanceled)
+	})
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusOK, HTTPStatus(nil))
+	assert.Equal(t, StatusClientClosedRequest, HTTPStatus(FromContext(nil, context.Canceled)))
+	assert.Equal(t, StatusClientClosedRequest, HTTPStatus(context.Canceled))
+	assert.Equal(t, http.StatusGatewayTimeout, HTTPStatus(FromContext(nil, context.DeadlineExceeded)))
+	assert.Equal(t, http.StatusGatewayTimeout, HTTPStatus(context.DeadlineExceeded))
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatus(New("boom")))
+}
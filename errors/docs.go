@@ -0,0 +1,46 @@
+package errors
+
+import (
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// docsBase is the template set by SetDocsBase.
+var docsBase atomic.Pointer[string]
+
+// SetDocsBase sets the template of the documentation URL of error codes,
+// such as "https://docs.ezex.io/errors/{code}", used by DocsURL for the Errors
+// without their own URL. "{code}" is replaced by the escaped code; without it,
+// the code is appended. An empty template disables the default URLs.
+func SetDocsBase(template string) {
+	docsBase.Store(&template)
+}
+
+// WithDocs returns a copy of e documented at url, overriding the template set by SetDocsBase.
+func (e *Error) WithDocs(url string) *Error {
+	clone := *e
+	clone.docs = url
+
+	return &clone
+}
+
+// DocsURL returns the documentation URL of e: the one set by WithDocs, or the
+// template set by SetDocsBase filled with the code. It is empty if neither applies.
+func (e *Error) DocsURL() string {
+	if e.docs != "" {
+		return e.docs
+	}
+
+	base := docsBase.Load()
+	if base == nil || *base == "" || e.Code == "" {
+		return ""
+	}
+
+	code := url.PathEscape(e.Code)
+	if strings.Contains(*base, "{code}") {
+		return strings.ReplaceAll(*base, "{code}", code)
+	}
+
+	return *base + code
+}
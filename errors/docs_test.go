@@ -0,0 +1,27 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocsURL(t *testing.T) {
+	t.Cleanup(func() { SetDocsBase("") })
+
+	err := NewError("not_found", "user not found")
+	assert.Empty(t, err.DocsURL())
+
+	SetDocsBase("https://docs.ezex.io/errors/{code}")
+	assert.Equal(t, "https://docs.ezex.io/errors/not_found", err.DocsURL())
+	assert.Equal(t, "https://docs.ezex.io/errors/a%2Fb", NewError("a/b", "").DocsURL())
+	assert.Empty(t, NewError("", "no code").DocsURL())
+
+	SetDocsBase("https://docs.ezex.io/errors#")
+	assert.Equal(t, "https://docs.ezex.io/errors#not_found", err.DocsURL())
+
+	documented := err.WithDocs("https://example.com/users")
+	assert.Equal(t, "https://example.com/users", documented.DocsURL())
+	assert.Equal(t, "https://example.com/users", documented.WithMeta("id", 1).DocsURL())
+	assert.Equal(t, "https://docs.ezex.io/errors#not_found", err.DocsURL(), "WithDocs must not modify the receiver")
+}
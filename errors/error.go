@@ -0,0 +1,167 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Error is a structured error with a machine-readable code, a message for humans,
+// metadata and the stack where it was created.
+//
+// Two Errors match with Is when they have the same code, so an Error can be used
+// as a sentinel:
+//
+//	var ErrNotFound = errors.NewError("not_found", "resource not found")
+//
+//	if errors.Is(err, ErrNotFound) { ... }
+type Error struct {
+	// Code identifies the kind of error, such as "not_found".
+	Code string
+
+	// Message describes the error.
+	Message string
+
+	// Meta holds additional key-value context.
+	Meta map[string]any
+
+	cause     error
+	docs      string
+	stack     []uintptr
+	recovered any
+}
+
+// NewError returns an Error with the given code and message,
+// recording the stack of the caller.
+func NewError(code, message string) *Error {
+	return &Error{Code: code, Message: message, stack: callers()}
+}
+
+// Wrap returns an Error with the given code and message caused by err,
+// recording the stack of the caller.
+func Wrap(err error, code, message string) *Error {
+	return &Error{Code: code, Message: message, cause: err, stack: callers()}
+}
+
+func callers() []uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:]) // Skip runtime.Callers, callers and the constructor
+
+	return pcs[:n]
+}
+
+// WithMeta returns a copy of e with key set to value in its metadata.
+func (e *Error) WithMeta(key string, value any) *Error {
+	clone := *e
+	clone.Meta = maps.Clone(e.Meta)
+	if clone.Meta == nil {
+		clone.Meta = make(map[string]any, 1)
+	}
+	clone.Meta[key] = value
+
+	return &clone
+}
+
+// Error returns "code: message", followed by ": cause" when e wraps an error.
+func (e *Error) Error() string {
+	var sb strings.Builder
+	if e.Code != "" {
+		sb.WriteString(e.Code)
+		if e.Message != "" {
+			sb.WriteString(": ")
+		}
+	}
+	sb.WriteString(e.Message)
+
+	if e.cause != nil {
+		sb.WriteString(": ")
+		sb.WriteString(e.cause.Error())
+	}
+
+	return sb.String()
+}
+
+// Unwrap returns the cause of e, if any.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	other, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+
+	return e.Code != "" && e.Code == other.Code
+}
+
+// Frames returns the stack where e was created, innermost first.
+func (e *Error) Frames() []runtime.Frame {
+	frames := make([]runtime.Frame, 0, len(e.stack))
+	iter := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := iter.Next()
+		if frame.Function != "" {
+			frames = append(frames, frame)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// Format implements fmt.Formatter:
+//
+//   - %s and %v print the same text as Error.
+//   - %+v also prints the metadata, the stack and the chain of causes, one item per line.
+//   - %q prints Error quoted, with newlines and other control characters escaped,
+//     so it is safe to write to line-based logs.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		e.writeDetails(s)
+	case verb == 'q':
+		_, _ = io.WriteString(s, strconv.Quote(e.Error()))
+	case verb == 's', verb == 'v':
+		_, _ = io.WriteString(s, e.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*errors.Error=%s)", verb, e.Error())
+	}
+}
+
+func (e *Error) writeDetails(w io.Writer) {
+	head := e.Code
+	if e.Code != "" && e.Message != "" {
+		head += ": "
+	}
+	_, _ = io.WriteString(w, head+e.Message)
+
+	for _, key := range slices.Sorted(maps.Keys(e.Meta)) {
+		_, _ = fmt.Fprintf(w, "\n    %s=%s", key, safeString(fmt.Sprint(e.Meta[key])))
+	}
+
+	for _, frame := range e.Frames() {
+		_, _ = fmt.Fprintf(w, "\n    at %s\n        %s:%d", frame.Function, frame.File, frame.Line)
+	}
+
+	if e.cause != nil {
+		_, _ = fmt.Fprintf(w, "\ncaused by: %+v", e.cause)
+	}
+}
+
+// safeString quotes s if it contains control characters such as newlines.
+func safeString(s string) string {
+	if strings.ContainsFunc(s, unicode.IsControl) {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
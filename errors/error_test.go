@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	errDial := New("dial failed")
+	err := Wrap(errDial, "unavailable", "database unavailable")
+
+	assert.Equal(t, "unavailable: database unavailable: dial failed", err.Error())
+	require.ErrorIs(t, err, errDial)
+	assert.Equal(t, "not_found", NewError("not_found", "").Error())
+	assert.Equal(t, "no code", NewError("", "no code").Error())
+}
+
+func TestErrorIs(t *testing.T) {
+	errNotFound := NewError("not_found", "resource not found")
+
+	err := fmt.Errorf("loading user: %w", NewError("not_found", "user 42 not found"))
+	require.ErrorIs(t, err, errNotFound)
+	assert.NotErrorIs(t, err, NewError("conflict", "resource not found"))
+	assert.NotErrorIs(t, NewError("", "a"), NewError("", "a"))
+}
+
+func TestErrorWithMeta(t *testing.T) {
+	base := NewError("not_found", "user not found")
+	err := base.WithMeta("user_id", 42)
+
+	assert.Nil(t, base.Meta, "WithMeta must not modify the receiver")
+	assert.Equal(t, map[string]any{"user_id": 42}, err.Meta)
+	require.ErrorIs(t, err, base)
+}
+
+func TestErrorFrames(t *testing.T) {
+	err := NewError("internal", "boom")
+
+	frames := err.Frames()
+	require.NotEmpty(t, frames)
+	assert.Contains(t, frames[0].Function, "TestErrorFrames")
+}
+
+func TestErrorFormat(t *testing.T) {
+	err := Wrap(
+		Wrap(New("connection reset"), "io", "read failed"),
+		"unavailable", "database unavailable",
+	).WithMeta("host", "db\nINJECTED").WithMeta("attempt", 3)
+
+	assert.Equal(t, "unavailable: database unavailable: io: read failed: connection reset", fmt.Sprintf("%v", err))
+	assert.Equal(t, err.Error(), fmt.Sprintf("%s", err))
+	assert.Equal(t, `"unavailable: database unavailable: io: read failed: connection reset"`, fmt.Sprintf("%q", err))
+
+	injected := Wrap(New("line1\nline2"), "bad", "input")
+	assert.Equal(t, `"bad: input: line1\nline2"`, fmt.Sprintf("%q", injected))
+
+	detailed := fmt.Sprintf("%+v", err)
+	lines := strings.Split(detailed, "\n")
+	assert.Equal(t, "unavailable: database unavailable", lines[0])
+	assert.Equal(t, "    attempt=3", lines[1])
+	assert.Equal(t, `    host="db\nINJECTED"`, lines[2])
+	assert.Contains(t, lines[3], "at github.com/ezex-io/gopkg/errors.TestErrorFormat")
+	assert.Contains(t, detailed, "\ncaused by: io: read failed\n")
+	assert.True(t, strings.HasSuffix(detailed, "\ncaused by: connection reset"))
+}
@@ -0,0 +1,33 @@
+// Package errors provides error conventions shared by ezex services.
+//
+// It re-exports the standard library helpers, so it can be imported
+// in place of the standard errors package.
+package errors
+
+import "errors"
+
+// New returns an error that formats as the given text.
+func New(text string) error {
+	return errors.New(text)
+}
+
+// Is reports whether any error in err's tree matches target.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's tree that matches target, and if one is found,
+// sets target to that error value and returns true.
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
+// Join returns an error that wraps the given errors.
+func Join(errs ...error) error {
+	return errors.Join(errs...)
+}
+
+// Unwrap returns the result of calling the Unwrap method on err, if any.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// CodePanic is the code of the errors returned by Panic.
+const CodePanic = "panic"
+
+// ErrPanic matches the errors returned by Panic with Is.
+var ErrPanic = &Error{Code: CodePanic, Message: "recovered panic"}
+
+// Panic returns the value recovered from a panic as an Error with the code CodePanic,
+// so panics are reported, alerted on and grouped like any other Error:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			report(errors.Panic(r))
+//		}
+//	}()
+//
+// The recovered value is the cause of the Error if it is an error, and its message otherwise;
+// Recovered returns it unchanged. Called from the deferred function, Panic records
+// the stack of the panic, starting at the function that panicked.
+func Panic(recovered any) *Error {
+	err := &Error{Code: CodePanic, stack: panicCallers(), recovered: recovered}
+	if cause, ok := recovered.(error); ok {
+		err.cause = cause
+	} else {
+		err.Message = fmt.Sprint(recovered)
+	}
+
+	return err
+}
+
+// Recovered returns the value recovered from a panic passed to Panic,
+// if err or an error it wraps was returned by Panic.
+func Recovered(err error) (any, bool) {
+	switch err := err.(type) {
+	case nil:
+		return nil, false
+	case *Error:
+		if err.recovered != nil {
+			return err.recovered, true
+		}
+	case interface{ Unwrap() []error }:
+		for _, err := range err.Unwrap() {
+			if recovered, ok := Recovered(err); ok {
+				return recovered, true
+			}
+		}
+
+		return nil, false
+	}
+
+	return Recovered(Unwrap(err))
+}
+
+// panicCallers returns the stack of the caller of Panic, without the frames
+// of the deferred call and of the runtime up to the function that panicked.
+func panicCallers() []uintptr {
+	var pcs [64]uintptr
+	n := runtime.Callers(3, pcs[:]) // Skip runtime.Callers, panicCallers and Panic
+	stack := pcs[:n]
+
+	for i, pc := range stack {
+		if fn := runtime.FuncForPC(pc - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			stack = stack[i+1:]
+
+			break
+		}
+	}
+
+	// Runtime errors, such as a nil dereference, panic from runtime functions.
+	for len(stack) > 1 {
+		fn := runtime.FuncForPC(stack[0] - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+			break
+		}
+		stack = stack[1:]
+	}
+
+	return stack
+}
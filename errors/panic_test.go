@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recoverPanic(fn func()) (err *Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Panic(r)
+		}
+	}()
+	fn()
+
+	return nil
+}
+
+func panickingFunc() {
+	panic("boom")
+}
+
+func TestPanic(t *testing.T) {
+	err := recoverPanic(panickingFunc)
+
+	require.ErrorIs(t, err, ErrPanic)
+	assert.Equal(t, "panic: boom", err.Error())
+
+	recovered, ok := Recovered(err)
+	assert.True(t, ok)
+	assert.Equal(t, "boom", recovered)
+
+	frames := err.Frames()
+	require.NotEmpty(t, frames)
+	assert.Contains(t, frames[0].Function, "panickingFunc")
+}
+
+func TestPanicError(t *testing.T) {
+	errBoom := New("boom")
+	err := recoverPanic(func() { panic(errBoom) })
+
+	require.ErrorIs(t, err, ErrPanic)
+	require.ErrorIs(t, err, errBoom)
+	assert.Equal(t, "panic: boom", err.Error())
+
+	recovered, ok := Recovered(fmt.Errorf("handling request: %w", Wrap(err, "internal", "failed")))
+	assert.True(t, ok)
+	assert.Same(t, errBoom, recovered)
+}
+
+func TestPanicRuntimeError(t *testing.T) {
+	err := recoverPanic(func() {
+		var m map[string]int
+		m["a"] = 1
+	})
+
+	require.ErrorIs(t, err, ErrPanic)
+	assert.NotContains(t, err.Frames()[0].Function, "runtime.")
+	assert.Contains(t, err.Frames()[0].Function, "TestPanicRuntimeError")
+}
+
+func TestRecovered(t *testing.T) {
+	_, ok := Recovered(nil)
+	assert.False(t, ok)
+
+	_, ok = Recovered(ErrPanic)
+	assert.False(t, ok)
+
+	_, ok = Recovered(NewError(CodePanic, "not recovered"))
+	assert.False(t, ok)
+
+	recovered, ok := Recovered(Join(New("other"), Panic(42)))
+	assert.True(t, ok)
+	assert.Equal(t, 42, recovered)
+}
@@ -0,0 +1,126 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+// Warning is a non-fatal issue as rendered in a response, under a "warnings" array.
+type Warning struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	DocsURL string `json:"docs_url,omitempty"`
+}
+
+// Warnings collects the non-fatal issues met while serving a request or running a job,
+// so a partial success, such as a batch import skipping bad rows, can report them
+// without failing. It is attached to a context with WithWarnings:
+//
+//	ctx, warnings := errors.WithWarnings(ctx)
+//	...
+//	errors.WarningsFromContext(ctx).Add(errors.NewError("row_skipped", "row 12 has no email"))
+//	...
+//	resp.Warnings = warnings.List()
+//
+// A nil *Warnings ignores what is added, so code can add to the Warnings of a context
+// without checking it has one. It is safe for concurrent use.
+type Warnings struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+type warningsKey struct{}
+
+// WithWarnings returns ctx carrying a Warnings, and the Warnings.
+// If ctx already carries one, it is returned, so nested calls share it.
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	if warnings := WarningsFromContext(ctx); warnings != nil {
+		return ctx, warnings
+	}
+
+	warnings := &Warnings{}
+
+	return context.WithValue(ctx, warningsKey{}, warnings), warnings
+}
+
+// WarningsFromContext returns the Warnings attached to ctx with WithWarnings, or nil.
+func WarningsFromContext(ctx context.Context) *Warnings {
+	warnings, _ := ctx.Value(warningsKey{}).(*Warnings)
+
+	return warnings
+}
+
+// Add records err as a warning. Nil errors are ignored.
+func (w *Warnings) Add(err error) {
+	if w == nil || err == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.errs = append(w.errs, err)
+}
+
+// Len returns the number of warnings.
+func (w *Warnings) Len() int {
+	if w == nil {
+		return 0
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.errs)
+}
+
+// Errors returns the warnings, in the order they were added.
+func (w *Warnings) Errors() []error {
+	if w == nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return slices.Clone(w.errs)
+}
+
+// List returns the warnings rendered for a response, in the order they were added.
+// An *Error is rendered with its code, message and documentation URL, without its cause;
+// any other error with its text only.
+func (w *Warnings) List() []Warning {
+	errs := w.Errors()
+	if len(errs) == 0 {
+		return nil
+	}
+
+	list := make([]Warning, 0, len(errs))
+	for _, err := range errs {
+		var coded *Error
+		if As(err, &coded) {
+			list = append(list, Warning{Code: coded.Code, Message: coded.Message, DocsURL: coded.DocsURL()})
+
+			continue
+		}
+		list = append(list, Warning{Message: err.Error()})
+	}
+
+	return list
+}
+
+// LogValue implements slog.LogValuer, logging the warnings as a list of their texts,
+// so they are logged once, with the request or job:
+//
+//	log.Info("import done", "warnings", warnings)
+func (w *Warnings) LogValue() slog.Value {
+	errs := w.Errors()
+	texts := make([]string, 0, len(errs))
+	for _, err := range errs {
+		texts = append(texts, err.Error())
+	}
+
+	return slog.AnyValue(texts)
+}
@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings_Context(t *testing.T) {
+	assert.Nil(t, WarningsFromContext(context.Background()))
+	WarningsFromContext(context.Background()).Add(New("ignored"))
+
+	ctx, warnings := WithWarnings(context.Background())
+	assert.Same(t, warnings, WarningsFromContext(ctx))
+
+	nested, shared := WithWarnings(ctx)
+	assert.Equal(t, ctx, nested)
+	assert.Same(t, warnings, shared)
+
+	WarningsFromContext(ctx).Add(New("row 12 skipped"))
+	WarningsFromContext(ctx).Add(nil)
+	assert.Equal(t, 1, warnings.Len())
+}
+
+func TestWarnings_List(t *testing.T) {
+	var nilWarnings *Warnings
+	assert.Nil(t, nilWarnings.List())
+	assert.Zero(t, nilWarnings.Len())
+
+	warnings := &Warnings{}
+	cause := New("missing column")
+	warnings.Add(Wrap(cause, "row_skipped", "row 12 skipped").WithDocs("https://docs.ezex.io/row_skipped"))
+	warnings.Add(New("rate limited, retried"))
+
+	assert.Equal(t, []Warning{
+		{Code: "row_skipped", Message: "row 12 skipped", DocsURL: "https://docs.ezex.io/row_skipped"},
+		{Message: "rate limited, retried"},
+	}, warnings.List())
+	assert.ErrorIs(t, warnings.Errors()[0], cause)
+
+	body, err := json.Marshal(map[string]any{"imported": 10, "warnings": warnings.List()})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imported": 10, "warnings": [
+		{"code": "row_skipped", "message": "row 12 skipped", "docs_url": "https://docs.ezex.io/row_skipped"},
+		{"message": "rate limited, retried"}
+	]}`, string(body))
+}
+
+func TestWarnings_LogValue(t *testing.T) {
+	warnings := &Warnings{}
+	warnings.Add(NewError("row_skipped", "row 12 skipped"))
+	warnings.Add(New("rate limited"))
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("import done", "warnings", warnings)
+
+	assert.Contains(t, buf.String(), `"warnings":["row_skipped: row 12 skipped","rate limited"]`)
+}
+
+func TestWarnings_Concurrent(t *testing.T) {
+	warnings := &Warnings{}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			warnings.Add(New("warning"))
+			_ = warnings.List()
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, warnings.Len())
+}
@@ -0,0 +1,37 @@
+package eventstore
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrConcurrency is wrapped by ConflictError.
+	ErrConcurrency = errors.New("eventstore: concurrency conflict")
+
+	// ErrSnapshotNotFound is returned when a stream has no snapshot.
+	ErrSnapshotNotFound = errors.New("eventstore: snapshot not found")
+)
+
+// ConflictError reports that a stream changed since the version the caller expected.
+type ConflictError struct {
+	StreamID string
+	Expected int64
+	Actual   int64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("eventstore: stream %q is at version %d, expected %d", e.StreamID, e.Actual, e.Expected)
+}
+
+func (*ConflictError) Unwrap() error {
+	return ErrConcurrency
+}
+
+func checkVersion(streamID string, expected, actual int64) error {
+	if expected != AnyVersion && expected != actual {
+		return &ConflictError{StreamID: streamID, Expected: expected, Actual: actual}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package eventstore defines append-only event streams with optimistic concurrency
+// and snapshots, with a Postgres implementation and an in-memory one for tests.
+//
+// Each stream is an ordered list of events. The first event of a stream has version 1,
+// and the version of a stream is the version of its last event (0 if it has no events).
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Expected versions with a special meaning for Append.
+const (
+	// AnyVersion appends regardless of the current version of the stream.
+	AnyVersion int64 = -1
+
+	// NoStream appends only if the stream has no events yet.
+	NoStream int64 = 0
+)
+
+// Event is a single entry of a stream.
+// StreamID, Version and RecordedAt are set by the store on Append.
+type Event struct {
+	StreamID   string
+	Version    int64
+	Type       string
+	Data       []byte
+	Metadata   map[string]string
+	RecordedAt time.Time
+}
+
+// Snapshot is the serialized state of a stream as of Version.
+// CreatedAt is set by the store.
+type Snapshot struct {
+	StreamID  string
+	Version   int64
+	Data      []byte
+	CreatedAt time.Time
+}
+
+// EventStore appends and reads event streams.
+type EventStore interface {
+	// Append adds events to the end of the stream if its current version is expectedVersion
+	// (or for any version with AnyVersion), and returns the new version of the stream.
+	// A mismatch is reported as a *ConflictError.
+	Append(ctx context.Context, streamID string, expectedVersion int64, events ...Event) (int64, error)
+
+	// Load returns the events of the stream with a version greater than afterVersion, in order.
+	Load(ctx context.Context, streamID string, afterVersion int64) ([]Event, error)
+
+	// Version returns the current version of the stream, 0 if it has no events.
+	Version(ctx context.Context, streamID string) (int64, error)
+}
+
+// SnapshotStore keeps the latest snapshot of each stream.
+type SnapshotStore interface {
+	// SaveSnapshot stores the snapshot, unless a newer one is already stored.
+	SaveSnapshot(ctx context.Context, snapshot Snapshot) error
+
+	// LoadSnapshot returns the latest snapshot of the stream, or ErrSnapshotNotFound.
+	LoadSnapshot(ctx context.Context, streamID string) (Snapshot, error)
+}
+
+// Store combines event streams and their snapshots.
+type Store interface {
+	EventStore
+	SnapshotStore
+}
+
+// LoadWithSnapshot returns the latest snapshot of the stream, if any,
+// and the events recorded after it. Without a snapshot, the returned snapshot
+// has version 0 and all events are returned.
+func LoadWithSnapshot(ctx context.Context, store Store, streamID string) (Snapshot, []Event, error) {
+	snapshot, err := store.LoadSnapshot(ctx, streamID)
+	if err != nil {
+		if !errors.Is(err, ErrSnapshotNotFound) {
+			return Snapshot{}, nil, err
+		}
+		snapshot = Snapshot{StreamID: streamID}
+	}
+
+	events, err := store.Load(ctx, streamID, snapshot.Version)
+	if err != nil {
+		return Snapshot{}, nil, err
+	}
+
+	return snapshot, events, nil
+}
@@ -0,0 +1,159 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStore runs the behavior every Store implementation must provide.
+// Stream IDs are prefixed with prefix so persistent stores can be reused across runs.
+func testStore(t *testing.T, store Store, prefix string) {
+	t.Helper()
+
+	t.Run("Append and Load", func(t *testing.T) {
+		ctx := t.Context()
+		streamID := prefix + "append"
+
+		version, err := store.Append(ctx, streamID, NoStream,
+			Event{Type: "created", Data: []byte(`{"id":1}`), Metadata: map[string]string{"by": "alice"}},
+			Event{Type: "updated", Data: []byte(`{"id":2}`)},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		events, err := store.Load(ctx, streamID, 0)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, streamID, events[0].StreamID)
+		assert.Equal(t, int64(1), events[0].Version)
+		assert.Equal(t, "created", events[0].Type)
+		assert.JSONEq(t, `{"id":1}`, string(events[0].Data))
+		assert.Equal(t, "alice", events[0].Metadata["by"])
+		assert.WithinDuration(t, time.Now(), events[0].RecordedAt, time.Minute)
+		assert.Equal(t, int64(2), events[1].Version)
+
+		events, err = store.Load(ctx, streamID, 1)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "updated", events[0].Type)
+
+		current, err := store.Version(ctx, streamID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), current)
+	})
+
+	t.Run("Unknown stream", func(t *testing.T) {
+		ctx := t.Context()
+		streamID := prefix + "unknown"
+
+		events, err := store.Load(ctx, streamID, 0)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+
+		version, err := store.Version(ctx, streamID)
+		require.NoError(t, err)
+		assert.Zero(t, version)
+	})
+
+	t.Run("Expected version mismatch", func(t *testing.T) {
+		ctx := t.Context()
+		streamID := prefix + "conflict"
+
+		_, err := store.Append(ctx, streamID, NoStream, Event{Type: "created"})
+		require.NoError(t, err)
+
+		_, err = store.Append(ctx, streamID, NoStream, Event{Type: "created"})
+		require.ErrorIs(t, err, ErrConcurrency)
+
+		var conflict *ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, int64(0), conflict.Expected)
+		assert.Equal(t, int64(1), conflict.Actual)
+
+		version, err := store.Append(ctx, streamID, 1, Event{Type: "updated"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		version, err = store.Append(ctx, streamID, AnyVersion, Event{Type: "updated"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), version)
+	})
+
+	t.Run("Concurrent appends", func(t *testing.T) {
+		ctx := t.Context()
+		streamID := prefix + "concurrent"
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		succeeded := 0
+		for i := range 10 {
+			wg.Go(func() {
+				_, err := store.Append(ctx, streamID, NoStream, Event{Type: fmt.Sprintf("event-%d", i)})
+				if err == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+				} else {
+					assert.ErrorIs(t, err, ErrConcurrency)
+				}
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, succeeded)
+		version, err := store.Version(ctx, streamID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), version)
+	})
+
+	t.Run("Snapshots", func(t *testing.T) {
+		ctx := t.Context()
+		streamID := prefix + "snapshot"
+
+		_, err := store.LoadSnapshot(ctx, streamID)
+		require.ErrorIs(t, err, ErrSnapshotNotFound)
+
+		_, err = store.Append(ctx, streamID, NoStream,
+			Event{Type: "a"}, Event{Type: "b"}, Event{Type: "c"})
+		require.NoError(t, err)
+
+		snapshot, events, err := LoadWithSnapshot(ctx, store, streamID)
+		require.NoError(t, err)
+		assert.Zero(t, snapshot.Version)
+		assert.Len(t, events, 3)
+
+		require.NoError(t, store.SaveSnapshot(ctx, Snapshot{StreamID: streamID, Version: 2, Data: []byte("ab")}))
+
+		snapshot, events, err = LoadWithSnapshot(ctx, store, streamID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), snapshot.Version)
+		assert.Equal(t, []byte("ab"), snapshot.Data)
+		require.Len(t, events, 1)
+		assert.Equal(t, "c", events[0].Type)
+
+		// An older snapshot doesn't replace a newer one.
+		require.NoError(t, store.SaveSnapshot(ctx, Snapshot{StreamID: streamID, Version: 1, Data: []byte("a")}))
+		snapshot, err = store.LoadSnapshot(ctx, streamID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), snapshot.Version)
+	})
+}
+
+func TestLoadWithSnapshot_Error(t *testing.T) {
+	_, _, err := LoadWithSnapshot(t.Context(), failingStore{MemoryStore: NewMemory()}, "s")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+type failingStore struct {
+	*MemoryStore
+}
+
+func (failingStore) LoadSnapshot(context.Context, string) (Snapshot, error) {
+	return Snapshot{}, context.Canceled
+}
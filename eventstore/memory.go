@@ -0,0 +1,117 @@
+package eventstore
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+var _ Store = &MemoryStore{}
+
+// MemoryStore is an in-memory Store, intended for tests.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	streams   map[string][]Event
+	snapshots map[string]Snapshot
+}
+
+// NewMemory creates an empty in-memory store.
+func NewMemory() *MemoryStore {
+	return &MemoryStore{
+		streams:   make(map[string][]Event),
+		snapshots: make(map[string]Snapshot),
+	}
+}
+
+func (s *MemoryStore) Append(_ context.Context, streamID string,
+	expectedVersion int64, events ...Event,
+) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stream := s.streams[streamID]
+	version := int64(len(stream))
+	if err := checkVersion(streamID, expectedVersion, version); err != nil {
+		return version, err
+	}
+
+	now := time.Now().UTC()
+	for _, event := range events {
+		version++
+		event.StreamID = streamID
+		event.Version = version
+		event.RecordedAt = now
+		stream = append(stream, cloneEvent(event))
+	}
+	s.streams[streamID] = stream
+
+	return version, nil
+}
+
+func (s *MemoryStore) Load(_ context.Context, streamID string, afterVersion int64) ([]Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stream := s.streams[streamID]
+	if afterVersion < 0 {
+		afterVersion = 0
+	}
+	if afterVersion >= int64(len(stream)) {
+		return []Event{}, nil
+	}
+
+	events := make([]Event, 0, int64(len(stream))-afterVersion)
+	for _, event := range stream[afterVersion:] {
+		events = append(events, cloneEvent(event))
+	}
+
+	return events, nil
+}
+
+// cloneEvent copies the data and metadata of event, so neither the caller
+// nor the store sees the changes the other makes to them.
+func cloneEvent(event Event) Event {
+	event.Data = slices.Clone(event.Data)
+	event.Metadata = maps.Clone(event.Metadata)
+
+	return event
+}
+
+func (s *MemoryStore) Version(_ context.Context, streamID string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return int64(len(s.streams[streamID])), nil
+}
+
+func (s *MemoryStore) SaveSnapshot(_ context.Context, snapshot Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.snapshots[snapshot.StreamID]; ok && current.Version > snapshot.Version {
+		return nil
+	}
+
+	snapshot.Data = slices.Clone(snapshot.Data)
+	snapshot.CreatedAt = time.Now().UTC()
+	s.snapshots[snapshot.StreamID] = snapshot
+
+	return nil
+}
+
+func (s *MemoryStore) LoadSnapshot(_ context.Context, streamID string) (Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, ok := s.snapshots[streamID]
+	if !ok {
+		return Snapshot{}, ErrSnapshotNotFound
+	}
+
+	snapshot.Data = slices.Clone(snapshot.Data)
+
+	return snapshot, nil
+}
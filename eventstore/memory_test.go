@@ -0,0 +1,52 @@
+package eventstore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemory(), "")
+}
+
+func TestMemoryStore_CopiesData(t *testing.T) {
+	store := NewMemory()
+	data := []byte("original")
+
+	_, err := store.Append(t.Context(), "s", NoStream, Event{Type: "t", Data: data})
+	require.NoError(t, err)
+	data[0] = 'X'
+
+	events, err := store.Load(t.Context(), "s", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("original"), events[0].Data)
+}
+
+func TestMemoryStore_CopiesDataOnRead(t *testing.T) {
+	store := NewMemory()
+
+	_, err := store.Append(t.Context(), "s", NoStream,
+		Event{Type: "t", Data: []byte("original"), Metadata: map[string]string{"k": "v"}})
+	require.NoError(t, err)
+	require.NoError(t, store.SaveSnapshot(t.Context(), Snapshot{StreamID: "s", Version: 1, Data: []byte("state")}))
+
+	events, err := store.Load(t.Context(), "s", 0)
+	require.NoError(t, err)
+	events[0].Data[0] = 'X'
+	events[0].Metadata["k"] = "changed"
+
+	snapshot, err := store.LoadSnapshot(t.Context(), "s")
+	require.NoError(t, err)
+	snapshot.Data[0] = 'X'
+
+	events, err = store.Load(t.Context(), "s", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("original"), events[0].Data)
+	assert.Equal(t, map[string]string{"k": "v"}, events[0].Metadata)
+
+	snapshot, err = store.LoadSnapshot(t.Context(), "s")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("state"), snapshot.Data)
+}
@@ -0,0 +1,186 @@
+package eventstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var _ Store = &PostgresStore{}
+
+// PostgresSchema creates the tables used by PostgresStore.
+const PostgresSchema = `
+CREATE TABLE IF NOT EXISTS eventstore_events (
+	stream_id   TEXT        NOT NULL,
+	version     BIGINT      NOT NULL,
+	type        TEXT        NOT NULL,
+	data        BYTEA       NOT NULL,
+	metadata    JSONB       NOT NULL DEFAULT '{}',
+	recorded_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (stream_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS eventstore_snapshots (
+	stream_id  TEXT        PRIMARY KEY,
+	version    BIGINT      NOT NULL,
+	data       BYTEA       NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);`
+
+// pgUniqueViolation is the SQLSTATE code of a unique constraint violation.
+const pgUniqueViolation = "23505"
+
+// PostgresStore is a Store backed by PostgreSQL.
+// It works with any database/sql driver for Postgres, such as pgx's stdlib driver.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgres creates a store on db. Call Migrate, or apply PostgresSchema
+// with your own migrations, before using it.
+func NewPostgres(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+// Migrate creates the tables of the store if they don't exist.
+func (s *PostgresStore) Migrate(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, PostgresSchema)
+
+	return err
+}
+
+func (s *PostgresStore) Append(ctx context.Context, streamID string,
+	expectedVersion int64, events ...Event,
+) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Serialize appends to the same stream, so the version check below is not racy.
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, streamID); err != nil {
+		return 0, err
+	}
+
+	var version int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM eventstore_events WHERE stream_id = $1`,
+		streamID).Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := checkVersion(streamID, expectedVersion, version); err != nil {
+		return version, err
+	}
+
+	now := time.Now().UTC()
+	for _, event := range events {
+		version++
+
+		metadata, err := json.Marshal(event.Metadata)
+		if err != nil {
+			return 0, err
+		}
+		if event.Data == nil {
+			event.Data = []byte{}
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO eventstore_events (stream_id, version, type, data, metadata, recorded_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			streamID, version, event.Type, event.Data, string(metadata), now)
+		if err != nil {
+			if isUniqueViolation(err) {
+				return 0, &ConflictError{StreamID: streamID, Expected: expectedVersion, Actual: version}
+			}
+
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func (s *PostgresStore) Load(ctx context.Context, streamID string, afterVersion int64) ([]Event, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT version, type, data, metadata, recorded_at FROM eventstore_events
+		 WHERE stream_id = $1 AND version > $2 ORDER BY version`,
+		streamID, afterVersion)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	events := []Event{}
+	for rows.Next() {
+		event := Event{StreamID: streamID}
+		var metadata []byte
+		if err := rows.Scan(&event.Version, &event.Type, &event.Data, &metadata, &event.RecordedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+			return nil, err
+		}
+		event.RecordedAt = event.RecordedAt.UTC()
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *PostgresStore) Version(ctx context.Context, streamID string) (int64, error) {
+	var version int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(version), 0) FROM eventstore_events WHERE stream_id = $1`,
+		streamID).Scan(&version)
+
+	return version, err
+}
+
+func (s *PostgresStore) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	if snapshot.Data == nil {
+		snapshot.Data = []byte{}
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO eventstore_snapshots (stream_id, version, data, created_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (stream_id) DO UPDATE
+		 SET version = EXCLUDED.version, data = EXCLUDED.data, created_at = EXCLUDED.created_at
+		 WHERE eventstore_snapshots.version <= EXCLUDED.version`,
+		snapshot.StreamID, snapshot.Version, snapshot.Data, time.Now().UTC())
+
+	return err
+}
+
+func (s *PostgresStore) LoadSnapshot(ctx context.Context, streamID string) (Snapshot, error) {
+	snapshot := Snapshot{StreamID: streamID}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT version, data, created_at FROM eventstore_snapshots WHERE stream_id = $1`,
+		streamID).Scan(&snapshot.Version, &snapshot.Data, &snapshot.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Snapshot{}, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return Snapshot{}, err
+	}
+	snapshot.CreatedAt = snapshot.CreatedAt.UTC()
+
+	return snapshot, nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique violation.
+// Drivers expose the SQLSTATE code through a SQLState method.
+func isUniqueViolation(err error) bool {
+	var sqlErr interface{ SQLState() string }
+
+	return errors.As(err, &sqlErr) && sqlErr.SQLState() == pgUniqueViolation
+}
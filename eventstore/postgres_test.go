@@ -0,0 +1,41 @@
+package eventstore
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresStore runs against the database in EVENTSTORE_POSTGRES_DSN, if set.
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv("EVENTSTORE_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("EVENTSTORE_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	store := NewPostgres(db)
+	require.NoError(t, store.Migrate(t.Context()))
+
+	testStore(t, store, fmt.Sprintf("test-%d-", time.Now().UnixNano()))
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	assert.True(t, isUniqueViolation(fmt.Errorf("insert: %w", sqlStateError("23505"))))
+	assert.False(t, isUniqueViolation(sqlStateError("40001")))
+	assert.False(t, isUniqueViolation(sql.ErrNoRows))
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
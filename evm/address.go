@@ -0,0 +1,129 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ZeroAddress is the address 0x0000000000000000000000000000000000000000.
+	ZeroAddress = common.Address{}
+
+	// DeadAddress is 0x000000000000000000000000000000000000dEaD,
+	// commonly used to burn tokens.
+	DeadAddress = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+)
+
+var (
+	// ErrInvalidAddress is returned for a string that is not a 0x-prefixed, 20-byte hex address.
+	ErrInvalidAddress = errors.New("evm: invalid address")
+
+	// ErrBadChecksum is returned for a mixed-case address whose EIP-55 checksum doesn't match.
+	ErrBadChecksum = errors.New("evm: address checksum mismatch")
+
+	// ErrZeroAddress is returned by ValidateRecipient for the zero address.
+	ErrZeroAddress = errors.New("evm: zero address")
+
+	// ErrDeadAddress is returned by ValidateRecipient for the burn address.
+	ErrDeadAddress = errors.New("evm: dead address")
+)
+
+// NormalizeAddress parses a 0x-prefixed hex address, ignoring surrounding spaces.
+// A mixed-case address must carry a valid EIP-55 checksum, so a typo in a checksummed
+// address is caught; all-lowercase and all-uppercase addresses carry no checksum.
+func NormalizeAddress(s string) (common.Address, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "0x") || !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("%w: %q", ErrInvalidAddress, s)
+	}
+
+	addr := common.HexToAddress(s)
+	hexPart := s[2:]
+	if hexPart != strings.ToLower(hexPart) && hexPart != strings.ToUpper(hexPart) && s != addr.Hex() {
+		return common.Address{}, fmt.Errorf("%w: %q, expected %s", ErrBadChecksum, s, addr.Hex())
+	}
+
+	return addr, nil
+}
+
+// ValidateRecipient rejects the addresses funds must never be sent to:
+// the zero address and the dead address.
+func ValidateRecipient(addr common.Address) error {
+	switch addr {
+	case ZeroAddress:
+		return ErrZeroAddress
+	case DeadAddress:
+		return ErrDeadAddress
+	default:
+		return nil
+	}
+}
+
+// IsLookalike reports whether a and b are different addresses sharing their first and
+// last n hex digits, as vanity addresses generated for address poisoning do to pass
+// a glance at the start and the end of a known address.
+func IsLookalike(a, b common.Address, n int) bool {
+	if a == b || n <= 0 {
+		return false
+	}
+
+	ha, hb := strings.ToLower(a.Hex()[2:]), strings.ToLower(b.Hex()[2:])
+	n = min(n, len(ha)/2)
+
+	return ha[:n] == hb[:n] && ha[len(ha)-n:] == hb[len(hb)-n:]
+}
+
+// CodeReader reads the code of an account, like ethclient.Client.
+type CodeReader interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// delegationPrefix starts the code of an EOA delegating to a contract (EIP-7702).
+var delegationPrefix = []byte{0xef, 0x01, 0x00}
+
+// contractKey identifies an address on the chain of a client.
+type contractKey struct {
+	client any
+	addr   common.Address
+}
+
+// contracts remembers the addresses found to be contracts.
+var contracts sync.Map
+
+// IsContract reports whether addr holds contract code at the latest block.
+// An EOA delegating to a contract (EIP-7702) is not a contract.
+//
+// Contracts are remembered per client, since deployed code doesn't go away;
+// other addresses are checked on every call, as a contract may be deployed to them later.
+func IsContract(ctx context.Context, client CodeReader, addr common.Address) (bool, error) {
+	cacheable := reflect.TypeOf(client).Comparable()
+	key := contractKey{client: client, addr: addr}
+	if cacheable {
+		if _, ok := contracts.Load(key); ok {
+			return true, nil
+		}
+	}
+
+	code, err := client.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if len(code) == 0 || bytes.HasPrefix(code, delegationPrefix) {
+		return false, nil
+	}
+
+	if cacheable {
+		contracts.Store(key, struct{}{})
+	}
+
+	return true, nil
+}
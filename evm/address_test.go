@@ -0,0 +1,110 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	checksummed := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	want := common.HexToAddress(checksummed)
+
+	for _, input := range []string{
+		checksummed,
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+		"  " + checksummed + "\n",
+	} {
+		addr, err := NormalizeAddress(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, addr, input)
+	}
+
+	_, err := NormalizeAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	require.ErrorIs(t, err, ErrBadChecksum)
+	assert.Contains(t, err.Error(), checksummed)
+
+	for _, input := range []string{
+		"",
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA",
+		"0xZZAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	} {
+		_, err := NormalizeAddress(input)
+		require.ErrorIs(t, err, ErrInvalidAddress, input)
+	}
+}
+
+func TestValidateRecipient(t *testing.T) {
+	require.ErrorIs(t, ValidateRecipient(ZeroAddress), ErrZeroAddress)
+	require.ErrorIs(t, ValidateRecipient(DeadAddress), ErrDeadAddress)
+	require.NoError(t, ValidateRecipient(common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")))
+}
+
+func TestIsLookalike(t *testing.T) {
+	known := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	poisoned := common.HexToAddress("0x5aAe00000000000000000000000000000000eAed")
+
+	assert.True(t, IsLookalike(known, poisoned, 4))
+	assert.False(t, IsLookalike(known, poisoned, 6))
+	assert.False(t, IsLookalike(known, known, 4), "an address is not a lookalike of itself")
+	assert.False(t, IsLookalike(known, DeadAddress, 4))
+	assert.False(t, IsLookalike(known, poisoned, 0))
+}
+
+// fakeCodeReader returns the code of the accounts and counts the calls.
+type fakeCodeReader struct {
+	code  map[common.Address][]byte
+	err   error
+	calls int
+}
+
+func (f *fakeCodeReader) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	f.calls++
+
+	return f.code[account], f.err
+}
+
+func TestIsContract(t *testing.T) {
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	eoa := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	delegated := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	client := &fakeCodeReader{code: map[common.Address][]byte{
+		contract:  {0x60, 0x80, 0x60, 0x40},
+		delegated: append([]byte{0xef, 0x01, 0x00}, contract.Bytes()...),
+	}}
+
+	for range 2 {
+		isContract, err := IsContract(t.Context(), client, contract)
+		require.NoError(t, err)
+		assert.True(t, isContract)
+	}
+	assert.Equal(t, 1, client.calls, "contracts are cached")
+
+	for range 2 {
+		isContract, err := IsContract(t.Context(), client, eoa)
+		require.NoError(t, err)
+		assert.False(t, isContract)
+	}
+	assert.Equal(t, 3, client.calls, "other addresses are not cached")
+
+	isContract, err := IsContract(t.Context(), client, delegated)
+	require.NoError(t, err)
+	assert.False(t, isContract)
+
+	other := &fakeCodeReader{}
+	isContract, err = IsContract(t.Context(), other, contract)
+	require.NoError(t, err)
+	assert.False(t, isContract, "the cache is per client")
+
+	failing := &fakeCodeReader{err: errors.New("rpc down")}
+	_, err = IsContract(t.Context(), failing, contract)
+	require.Error(t, err)
+}
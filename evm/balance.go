@@ -0,0 +1,139 @@
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ezex-io/gopkg/canonical/amount"
+)
+
+var (
+	// ErrNotEnoughBlocks is returned when the chain is shorter than the confirmations asked for.
+	ErrNotEnoughBlocks = errors.New("evm: chain shorter than the confirmations")
+
+	// ErrDecimalsMismatch is returned when the decimals of a token contract
+	// don't match the ones of its registered coin.
+	ErrDecimalsMismatch = errors.New("evm: token decimals mismatch")
+)
+
+// BalanceReader is the part of an Ethereum client BalanceAt needs.
+type BalanceReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// TokenBalanceReader is the part of an Ethereum client TokenBalanceAt needs.
+type TokenBalanceReader interface {
+	ethereum.ContractCaller
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// BalanceAt returns the ETH balance of addr as of confirmations blocks below the head,
+// so the balance only counts transfers buried that deep: zero reads the head,
+// giving the pending balance, and a few blocks give the available one.
+//
+// It has 2 RPC calls: eth_blockNumber and eth_getBalance.
+func BalanceAt(
+	ctx context.Context,
+	client BalanceReader,
+	addr common.Address,
+	confirmations int,
+) (amount.Amount, error) {
+	block, err := confirmedBlock(ctx, client, confirmations)
+	if err != nil {
+		return amount.Amount{}, err
+	}
+
+	wei, err := client.BalanceAt(ctx, addr, block)
+	if err != nil {
+		return amount.Amount{}, err
+	}
+
+	return amount.FromUnits(wei, "ETH")
+}
+
+// TokenBalanceAt returns the ERC-20 balance of addr as of confirmations blocks below
+// the head, like BalanceAt, as an amount of the coin registered under symbol.
+// The decimals of the token contract must match the ones of the coin,
+// or it returns an error wrapping ErrDecimalsMismatch.
+//
+// It has 3 RPC calls: eth_blockNumber and eth_call for decimals and balanceOf.
+func TokenBalanceAt(
+	ctx context.Context,
+	client TokenBalanceReader,
+	token, addr common.Address,
+	symbol string,
+	confirmations int,
+) (amount.Amount, error) {
+	coin, err := amount.Lookup(symbol)
+	if err != nil {
+		return amount.Amount{}, err
+	}
+
+	block, err := confirmedBlock(ctx, client, confirmations)
+	if err != nil {
+		return amount.Amount{}, err
+	}
+
+	erc20, err := erc20ABI()
+	if err != nil {
+		return amount.Amount{}, err
+	}
+
+	decimals, err := callAt[uint8](ctx, client, token, erc20, block, "decimals")
+	if err != nil {
+		return amount.Amount{}, err
+	}
+	if int32(decimals) != coin.Decimals {
+		return amount.Amount{}, fmt.Errorf("%w: %s has %d decimals, %s has %d",
+			ErrDecimalsMismatch, token.Hex(), decimals, coin.Symbol, coin.Decimals)
+	}
+
+	units, err := callAt[*big.Int](ctx, client, token, erc20, block, "balanceOf", addr)
+	if err != nil {
+		return amount.Amount{}, err
+	}
+
+	return amount.FromUnits(units, coin.Symbol)
+}
+
+type blockNumberReader interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// confirmedBlock returns the number of the block confirmations blocks below the head.
+func confirmedBlock(ctx context.Context, client blockNumberReader, confirmations int) (*big.Int, error) {
+	if confirmations < 0 {
+		return nil, fmt.Errorf("evm: negative confirmations %d", confirmations)
+	}
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(confirmations) > head {
+		return nil, fmt.Errorf("%w: head %d, %d confirmations", ErrNotEnoughBlocks, head, confirmations)
+	}
+
+	return new(big.Int).SetUint64(head - uint64(confirmations)), nil
+}
+
+const erc20ABIJSON = `[
+	{"type":"function","name":"decimals","stateMutability":"view",
+	 "inputs":[],"outputs":[{"name":"","type":"uint8"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view",
+	 "inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]}
+]`
+
+var erc20ABI = sync.OnceValues(func() (*abi.ABI, error) {
+	parsed, err := abi.JSON(strings.NewReader(erc20ABIJSON))
+
+	return &parsed, err
+})
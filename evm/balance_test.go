@@ -0,0 +1,99 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ezex-io/gopkg/canonical/amount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeChain keeps a balance per block, and answers ERC-20 calls like a token contract.
+type fakeChain struct {
+	head     uint64
+	balances map[uint64]*big.Int
+	decimals uint8
+	blocks   []*big.Int
+}
+
+func (f *fakeChain) BlockNumber(context.Context) (uint64, error) {
+	return f.head, nil
+}
+
+func (f *fakeChain) BalanceAt(_ context.Context, _ common.Address, block *big.Int) (*big.Int, error) {
+	f.blocks = append(f.blocks, block)
+
+	return f.balances[block.Uint64()], nil
+}
+
+func (f *fakeChain) CallContract(_ context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	f.blocks = append(f.blocks, block)
+
+	erc20, err := erc20ABI()
+	if err != nil {
+		return nil, err
+	}
+	method, err := erc20.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	if method.Name == "decimals" {
+		return method.Outputs.Pack(f.decimals)
+	}
+
+	return method.Outputs.Pack(f.balances[block.Uint64()])
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		head: 100,
+		balances: map[uint64]*big.Int{
+			100: big.NewInt(3_000_000),
+			94:  big.NewInt(1_000_000),
+		},
+		decimals: 6,
+	}
+}
+
+func TestBalanceAt(t *testing.T) {
+	chain := newFakeChain()
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	pending, err := BalanceAt(t.Context(), chain, addr, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", pending.Coin().Symbol)
+	assert.Equal(t, big.NewInt(3_000_000), pending.Units())
+
+	available, err := BalanceAt(t.Context(), chain, addr, 6)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1_000_000), available.Units())
+	assert.Equal(t, big.NewInt(94), chain.blocks[len(chain.blocks)-1])
+
+	_, err = BalanceAt(t.Context(), chain, addr, 101)
+	require.ErrorIs(t, err, ErrNotEnoughBlocks)
+
+	_, err = BalanceAt(t.Context(), chain, addr, -1)
+	require.Error(t, err)
+}
+
+func TestTokenBalanceAt(t *testing.T) {
+	chain := newFakeChain()
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	addr := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	balance, err := TokenBalanceAt(t.Context(), chain, token, addr, "usdt", 6)
+	require.NoError(t, err)
+	assert.Equal(t, "1", balance.Text())
+	assert.Equal(t, []*big.Int{big.NewInt(94), big.NewInt(94)}, chain.blocks, "expected both calls at the same block")
+
+	_, err = TokenBalanceAt(t.Context(), chain, token, addr, "ETH", 0)
+	require.ErrorIs(t, err, ErrDecimalsMismatch)
+
+	_, err = TokenBalanceAt(t.Context(), chain, token, addr, "NOPE", 0)
+	require.ErrorIs(t, err, amount.ErrUnknownCoin)
+}
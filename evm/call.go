@@ -0,0 +1,62 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Call executes a read-only contract method (eth_call) at the latest block
+// and decodes its outputs into T.
+//
+// T is the type of the single output (e.g. *big.Int, common.Address),
+// or a struct whose fields match the output names for methods returning several values.
+func Call[T any](
+	ctx context.Context,
+	client ethereum.ContractCaller,
+	contract common.Address,
+	contractABI *abi.ABI,
+	method string,
+	args ...any,
+) (T, error) {
+	return callAt[T](ctx, client, contract, contractABI, nil, method, args...)
+}
+
+// callAt is Call at the given block, or at the latest block if block is nil.
+func callAt[T any](
+	ctx context.Context,
+	client ethereum.ContractCaller,
+	contract common.Address,
+	contractABI *abi.ABI,
+	block *big.Int,
+	method string,
+	args ...any,
+) (T, error) {
+	var out T
+
+	// ABI-encode the contract method call and its arguments.
+	data, err := contractABI.Pack(method, args...)
+	if err != nil {
+		return out, err
+	}
+
+	msg := ethereum.CallMsg{
+		To:   &contract,
+		Data: data,
+	}
+
+	result, err := client.CallContract(ctx, msg, block)
+	if err != nil {
+		return out, err
+	}
+
+	if err := contractABI.UnpackIntoInterface(&out, method, result); err != nil {
+		return out, fmt.Errorf("failed to unpack %s output: %w", method, err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,79 @@
+package evm
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testABI = `[
+	{"type":"function","name":"balanceOf","stateMutability":"view",
+	 "inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}]},
+	{"type":"function","name":"reserves","stateMutability":"view",
+	 "inputs":[],"outputs":[{"name":"reserve0","type":"uint112"},{"name":"reserve1","type":"uint112"}]}
+]`
+
+// fakeCaller answers eth_call with pre-encoded outputs.
+type fakeCaller struct {
+	abi     *abi.ABI
+	outputs map[string][]any
+	lastMsg ethereum.CallMsg
+}
+
+func (f *fakeCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	f.lastMsg = msg
+
+	method, err := f.abi.MethodById(msg.Data[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	return method.Outputs.Pack(f.outputs[method.Name]...)
+}
+
+func TestCall(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(testABI))
+	require.NoError(t, err)
+
+	contract := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	caller := &fakeCaller{
+		abi: &contractABI,
+		outputs: map[string][]any{
+			"balanceOf": {big.NewInt(1_000)},
+			"reserves":  {big.NewInt(10), big.NewInt(20)},
+		},
+	}
+
+	t.Run("single output", func(t *testing.T) {
+		balance, err := Call[*big.Int](t.Context(), caller, contract, &contractABI, "balanceOf", owner)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(1_000), balance)
+		assert.Equal(t, &contract, caller.lastMsg.To)
+	})
+
+	t.Run("struct output", func(t *testing.T) {
+		type reserves struct {
+			Reserve0 *big.Int
+			Reserve1 *big.Int
+		}
+
+		out, err := Call[reserves](t.Context(), caller, contract, &contractABI, "reserves")
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), out.Reserve0)
+		assert.Equal(t, big.NewInt(20), out.Reserve1)
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		_, err := Call[*big.Int](t.Context(), caller, contract, &contractABI, "totalSupply")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,659 @@
+// Package evmtest simulates an Ethereum chain in memory, so the code confirming
+// transactions, watching deposits or following the head can be tested
+// deterministically, reorgs included, without running a node in CI.
+package evmtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// GenesisTime is the time of the genesis block; each block comes BlockTime seconds
+// after its parent.
+const (
+	GenesisTime = 1767225600 // 2026-01-01T00:00:00Z
+	BlockTime   = 12
+)
+
+// ErrReorgTooDeep is returned by Reorg for a depth beyond the genesis block.
+var ErrReorgTooDeep = errors.New("evmtest: reorg deeper than the chain")
+
+// Tx is a transaction to include in a mined block, with the outcome of its execution.
+type Tx struct {
+	Tx *types.Transaction
+	// Failed gives the transaction a failed receipt status.
+	Failed bool
+	// Logs are the logs of the transaction. Their block, transaction and index fields are set
+	// when the transaction is mined.
+	Logs []types.Log
+	// ContractAddress is the address of the contract the transaction deploys, if any.
+	ContractAddress common.Address
+}
+
+var (
+	_ ethereum.ChainReader       = &ChainSim{}
+	_ ethereum.TransactionReader = &ChainSim{}
+	_ ethereum.LogFilterer       = &ChainSim{}
+	_ ethereum.TransactionSender = &ChainSim{}
+)
+
+// block is a mined block with the receipts of its transactions.
+type block struct {
+	header   *types.Header
+	txs      []*types.Transaction
+	receipts []*types.Receipt
+}
+
+// state is a value set at a block number, such as a balance.
+type state[T any] struct {
+	number uint64
+	value  T
+}
+
+// ChainSim is an in-memory chain implementing the parts of ethclient.Client that follow
+// the head, read receipts, logs, balances and code, and send transactions:
+// ethereum.ChainReader, ethereum.TransactionReader, ethereum.LogFilterer, bind.DeployBackend,
+// and the evm package's BalanceReader and CodeReader.
+//
+// Blocks are mined on demand with Mine, and replaced with Reorg:
+//
+//	sim := evmtest.NewChainSim()
+//	_ = sim.SendTransaction(ctx, tx)
+//	sim.Mine()                  // includes tx
+//	sim.MineEmpty(5)            // buries it under 5 blocks
+//	err := sim.Reorg(6)         // drops it from the chain, back to the pending transactions
+//
+// It is safe for concurrent use.
+type ChainSim struct {
+	mu       sync.Mutex
+	chain    []*block // canonical, from the genesis block
+	byHash   map[common.Hash]*block
+	pending  []*types.Transaction
+	balances map[common.Address][]state[*big.Int]
+	code     map[common.Address][]state[[]byte]
+	branch   uint64 // distinguishes the blocks of a reorg from those they replace
+	heads    []*subscription[*types.Header]
+	logSubs  []*logSubscription
+	receipts []*receiptSubscription
+}
+
+// NewChainSim returns a chain holding the genesis block only.
+func NewChainSim() *ChainSim {
+	genesis := &block{header: &types.Header{
+		Number:     new(big.Int),
+		Time:       GenesisTime,
+		Difficulty: new(big.Int),
+		GasLimit:   30_000_000,
+		BaseFee:    big.NewInt(1_000_000_000),
+	}}
+
+	return &ChainSim{
+		chain:    []*block{genesis},
+		byHash:   map[common.Hash]*block{genesis.header.Hash(): genesis},
+		balances: map[common.Address][]state[*big.Int]{},
+		code:     map[common.Address][]state[[]byte]{},
+	}
+}
+
+// Head returns the header of the head block.
+func (c *ChainSim) Head() *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return types.CopyHeader(c.head().header)
+}
+
+// Mine mines a block on the head holding the pending transactions, which succeed
+// without logs, then txs, and returns its header.
+func (c *ChainSim) Mine(txs ...Tx) *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	mined := c.mine(txs)
+	c.notify(nil, []*block{mined})
+
+	return types.CopyHeader(mined.header)
+}
+
+// MineEmpty mines n blocks, holding the pending transactions in the first one.
+func (c *ChainSim) MineEmpty(n int) {
+	for range n {
+		c.Mine()
+	}
+}
+
+// Reorg replaces the depth blocks below and including the head with a new branch
+// of len(blocks) blocks, at least one, holding the transactions of each of blocks.
+// The transactions of the dropped blocks that aren't in the new branch go back
+// to the pending transactions, and their logs are sent to the log subscribers again
+// with Removed set, as a node does.
+func (c *ChainSim) Reorg(depth int, blocks ...[]Tx) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if depth < 1 || depth >= len(c.chain) {
+		return fmt.Errorf("%w: depth %d, head %d", ErrReorgTooDeep, depth, c.head().header.Number)
+	}
+	if len(blocks) == 0 {
+		blocks = [][]Tx{nil}
+	}
+
+	fork := len(c.chain) - depth
+	dropped := slices.Clone(c.chain[fork:])
+	c.chain = c.chain[:fork]
+	c.branch++
+	forkNumber := uint64(fork - 1)
+	for addr, states := range c.balances {
+		c.balances[addr] = truncate(states, forkNumber)
+	}
+	for addr, states := range c.code {
+		c.code[addr] = truncate(states, forkNumber)
+	}
+
+	var orphans []*types.Transaction
+	for _, b := range dropped {
+		orphans = append(orphans, b.txs...)
+	}
+	orphans = append(orphans, c.pending...)
+	c.pending = nil
+
+	mined := make([]*block, 0, len(blocks))
+	for _, txs := range blocks {
+		mined = append(mined, c.mine(txs))
+	}
+	for _, tx := range orphans {
+		if !c.canonical(tx.Hash()) {
+			c.pending = append(c.pending, tx)
+		}
+	}
+	c.notify(dropped, mined)
+
+	return nil
+}
+
+// SetBalance sets the balance of addr from the next block on.
+func (c *ChainSim) SetBalance(addr common.Address, balance *big.Int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.balances[addr] = append(c.balances[addr], state[*big.Int]{c.nextNumber(), new(big.Int).Set(balance)})
+}
+
+// SetCode sets the code of addr from the next block on.
+func (c *ChainSim) SetCode(addr common.Address, code []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.code[addr] = append(c.code[addr], state[[]byte]{c.nextNumber(), slices.Clone(code)})
+}
+
+// Pending returns the transactions waiting for the next block.
+func (c *ChainSim) Pending() []*types.Transaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.pending)
+}
+
+// SendTransaction adds tx to the pending transactions, mined by the next block.
+func (c *ChainSim) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.canonical(tx.Hash()) || slices.ContainsFunc(c.pending, func(p *types.Transaction) bool {
+		return p.Hash() == tx.Hash()
+	}) {
+		return errors.New("already known")
+	}
+	c.pending = append(c.pending, tx)
+
+	return nil
+}
+
+// BlockNumber returns the number of the head block.
+func (c *ChainSim) BlockNumber(context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.head().header.Number.Uint64(), nil
+}
+
+// HeaderByNumber returns the header of the canonical block number, or of the head if nil.
+func (c *ChainSim) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.blockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.CopyHeader(b.header), nil
+}
+
+// HeaderByHash returns the header of a block, including one dropped by a reorg.
+func (c *ChainSim) HeaderByHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.byHash[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+
+	return types.CopyHeader(b.header), nil
+}
+
+// BlockByNumber returns the canonical block number, or the head if nil.
+func (c *ChainSim) BlockByNumber(_ context.Context, number *big.Int) (*types.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.blockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.block(), nil
+}
+
+// BlockByHash returns a block, including one dropped by a reorg.
+func (c *ChainSim) BlockByHash(_ context.Context, hash common.Hash) (*types.Block, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.byHash[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+
+	return b.block(), nil
+}
+
+// TransactionCount returns the number of transactions in a block.
+func (c *ChainSim) TransactionCount(_ context.Context, blockHash common.Hash) (uint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.byHash[blockHash]
+	if !ok {
+		return 0, ethereum.NotFound
+	}
+
+	return uint(len(b.txs)), nil
+}
+
+// TransactionInBlock returns the transaction at index in a block.
+func (c *ChainSim) TransactionInBlock(_ context.Context, blockHash common.Hash,
+	index uint,
+) (*types.Transaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.byHash[blockHash]
+	if !ok || index >= uint(len(b.txs)) {
+		return nil, ethereum.NotFound
+	}
+
+	return b.txs[index], nil
+}
+
+// TransactionByHash returns a canonical or pending transaction.
+func (c *ChainSim) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, receipt := c.find(hash); receipt != nil {
+		b := c.chain[receipt.BlockNumber.Uint64()]
+
+		return b.txs[receipt.TransactionIndex], false, nil
+	}
+	for _, tx := range c.pending {
+		if tx.Hash() == hash {
+			return tx, true, nil
+		}
+	}
+
+	return nil, false, ethereum.NotFound
+}
+
+// TransactionReceipt returns the receipt of a canonical transaction, or ethereum.NotFound
+// for a pending one or one dropped by a reorg.
+func (c *ChainSim) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, receipt := c.find(hash)
+	if receipt == nil {
+		return nil, ethereum.NotFound
+	}
+
+	return copyReceipt(receipt), nil
+}
+
+// BalanceAt returns the balance of account at the canonical block number, or at the head if nil.
+func (c *ChainSim) BalanceAt(_ context.Context, account common.Address, number *big.Int) (*big.Int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.blockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := valueAt(c.balances[account], b.header.Number.Uint64())
+	if balance == nil {
+		return new(big.Int), nil
+	}
+
+	return new(big.Int).Set(balance), nil
+}
+
+// CodeAt returns the code of account at the canonical block number, or at the head if nil.
+func (c *ChainSim) CodeAt(_ context.Context, account common.Address, number *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := c.blockByNumber(number)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(valueAt(c.code[account], b.header.Number.Uint64())), nil
+}
+
+// FilterLogs returns the logs of the canonical chain matching q.
+func (c *ChainSim) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var blocks []*block
+	if q.BlockHash != nil {
+		b, ok := c.byHash[*q.BlockHash]
+		if !ok {
+			return nil, ethereum.NotFound
+		}
+		blocks = []*block{b}
+	} else {
+		from, to := uint64(0), c.head().header.Number.Uint64()
+		if q.FromBlock != nil && q.FromBlock.Sign() >= 0 {
+			from = q.FromBlock.Uint64()
+		}
+		if q.ToBlock != nil && q.ToBlock.Sign() >= 0 {
+			to = min(to, q.ToBlock.Uint64())
+		}
+		if from <= to {
+			blocks = c.chain[from : to+1]
+		}
+	}
+
+	var logs []types.Log
+	for _, b := range blocks {
+		for _, log := range b.logs(false) {
+			if matches(q, log) {
+				logs = append(logs, log)
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+// SubscribeNewHead sends the header of each new head to ch, including the blocks
+// of a reorg, until the subscription is unsubscribed or ctx is done.
+func (c *ChainSim) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub := newSubscription(ctx, ch)
+	c.heads = append(c.heads, sub)
+
+	return sub, nil
+}
+
+// SubscribeFilterLogs sends the logs matching q of the blocks mined from now on to ch,
+// and the logs of the blocks dropped by a reorg again with Removed set.
+// The block range of q is ignored.
+func (c *ChainSim) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery,
+	ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub := &logSubscription{subscription: newSubscription(ctx, ch), query: q}
+	c.logSubs = append(c.logSubs, sub)
+
+	return sub.subscription, nil
+}
+
+// SubscribeTransactionReceipts sends the receipts of the transactions of q, or of all of them
+// if q is nil or empty, to ch, a batch per block mined from now on.
+func (c *ChainSim) SubscribeTransactionReceipts(ctx context.Context, q *ethereum.TransactionReceiptsQuery,
+	ch chan<- []*types.Receipt,
+) (ethereum.Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sub := &receiptSubscription{subscription: newSubscription(ctx, ch)}
+	if q != nil {
+		sub.hashes = slices.Clone(q.TransactionHashes)
+	}
+	c.receipts = append(c.receipts, sub)
+
+	return sub.subscription, nil
+}
+
+func (c *ChainSim) head() *block {
+	return c.chain[len(c.chain)-1]
+}
+
+func (c *ChainSim) nextNumber() uint64 {
+	return uint64(len(c.chain))
+}
+
+func (c *ChainSim) blockByNumber(number *big.Int) (*block, error) {
+	if number == nil || number.Sign() < 0 { // nil and the latest, pending... tags
+		return c.head(), nil
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(c.chain)) {
+		return nil, ethereum.NotFound
+	}
+
+	return c.chain[number.Uint64()], nil
+}
+
+// find returns the canonical block holding the transaction hash, and its receipt.
+func (c *ChainSim) find(hash common.Hash) (*block, *types.Receipt) {
+	for _, b := range c.chain {
+		for _, receipt := range b.receipts {
+			if receipt.TxHash == hash {
+				return b, receipt
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (c *ChainSim) canonical(hash common.Hash) bool {
+	b, _ := c.find(hash)
+
+	return b != nil
+}
+
+// mine appends a block holding the pending transactions and txs to the chain.
+func (c *ChainSim) mine(txs []Tx) *block {
+	parent := c.head().header
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+		Time:       parent.Time + BlockTime,
+		Difficulty: new(big.Int),
+		GasLimit:   parent.GasLimit,
+		BaseFee:    parent.BaseFee,
+		Extra:      big.NewInt(int64(c.branch)).Bytes(),
+	}
+
+	all := make([]Tx, 0, len(c.pending)+len(txs))
+	for _, tx := range c.pending {
+		all = append(all, Tx{Tx: tx})
+	}
+	c.pending = nil
+	all = append(all, txs...)
+
+	b := &block{header: header}
+	hash := header.Hash()
+	logIndex := uint(0)
+	for i, tx := range all {
+		receipt := &types.Receipt{
+			Type:              tx.Tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			TxHash:            tx.Tx.Hash(),
+			ContractAddress:   tx.ContractAddress,
+			GasUsed:           tx.Tx.Gas(),
+			EffectiveGasPrice: new(big.Int).Set(header.BaseFee),
+			BlockHash:         hash,
+			BlockNumber:       new(big.Int).Set(header.Number),
+			TransactionIndex:  uint(i),
+		}
+		if tx.Failed {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		for _, log := range tx.Logs {
+			log.BlockNumber = header.Number.Uint64()
+			log.BlockHash = hash
+			log.BlockTimestamp = header.Time
+			log.TxHash = receipt.TxHash
+			log.TxIndex = uint(i)
+			log.Index = logIndex
+			log.Removed = false
+			logIndex++
+			receipt.Logs = append(receipt.Logs, &log)
+		}
+		receipt.Bloom = types.CreateBloom(receipt)
+
+		b.txs = append(b.txs, tx.Tx)
+		b.receipts = append(b.receipts, receipt)
+	}
+
+	c.chain = append(c.chain, b)
+	c.byHash[hash] = b
+
+	return b
+}
+
+// notify sends the new heads and the logs of the dropped and mined blocks to the subscribers.
+func (c *ChainSim) notify(dropped, mined []*block) {
+	c.heads = slices.DeleteFunc(c.heads, (*subscription[*types.Header]).closed)
+	c.logSubs = slices.DeleteFunc(c.logSubs, func(sub *logSubscription) bool { return sub.closed() })
+	c.receipts = slices.DeleteFunc(c.receipts, func(sub *receiptSubscription) bool { return sub.closed() })
+
+	for _, b := range mined {
+		for _, sub := range c.heads {
+			sub.send(types.CopyHeader(b.header))
+		}
+	}
+
+	for _, sub := range c.receipts {
+		for _, b := range mined {
+			var batch []*types.Receipt
+			for _, receipt := range b.receipts {
+				if len(sub.hashes) == 0 || slices.Contains(sub.hashes, receipt.TxHash) {
+					batch = append(batch, copyReceipt(receipt))
+				}
+			}
+			if len(batch) > 0 {
+				sub.send(batch)
+			}
+		}
+	}
+
+	for _, sub := range c.logSubs {
+		for _, b := range slices.Backward(dropped) {
+			for _, log := range slices.Backward(b.logs(true)) {
+				if matches(sub.query, log) {
+					sub.send(log)
+				}
+			}
+		}
+		for _, b := range mined {
+			for _, log := range b.logs(false) {
+				if matches(sub.query, log) {
+					sub.send(log)
+				}
+			}
+		}
+	}
+}
+
+// block returns b as a types.Block. Its transaction and receipt roots aren't computed.
+func (b *block) block() *types.Block {
+	return types.NewBlockWithHeader(b.header).WithBody(types.Body{Transactions: b.txs})
+}
+
+// logs returns copies of the logs of b, marked removed if asked.
+func (b *block) logs(removed bool) []types.Log {
+	var logs []types.Log
+	for _, receipt := range b.receipts {
+		for _, log := range receipt.Logs {
+			copied := *log
+			copied.Removed = removed
+			logs = append(logs, copied)
+		}
+	}
+
+	return logs
+}
+
+func copyReceipt(receipt *types.Receipt) *types.Receipt {
+	copied := *receipt
+	copied.Logs = make([]*types.Log, len(receipt.Logs))
+	for i, log := range receipt.Logs {
+		l := *log
+		copied.Logs[i] = &l
+	}
+
+	return &copied
+}
+
+// matches reports whether log matches the addresses and topics of q, as a node filters them.
+func matches(q ethereum.FilterQuery, log types.Log) bool {
+	if len(q.Addresses) > 0 && !slices.Contains(q.Addresses, log.Address) {
+		return false
+	}
+	if len(q.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range q.Topics {
+		if len(alternatives) > 0 && !slices.Contains(alternatives, log.Topics[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// valueAt returns the value of the last state set at or before number.
+func valueAt[T any](states []state[T], number uint64) T {
+	var value T
+	for _, s := range states {
+		if s.number <= number {
+			value = s.value
+		}
+	}
+
+	return value
+}
+
+// truncate drops the states set after number.
+func truncate[T any](states []state[T], number uint64) []state[T] {
+	return slices.DeleteFunc(states, func(s state[T]) bool { return s.number > number })
+}
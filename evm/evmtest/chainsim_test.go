@@ -0,0 +1,204 @@
+package evmtest
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ezex-io/gopkg/evm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice    = common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	token    = common.HexToAddress("0x0000000000000000000000000000000000070ce0")
+	transfer = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+)
+
+func newTx(nonce uint64) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{Nonce: nonce, To: &alice, Value: big.NewInt(1), Gas: 21_000})
+}
+
+func TestMineAndReceipts(t *testing.T) {
+	sim := NewChainSim()
+	tx := newTx(0)
+	require.NoError(t, sim.SendTransaction(t.Context(), tx))
+	require.Error(t, sim.SendTransaction(t.Context(), tx), "already known")
+
+	_, pending, err := sim.TransactionByHash(t.Context(), tx.Hash())
+	require.NoError(t, err)
+	assert.True(t, pending)
+	_, err = sim.TransactionReceipt(t.Context(), tx.Hash())
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	failed := newTx(1)
+	header := sim.Mine(Tx{Tx: failed, Failed: true})
+	assert.Equal(t, uint64(1), header.Number.Uint64())
+	assert.Equal(t, uint64(GenesisTime+BlockTime), header.Time)
+	assert.Empty(t, sim.Pending())
+
+	receipt, err := bind.WaitMined(t.Context(), sim, tx)
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+	assert.Equal(t, header.Hash(), receipt.BlockHash)
+
+	receipt, err = sim.TransactionReceipt(t.Context(), failed.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusFailed, receipt.Status)
+	assert.Equal(t, uint(1), receipt.TransactionIndex)
+
+	block, err := sim.BlockByNumber(t.Context(), nil)
+	require.NoError(t, err)
+	assert.Len(t, block.Transactions(), 2)
+	count, err := sim.TransactionCount(t.Context(), header.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+}
+
+func TestReorg(t *testing.T) {
+	sim := NewChainSim()
+	tx := newTx(0)
+	mined := sim.Mine(Tx{Tx: tx, Logs: []types.Log{{Address: token, Topics: []common.Hash{transfer}}}})
+	sim.MineEmpty(2)
+
+	logs, err := sim.FilterLogs(t.Context(), ethereum.FilterQuery{Addresses: []common.Address{token}})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, mined.Hash(), logs[0].BlockHash)
+
+	require.ErrorIs(t, sim.Reorg(4), ErrReorgTooDeep)
+	require.NoError(t, sim.Reorg(3, nil, nil))
+
+	head, err := sim.BlockNumber(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), head, "the new branch is shorter")
+
+	_, err = sim.TransactionReceipt(t.Context(), tx.Hash())
+	require.ErrorIs(t, err, ethereum.NotFound)
+	assert.Equal(t, []*types.Transaction{tx}, sim.Pending(), "the dropped transaction is pending again")
+
+	replaced, err := sim.HeaderByNumber(t.Context(), big.NewInt(1))
+	require.NoError(t, err)
+	assert.NotEqual(t, mined.Hash(), replaced.Hash())
+	_, err = sim.HeaderByHash(t.Context(), mined.Hash())
+	require.NoError(t, err, "dropped blocks can still be read by hash")
+
+	logs, err = sim.FilterLogs(t.Context(), ethereum.FilterQuery{Addresses: []common.Address{token}})
+	require.NoError(t, err)
+	assert.Empty(t, logs)
+
+	// Mined again, deeper.
+	sim.Mine()
+	receipt, err := sim.TransactionReceipt(t.Context(), tx.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), receipt.BlockNumber.Uint64())
+}
+
+func TestBalanceConfirmations(t *testing.T) {
+	sim := NewChainSim()
+	sim.MineEmpty(3)
+	sim.SetBalance(alice, big.NewInt(1e18))
+	sim.Mine()
+
+	pending, err := evm.BalanceAt(t.Context(), sim, alice, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "1 ETH", pending.String())
+
+	confirmed, err := evm.BalanceAt(t.Context(), sim, alice, 1)
+	require.NoError(t, err)
+	assert.True(t, confirmed.IsZero())
+
+	// A reorg dropping the block of the deposit drops the balance.
+	require.NoError(t, sim.Reorg(1))
+	pending, err = evm.BalanceAt(t.Context(), sim, alice, 0)
+	require.NoError(t, err)
+	assert.True(t, pending.IsZero())
+}
+
+func TestCode(t *testing.T) {
+	sim := NewChainSim()
+	isContract, err := evm.IsContract(t.Context(), sim, token)
+	require.NoError(t, err)
+	assert.False(t, isContract)
+
+	sim.SetCode(token, []byte{0x60, 0x80})
+	sim.Mine()
+
+	isContract, err = evm.IsContract(t.Context(), sim, token)
+	require.NoError(t, err)
+	assert.True(t, isContract)
+
+	code, err := sim.CodeAt(t.Context(), token, big.NewInt(0))
+	require.NoError(t, err)
+	assert.Empty(t, code)
+}
+
+func TestSubscriptions(t *testing.T) {
+	sim := NewChainSim()
+
+	heads := make(chan *types.Header)
+	headSub, err := sim.SubscribeNewHead(t.Context(), heads)
+	require.NoError(t, err)
+	defer headSub.Unsubscribe()
+
+	logs := make(chan types.Log)
+	query := ethereum.FilterQuery{Topics: [][]common.Hash{{transfer}}}
+	logSub, err := sim.SubscribeFilterLogs(t.Context(), query, logs)
+	require.NoError(t, err)
+	defer logSub.Unsubscribe()
+
+	// The chain doesn't wait for the subscribers.
+	mined := sim.Mine(Tx{Tx: newTx(0), Logs: []types.Log{
+		{Address: token, Topics: []common.Hash{transfer}},
+		{Address: token, Topics: []common.Hash{{0x01}}},
+	}})
+	require.NoError(t, sim.Reorg(1))
+
+	receive := func() types.Log {
+		select {
+		case log := <-logs:
+			return log
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a log")
+
+			return types.Log{}
+		}
+	}
+	added, removed := receive(), receive()
+	assert.Equal(t, mined.Hash(), added.BlockHash)
+	assert.False(t, added.Removed)
+	assert.Equal(t, mined.Hash(), removed.BlockHash)
+	assert.True(t, removed.Removed)
+
+	first, second := <-heads, <-heads
+	assert.Equal(t, mined.Hash(), first.Hash())
+	assert.Equal(t, mined.Number, second.Number)
+	assert.NotEqual(t, first.Hash(), second.Hash())
+
+	headSub.Unsubscribe()
+	_, open := <-headSub.Err()
+	assert.False(t, open)
+}
+
+func TestSubscribeTransactionReceipts(t *testing.T) {
+	sim := NewChainSim()
+
+	receipts := make(chan []*types.Receipt, 1)
+	tx := newTx(1)
+	sub, err := sim.SubscribeTransactionReceipts(t.Context(),
+		&ethereum.TransactionReceiptsQuery{TransactionHashes: []common.Hash{tx.Hash()}}, receipts)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	sim.Mine(Tx{Tx: newTx(2)})
+	sim.Mine(Tx{Tx: tx}, Tx{Tx: newTx(3)})
+
+	batch := <-receipts
+	require.Len(t, batch, 1)
+	assert.Equal(t, tx.Hash(), batch[0].TxHash)
+}
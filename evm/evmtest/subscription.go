@@ -0,0 +1,119 @@
+package evmtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// subscription delivers values to the channel of a subscriber in order, from a queue,
+// so the chain never waits for a slow subscriber.
+type subscription[T any] struct {
+	mu       sync.Mutex
+	queue    []T
+	wake     chan struct{}
+	done     chan struct{}
+	errs     chan error
+	stopOnce sync.Once
+}
+
+func newSubscription[T any](ctx context.Context, ch chan<- T) *subscription[T] {
+	sub := &subscription[T]{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+		errs: make(chan error, 1),
+	}
+	go sub.deliver(ctx, ch)
+
+	return sub
+}
+
+func (s *subscription[T]) send(value T) {
+	s.mu.Lock()
+	s.queue = append(s.queue, value)
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscription[T]) deliver(ctx context.Context, ch chan<- T) {
+	defer close(s.errs)
+
+	for {
+		s.mu.Lock()
+		var next T
+		ready := len(s.queue) > 0
+		if ready {
+			next = s.queue[0]
+			s.queue = s.queue[1:]
+		}
+		s.mu.Unlock()
+
+		if !ready {
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			case <-ctx.Done():
+				s.errs <- ctx.Err()
+				s.Unsubscribe()
+
+				return
+			}
+		}
+
+		select {
+		case ch <- next:
+		case <-s.done:
+			return
+		case <-ctx.Done():
+			s.errs <- ctx.Err()
+			s.Unsubscribe()
+
+			return
+		}
+	}
+}
+
+// Unsubscribe stops the delivery and closes the error channel.
+func (s *subscription[T]) Unsubscribe() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// Err returns the channel receiving the error ending the subscription, such as
+// the error of its context, and closed once it is unsubscribed.
+func (s *subscription[T]) Err() <-chan error {
+	return s.errs
+}
+
+func (s *subscription[T]) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// logSubscription is a subscription to the logs matching a query.
+type logSubscription struct {
+	*subscription[types.Log]
+
+	query ethereum.FilterQuery
+}
+
+// receiptSubscription is a subscription to the receipts of transactions.
+type receiptSubscription struct {
+	*subscription[[]*types.Receipt]
+
+	hashes []common.Hash
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+type (
+	loggerKey    struct{}
+	traceKey     struct{}
+	requestIDKey struct{}
+)
+
+// trace is the W3C trace context carried by a context.
+type trace struct {
+	traceID, spanID string
+}
+
+// WithContext returns a copy of ctx carrying l, for FromContext to retrieve
+// deeper in the call stack.
+func WithContext(ctx context.Context, l *Slog) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or DefaultSlog.
+// Log with its Ctx methods, passing ctx, so the records carry the trace ID,
+// span ID and request ID of ctx, and its scope:
+//
+//	logger.FromContext(ctx).InfoCtx(ctx, "order placed", "order_id", order.ID)
+func FromContext(ctx context.Context) *Slog {
+	if l, ok := ctx.Value(loggerKey{}).(*Slog); ok && l != nil {
+		return l
+	}
+
+	return DefaultSlog
+}
+
+// WithTrace returns a copy of ctx carrying the W3C trace ID and span ID of the current
+// operation, logged as trace_id and span_id by the Ctx methods of Slog
+// and propagated in the traceparent header by HTTPTransport.
+func WithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, trace{traceID: traceID, spanID: spanID})
+}
+
+// TraceFromContext returns the trace ID and span ID carried by ctx, if any.
+func TraceFromContext(ctx context.Context) (traceID, spanID string, ok bool) {
+	t, ok := ctx.Value(traceKey{}).(trace)
+
+	return t.traceID, t.spanID, ok
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID, logged as request_id
+// by the Ctx methods of Slog and propagated in the X-Request-Id header by HTTPTransport.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+
+	return requestID
+}
+
+// ContextFromHeader returns a copy of ctx carrying the request ID and the trace context
+// of the X-Request-Id and traceparent headers, if set, such as those of an inbound request:
+//
+//	ctx := logger.ContextFromHeader(r.Context(), r.Header)
+func ContextFromHeader(ctx context.Context, header http.Header) context.Context {
+	if requestID := header.Get(HeaderRequestID); requestID != "" {
+		ctx = WithRequestID(ctx, requestID)
+	}
+	if traceID, spanID, ok := ParseTraceParent(header.Get(HeaderTraceParent)); ok {
+		ctx = WithTrace(ctx, traceID, spanID)
+	}
+
+	return ctx
+}
+
+// contextAttrs returns the attributes of the trace context and request ID of ctx.
+func contextAttrs(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+	if traceID, spanID, ok := TraceFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("trace_id", traceID), slog.String("span_id", spanID))
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		attrs = append(attrs, slog.String("request_id", requestID))
+	}
+
+	return attrs
+}
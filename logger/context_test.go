@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Same(t, DefaultSlog, FromContext(t.Context()))
+
+	log := NewSlog(WithTextHandler(&bytes.Buffer{}, slog.LevelInfo))
+	assert.Same(t, log, FromContext(WithContext(t.Context(), log)))
+}
+
+func TestContextAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithTextHandler(&buf, slog.LevelInfo))
+
+	ctx := WithTrace(t.Context(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7")
+	ctx = WithRequestID(ctx, "req-1")
+	ctx = WithContext(ctx, log)
+
+	FromContext(ctx).InfoCtx(ctx, "order placed")
+	assert.Contains(t, buf.String(),
+		"trace_id=4bf92f3577b34da6a3ce929d0e0e4736 span_id=00f067aa0ba902b7 request_id=req-1")
+
+	buf.Reset()
+	FromContext(ctx).InfoCtx(PushScope(ctx, "request_id", "req-2"), "scoped")
+	assert.Contains(t, buf.String(), "request_id=req-2")
+	assert.NotContains(t, buf.String(), "req-1", "the scope wins")
+
+	buf.Reset()
+	log.Info("no context")
+	assert.NotContains(t, buf.String(), "trace_id")
+}
+
+func TestContextFromHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set(HeaderRequestID, "req-1")
+	header.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := ContextFromHeader(t.Context(), header)
+	assert.Equal(t, "req-1", RequestIDFromContext(ctx))
+	traceID, spanID, ok := TraceFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Equal(t, "00f067aa0ba902b7", spanID)
+
+	ctx = ContextFromHeader(t.Context(), http.Header{HeaderTraceParent: {"invalid"}})
+	_, _, ok = TraceFromContext(ctx)
+	assert.False(t, ok)
+	assert.Empty(t, RequestIDFromContext(ctx))
+}
+
+func TestHTTPTransport_Propagation(t *testing.T) {
+	received := make(chan http.Header, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received <- r.Header
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: HTTPTransport(nil, WithTransportLogger(NewSlog(nil)))}
+	ctx := WithRequestID(WithTrace(t.Context(), "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"), "req-1")
+
+	send := func(ctx context.Context, requestID string) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, http.NoBody)
+		require.NoError(t, err)
+		if requestID != "" {
+			req.Header.Set(HeaderRequestID, requestID)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, requestID, req.Header.Get(HeaderRequestID), "the request of the caller is unchanged")
+	}
+
+	send(ctx, "")
+	header := <-received
+	assert.Equal(t, "req-1", header.Get(HeaderRequestID))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", header.Get(HeaderTraceParent))
+
+	send(ctx, "explicit")
+	assert.Equal(t, "explicit", (<-received).Get(HeaderRequestID))
+}
@@ -0,0 +1,22 @@
+package logger
+
+import "log/slog"
+
+// lazyValue defers computing an attribute value until a handler resolves it.
+type lazyValue func() any
+
+func (f lazyValue) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
+
+// Lazy wraps an expensive attribute value, such as a serialized payload, so fn only runs
+// when the record is emitted, not when its level is disabled:
+//
+//	log.Debug("request", "payload", logger.Lazy(func() any { return dump(req) }))
+//
+// fn runs at most once per emitted record, even with WithMultiHandler; a panic in fn
+// is logged in place of the value.
+// Attributes passed to With are resolved by the handler when With is called.
+func Lazy(fn func() any) slog.LogValuer {
+	return lazyValue(fn)
+}
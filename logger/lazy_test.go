@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy_NotEvaluatedWhenDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithTextHandler(&buf, slog.LevelInfo))
+
+	calls := 0
+	log.Debug("debug msg", "payload", Lazy(func() any {
+		calls++
+
+		return "expensive"
+	}))
+
+	assert.Zero(t, calls)
+	assert.Empty(t, buf.String())
+}
+
+func TestLazy_EvaluatedWhenEmitted(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithJSONHandler(&buf, slog.LevelDebug))
+
+	calls := 0
+	log.Debug("debug msg", "payload", Lazy(func() any {
+		calls++
+
+		return map[string]int{"size": 42}
+	}))
+
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, buf.String(), `"payload":{"size":42}`)
+}
+
+func TestLazy_Routing(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithRouting(RouteByAttr("audit", "audit", "app"), map[string]SlogHandler{
+		"app": WithTextHandler(&buf, slog.LevelInfo),
+	}))
+
+	calls := 0
+	log.Debug("debug msg", "payload", Lazy(func() any {
+		calls++
+
+		return "expensive"
+	}))
+
+	assert.Zero(t, calls)
+}
+
+func TestLazy_Panic(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithTextHandler(&buf, slog.LevelInfo))
+
+	assert.NotPanics(t, func() {
+		log.Info("msg", "payload", Lazy(func() any { panic("boom") }))
+	})
+	assert.Contains(t, buf.String(), "LogValue panicked")
+}
+
+func TestLazy_MultiHandler(t *testing.T) {
+	var text, json bytes.Buffer
+	log := NewSlog(WithMultiHandler(
+		WithTextHandler(&text, slog.LevelInfo),
+		WithJSONHandler(&json, slog.LevelInfo),
+	))
+
+	calls := 0
+	payload := Lazy(func() any {
+		calls++
+
+		return "expensive"
+	})
+	log.Info("request", "payload", payload, slog.Group("http", "body", payload))
+
+	assert.Equal(t, 2, calls, "once per attribute, not per handler")
+	assert.Contains(t, text.String(), "payload=expensive http.body=expensive")
+	assert.Contains(t, json.String(), `"payload":"expensive","http":{"body":"expensive"}`)
+
+	calls = 0
+	log.With("payload", payload).Info("request")
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,201 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	// LevelsEnv is the environment variable read by FromEnv.
+	LevelsEnv = "LOG_LEVELS"
+
+	// ModuleKey is the attribute naming the module of a logger, as in log.With("module", "evm").
+	ModuleKey = "module"
+)
+
+// levelRule sets the level of the modules matching pattern.
+type levelRule struct {
+	pattern string
+	level   slog.Level
+}
+
+// ModuleLevels are the levels of modules, parsed by ParseModuleLevels.
+type ModuleLevels struct {
+	rules []levelRule
+}
+
+// ParseModuleLevels parses a comma-separated list of module=level rules,
+// such as "evm=debug,middleware=warn,*=info". Levels are those of slog.Level,
+// such as "debug" or "warn+2". Patterns may contain * wildcards, matching any text:
+// "http-*" matches "http-mdl" and "http-client".
+//
+// The level of a module is the level of its rule, or else of the longest pattern matching it;
+// info if none does.
+func ParseModuleLevels(spec string) (ModuleLevels, error) {
+	var levels ModuleLevels
+	for rule := range strings.SplitSeq(spec, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		pattern, text, ok := strings.Cut(rule, "=")
+		pattern = strings.TrimSpace(pattern)
+		if !ok || pattern == "" {
+			return ModuleLevels{}, fmt.Errorf("logger: invalid level rule %q, want module=level", rule)
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(text))); err != nil {
+			return ModuleLevels{}, fmt.Errorf("logger: invalid level rule %q: %w", rule, err)
+		}
+		levels.rules = append(levels.rules, levelRule{pattern: pattern, level: level})
+	}
+
+	return levels, nil
+}
+
+// Level returns the level of the module.
+func (m ModuleLevels) Level(module string) slog.Level {
+	best := -1
+	level := slog.LevelInfo
+	for _, rule := range m.rules {
+		if rule.pattern == module {
+			return rule.level
+		}
+		if strings.Contains(rule.pattern, "*") && len(rule.pattern) > best && matchPattern(rule.pattern, module) {
+			best = len(rule.pattern)
+			level = rule.level
+		}
+	}
+
+	return level
+}
+
+// minLevel returns the lowest level of the rules, the lowest a record of any module may have.
+func (m ModuleLevels) minLevel() slog.Level {
+	level := m.Level("")
+	for _, rule := range m.rules {
+		level = min(level, rule.level)
+	}
+
+	return level
+}
+
+// matchPattern reports whether name matches pattern, where * matches any text.
+func matchPattern(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+
+	return strings.HasSuffix(name, parts[last])
+}
+
+// FromEnv returns a logger filtering the records of handler by the level of their module,
+// named by the ModuleKey attribute, as set by LOG_LEVELS and parsed by ParseModuleLevels:
+//
+//	// LOG_LEVELS="evm=debug,middleware=warn,*=info"
+//	handler, err := logger.FromEnv(logger.WithJSONHandler(os.Stdout, slog.LevelDebug))
+//	...
+//	log := logger.NewSlog(handler).With(logger.ModuleKey, "evm") // logs debug records
+//
+// Records without a module have the level of the "*" rule. Create handler at
+// the lowest level the rules use, as it filters the records too.
+func FromEnv(handler SlogHandler) (SlogHandler, error) {
+	levels, err := ParseModuleLevels(os.Getenv(LevelsEnv))
+	if err != nil {
+		return nil, err
+	}
+
+	return WithModuleLevels(handler, levels), nil
+}
+
+// WithModuleLevels returns a logger filtering the records of handler by the level of their module,
+// as FromEnv does with the levels it reads.
+func WithModuleLevels(handler SlogHandler, levels ModuleLevels) SlogHandler {
+	return func() *slog.Logger {
+		return slog.New(&levelsHandler{
+			Handler:  handler().Handler(),
+			levels:   levels,
+			level:    levels.Level(""),
+			minLevel: levels.minLevel(),
+		})
+	}
+}
+
+// levelsHandler filters records by the level of their module: the one added with WithAttrs
+// outside of any group, or else the one of the record, if it is not in a group either.
+type levelsHandler struct {
+	slog.Handler
+	levels ModuleLevels
+
+	// minLevel is the lowest level of the modules records may name.
+	minLevel slog.Level
+
+	// level is the level of the module added with WithAttrs, or of no module.
+	level     slog.Level
+	hasModule bool
+	grouped   bool
+}
+
+func (h *levelsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.hasModule {
+		return level >= h.level && h.Handler.Enabled(ctx, level)
+	}
+
+	// The record may name its module.
+	return level >= h.minLevel && h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelsHandler) Handle(ctx context.Context, record slog.Record) error {
+	level := h.level
+	if !h.hasModule && !h.grouped {
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key != ModuleKey {
+				return true
+			}
+			level = h.levels.Level(attr.Value.String())
+
+			return false
+		})
+	}
+	if record.Level < level {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *levelsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.Handler = h.Handler.WithAttrs(attrs)
+	if i := slices.IndexFunc(attrs, func(attr slog.Attr) bool { return attr.Key == ModuleKey }); i >= 0 && !h.grouped {
+		clone.level = h.levels.Level(attrs[i].Value.String())
+		clone.hasModule = true
+	}
+
+	return &clone
+}
+
+func (h *levelsHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.Handler = h.Handler.WithGroup(name)
+	clone.grouped = clone.grouped || name != ""
+
+	return &clone
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModuleLevels(t *testing.T) {
+	levels, err := ParseModuleLevels(" evm=debug, middleware=warn,http-*=error,*-mdl=debug, *=info,")
+	require.NoError(t, err)
+
+	tests := map[string]slog.Level{
+		"evm":        slog.LevelDebug,
+		"middleware": slog.LevelWarn,
+		"http-mdl":   slog.LevelError, // the longest pattern wins
+		"grpc-mdl":   slog.LevelDebug,
+		"scheduler":  slog.LevelInfo,
+		"":           slog.LevelInfo,
+	}
+	for module, want := range tests {
+		assert.Equal(t, want, levels.Level(module), module)
+	}
+
+	levels, err = ParseModuleLevels("")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelInfo, levels.Level("evm"))
+
+	for _, spec := range []string{"evm", "=debug", "evm=loud"} {
+		_, err := ParseModuleLevels(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	assert.True(t, matchPattern("*", "evm"))
+	assert.True(t, matchPattern("e*m", "evm"))
+	assert.True(t, matchPattern("*v*", "evm"))
+	assert.False(t, matchPattern("e*v", "evm"))
+	assert.False(t, matchPattern("ev*vm", "evm"))
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(LevelsEnv, "evm=debug,middleware=warn,*=info")
+
+	var buf bytes.Buffer
+	handler, err := FromEnv(WithTextHandler(&buf, slog.LevelDebug))
+	require.NoError(t, err)
+	log := NewSlog(handler)
+
+	log.With(ModuleKey, "evm").Debug("block fetched")
+	log.With(ModuleKey, "middleware").Info("request served")
+	log.With(ModuleKey, "middleware").Warn("request slow")
+	log.Debug("no module")
+	log.Info("started")
+	log.Debug("record module", ModuleKey, "evm")
+	log.InfoCtx(PushScope(context.Background(), ModuleKey, "middleware"), "scoped module")
+
+	output := buf.String()
+	assert.Contains(t, output, "block fetched")
+	assert.NotContains(t, output, "request served")
+	assert.Contains(t, output, "request slow")
+	assert.NotContains(t, output, "no module")
+	assert.Contains(t, output, "started")
+	assert.Contains(t, output, "record module")
+	assert.NotContains(t, output, "scoped module")
+}
+
+func TestFromEnv_GroupedModule(t *testing.T) {
+	t.Setenv(LevelsEnv, "evm=debug")
+
+	var buf bytes.Buffer
+	handler, err := FromEnv(WithTextHandler(&buf, slog.LevelDebug))
+	require.NoError(t, err)
+
+	log := NewSlog(handler).Logger().WithGroup("rpc").With(ModuleKey, "evm")
+	log.Debug("not a module")
+
+	assert.Empty(t, buf.String(), "a module attribute in a group doesn't name the module")
+}
+
+func TestFromEnv_Invalid(t *testing.T) {
+	t.Setenv(LevelsEnv, "evm")
+
+	_, err := FromEnv(WithTextHandler(nil, slog.LevelDebug))
+	require.Error(t, err)
+}
@@ -0,0 +1,340 @@
+// Package logschema checks that log records follow the field naming policy,
+// so a renamed or mistyped field doesn't silently break the alerts and dashboards
+// built on the logs. Run it in tests, wrapping the handler of the logger under test:
+//
+//	schema := logschema.New(logschema.WithAllowed("userID")) // legacy key
+//	log := logger.NewSlog(schema.Wrap(logger.WithJSONHandler(io.Discard, slog.LevelDebug)))
+//	... // exercise the code logging to log
+//	schema.AssertValid(t)
+//
+// The policy is:
+//   - keys, and group names, are snake_case, unless allowed;
+//   - the keys of the handler, such as ts, time, level and msg, are not used as attributes;
+//   - reserved keys, such as request_id, hold values of their kind.
+package logschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ezex-io/gopkg/logger"
+)
+
+// BuiltinKeys are the keys written by the handlers, which attributes must not shadow.
+var BuiltinKeys = []string{"ts", slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey}
+
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// Violation is a breach of the policy by a field.
+type Violation struct {
+	// Key is the dotted path of the field, with its groups.
+	Key string
+	// Reason describes the breach.
+	Reason string
+	// Message is the message of the first record found with the breach.
+	Message string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("logschema: %s: %s (in %q)", v.Key, v.Reason, v.Message)
+}
+
+type config struct {
+	allowed  map[string]bool
+	reserved map[string]slog.Kind
+}
+
+// Option configures a Validator.
+type Option func(*config)
+
+// WithAllowed exempts keys, or dotted paths, from the snake_case rule,
+// such as legacy fields that alerts already depend on.
+func WithAllowed(keys ...string) Option {
+	return func(cfg *config) {
+		for _, key := range keys {
+			cfg.allowed[key] = true
+		}
+	}
+}
+
+// WithReserved requires the values of key to be of kind, in addition to
+// request_id, which must be a string.
+func WithReserved(key string, kind slog.Kind) Option {
+	return func(cfg *config) {
+		cfg.reserved[key] = kind
+	}
+}
+
+// Validator checks records against the policy, and collects the violations
+// of the records handled by its handlers. It is safe for concurrent use.
+type Validator struct {
+	cfg config
+
+	mu         sync.Mutex
+	violations []Violation
+	seen       map[string]bool
+}
+
+// New returns a Validator of the policy, customized by opts.
+func New(opts ...Option) *Validator {
+	cfg := config{
+		allowed:  map[string]bool{},
+		reserved: map[string]slog.Kind{"request_id": slog.KindString},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Validator{cfg: cfg, seen: map[string]bool{}}
+}
+
+// Check returns the violations of the attributes of record.
+func (v *Validator) Check(record slog.Record) []Violation {
+	var violations []Violation
+	record.Attrs(func(attr slog.Attr) bool {
+		violations = append(violations, v.checkAttr("", attr)...)
+
+		return true
+	})
+
+	return withMessage(violations, record.Message)
+}
+
+// CheckJSON returns the violations of a record written by a JSON handler, as one line.
+// The keys of the handler are accepted at the top level of the line, as they can't be told
+// from attributes shadowing them there; check records with Handler to catch those.
+func (v *Validator) CheckJSON(line []byte) ([]Violation, error) {
+	var fields map[string]any
+	decoder := json.NewDecoder(strings.NewReader(string(line)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("logschema: invalid JSON record: %w", err)
+	}
+
+	message, _ := fields[slog.MessageKey].(string)
+	var violations []Violation
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if slices.Contains(BuiltinKeys, key) {
+			continue
+		}
+		violations = append(violations, v.checkJSON("", key, fields[key])...)
+	}
+
+	return withMessage(violations, message), nil
+}
+
+// Handler returns a handler passing records on to next, which may be nil
+// to discard them, and collecting their violations.
+func (v *Validator) Handler(next slog.Handler) slog.Handler {
+	if next == nil {
+		next = slog.DiscardHandler
+	}
+
+	return &handler{validator: v, next: next}
+}
+
+// Wrap returns next with its records checked, to pass to logger.NewSlog.
+func (v *Validator) Wrap(next logger.SlogHandler) logger.SlogHandler {
+	return func() *slog.Logger {
+		return slog.New(v.Handler(next().Handler()))
+	}
+}
+
+// Violations returns the violations collected by the handlers, once per field and reason.
+func (v *Validator) Violations() []Violation {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return slices.Clone(v.violations)
+}
+
+// AssertValid fails t with each violation collected by the handlers,
+// and reports whether there was none.
+func (v *Validator) AssertValid(t testing.TB) bool {
+	t.Helper()
+
+	violations := v.Violations()
+	for _, violation := range violations {
+		t.Error(violation.Error())
+	}
+
+	return len(violations) == 0
+}
+
+func (v *Validator) collect(violations []Violation) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, violation := range violations {
+		id := violation.Key + "\x00" + violation.Reason
+		if !v.seen[id] {
+			v.seen[id] = true
+			v.violations = append(v.violations, violation)
+		}
+	}
+}
+
+func (v *Validator) checkAttr(prefix string, attr slog.Attr) []Violation {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		var violations []Violation
+		if attr.Key != "" {
+			groupPrefix = joinKey(prefix, attr.Key)
+			violations = v.checkKey(prefix, attr.Key)
+		}
+		for _, member := range attr.Value.Group() {
+			violations = append(violations, v.checkAttr(groupPrefix, member)...)
+		}
+
+		return violations
+	}
+
+	violations := v.checkKey(prefix, attr.Key)
+	if kind, ok := v.cfg.reserved[joinKey(prefix, attr.Key)]; ok && attr.Value.Kind() != kind {
+		violations = append(violations, Violation{
+			Key:    joinKey(prefix, attr.Key),
+			Reason: fmt.Sprintf("reserved key holds %s, want %s", attr.Value.Kind(), kind),
+		})
+	}
+
+	return violations
+}
+
+func (v *Validator) checkJSON(prefix, key string, val any) []Violation {
+	violations := v.checkKey(prefix, key)
+	path := joinKey(prefix, key)
+
+	if group, ok := val.(map[string]any); ok {
+		for _, member := range slices.Sorted(maps.Keys(group)) {
+			violations = append(violations, v.checkJSON(path, member, group[member])...)
+		}
+
+		return violations
+	}
+
+	if kind, ok := v.cfg.reserved[path]; ok && !jsonKind(val, kind) {
+		violations = append(violations, Violation{
+			Key:    path,
+			Reason: fmt.Sprintf("reserved key holds %T, want %s", val, kind),
+		})
+	}
+
+	return violations
+}
+
+// checkKey checks the naming of key, in the group at prefix.
+func (v *Validator) checkKey(prefix, key string) []Violation {
+	path := joinKey(prefix, key)
+	switch {
+	case v.cfg.allowed[path] || v.cfg.allowed[key]:
+		return nil
+	case prefix == "" && slices.Contains(BuiltinKeys, key):
+		return []Violation{{Key: path, Reason: "shadows a key of the handler"}}
+	case !snakeCase.MatchString(key):
+		return []Violation{{Key: path, Reason: "not snake_case"}}
+	default:
+		return nil
+	}
+}
+
+// jsonKind reports whether a decoded JSON value is of kind, as the JSON handler writes it.
+func jsonKind(val any, kind slog.Kind) bool {
+	switch kind {
+	case slog.KindString, slog.KindTime:
+		_, ok := val.(string)
+
+		return ok
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindDuration:
+		_, ok := val.(json.Number)
+
+		return ok
+	case slog.KindBool:
+		_, ok := val.(bool)
+
+		return ok
+	default:
+		return true
+	}
+}
+
+func withMessage(violations []Violation, message string) []Violation {
+	for i := range violations {
+		violations[i].Message = message
+	}
+
+	return violations
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// handler collects the violations of the records it passes on.
+// The violations of the attributes added with WithAttrs are reported
+// with the records carrying them.
+type handler struct {
+	validator *Validator
+	next      slog.Handler
+	groups    []string
+	pending   []Violation
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	prefix := strings.Join(h.groups, ".")
+	var violations []Violation
+	record.Attrs(func(attr slog.Attr) bool {
+		violations = append(violations, h.validator.checkAttr(prefix, attr)...)
+
+		return true
+	})
+	h.validator.collect(withMessage(append(slices.Clone(h.pending), violations...), record.Message))
+
+	return h.next.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	prefix := strings.Join(h.groups, ".")
+	pending := slices.Clone(h.pending)
+	for _, attr := range attrs {
+		pending = append(pending, h.validator.checkAttr(prefix, attr)...)
+	}
+
+	return &handler{validator: h.validator, next: h.next.WithAttrs(attrs), groups: h.groups, pending: pending}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	pending := slices.Clone(h.pending)
+	pending = append(pending, h.validator.checkKey(strings.Join(h.groups, "."), name)...)
+
+	return &handler{
+		validator: h.validator,
+		next:      h.next.WithGroup(name),
+		groups:    append(slices.Clone(h.groups), name),
+		pending:   pending,
+	}
+}
@@ -0,0 +1,107 @@
+package logschema
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keys returns the "key: reason" of each violation.
+func keys(violations []Violation) []string {
+	var keys []string
+	for _, violation := range violations {
+		keys = append(keys, violation.Key+": "+violation.Reason)
+	}
+
+	return keys
+}
+
+func TestCheck(t *testing.T) {
+	schema := New(WithAllowed("userID"), WithReserved("latency_ms", slog.KindInt64))
+
+	record := slog.NewRecord(testTime, slog.LevelInfo, "order placed", 0)
+	record.AddAttrs(
+		slog.String("order_id", "o1"),
+		slog.String("userID", "u1"),
+		slog.String("orderTotal", "9.99"),
+		slog.String("msg", "shadowed"),
+		slog.Int("request_id", 42),
+		slog.Float64("latency_ms", 1.5),
+		slog.Group("http", slog.Int("status", 200), slog.String("Method", "GET"), slog.String("msg", "nested")),
+		slog.Group("", slog.String("inline-key", "x")),
+	)
+
+	violations := schema.Check(record)
+	assert.Equal(t, []string{
+		"orderTotal: not snake_case",
+		"msg: shadows a key of the handler",
+		"request_id: reserved key holds Int64, want String",
+		"latency_ms: reserved key holds Float64, want Int64",
+		"http.Method: not snake_case",
+		"inline-key: not snake_case",
+	}, keys(violations))
+	assert.Equal(t, "order placed", violations[0].Message)
+}
+
+func TestHandler(t *testing.T) {
+	schema := New()
+	var buf bytes.Buffer
+	log := logger.NewSlog(schema.Wrap(logger.WithJSONHandler(&buf, slog.LevelInfo)))
+
+	log.Info("clean", "order_id", "o1", "request_id", "r1")
+	assert.Empty(t, schema.Violations())
+
+	scoped := log.Logger().With("Module", "orders").WithGroup("db")
+	scoped.Info("first", "rowCount", 1)
+	scoped.Info("second", "rowCount", 2)
+
+	assert.Equal(t, []string{"Module: not snake_case", "db.rowCount: not snake_case"}, keys(schema.Violations()),
+		"each violation is collected once")
+	assert.Equal(t, "first", schema.Violations()[0].Message)
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"), "records are passed on")
+
+	tb := &failures{TB: t}
+	assert.False(t, schema.AssertValid(tb))
+	assert.Equal(t, 2, tb.errors)
+	assert.True(t, New().AssertValid(t))
+}
+
+func TestCheckJSON(t *testing.T) {
+	schema := New()
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	log.Info("served", "request_id", 7, slog.Group("http", "statusCode", 200))
+
+	violations, err := schema.CheckJSON(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"http.statusCode: not snake_case",
+		"request_id: reserved key holds json.Number, want String",
+	}, keys(violations))
+	assert.Equal(t, "served", violations[0].Message)
+
+	_, err = schema.CheckJSON([]byte("not json"))
+	require.Error(t, err)
+}
+
+// failures records the failures of the assertions under test.
+type failures struct {
+	testing.TB
+
+	errors int
+}
+
+func (*failures) Helper() {}
+
+func (f *failures) Error(...any) {
+	f.errors++
+}
+
+var testTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
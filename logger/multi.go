@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// WithMultiHandler returns a logger writing each record to all the handlers,
+// such as text on stdout and JSON to a file or a remote collector.
+// Each handler keeps its own format and level:
+//
+//	log := logger.NewSlog(logger.WithMultiHandler(
+//		logger.WithTextHandler(os.Stdout, slog.LevelDebug),
+//		logger.WithRotatingFileHandler("/var/log/app.log", 100, 10, 30, true),
+//	))
+//
+// The errors of the handlers are joined, after every handler had the record.
+// Attribute values such as Lazy are resolved once, then shared by the handlers.
+func WithMultiHandler(handlers ...SlogHandler) SlogHandler {
+	return func() *slog.Logger {
+		multi := make(multiHandler, 0, len(handlers))
+		for _, handler := range handlers {
+			multi = append(multi, handler().Handler())
+		}
+
+		return slog.New(multi)
+	}
+}
+
+// multiHandler fans records out to handlers.
+type multiHandler []slog.Handler
+
+func (h multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h multiHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	resolved := false
+	for _, handler := range h {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+		if !resolved {
+			record = resolveRecord(record)
+			resolved = true
+		}
+		if err := handler.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (h multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	attrs = resolveAttrs(attrs)
+	multi := make(multiHandler, 0, len(h))
+	for _, handler := range h {
+		multi = append(multi, handler.WithAttrs(attrs))
+	}
+
+	return multi
+}
+
+func (h multiHandler) WithGroup(name string) slog.Handler {
+	multi := make(multiHandler, 0, len(h))
+	for _, handler := range h {
+		multi = append(multi, handler.WithGroup(name))
+	}
+
+	return multi
+}
+
+// resolveRecord returns record with its attribute values resolved, so each slog.LogValuer,
+// such as Lazy, runs once instead of once per handler.
+func resolveRecord(record slog.Record) slog.Record {
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+
+		return true
+	})
+
+	resolved := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	resolved.AddAttrs(resolveAttrs(attrs)...)
+
+	return resolved
+}
+
+// resolveAttrs returns attrs with their values resolved, in groups too.
+func resolveAttrs(attrs []slog.Attr) []slog.Attr {
+	resolved := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+		if attr.Value.Kind() == slog.KindGroup {
+			attr.Value = slog.GroupValue(resolveAttrs(attr.Value.Group())...)
+		}
+		resolved[i] = attr
+	}
+
+	return resolved
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiHandler_FansOut(t *testing.T) {
+	var text, json bytes.Buffer
+	log := NewSlog(WithMultiHandler(
+		WithTextHandler(&text, slog.LevelDebug),
+		WithJSONHandler(&json, slog.LevelWarn),
+	)).With("module", "auth")
+
+	log.Debug("token refreshed")
+	log.Warn("token expired", "user_id", "42")
+
+	assert.Contains(t, text.String(), "msg=\"token refreshed\" module=auth")
+	assert.Contains(t, text.String(), "msg=\"token expired\" module=auth user_id=42")
+	assert.NotContains(t, json.String(), "token refreshed")
+	assert.Contains(t, json.String(), `"msg":"token expired","module":"auth","user_id":"42"`)
+}
+
+func TestMultiHandler_Groups(t *testing.T) {
+	var first, second bytes.Buffer
+	log := NewSlog(WithMultiHandler(
+		WithJSONHandler(&first, slog.LevelInfo),
+		WithJSONHandler(&second, slog.LevelInfo),
+	)).Logger().WithGroup("http")
+
+	log.Info("request", "status", 200)
+
+	assert.Contains(t, first.String(), `"http":{"status":200}`)
+	assert.Equal(t, first.String(), second.String())
+}
+
+type failingHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h failingHandler) Handle(context.Context, slog.Record) error {
+	return h.err
+}
+
+func TestMultiHandler_JoinsErrors(t *testing.T) {
+	errSink := errors.New("sink down")
+	var buf bytes.Buffer
+	handler := WithMultiHandler(
+		func() *slog.Logger { return slog.New(failingHandler{slog.NewTextHandler(&buf, nil), errSink}) },
+		WithTextHandler(&buf, slog.LevelInfo),
+	)().Handler()
+
+	err := handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "delivered", 0))
+	require.ErrorIs(t, err, errSink)
+	assert.Contains(t, buf.String(), "msg=delivered", "the other handlers still get the record")
+}
@@ -0,0 +1,291 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMaxSizeMB is the size of the files of a RotatingFile created with no size.
+	defaultMaxSizeMB = 100
+
+	// backupTimeFormat is the timestamp in the names of the backups,
+	// sortable and valid on every file system.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// RotatingFile is an io.WriteCloser writing to a file that is rotated when it grows
+// past a size. The rotated files, the backups, are named after the file with
+// the time of their rotation, such as app-2026-01-02T15-04-05.000.log, and are
+// removed once there are too many or they are too old.
+// It is safe for concurrent use.
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+	now        func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// mill runs the cleanup of the backups in the background, one at a time.
+	mill sync.Mutex
+	wg   sync.WaitGroup
+}
+
+// NewRotatingFile returns a RotatingFile writing to path, which is created with
+// its directory on the first write and appended to if it exists.
+// The file is rotated when a write would grow it past maxSizeMB megabytes, 100 if zero.
+// At most maxBackups backups are kept, and none older than maxAgeDays days;
+// zero keeps them all. If compress is true, the backups are compressed with gzip.
+func NewRotatingFile(path string, maxSizeMB, maxBackups, maxAgeDays int, compress bool) *RotatingFile {
+	if maxSizeMB <= 0 {
+		maxSizeMB = defaultMaxSizeMB
+	}
+
+	return &RotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+		maxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+		compress:   compress,
+		now:        time.Now,
+	}
+}
+
+// WithRotatingFileHandler returns a logger with JSON formatting at info level
+// writing to a RotatingFile, created as NewRotatingFile does.
+// Use a RotatingFile with WithJSONHandler or WithTextHandler for another format or level.
+func WithRotatingFileHandler(path string, maxSizeMB, maxBackups, maxAgeDays int, compress bool) SlogHandler {
+	return WithJSONHandler(NewRotatingFile(path, maxSizeMB, maxBackups, maxAgeDays, compress), slog.LevelInfo)
+}
+
+// Write writes data to the file, rotating it first if data doesn't fit.
+// Data larger than the maximum size is written to a file of its own.
+func (f *RotatingFile) Write(data []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.size > 0 && f.size+int64(len(data)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Rotate closes the file and moves it to a backup, so the next write starts a new one,
+// as log shippers signaling a rotation expect.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+
+	return f.rotate()
+}
+
+// Close closes the file and waits for the cleanup of the backups.
+// A later write opens the file again.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.wg.Wait()
+
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return fmt.Errorf("logger: rotating file: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("logger: rotating file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("logger: rotating file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("logger: rotating file: %w", err)
+	}
+	f.file = nil
+
+	if err := os.Rename(f.path, f.backupName(f.now())); err != nil {
+		return fmt.Errorf("logger: rotating file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		f.mill.Lock()
+		defer f.mill.Unlock()
+
+		f.cleanup()
+	}()
+
+	return nil
+}
+
+// backupName returns the name of the backup rotated at t: the file name
+// with the time inserted before its extension.
+func (f *RotatingFile) backupName(t time.Time) string {
+	prefix, ext := f.nameParts()
+
+	return prefix + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// nameParts splits the path around where backupName inserts the time.
+func (f *RotatingFile) nameParts() (prefix, ext string) {
+	ext = filepath.Ext(f.path)
+
+	return strings.TrimSuffix(f.path, ext) + "-", ext
+}
+
+type backup struct {
+	path    string
+	rotated time.Time
+}
+
+// cleanup removes the backups beyond the limits and compresses the others.
+// Errors are ignored: the next rotation tries again.
+func (f *RotatingFile) cleanup() {
+	backups := f.backups()
+
+	var keep []backup
+	for i, b := range backups {
+		tooMany := f.maxBackups > 0 && i >= f.maxBackups
+		tooOld := f.maxAge > 0 && f.now().Sub(b.rotated) > f.maxAge
+		if tooMany || tooOld {
+			_ = os.Remove(b.path)
+
+			continue
+		}
+		keep = append(keep, b)
+	}
+
+	if !f.compress {
+		return
+	}
+	for _, b := range keep {
+		if !strings.HasSuffix(b.path, ".gz") {
+			_ = compressFile(b.path)
+		}
+	}
+}
+
+// backups returns the backups of the file, newest first.
+func (f *RotatingFile) backups() []backup {
+	prefix, ext := f.nameParts()
+
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil
+	}
+
+	var backups []backup
+	for _, entry := range entries {
+		name := filepath.Join(filepath.Dir(f.path), entry.Name())
+		if !entry.Type().IsRegular() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		rotated, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: name, rotated: rotated})
+	}
+
+	slices.SortFunc(backups, func(a, b backup) int { return b.rotated.Compare(a.rotated) })
+
+	return backups
+}
+
+// compressFile replaces the file at path with a gzip-compressed path.gz.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(dst.Name())
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+
+		return err
+	}
+	if err := errors.Join(gz.Close(), dst.Close()); err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
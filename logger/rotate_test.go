@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRotatingFile returns a RotatingFile of 10 bytes with a clock advancing
+// a second per rotation.
+func newTestRotatingFile(t *testing.T, maxBackups, maxAgeDays int, compress bool) *RotatingFile {
+	t.Helper()
+
+	file := NewRotatingFile(filepath.Join(t.TempDir(), "logs", "app.log"), 1, maxBackups, maxAgeDays, compress)
+	file.maxSize = 10
+
+	clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	file.now = func() time.Time {
+		clock = clock.Add(time.Second)
+
+		return clock
+	}
+	t.Cleanup(func() { _ = file.Close() })
+
+	return file
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestRotatingFile_RotatesOnSize(t *testing.T) {
+	file := newTestRotatingFile(t, 0, 0, false)
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		_, err := file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, file.Close())
+
+	assert.Equal(t, "third\n", readFile(t, file.path))
+	backups := file.backups()
+	require.Len(t, backups, 2)
+	assert.Equal(t, "second\n", readFile(t, backups[0].path))
+	assert.Equal(t, "first\n", readFile(t, backups[1].path))
+	assert.Equal(t, "app-2026-01-02T15-04-06.000.log", filepath.Base(backups[1].path))
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	file := newTestRotatingFile(t, 0, 0, false)
+	require.NoError(t, os.MkdirAll(filepath.Dir(file.path), 0o755))
+	require.NoError(t, os.WriteFile(file.path, []byte("old\n"), 0o644))
+
+	_, err := file.Write([]byte("new\n"))
+	require.NoError(t, err)
+	_, err = file.Write([]byte("newer\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	assert.Equal(t, "newer\n", readFile(t, file.path))
+	require.Len(t, file.backups(), 1)
+	assert.Equal(t, "old\nnew\n", readFile(t, file.backups()[0].path))
+}
+
+func TestRotatingFile_LargeWrite(t *testing.T) {
+	file := newTestRotatingFile(t, 0, 0, false)
+
+	_, err := file.Write([]byte("a line longer than the maximum size\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	assert.Equal(t, "a line longer than the maximum size\n", readFile(t, file.path))
+	assert.Empty(t, file.backups())
+}
+
+func TestRotatingFile_MaxBackups(t *testing.T) {
+	file := newTestRotatingFile(t, 2, 0, false)
+
+	for range 5 {
+		require.NoError(t, file.Rotate())
+	}
+	require.NoError(t, file.Close())
+
+	backups := file.backups()
+	require.Len(t, backups, 2)
+	assert.Equal(t, "app-2026-01-02T15-04-10.000.log", filepath.Base(backups[0].path))
+}
+
+func TestRotatingFile_MaxAge(t *testing.T) {
+	file := newTestRotatingFile(t, 0, 1, false)
+
+	require.NoError(t, file.Rotate())
+	require.NoError(t, file.Close())
+	require.Len(t, file.backups(), 1)
+
+	now := file.now
+	file.now = func() time.Time { return now().Add(25 * time.Hour) }
+	require.NoError(t, file.Rotate())
+	require.NoError(t, file.Close())
+
+	backups := file.backups()
+	require.Len(t, backups, 1, "the day old backup is removed")
+	assert.Equal(t, "app-2026-01-03T16-04-08.000.log", filepath.Base(backups[0].path))
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	file := newTestRotatingFile(t, 0, 0, true)
+
+	_, err := file.Write([]byte("compressed\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Rotate())
+	require.NoError(t, file.Close())
+
+	backups := file.backups()
+	require.Len(t, backups, 1)
+	assert.Equal(t, "app-2026-01-02T15-04-06.000.log.gz", filepath.Base(backups[0].path))
+
+	gz, err := os.Open(backups[0].path)
+	require.NoError(t, err)
+	defer gz.Close()
+	reader, err := gzip.NewReader(gz)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "compressed\n", string(data))
+}
+
+func TestWithRotatingFileHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := NewSlog(WithRotatingFileHandler(path, 1, 3, 7, false))
+
+	log.Info("user logged in", "user_id", "123")
+	log.Debug("not logged")
+
+	output := readFile(t, path)
+	assert.Contains(t, output, `"msg":"user logged in"`)
+	assert.Contains(t, output, `"user_id":"123"`)
+	assert.NotContains(t, output, "not logged")
+}
@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RouteRule picks the destination name for a log record.
+type RouteRule func(record slog.Record) string
+
+// RouteByAttr returns a rule that routes records carrying the boolean attribute
+// key=true to matched, and everything else to otherwise.
+// Attributes attached with With are taken into account.
+func RouteByAttr(key, matched, otherwise string) RouteRule {
+	return func(record slog.Record) string {
+		dest := otherwise
+		record.Attrs(func(attr slog.Attr) bool {
+			if attr.Key == key && attr.Value.Equal(slog.BoolValue(true)) {
+				dest = matched
+
+				return false
+			}
+
+			return true
+		})
+
+		return dest
+	}
+}
+
+// WithRouting returns a logger that sends each record to one of the destinations,
+// chosen by the rule. Each destination keeps its own format and level.
+// Records routed to an unknown destination are dropped.
+func WithRouting(rule RouteRule, destinations map[string]SlogHandler) SlogHandler {
+	return func() *slog.Logger {
+		handlers := make(map[string]slog.Handler, len(destinations))
+		for name, dest := range destinations {
+			handlers[name] = dest().Handler()
+		}
+
+		return slog.New(&routingHandler{
+			rule:     rule,
+			handlers: handlers,
+		})
+	}
+}
+
+// routingHandler dispatches records to named handlers.
+// It remembers attributes added by WithAttrs so the rule can see them.
+type routingHandler struct {
+	rule     RouteRule
+	handlers map[string]slog.Handler
+	attrs    []slog.Attr
+}
+
+func (h *routingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *routingHandler) Handle(ctx context.Context, record slog.Record) error {
+	routed := record
+	if len(h.attrs) > 0 {
+		routed = record.Clone()
+		routed.AddAttrs(h.attrs...)
+	}
+
+	handler, ok := h.handlers[h.rule(routed)]
+	if !ok || !handler.Enabled(ctx, record.Level) {
+		return nil
+	}
+
+	return handler.Handle(ctx, record)
+}
+
+func (h *routingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(map[string]slog.Handler, len(h.handlers))
+	for name, handler := range h.handlers {
+		handlers[name] = handler.WithAttrs(attrs)
+	}
+
+	return &routingHandler{
+		rule:     h.rule,
+		handlers: handlers,
+		attrs:    append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...),
+	}
+}
+
+func (h *routingHandler) WithGroup(name string) slog.Handler {
+	handlers := make(map[string]slog.Handler, len(h.handlers))
+	for dest, handler := range h.handlers {
+		handlers[dest] = handler.WithGroup(name)
+	}
+
+	return &routingHandler{
+		rule:     h.rule,
+		handlers: handlers,
+		attrs:    h.attrs,
+	}
+}
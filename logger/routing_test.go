@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouting_AuditAndOps(t *testing.T) {
+	var audit, ops bytes.Buffer
+	log := NewSlog(WithRouting(RouteByAttr("audit", "audit", "ops"), map[string]SlogHandler{
+		"audit": WithJSONHandler(&audit, slog.LevelInfo),
+		"ops":   WithTextHandler(&ops, slog.LevelInfo),
+	}))
+
+	log.Info("user deleted", "audit", true, "user_id", "42")
+	log.Info("cache warmed")
+
+	assert.Contains(t, audit.String(), `"msg":"user deleted"`)
+	assert.NotContains(t, audit.String(), "cache warmed")
+	assert.Contains(t, ops.String(), "cache warmed")
+	assert.NotContains(t, ops.String(), "user deleted")
+}
+
+func TestRouting_RuleSeesWithAttrs(t *testing.T) {
+	var audit, ops bytes.Buffer
+	log := NewSlog(WithRouting(RouteByAttr("audit", "audit", "ops"), map[string]SlogHandler{
+		"audit": WithTextHandler(&audit, slog.LevelInfo),
+		"ops":   WithTextHandler(&ops, slog.LevelInfo),
+	})).With("audit", true)
+
+	log.Info("role granted")
+
+	assert.Contains(t, audit.String(), "role granted")
+	assert.Contains(t, audit.String(), "audit=true")
+	assert.Empty(t, ops.String())
+}
+
+func TestRouting_LevelPerDestination(t *testing.T) {
+	var audit, ops bytes.Buffer
+	log := NewSlog(WithRouting(RouteByAttr("audit", "audit", "ops"), map[string]SlogHandler{
+		"audit": WithTextHandler(&audit, slog.LevelDebug),
+		"ops":   WithTextHandler(&ops, slog.LevelWarn),
+	}))
+
+	log.Debug("audit debug", "audit", true)
+	log.Info("ops info")
+
+	assert.Contains(t, audit.String(), "audit debug")
+	assert.Empty(t, ops.String())
+}
+
+func TestRouting_UnknownDestinationDropped(t *testing.T) {
+	var ops bytes.Buffer
+	log := NewSlog(WithRouting(func(slog.Record) string { return "missing" }, map[string]SlogHandler{
+		"ops": WithTextHandler(&ops, slog.LevelInfo),
+	}))
+
+	assert.NotPanics(t, func() {
+		log.Info("lost")
+	})
+	assert.Empty(t, ops.String())
+}
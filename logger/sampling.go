@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// minSamplingKeys is the number of messages the sampler tracks before it forgets idle ones.
+const minSamplingKeys = 1024
+
+// WithSampling returns a logger that throttles each message to rate records a second,
+// after an initial burst, passing the records through to handler. Records are throttled
+// per level and message, so a warning repeated in a tight retry loop doesn't flood
+// the log sink while other messages keep flowing:
+//
+//	log := logger.NewSlog(logger.WithSampling(logger.WithJSONHandler(os.Stdout, slog.LevelInfo), 1, 10))
+//
+// The first record of a message let through after some were dropped carries
+// the number of dropped records as the "dropped" attribute.
+// A rate of zero lets burst records of each message through, then drops the others;
+// a burst less than 1 is 1.
+func WithSampling(handler SlogHandler, rate float64, burst int) SlogHandler {
+	return func() *slog.Logger {
+		return slog.New(&samplingHandler{
+			Handler: handler().Handler(),
+			sampler: newSampler(rate, burst, time.Now),
+		})
+	}
+}
+
+// samplingHandler drops the records its sampler refuses.
+// The handlers derived from it with WithAttrs and WithGroup share its sampler.
+type samplingHandler struct {
+	slog.Handler
+	sampler *sampler
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	allowed, dropped := h.sampler.allow(samplingKey{level: record.Level, msg: record.Message})
+	if !allowed {
+		return nil
+	}
+	if dropped > 0 {
+		record = record.Clone()
+		record.AddAttrs(slog.Int64("dropped", dropped))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), sampler: h.sampler}
+}
+
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+// bucket is the token bucket of a message.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	dropped int64
+}
+
+// sampler keeps a token bucket per message.
+type sampler struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[samplingKey]*bucket
+	maxKeys int
+}
+
+func newSampler(rate float64, burst int, now func() time.Time) *sampler {
+	return &sampler{
+		rate:    max(rate, 0),
+		burst:   float64(max(burst, 1)),
+		now:     now,
+		buckets: make(map[samplingKey]*bucket),
+		maxKeys: minSamplingKeys,
+	}
+}
+
+// allow reports whether a record of key is let through, with the number of records
+// of key dropped since the last one let through.
+func (s *sampler) allow(key samplingKey) (bool, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	b, ok := s.buckets[key]
+	if !ok {
+		s.forgetIdle(now)
+		b = &bucket{tokens: s.burst, updated: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = min(s.burst, b.tokens+now.Sub(b.updated).Seconds()*s.rate)
+	b.updated = now
+	if b.tokens < 1 {
+		b.dropped++
+
+		return false, 0
+	}
+
+	b.tokens--
+	dropped := b.dropped
+	b.dropped = 0
+
+	return true, dropped
+}
+
+// forgetIdle drops the buckets that are full again once the sampler tracks too many messages,
+// so one-off messages don't grow it forever. Forgetting them changes nothing:
+// a new bucket starts full.
+func (s *sampler) forgetIdle(now time.Time) {
+	if len(s.buckets) < s.maxKeys {
+		return
+	}
+
+	for key, b := range s.buckets {
+		if b.dropped == 0 && b.tokens+now.Sub(b.updated).Seconds()*s.rate >= s.burst {
+			delete(s.buckets, key)
+		}
+	}
+	s.maxKeys = max(minSamplingKeys, 2*len(s.buckets))
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampling_ThrottlesRepeatedMessages(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithSampling(WithTextHandler(&buf, slog.LevelInfo), 0, 3))
+
+	for range 10 {
+		log.Warn("retrying", "attempt", 1)
+	}
+	log.Info("retrying")
+	log.With("module", "db").Warn("connected")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, 3, strings.Count(buf.String(), "level=WARN msg=retrying"))
+	assert.Contains(t, lines[3], "level=INFO msg=retrying", "throttled per level")
+	assert.Contains(t, lines[4], "module=db")
+}
+
+func TestSampler_RefillsAtRate(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	s := newSampler(2, 2, func() time.Time { return clock })
+	key := samplingKey{level: slog.LevelWarn, msg: "retrying"}
+
+	allow := func() (bool, int64) {
+		return s.allow(key)
+	}
+
+	for range 2 {
+		allowed, _ := allow()
+		assert.True(t, allowed)
+	}
+	for range 3 {
+		allowed, _ := allow()
+		assert.False(t, allowed)
+	}
+
+	clock = clock.Add(500 * time.Millisecond)
+	allowed, dropped := allow()
+	assert.True(t, allowed)
+	assert.Equal(t, int64(3), dropped)
+	allowed, _ = allow()
+	assert.False(t, allowed)
+
+	clock = clock.Add(time.Hour)
+	for range 2 {
+		allowed, dropped = allow()
+		assert.True(t, allowed, "the bucket is capped to the burst")
+	}
+	assert.Zero(t, dropped)
+	allowed, _ = allow()
+	assert.False(t, allowed)
+}
+
+func TestSampling_DroppedAttr(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithSampling(WithJSONHandler(&buf, slog.LevelInfo), 100, 1))
+
+	log.Info("tick")
+	log.Info("tick")
+	time.Sleep(20 * time.Millisecond)
+	log.Info("tick")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.NotContains(t, lines[0], "dropped")
+	assert.Contains(t, lines[1], `"dropped":1`)
+}
+
+func TestSampler_ForgetsIdleMessages(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	s := newSampler(1, 1, func() time.Time { return clock })
+
+	s.allow(samplingKey{msg: "throttled"})
+	s.allow(samplingKey{msg: "throttled"})
+	for i := range minSamplingKeys - 1 {
+		s.allow(samplingKey{level: slog.Level(i), msg: "once"})
+	}
+	clock = clock.Add(time.Second)
+	s.allow(samplingKey{msg: "new"})
+
+	assert.Len(t, s.buckets, 2, "only the bucket with dropped records and the new one are kept")
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+type scopeKey struct{}
+
+// PushScope returns a copy of ctx carrying args, as key-value pairs or slog.Attr values,
+// on top of the scope already in ctx. Every record logged with the returned context,
+// through the Ctx methods of Slog or the Context methods of its slog.Logger, carries
+// the attributes of the scope, so deep call stacks log consistent fields without
+// passing loggers around:
+//
+//	ctx = logger.PushScope(ctx, "request_id", id)
+//	ctx = logger.PushScope(ctx, "order_id", order.ID)
+//	log.InfoCtx(ctx, "order placed") // request_id=... order_id=...
+//
+// An attribute replaces the one of the same key pushed earlier.
+func PushScope(ctx context.Context, args ...any) context.Context {
+	attrs := slog.Group("", args...).Value.Group()
+	if len(attrs) == 0 {
+		return ctx
+	}
+
+	scope := slices.DeleteFunc(Scope(ctx), func(old slog.Attr) bool {
+		return slices.ContainsFunc(attrs, func(attr slog.Attr) bool { return attr.Key == old.Key })
+	})
+
+	return context.WithValue(ctx, scopeKey{}, append(scope, attrs...))
+}
+
+// Scope returns the attributes pushed to ctx with PushScope, outermost first.
+func Scope(ctx context.Context) []slog.Attr {
+	scope, _ := ctx.Value(scopeKey{}).([]slog.Attr)
+
+	return slices.Clone(scope)
+}
+
+// scopeHandler adds the trace context, the request ID and the scope of the context
+// to the records it handles. The scope wins over the attributes of the same key.
+type scopeHandler struct {
+	slog.Handler
+}
+
+func (h scopeHandler) Handle(ctx context.Context, record slog.Record) error {
+	scope, _ := ctx.Value(scopeKey{}).([]slog.Attr)
+	attrs := slices.DeleteFunc(contextAttrs(ctx), func(attr slog.Attr) bool {
+		return slices.ContainsFunc(scope, func(scoped slog.Attr) bool { return scoped.Key == attr.Key })
+	})
+	if attrs = append(attrs, scope...); len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(attrs...)
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h scopeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return scopeHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h scopeHandler) WithGroup(name string) slog.Handler {
+	return scopeHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushScope(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithTextHandler(&buf, slog.LevelInfo))
+
+	ctx := PushScope(t.Context(), "request_id", "r1")
+	inner := PushScope(ctx, "order_id", 42, slog.String("request_id", "r2"))
+
+	log.InfoCtx(ctx, "outer")
+	log.With("module", "orders").InfoCtx(inner, "inner")
+	log.Info("no scope")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+	assert.Contains(t, string(lines[0]), "msg=outer request_id=r1")
+	assert.Contains(t, string(lines[1]), "msg=inner module=orders order_id=42 request_id=r2")
+	assert.NotContains(t, string(lines[2]), "request_id")
+}
+
+func TestScope(t *testing.T) {
+	assert.Empty(t, Scope(t.Context()))
+
+	ctx := PushScope(t.Context(), "a", 1, "b", 2)
+	assert.Equal(t, ctx, PushScope(ctx), "expected an empty push to keep the context")
+
+	ctx = PushScope(ctx, "a", 3)
+	scope := Scope(ctx)
+	assert.Equal(t, []slog.Attr{slog.Int("b", 2), slog.Int("a", 3)}, scope)
+
+	scope[0] = slog.Int("b", 4)
+	assert.Equal(t, slog.Int("b", 2), Scope(ctx)[0], "expected Scope to return a copy")
+}
+
+func TestScopeWithSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithJSONHandler(&buf, slog.LevelInfo)).Logger()
+
+	log.WarnContext(PushScope(t.Context(), "user_id", "7"), "slow query")
+
+	assert.Contains(t, buf.String(), `"user_id":"7"`)
+}
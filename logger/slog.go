@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"os"
@@ -15,13 +16,15 @@ type SlogHandler func() *slog.Logger
 var DefaultSlog = NewSlog(nil)
 
 // NewSlog creates a new Slog logger using functional options.
+// Records logged with a context carry the attributes pushed to it with PushScope,
+// and its trace context and request ID, set with WithTrace and WithRequestID.
 func NewSlog(handler SlogHandler) *Slog {
 	if handler == nil {
 		handler = WithTextHandler(os.Stdout, slog.LevelInfo)
 	}
 
 	return &Slog{
-		log: handler(),
+		log: slog.New(scopeHandler{handler().Handler()}),
 	}
 }
 
@@ -69,6 +72,32 @@ func (s *Slog) Error(msg string, args ...any) {
 	s.log.Error(msg, args...)
 }
 
+// DebugCtx logs at debug level with the scope of ctx.
+func (s *Slog) DebugCtx(ctx context.Context, msg string, args ...any) {
+	s.log.DebugContext(ctx, msg, args...)
+}
+
+// InfoCtx logs at info level with the scope of ctx.
+func (s *Slog) InfoCtx(ctx context.Context, msg string, args ...any) {
+	s.log.InfoContext(ctx, msg, args...)
+}
+
+// WarnCtx logs at warning level with the scope of ctx.
+func (s *Slog) WarnCtx(ctx context.Context, msg string, args ...any) {
+	s.log.WarnContext(ctx, msg, args...)
+}
+
+// ErrorCtx logs at error level with the scope of ctx.
+func (s *Slog) ErrorCtx(ctx context.Context, msg string, args ...any) {
+	s.log.ErrorContext(ctx, msg, args...)
+}
+
+// Logger returns the underlying slog.Logger, whose Context methods
+// also log the scope of their context.
+func (s *Slog) Logger() *slog.Logger {
+	return s.log
+}
+
 func (s *Slog) Fatal(msg string, args ...any) {
 	s.log.Error(msg, args...)
 	//nolint:revive // exit on fatal log
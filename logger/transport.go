@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Headers that carry the request ID and W3C trace context across services.
+const (
+	HeaderRequestID   = "X-Request-Id"
+	HeaderTraceParent = "Traceparent"
+	HeaderTraceState  = "Tracestate"
+)
+
+// redactedHeaders are never logged in clear, even when requested with WithTransportHeaders.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+type transportOptions struct {
+	logger  Logger
+	headers []string
+}
+
+// TransportOption configures HTTPTransport.
+type TransportOption func(*transportOptions)
+
+// WithTransportLogger sets the logger used by HTTPTransport. Defaults to the global logger.
+func WithTransportLogger(logger Logger) TransportOption {
+	return func(opts *transportOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithTransportHeaders logs the given request headers as well.
+// Credentials such as Authorization and Cookie are redacted.
+func WithTransportHeaders(names ...string) TransportOption {
+	return func(opts *transportOptions) {
+		opts.headers = append(opts.headers, names...)
+	}
+}
+
+type loggingTransport struct {
+	base http.RoundTripper
+	opts transportOptions
+}
+
+// HTTPTransport wraps base (http.DefaultTransport if nil) to log every outbound request
+// at debug level: method, host, path, status, duration and the request ID and
+// W3C trace context headers it propagates. The query string is never logged.
+// The request ID and trace context of the request context, set with WithRequestID
+// and WithTrace, are sent in these headers unless the request already has them.
+func HTTPTransport(base http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	cfg := transportOptions{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &loggingTransport{base: base, opts: cfg}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = propagate(req)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	args := []any{
+		"method", req.Method,
+		"host", req.URL.Host,
+		"path", req.URL.Path,
+		"duration", time.Since(start),
+	}
+	if requestID := req.Header.Get(HeaderRequestID); requestID != "" {
+		args = append(args, "request_id", requestID)
+	}
+	if traceID, spanID, ok := ParseTraceParent(req.Header.Get(HeaderTraceParent)); ok {
+		args = append(args, "trace_id", traceID, "span_id", spanID)
+	}
+	if traceState := req.Header.Get(HeaderTraceState); traceState != "" {
+		args = append(args, "trace_state", traceState)
+	}
+	for _, name := range t.opts.headers {
+		if value := req.Header.Get(name); value != "" {
+			args = append(args, "header."+strings.ToLower(name), redactHeader(name, value))
+		}
+	}
+
+	if err != nil {
+		args = append(args, "error", err)
+		t.debug("outbound request failed", args...)
+
+		return resp, err
+	}
+
+	args = append(args, "status", resp.StatusCode)
+	t.debug("outbound request", args...)
+
+	return resp, nil
+}
+
+func (t *loggingTransport) debug(msg string, args ...any) {
+	if t.opts.logger == nil {
+		Debug(msg, args...)
+
+		return
+	}
+
+	t.opts.logger.Debug(msg, args...)
+}
+
+// propagate returns req with the request ID and trace context headers of its context,
+// cloning it rather than modifying the request of the caller.
+func propagate(req *http.Request) *http.Request {
+	requestID := RequestIDFromContext(req.Context())
+	traceID, spanID, hasTrace := TraceFromContext(req.Context())
+	setRequestID := requestID != "" && req.Header.Get(HeaderRequestID) == ""
+	setTrace := hasTrace && req.Header.Get(HeaderTraceParent) == ""
+	if !setRequestID && !setTrace {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+	if setRequestID {
+		req.Header.Set(HeaderRequestID, requestID)
+	}
+	if setTrace {
+		req.Header.Set(HeaderTraceParent, "00-"+traceID+"-"+spanID+"-01")
+	}
+
+	return req
+}
+
+func redactHeader(name, value string) string {
+	if redactedHeaders[http.CanonicalHeaderKey(name)] {
+		return "[REDACTED]"
+	}
+
+	return value
+}
+
+// ParseTraceParent extracts the trace ID and the parent span ID from a W3C traceparent
+// header value ("version-traceid-parentid-flags"). It reports false for malformed values
+// and for the all-zero IDs the specification declares invalid.
+func ParseTraceParent(value string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(value, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return "", "", false
+	}
+
+	if !isLowerHex(parts[1]) || !isLowerHex(parts[2]) ||
+		strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return "", "", false
+	}
+
+	return parts[1], parts[2], true
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	log := NewSlog(WithTextHandler(&buf, slog.LevelDebug))
+	client := &http.Client{Transport: HTTPTransport(nil,
+		WithTransportLogger(log), WithTransportHeaders("Authorization", "X-Tenant"))}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost,
+		server.URL+"/v1/orders?api_key=secret", http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set(HeaderRequestID, "req-1")
+	req.Header.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Tenant", "acme")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	output := buf.String()
+	assert.Contains(t, output, "level=DEBUG")
+	assert.Contains(t, output, `msg="outbound request"`)
+	assert.Contains(t, output, "method=POST")
+	assert.Contains(t, output, "path=/v1/orders")
+	assert.Contains(t, output, "status=202")
+	assert.Contains(t, output, "request_id=req-1")
+	assert.Contains(t, output, "trace_id=4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Contains(t, output, "span_id=00f067aa0ba902b7")
+	assert.Contains(t, output, "header.authorization=[REDACTED]")
+	assert.Contains(t, output, "header.x-tenant=acme")
+	assert.NotContains(t, output, "secret")
+	assert.NotContains(t, output, "Bearer")
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestHTTPTransport_Error(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewSlog(WithTextHandler(&buf, slog.LevelDebug))
+	transport := HTTPTransport(failingTransport{}, WithTransportLogger(log))
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://node.local/status", http.NoBody)
+	require.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	require.Error(t, err)
+	assert.Contains(t, buf.String(), `msg="outbound request failed"`)
+	assert.Contains(t, buf.String(), `error="connection refused"`)
+}
+
+func TestParseTraceParent(t *testing.T) {
+	traceID, spanID, ok := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Equal(t, "00f067aa0ba902b7", spanID)
+
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+	} {
+		_, _, ok := ParseTraceParent(value)
+		assert.False(t, ok, value)
+	}
+}
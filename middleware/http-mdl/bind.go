@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Media types supported by Bind and Render.
+const (
+	MIMEJSON          = "application/json"
+	MIMEMsgpack       = "application/msgpack"
+	MIMEForm          = "application/x-www-form-urlencoded"
+	MIMEMultipartForm = "multipart/form-data"
+)
+
+// DefaultMaxBodySize is the request body limit used by Bind unless WithMaxBodySize is given.
+const DefaultMaxBodySize = 1 << 20
+
+var (
+	// ErrUnsupportedMediaType is returned for a content type Bind or Render can't handle.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+	// ErrBodyTooLarge is returned when the request body exceeds the size limit.
+	ErrBodyTooLarge = errors.New("request body too large")
+
+	// ErrEmptyBody is returned when a JSON or msgpack request has no body.
+	ErrEmptyBody = errors.New("empty request body")
+)
+
+// BindError describes why a request body could not be decoded.
+// WriteError replies to it with Status and the error message.
+type BindError struct {
+	// Status is the HTTP status to reply with: 400, 413 or 415.
+	Status int
+
+	// ContentType is the media type of the request.
+	ContentType string
+
+	// Field is the field that failed to decode, when known.
+	Field string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *BindError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("bind %s: field %q: %v", e.ContentType, e.Field, e.Err)
+	}
+
+	return fmt.Sprintf("bind %s: %v", e.ContentType, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// BindOption configures Bind.
+type BindOption func(*bindOptions)
+
+type bindOptions struct {
+	maxBodySize int64
+}
+
+// WithMaxBodySize limits the request body to n bytes.
+func WithMaxBodySize(n int64) BindOption {
+	return func(opts *bindOptions) {
+		opts.maxBodySize = n
+	}
+}
+
+// Bind decodes the request body into dst according to the Content-Type header:
+// JSON (the default when no header is set), msgpack, or URL-encoded and multipart forms.
+//
+// JSON and msgpack use the `json` struct tags, so one struct serves both.
+// Forms use the `form` tag, falling back to the `json` tag; dst may also be a *url.Values.
+// Every failure is a *BindError carrying the HTTP status to reply with.
+func Bind(r *http.Request, dst any, opts ...BindOption) error {
+	cfg := bindOptions{maxBodySize: DefaultMaxBodySize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	mediaType := MIMEJSON
+	if header := r.Header.Get("Content-Type"); header != "" {
+		parsed, _, err := mime.ParseMediaType(header)
+		if err != nil {
+			return &BindError{Status: http.StatusUnsupportedMediaType, ContentType: header, Err: err}
+		}
+		mediaType = parsed
+	}
+
+	r.Body = http.MaxBytesReader(nil, r.Body, cfg.maxBodySize)
+
+	var err error
+	switch mediaType {
+	case MIMEJSON:
+		err = bindJSON(r.Body, dst)
+	case MIMEMsgpack, "application/x-msgpack", "application/vnd.msgpack":
+		err = bindMsgpack(r.Body, dst)
+	case MIMEForm, MIMEMultipartForm:
+		err = bindForm(r, mediaType, dst, cfg.maxBodySize)
+	default:
+		err = &BindError{Status: http.StatusUnsupportedMediaType, Err: ErrUnsupportedMediaType}
+	}
+
+	return bindError(mediaType, err)
+}
+
+func bindJSON(body io.Reader, dst any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return ErrEmptyBody
+	}
+
+	if err := json.Unmarshal(data, dst); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return &BindError{Field: typeErr.Field, Err: err}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func bindMsgpack(body io.Reader, dst any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return ErrEmptyBody
+	}
+
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(dst)
+}
+
+func bindForm(r *http.Request, mediaType string, dst any, maxBodySize int64) error {
+	var err error
+	if mediaType == MIMEMultipartForm {
+		err = r.ParseMultipartForm(maxBodySize)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		return err
+	}
+
+	if values, ok := dst.(*url.Values); ok {
+		*values = r.PostForm
+
+		return nil
+	}
+
+	return decodeForm(r.PostForm, dst)
+}
+
+// bindError wraps err in a *BindError, filling in the status and content type.
+func bindError(mediaType string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		bindErr = &BindError{Err: err}
+	}
+	bindErr.ContentType = mediaType
+
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case bindErr.Status != 0:
+	case errors.As(err, &maxBytesErr), errors.Is(err, multipart.ErrMessageTooLarge):
+		bindErr.Status = http.StatusRequestEntityTooLarge
+		bindErr.Err = fmt.Errorf("%w: %w", ErrBodyTooLarge, bindErr.Err)
+	default:
+		bindErr.Status = http.StatusBadRequest
+	}
+
+	return bindErr
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type bindPayload struct {
+	Name    string        `json:"name"`
+	Count   int           `json:"count"`
+	Tags    []string      `json:"tags"`
+	Timeout time.Duration `form:"timeout" json:"timeout"`
+	Since   *time.Time    `json:"since"`
+	Ignored string        `json:"-"`
+}
+
+func newBindRequest(contentType, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "http://test.com", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return req
+}
+
+func TestBindJSON(t *testing.T) {
+	for _, contentType := range []string{"", MIMEJSON, "application/json; charset=utf-8"} {
+		var dst bindPayload
+		err := Bind(newBindRequest(contentType, `{"name":"ezex","count":2,"tags":["a","b"]}`), &dst)
+
+		require.NoError(t, err)
+		assert.Equal(t, bindPayload{Name: "ezex", Count: 2, Tags: []string{"a", "b"}}, dst)
+	}
+}
+
+func TestBindMsgpack(t *testing.T) {
+	enc := msgpack.GetEncoder()
+	defer msgpack.PutEncoder(enc)
+
+	var buf bytes.Buffer
+	enc.Reset(&buf)
+	enc.SetCustomStructTag("json")
+	require.NoError(t, enc.Encode(map[string]any{"name": "ezex", "count": 3}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://test.com", &buf)
+	req.Header.Set("Content-Type", MIMEMsgpack)
+
+	var dst bindPayload
+	require.NoError(t, Bind(req, &dst))
+	assert.Equal(t, "ezex", dst.Name)
+	assert.Equal(t, 3, dst.Count)
+}
+
+func TestBindForm(t *testing.T) {
+	form := url.Values{
+		"name":    {"ezex"},
+		"count":   {"4"},
+		"tags":    {"a", "b"},
+		"timeout": {"1m30s"},
+		"since":   {"2026-01-02T03:04:05Z"},
+		"Ignored": {"x"},
+	}
+
+	var dst bindPayload
+	require.NoError(t, Bind(newBindRequest(MIMEForm, form.Encode()), &dst))
+
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, bindPayload{
+		Name:    "ezex",
+		Count:   4,
+		Tags:    []string{"a", "b"},
+		Timeout: 90 * time.Second,
+		Since:   &since,
+	}, dst)
+}
+
+func TestBindMultipartForm(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("name", "ezex"))
+	require.NoError(t, writer.WriteField("count", "5"))
+	require.NoError(t, writer.Close())
+
+	var dst bindPayload
+	require.NoError(t, Bind(newBindRequest(writer.FormDataContentType(), body.String()), &dst))
+	assert.Equal(t, "ezex", dst.Name)
+	assert.Equal(t, 5, dst.Count)
+}
+
+func TestBindFormValues(t *testing.T) {
+	var dst url.Values
+	require.NoError(t, Bind(newBindRequest(MIMEForm, "a=1&a=2"), &dst))
+	assert.Equal(t, []string{"1", "2"}, dst["a"])
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []BindOption
+		status      int
+		field       string
+		target      error
+	}{
+		{"unsupported", "text/plain", "hi", nil, http.StatusUnsupportedMediaType, "", ErrUnsupportedMediaType},
+		{"empty", MIMEJSON, " ", nil, http.StatusBadRequest, "", ErrEmptyBody},
+		{"syntax", MIMEJSON, "{", nil, http.StatusBadRequest, "", nil},
+		{"json type", MIMEJSON, `{"count":"x"}`, nil, http.StatusBadRequest, "count", nil},
+		{"form type", MIMEForm, "count=x", nil, http.StatusBadRequest, "count", nil},
+		{
+			"too large", MIMEJSON, `{"name":"ezex"}`,
+			[]BindOption{WithMaxBodySize(4)},
+			http.StatusRequestEntityTooLarge, "", ErrBodyTooLarge,
+		},
+		{
+			"form too large", MIMEForm, "name=ezex",
+			[]BindOption{WithMaxBodySize(4)},
+			http.StatusRequestEntityTooLarge, "", ErrBodyTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst bindPayload
+			err := Bind(newBindRequest(tt.contentType, tt.body), &dst, tt.opts...)
+
+			var bindErr *BindError
+			require.ErrorAs(t, err, &bindErr)
+			assert.Equal(t, tt.status, bindErr.Status)
+			assert.Equal(t, tt.field, bindErr.Field)
+			if tt.target != nil {
+				assert.ErrorIs(t, err, tt.target)
+			}
+		})
+	}
+}
+
+func TestWriteErrorBindError(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://test.com", http.NoBody)
+	w := httptest.NewRecorder()
+
+	WriteError(w, req, &BindError{
+		Status:      http.StatusBadRequest,
+		ContentType: MIMEJSON,
+		Field:       "count",
+		Err:         errors.New("not a number"),
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "bind application/json: field \"count\": not a number\n", w.Body.String())
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+// WriteError replies to the request with the status code matching err.
+// Errors are classified against the request context with errors.FromContext,
+// so a client that gave up gets 499 and a server-side timeout gets 504.
+// A *BindError is replied to with its own status and message.
+//
+// An *errors.Error is replied to with a body rendered like Render does, in the
+// format negotiated with Negotiate, holding its code, message and documentation URL:
+//
+//	{"code": "not_found", "message": "user not found", "docs_url": "https://docs.ezex.io/errors/not_found"}
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var bindErr *BindError
+	if errors.As(err, &bindErr) {
+		http.Error(w, bindErr.Error(), bindErr.Status)
+
+		return
+	}
+
+	status := errors.HTTPStatus(errors.FromContext(err, r.Context().Err()))
+
+	var coded *errors.Error
+	if errors.As(err, &coded) {
+		w.Header().Set("Content-Type", Negotiate(r))
+		_ = Render(w, status, errorBody{
+			Code:    coded.Code,
+			Message: coded.Message,
+			DocsURL: coded.DocsURL(),
+		})
+
+		return
+	}
+
+	text := http.StatusText(status)
+	if status == errors.StatusClientClosedRequest {
+		text = "Client Closed Request"
+	}
+
+	http.Error(w, text, status)
+}
+
+// errorBody is the response body of an *errors.Error.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+	DocsURL string `json:"docs_url,omitempty"`
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ezerrors "github.com/ezex-io/gopkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteError(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		status int
+		body   string
+	}{
+		{"generic error", t.Context(), errors.New("boom"), http.StatusInternalServerError, "Internal Server Error\n"},
+		{"client gave up", canceledCtx, errors.New("query aborted"), 499, "Client Closed Request\n"},
+		{"upstream timeout", t.Context(), context.DeadlineExceeded, http.StatusGatewayTimeout, "Gateway Timeout\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequestWithContext(tt.ctx, http.MethodGet, "http://test.com", http.NoBody)
+			w := httptest.NewRecorder()
+
+			WriteError(w, req, tt.err)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestWriteErrorCoded(t *testing.T) {
+	ezerrors.SetDocsBase("https://docs.ezex.io/errors/{code}")
+	t.Cleanup(func() { ezerrors.SetDocsBase("") })
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "http://test.com", http.NoBody)
+	w := httptest.NewRecorder()
+
+	err := ezerrors.Wrap(errors.New("no rows"), "not_found", "user not found")
+	WriteError(w, req, fmt.Errorf("loading user: %w", err))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, MIMEJSON+"; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"code":"not_found","message":"user not found",`+
+		`"docs_url":"https://docs.ezex.io/errors/not_found"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	WriteError(w, req, err.WithDocs("https://example.com/users"))
+	assert.JSONEq(t, `{"code":"not_found","message":"user not found",`+
+		`"docs_url":"https://example.com/users"}`, w.Body.String())
+}
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	durationType        = reflect.TypeFor[time.Duration]()
+)
+
+// formFields returns the settable fields of the struct val keyed by form name.
+// Untagged embedded structs are flattened.
+func formFields(val reflect.Value, fields map[string]reflect.Value) {
+	typ := val.Type()
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, tagged := formName(field)
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			formFields(val.Field(i), fields)
+
+			continue
+		}
+
+		fields[name] = val.Field(i)
+	}
+}
+
+// formName returns the `form` tag, falling back to the `json` tag and then the field name.
+func formName(field reflect.StructField) (string, bool) {
+	for _, key := range []string{"form", "json"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name != "" {
+				return name, true
+			}
+		}
+	}
+
+	return field.Name, false
+}
+
+// decodeForm sets the fields of the struct pointed to by dst from values.
+func decodeForm(values url.Values, dst any) error {
+	ptr := reflect.ValueOf(dst)
+	if ptr.Kind() != reflect.Pointer || ptr.IsNil() || ptr.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("form destination must be a non-nil struct pointer, got %T", dst)
+	}
+
+	fields := make(map[string]reflect.Value)
+	formFields(ptr.Elem(), fields)
+
+	for name, field := range fields {
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+
+		if err := setFormField(field, vals); err != nil {
+			return &BindError{Field: name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func setFormField(field reflect.Value, vals []string) error {
+	if field.Kind() == reflect.Slice && !field.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(field.Type(), len(vals), len(vals))
+		for i, val := range vals {
+			if err := setFormValue(slice.Index(i), val); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+
+		return nil
+	}
+
+	return setFormValue(field, vals[0])
+}
+
+func setFormValue(field reflect.Value, val string) error {
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := setFormValue(elem.Elem(), val); err != nil {
+			return err
+		}
+		field.Set(elem)
+
+		return nil
+	}
+
+	if field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	}
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported form field type %s", field.Type())
+	}
+
+	return nil
+}
+
+// encodeForm encodes v, a url.Values, a map[string][]string, a map[string]string
+// or a struct (or pointer to one), as form values.
+func encodeForm(v any) (url.Values, error) {
+	switch v := v.(type) {
+	case url.Values:
+		return v, nil
+	case map[string][]string:
+		return v, nil
+	case map[string]string:
+		values := make(url.Values, len(v))
+		for key, val := range v {
+			values.Set(key, val)
+		}
+
+		return values, nil
+	}
+
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: can't encode %T as a form", ErrUnsupportedMediaType, v)
+	}
+
+	// Copy the struct so its fields are addressable for TextMarshaler lookups.
+	addressable := reflect.New(val.Type()).Elem()
+	addressable.Set(val)
+
+	fields := make(map[string]reflect.Value)
+	formFields(addressable, fields)
+
+	values := make(url.Values, len(fields))
+	for name, field := range fields {
+		vals, err := formFieldStrings(field)
+		if err != nil {
+			return nil, fmt.Errorf("form field %q: %w", name, err)
+		}
+		if len(vals) > 0 {
+			values[name] = vals
+		}
+	}
+
+	return values, nil
+}
+
+func formFieldStrings(field reflect.Value) ([]string, error) {
+	if field.Kind() == reflect.Slice && !field.Type().Implements(textMarshalerType) {
+		vals := make([]string, 0, field.Len())
+		for i := range field.Len() {
+			val, ok, err := formString(field.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				vals = append(vals, val)
+			}
+		}
+
+		return vals, nil
+	}
+
+	val, ok, err := formString(field)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return []string{val}, nil
+}
+
+// formString formats a single value; ok is false for nil pointers.
+func formString(field reflect.Value) (string, bool, error) {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return "", false, nil
+		}
+
+		return formString(field.Elem())
+	}
+
+	if marshaler, ok := field.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+
+		return string(text), err == nil, err
+	}
+	if field.CanAddr() {
+		if marshaler, ok := field.Addr().Interface().(encoding.TextMarshaler); ok {
+			text, err := marshaler.MarshalText()
+
+			return string(text), err == nil, err
+		}
+	}
+
+	if field.Type() == durationType {
+		return time.Duration(field.Int()).String(), true, nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		return field.String(), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(field.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(field.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(field.Float(), 'g', -1, field.Type().Bits()), true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported form field type %s", field.Type())
+	}
+}
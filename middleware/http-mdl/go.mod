@@ -2,10 +2,15 @@ module github.com/ezex-io/gopkg/middleware/http-mdl
 
 go 1.25.1
 
-require github.com/stretchr/testify v1.11.1
+require (
+	github.com/ezex-io/gopkg/errors v0.0.0-20261016203821-268b83c0bbf8
+	github.com/stretchr/testify v1.11.1
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Priority is how important a request is to keep serving under load.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	// PriorityCritical requests, such as health checks, are never shed.
+	PriorityCritical
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// shedThresholds is the load, as a fraction of the limits, from which requests
+// of each priority are shed: low ones first, so the others keep being served.
+var shedThresholds = [...]float64{
+	PriorityLow:      0.7,
+	PriorityNormal:   0.85,
+	PriorityHigh:     1,
+	PriorityCritical: math.Inf(1),
+}
+
+const (
+	// latencyWeight is the weight of each request in the latency average.
+	latencyWeight = 0.1
+	// latencyHalfLife is how fast the latency average decays without requests,
+	// so shedding stops once the requests that raised it are gone.
+	latencyHalfLife = time.Second
+)
+
+type LoadShedConfig struct {
+	// MaxInFlight is the number of requests served at once the service can take,
+	// or zero for no limit.
+	MaxInFlight int
+	// TargetLatency is the average latency the service can take, or zero for no limit.
+	TargetLatency time.Duration
+	// PriorityHeader names the request header holding the priority:
+	// "low", "normal", "high" or "critical". Requests without it are normal.
+	PriorityHeader string
+	// Classify, if set, returns the priority of a request instead of the header,
+	// such as by route.
+	Classify func(r *http.Request) Priority
+	// OnShed, if set, is called for each request shed, for exporting metrics.
+	// It must be fast.
+	OnShed func(r *http.Request, priority Priority)
+}
+
+// DefaultLoadShedConfig returns a default load shedding configuration.
+func DefaultLoadShedConfig() LoadShedConfig {
+	return LoadShedConfig{
+		MaxInFlight:    1000,
+		TargetLatency:  time.Second,
+		PriorityHeader: "X-Priority",
+	}
+}
+
+// LoadShed creates middleware that sheds requests with 503 Service Unavailable
+// before the service tips over. It tracks the requests in flight and the average
+// latency, and as their load nears the limits of config, rejects low priority requests
+// first, then normal ones, then high ones at the limits. Critical requests are always served.
+//
+// The load is the highest of the requests in flight and the average latency,
+// relative to their limits. Low priority requests are shed from 70% of the load,
+// and normal ones from 85%.
+func LoadShed(config *LoadShedConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		shedder := &loadShedder{config: config}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			priority := shedder.priority(r)
+			if shedder.load() >= shedThresholds[priority] {
+				if config.OnShed != nil {
+					config.OnShed(r, priority)
+				}
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+
+				return
+			}
+
+			shedder.inFlight.Add(1)
+			start := time.Now()
+			defer func() {
+				shedder.inFlight.Add(-1)
+				shedder.observe(time.Since(start))
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// loadShedder tracks the load of the requests passing through LoadShed.
+type loadShedder struct {
+	config   *LoadShedConfig
+	inFlight atomic.Int64
+
+	mu      sync.Mutex
+	latency float64 // average, in nanoseconds
+	updated time.Time
+}
+
+func (s *loadShedder) priority(r *http.Request) Priority {
+	if s.config.Classify != nil {
+		return min(max(s.config.Classify(r), PriorityLow), PriorityCritical)
+	}
+	if s.config.PriorityHeader == "" {
+		return PriorityNormal
+	}
+
+	switch strings.ToLower(r.Header.Get(s.config.PriorityHeader)) {
+	case "low":
+		return PriorityLow
+	case "high":
+		return PriorityHigh
+	case "critical":
+		return PriorityCritical
+	default:
+		return PriorityNormal
+	}
+}
+
+// load returns the load of the service, as a fraction of its limits.
+func (s *loadShedder) load() float64 {
+	var load float64
+	if s.config.MaxInFlight > 0 {
+		load = float64(s.inFlight.Load()) / float64(s.config.MaxInFlight)
+	}
+	if s.config.TargetLatency > 0 {
+		s.mu.Lock()
+		latency := s.decayed(time.Now())
+		s.mu.Unlock()
+
+		load = max(load, latency/float64(s.config.TargetLatency))
+	}
+
+	return load
+}
+
+func (s *loadShedder) observe(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.latency = s.decayed(now)*(1-latencyWeight) + float64(latency)*latencyWeight
+	s.updated = now
+}
+
+// decayed returns the latency average decayed for the time elapsed since its last update.
+func (s *loadShedder) decayed(now time.Time) float64 {
+	if s.updated.IsZero() {
+		return 0
+	}
+
+	return s.latency * math.Exp2(-float64(now.Sub(s.updated))/float64(latencyHalfLife))
+}
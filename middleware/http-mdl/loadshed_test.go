@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveWithPriority(handler http.Handler, priority string) int {
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	if priority != "" {
+		req.Header.Set("X-Priority", priority)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func TestLoadShedInFlight(t *testing.T) {
+	var shed atomic.Int32
+	config := DefaultLoadShedConfig()
+	config.MaxInFlight = 10
+	config.TargetLatency = 0
+	config.OnShed = func(*http.Request, Priority) { shed.Add(1) }
+
+	release := make(chan struct{})
+	var started, done sync.WaitGroup
+	handler := LoadShed(&config)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/block" {
+			started.Done()
+			<-release
+		}
+	}))
+	block := func(n int) {
+		started.Add(n)
+		done.Add(n)
+		for range n {
+			go func() {
+				defer done.Done()
+				req := httptest.NewRequest(http.MethodGet, "/block", http.NoBody)
+				req.Header.Set("X-Priority", "critical")
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+			}()
+		}
+		started.Wait()
+	}
+	defer func() {
+		close(release)
+		done.Wait()
+	}()
+
+	assert.Equal(t, http.StatusOK, serveWithPriority(handler, "low"))
+
+	block(7)
+	assert.Equal(t, http.StatusServiceUnavailable, serveWithPriority(handler, "low"))
+	assert.Equal(t, http.StatusOK, serveWithPriority(handler, ""))
+
+	block(2)
+	assert.Equal(t, http.StatusServiceUnavailable, serveWithPriority(handler, "normal"))
+	assert.Equal(t, http.StatusOK, serveWithPriority(handler, "HIGH"))
+
+	block(1)
+	assert.Equal(t, http.StatusServiceUnavailable, serveWithPriority(handler, "high"))
+	assert.Equal(t, http.StatusOK, serveWithPriority(handler, "critical"))
+
+	assert.Equal(t, int32(3), shed.Load())
+}
+
+func TestLoadShedLatency(t *testing.T) {
+	config := DefaultLoadShedConfig()
+	config.TargetLatency = 10 * time.Millisecond
+	handler := LoadShed(&config)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", http.NoBody))
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set("X-Priority", "high")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// The average decays without requests, until low priority requests are served again.
+	time.Sleep(1200 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, serveWithPriority(handler, "low"))
+}
+
+func TestLoadShedClassify(t *testing.T) {
+	config := LoadShedConfig{
+		MaxInFlight: 1,
+		Classify: func(r *http.Request) Priority {
+			if r.URL.Path == "/health" {
+				return PriorityCritical
+			}
+
+			return PriorityLow
+		},
+	}
+
+	var inner http.Handler
+	handler := LoadShed(&config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/outer" {
+			// With this request in flight, the load is at the limit.
+			for _, path := range []string{"/health", "/report"} {
+				rec := httptest.NewRecorder()
+				inner.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+				w.Header().Add("X-Inner", rec.Result().Status)
+			}
+		}
+	}))
+	inner = handler
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/outer", http.NoBody)
+	req.Header.Set("X-Priority", "critical") // ignored with Classify
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, []string{"200 OK", "503 Service Unavailable"}, w.Header().Values("X-Inner"))
+}
+
+func TestPriorityString(t *testing.T) {
+	assert.Equal(t, "low", PriorityLow.String())
+	assert.Equal(t, "critical", PriorityCritical.String())
+	assert.Equal(t, "unknown", Priority(9).String())
+}
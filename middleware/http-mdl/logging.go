@@ -1,25 +1,53 @@
 package middleware
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/ezex-io/gopkg/errors"
 )
 
-// Logging logs incoming HTTP requests and their duration.
+// Logging logs incoming HTTP requests with their status and duration.
+// The response writer keeps supporting flushes, hijacking and io.ReaderFrom,
+// so streaming and WebSocket endpoints can be logged too.
+//
+// The request context carries an errors.Warnings, so handlers can report non-fatal issues
+// with errors.WarningsFromContext; those collected are logged once, with the request.
 func Logging() Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
-			next.ServeHTTP(w, r)
+			ctx, warnings := errors.WithWarnings(r.Context())
+			rw := wrapResponseWriter(w)
+			next.ServeHTTP(rw, r.WithContext(ctx))
 			duration := time.Since(start)
 
-			log.Printf("[%s] %s %s %dms",
+			log.Printf("[%s] %s %s %d %dms%s",
 				r.Method,
 				r.URL.Path,
 				r.RemoteAddr,
+				rw.Status(),
 				duration.Milliseconds(),
+				formatWarnings(warnings),
 			)
 		})
 	}
 }
+
+// formatWarnings returns the warnings as a suffix of the request log line, on a single line.
+func formatWarnings(warnings *errors.Warnings) string {
+	errs := warnings.Errors()
+	if len(errs) == 0 {
+		return ""
+	}
+
+	texts := make([]string, 0, len(errs))
+	for _, err := range errs {
+		texts = append(texts, err.Error())
+	}
+
+	return fmt.Sprintf(" warnings=%q", strings.Join(texts, "; "))
+}
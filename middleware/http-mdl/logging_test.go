@@ -5,8 +5,10 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/ezex-io/gopkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,3 +35,27 @@ func TestLoggingMiddleware(t *testing.T) {
 	assert.Contains(t, logged, "[GET] /foo 127.0.0.1")
 	assert.Contains(t, logged, "ms")
 }
+
+func TestLoggingMiddleware_Warnings(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log.SetOutput(&logBuffer)
+
+	handler := Logging()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		warnings := errors.WarningsFromContext(r.Context())
+		warnings.Add(errors.NewError("row_skipped", "row 12 skipped"))
+		warnings.Add(errors.New("row 13\nskipped"))
+
+		_ = Render(w, http.StatusOK, map[string]any{"warnings": warnings.List()})
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "http://test.com/import", http.NoBody)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.JSONEq(t, `{"warnings": [
+		{"code": "row_skipped", "message": "row 12 skipped"},
+		{"message": "row 13\nskipped"}
+	]}`, w.Body.String())
+	assert.Equal(t, 1, strings.Count(logBuffer.String(), "\n"))
+	assert.Contains(t, logBuffer.String(), `warnings="row_skipped: row 12 skipped; row 13\nskipped"`)
+}
@@ -3,20 +3,24 @@ package middleware
 import (
 	"log/slog"
 	"net/http"
-	"runtime"
 	"strings"
+
+	"github.com/ezex-io/gopkg/errors"
 )
 
 // Recover middleware with structured stack trace logging.
+// The recovered value is logged as an errors.Panic error, with the code
+// errors.CodePanic, so panics are alerted on and grouped like other errors.
 func Recover() Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
-				if err := recover(); err != nil {
-					stack := captureStackTrace(3) // Skip 3 frames to start at panic origin
-					slog.Error("panic recovered",
+				if recovered := recover(); recovered != nil {
+					err := errors.Panic(recovered)
+					slog.ErrorContext(r.Context(), "panic recovered",
 						"error", err,
-						"stack", stack,
+						"code", err.Code,
+						"stack", stackTrace(err),
 					)
 
 					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -27,28 +31,20 @@ func Recover() Middleware {
 	}
 }
 
-// captureStackTrace formats the stack trace in a structured and readable way.
-func captureStackTrace(skip int) []map[string]any {
-	var pcs [32]uintptr
-	n := runtime.Callers(skip, pcs[:])
-
-	var stackTrace []map[string]any
-	frames := runtime.CallersFrames(pcs[:n])
-
-	for {
-		frame, more := frames.Next()
+// stackTrace formats the stack of err in a structured and readable way.
+func stackTrace(err *errors.Error) []map[string]any {
+	var trace []map[string]any
+	for _, frame := range err.Frames() {
 		// Skip runtime internal frames
-		if !strings.Contains(frame.File, "runtime/") {
-			stackTrace = append(stackTrace, map[string]any{
-				"function": frame.Function,
-				"file":     frame.File,
-				"line":     frame.Line,
-			})
-		}
-		if !more {
-			break
+		if strings.Contains(frame.File, "runtime/") {
+			continue
 		}
+		trace = append(trace, map[string]any{
+			"function": frame.Function,
+			"file":     frame.File,
+			"line":     frame.Line,
+		})
 	}
 
-	return stackTrace
+	return trace
 }
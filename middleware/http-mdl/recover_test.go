@@ -1,11 +1,16 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/ezex-io/gopkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRecoverMiddleware(t *testing.T) {
@@ -44,3 +49,28 @@ func TestRecoverMiddleware_NoPanic(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.StatusCode)
 	assert.Equal(t, "All Good", w.Body.String())
 }
+
+func TestRecoverMiddleware_LogsPanicError(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+	handler := Recover()(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		panic("unexpected error")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://test.com", http.NoBody))
+
+	var entry struct {
+		Error string           `json:"error"`
+		Code  string           `json:"code"`
+		Stack []map[string]any `json:"stack"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "panic: unexpected error", entry.Error)
+	assert.Equal(t, errors.CodePanic, entry.Code)
+	require.NotEmpty(t, entry.Stack)
+	assert.Contains(t, entry.Stack[0]["function"], "TestRecoverMiddleware_LogsPanicError")
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// renderTypes are the media types Render can produce, in order of preference.
+var renderTypes = []string{MIMEJSON, MIMEMsgpack, MIMEForm}
+
+// Render writes v with the given status, encoded according to the Content-Type
+// already set on w: JSON (the default when none is set), msgpack or a URL-encoded form.
+// Set it from the request with Negotiate to honor the Accept header:
+//
+//	w.Header().Set("Content-Type", middleware.Negotiate(r))
+//	middleware.Render(w, http.StatusOK, resp)
+//
+// v is encoded before anything is written, so on error the response is untouched.
+func Render(w http.ResponseWriter, status int, v any) error {
+	mediaType := MIMEJSON
+	if header := w.Header().Get("Content-Type"); header != "" {
+		parsed, _, err := mime.ParseMediaType(header)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrUnsupportedMediaType, err)
+		}
+		mediaType = parsed
+	}
+
+	var body []byte
+	var err error
+	switch mediaType {
+	case MIMEJSON:
+		body, err = json.Marshal(v)
+		mediaType = MIMEJSON + "; charset=utf-8"
+	case MIMEMsgpack, "application/x-msgpack", "application/vnd.msgpack":
+		var buf bytes.Buffer
+		enc := msgpack.NewEncoder(&buf)
+		enc.SetCustomStructTag("json")
+		err = enc.Encode(v)
+		body = buf.Bytes()
+	case MIMEForm:
+		var values url.Values
+		values, err = encodeForm(v)
+		body = []byte(values.Encode())
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	_, err = w.Write(body)
+
+	return err
+}
+
+// Negotiate returns the media type Render should use for the request,
+// picking the most preferred one of the Accept header that Render supports.
+// It returns MIMEJSON when the header is missing or nothing matches.
+func Negotiate(r *http.Request) string {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return MIMEJSON
+	}
+
+	type candidate struct {
+		mediaType string
+		quality   float64
+	}
+
+	candidates := make([]candidate, 0, 4)
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{mediaType: mediaType, quality: quality})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+
+	for _, c := range candidates {
+		for _, supported := range renderTypes {
+			if acceptsMediaType(c.mediaType, supported) {
+				return supported
+			}
+		}
+	}
+
+	return MIMEJSON
+}
+
+func acceptsMediaType(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(pattern, "/*")
+
+	return ok && strings.HasPrefix(mediaType, prefix+"/")
+}
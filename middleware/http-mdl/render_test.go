@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type renderPayload struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+func TestRenderJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	require.NoError(t, Render(w, http.StatusCreated, renderPayload{Name: "ezex", Count: 1}))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"name":"ezex","count":1}`, w.Body.String())
+}
+
+func TestRenderMsgpack(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", MIMEMsgpack)
+
+	require.NoError(t, Render(w, http.StatusOK, renderPayload{Name: "ezex", Count: 2}))
+
+	dec := msgpack.NewDecoder(w.Body)
+	dec.SetCustomStructTag("json")
+	var got map[string]any
+	require.NoError(t, dec.Decode(&got))
+	assert.Equal(t, "ezex", got["name"])
+	assert.Equal(t, MIMEMsgpack, w.Header().Get("Content-Type"))
+}
+
+func TestRenderForm(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", MIMEForm)
+
+	require.NoError(t, Render(w, http.StatusOK, &renderPayload{Name: "ezex", Count: 3, Tags: []string{"a", "b"}}))
+
+	values, err := url.ParseQuery(w.Body.String())
+	require.NoError(t, err)
+	assert.Equal(t, url.Values{"name": {"ezex"}, "count": {"3"}, "tags": {"a", "b"}}, values)
+}
+
+func TestRenderUnsupported(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "text/plain")
+
+	err := Render(w, http.StatusOK, "hi")
+
+	require.ErrorIs(t, err, ErrUnsupportedMediaType)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", MIMEJSON},
+		{"*/*", MIMEJSON},
+		{"application/msgpack", MIMEMsgpack},
+		{"text/html, application/msgpack;q=0.9, application/json;q=0.5", MIMEMsgpack},
+		{"application/json;q=0.2, application/x-www-form-urlencoded", MIMEForm},
+		{"application/msgpack;q=0, application/*", MIMEJSON},
+		{"text/html", MIMEJSON},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://test.com", http.NoBody)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+
+		assert.Equal(t, tt.want, Negotiate(req), tt.accept)
+	}
+}
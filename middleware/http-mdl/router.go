@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Router is a thin facade over http.ServeMux that scopes middleware to route groups:
+//
+//	r := middleware.NewRouter(middleware.Logging())
+//	api := r.Group("/api", Auth(cfg))
+//	api.Handle("GET /users/{id}", getUser) // served at GET /api/users/{id}, with Logging and Auth
+//
+// Middleware only wraps the routes registered through the router or its groups,
+// so requests matching no route get the plain 404 of the mux. Wrap the router
+// itself with Chain for middleware that must see every request.
+type Router struct {
+	mux        *http.ServeMux
+	prefix     string
+	middleware []Middleware
+}
+
+// NewRouter creates a router applying mw to every route registered on it and its groups.
+func NewRouter(mw ...Middleware) *Router {
+	return &Router{mux: http.NewServeMux(), middleware: mw}
+}
+
+// Group returns a router registering its routes on the same mux under prefix,
+// wrapped with the middleware of r then mw. Groups can be nested.
+func (r *Router) Group(prefix string, mw ...Middleware) *Router {
+	return &Router{
+		mux:        r.mux,
+		prefix:     r.prefix + strings.TrimSuffix(prefix, "/"),
+		middleware: slices.Concat(r.middleware, mw),
+	}
+}
+
+// Handle registers handler for pattern, using the http.ServeMux pattern syntax
+// ("[METHOD ][HOST]/[PATH]"), with the path prefixed by the group prefix.
+// Like http.ServeMux.Handle, it panics if the pattern is invalid or conflicts with another.
+func (r *Router) Handle(pattern string, handler http.Handler) {
+	r.mux.Handle(r.pattern(pattern), Chain(r.middleware...)(handler))
+}
+
+// HandleFunc registers handler for pattern, like Handle.
+func (r *Router) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	r.Handle(pattern, http.HandlerFunc(handler))
+}
+
+// ServeHTTP dispatches the request to the handler of the matching route.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
+// pattern inserts the group prefix before the path of pattern.
+func (r *Router) pattern(pattern string) string {
+	if r.prefix == "" {
+		return pattern
+	}
+
+	method, rest, found := strings.Cut(pattern, " ")
+	if !found {
+		method, rest = "", pattern
+	} else {
+		method += " "
+		rest = strings.TrimLeft(rest, " \t")
+	}
+
+	slash := strings.Index(rest, "/")
+	if slash < 0 {
+		// Invalid pattern: let the mux report it.
+		return pattern
+	}
+
+	return method + rest[:slash] + r.prefix + rest[slash:]
+}
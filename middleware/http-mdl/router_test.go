@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tag returns a middleware appending name to the X-Chain response header.
+func tag(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Chain", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func serve(handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(method, target, http.NoBody))
+
+	return w
+}
+
+func TestRouterGroups(t *testing.T) {
+	r := NewRouter(tag("root"))
+	r.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	api := r.Group("/api/", tag("auth"))
+	api.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte("user " + req.PathValue("id")))
+	})
+
+	admin := api.Group("/admin", tag("admin"))
+	admin.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("admin"))
+	})
+
+	tests := []struct {
+		target string
+		body   string
+		chain  []string
+	}{
+		{"/health", "ok", []string{"root"}},
+		{"/api/users/42", "user 42", []string{"root", "auth"}},
+		{"/api/admin/settings", "admin", []string{"root", "auth", "admin"}},
+	}
+
+	for _, tt := range tests {
+		w := serve(r, http.MethodGet, tt.target)
+		assert.Equal(t, http.StatusOK, w.Code, tt.target)
+		assert.Equal(t, tt.body, w.Body.String(), tt.target)
+		assert.Equal(t, tt.chain, w.Header().Values("X-Chain"), tt.target)
+	}
+
+	w := serve(r, http.MethodGet, "/users/42")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Values("X-Chain"), "unmatched requests are not wrapped")
+
+	w = serve(r, http.MethodPost, "/api/users/42")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestRouterGroupsDontShareMiddleware(t *testing.T) {
+	r := NewRouter()
+	public := r.Group("/public")
+	private := r.Group("/private", tag("auth"))
+
+	ok := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	public.Handle("/", ok)
+	private.Handle("/", ok)
+
+	assert.Empty(t, serve(r, http.MethodGet, "/public/x").Header().Values("X-Chain"))
+	assert.Equal(t, []string{"auth"}, serve(r, http.MethodGet, "/private/x").Header().Values("X-Chain"))
+}
+
+func TestRouterPattern(t *testing.T) {
+	r := NewRouter().Group("/v1")
+
+	tests := map[string]string{
+		"/users":                 "/v1/users",
+		"GET /users/{id}":        "GET /v1/users/{id}",
+		"example.com/":           "example.com/v1/",
+		"POST example.com/items": "POST example.com/v1/items",
+		"GET   /spaced":          "GET /v1/spaced",
+		"missing-slash":          "missing-slash",
+		"/":                      "/v1/",
+	}
+
+	for pattern, expected := range tests {
+		assert.Equal(t, expected, r.pattern(pattern), pattern)
+	}
+
+	assert.Panics(t, func() { r.Handle("missing-slash", http.NotFoundHandler()) })
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MIMEEventStream is the media type of server-sent events.
+const MIMEEventStream = "text/event-stream"
+
+// Event is a server-sent event.
+type Event struct {
+	// ID sets the last event ID the client sends back when it reconnects.
+	ID string
+	// Event names the event type; clients receive unnamed events as "message".
+	Event string
+	// Data is the payload; each of its lines is sent as a data field.
+	Data string
+	// Retry sets how long the client waits before reconnecting, if positive.
+	Retry time.Duration
+}
+
+// SSEWriter streams server-sent events to a client, flushing each one,
+// and keeps idle connections open with heartbeat comments.
+// It is safe for concurrent use.
+type SSEWriter struct {
+	mu   sync.Mutex
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewSSEWriter starts an event stream in reply to r, sending a heartbeat comment
+// every heartbeat until the writer is closed or the request is done, so proxies
+// don't close an idle connection; zero disables heartbeats.
+// It returns an error wrapping http.ErrNotSupported if w can't be flushed.
+//
+// Close the writer before the handler returns:
+//
+//	sse, err := middleware.NewSSEWriter(w, r, 15*time.Second)
+//	if err != nil {
+//		middleware.WriteError(w, r, err)
+//		return
+//	}
+//	defer sse.Close()
+//
+//	for update := range updates {
+//		if err := sse.Send(middleware.Event{Event: "update", Data: update}); err != nil {
+//			return // the client is gone
+//		}
+//	}
+func NewSSEWriter(w http.ResponseWriter, r *http.Request, heartbeat time.Duration) (*SSEWriter, error) {
+	if !canFlush(w) {
+		return nil, fmt.Errorf("middleware: event stream: %w", http.ErrNotSupported)
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", MIMEEventStream)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // disables the buffering of nginx
+
+	rc := http.NewResponseController(w)
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return nil, fmt.Errorf("middleware: event stream: %w", err)
+	}
+
+	sse := &SSEWriter{
+		w:    w,
+		rc:   rc,
+		done: make(chan struct{}),
+	}
+	if heartbeat > 0 {
+		sse.wg.Add(1)
+		go sse.heartbeat(r, heartbeat)
+	}
+
+	return sse, nil
+}
+
+// Send writes the event and flushes it to the client.
+func (s *SSEWriter) Send(event Event) error {
+	var buf strings.Builder
+	if event.ID != "" {
+		buf.WriteString("id: " + singleLine(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		buf.WriteString("event: " + singleLine(event.Event) + "\n")
+	}
+	if event.Retry > 0 {
+		buf.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+	for line := range strings.Lines(event.Data) {
+		buf.WriteString("data: " + strings.TrimRight(line, "\r\n") + "\n")
+	}
+	if event.Data == "" || strings.HasSuffix(event.Data, "\n") {
+		buf.WriteString("data: \n")
+	}
+	buf.WriteString("\n")
+
+	return s.write(buf.String())
+}
+
+// Close stops the heartbeats. The writer must not be used afterwards.
+func (s *SSEWriter) Close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+	s.wg.Wait()
+}
+
+func (s *SSEWriter) heartbeat(r *http.Request, interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if s.write(": heartbeat\n\n") != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *SSEWriter) write(data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write([]byte(data)); err != nil {
+		return err
+	}
+
+	return s.rc.Flush()
+}
+
+// canFlush reports whether w, or a writer it wraps, is an http.Flusher.
+func canFlush(w http.ResponseWriter) bool {
+	for {
+		if _, ok := w.(http.Flusher); ok {
+			return true
+		}
+
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// singleLine drops the line breaks of a field that must fit on one line.
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
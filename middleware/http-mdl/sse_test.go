@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEWriterSend(t *testing.T) {
+	w := httptest.NewRecorder()
+	sse, err := NewSSEWriter(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody), 0)
+	require.NoError(t, err)
+	defer sse.Close()
+
+	require.NoError(t, sse.Send(Event{ID: "1", Event: "update", Data: "line 1\nline 2", Retry: time.Second}))
+	require.NoError(t, sse.Send(Event{Data: "trailing\n"}))
+	require.NoError(t, sse.Send(Event{Event: "ping\nevil"}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, MIMEEventStream, w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.True(t, w.Flushed)
+	assert.Equal(t,
+		"id: 1\nevent: update\nretry: 1000\ndata: line 1\ndata: line 2\n\n"+
+			"data: trailing\ndata: \n\n"+
+			"event: pingevil\ndata: \n\n",
+		w.Body.String())
+}
+
+type plainWriter struct {
+	header http.Header
+}
+
+func (w *plainWriter) Header() http.Header          { return w.header }
+func (*plainWriter) Write(data []byte) (int, error) { return len(data), nil }
+func (*plainWriter) WriteHeader(int)                {}
+
+func TestSSEWriterNotSupported(t *testing.T) {
+	w := &plainWriter{header: http.Header{}}
+	_, err := NewSSEWriter(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody), 0)
+
+	require.ErrorIs(t, err, http.ErrNotSupported)
+	assert.Empty(t, w.header, "expected nothing to be written")
+}
+
+func TestSSEWriterHeartbeat(t *testing.T) {
+	handler := Logging()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sse, err := NewSSEWriter(w, r, 10*time.Millisecond)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer sse.Close()
+
+		_ = sse.Send(Event{Data: "hello"})
+		<-r.Context().Done()
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = res.Body.Close() }()
+
+	assert.Equal(t, MIMEEventStream, res.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(res.Body)
+	var lines []string
+	for len(lines) < 4 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, line)
+	}
+	assert.Equal(t, []string{"data: hello\n", "\n", ": heartbeat\n", "\n"}, lines)
+}
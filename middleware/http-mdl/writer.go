@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// responseWriter records the status of a response for the middlewares
+// wrapping it, while passing through the optional interfaces of the wrapped writer:
+// http.Flusher for streaming, http.Hijacker for WebSocket upgrades and io.ReaderFrom
+// for sendfile. Unwrap also exposes the wrapped writer to http.ResponseController.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func wrapResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: w}
+}
+
+// Status returns the status written, or 200 if none was, as the server replies.
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	// Informational headers, such as 103 Early Hints, may precede the final one.
+	if w.status == 0 && status >= http.StatusOK {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(data []byte) (int, error) {
+	w.implicitHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+// ReadFrom copies r to the response with the io.ReaderFrom of the wrapped writer,
+// if any, so the server can use sendfile.
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	w.implicitHeader()
+
+	if from, ok := w.ResponseWriter.(io.ReaderFrom); ok {
+		return from.ReadFrom(r)
+	}
+
+	return io.Copy(writerOnly{w.ResponseWriter}, r)
+}
+
+// Flush sends the buffered response to the client, if the wrapped writer supports it.
+func (w *responseWriter) Flush() {
+	w.implicitHeader()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack takes over the connection, as a WebSocket upgrade does, and records
+// the response as 101 Switching Protocols. It returns an error wrapping
+// http.ErrNotSupported if the wrapped writer can't be hijacked.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) implicitHeader() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+}
+
+// writerOnly hides the io.ReaderFrom of a writer from io.Copy.
+type writerOnly struct {
+	io.Writer
+}
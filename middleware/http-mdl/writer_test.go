@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggingFlush(t *testing.T) {
+	handler := Logging()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		require.NoError(t, http.NewResponseController(w).Flush())
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "chunk", w.Body.String())
+}
+
+func TestResponseWriterHijack(t *testing.T) {
+	statuses := make(chan int, 1)
+	upgrade := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		conn, rw, err := http.NewResponseController(w).Hijack()
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: test\r\nConnection: Upgrade\r\n\r\nhello")
+		_ = rw.Flush()
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := wrapResponseWriter(w)
+		upgrade.ServeHTTP(rw, r)
+		statuses <- rw.Status()
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "test")
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = res.Body.Close() }()
+
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	body, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "hello", string(body))
+	assert.Equal(t, http.StatusSwitchingProtocols, <-statuses)
+}
+
+func TestLoggingHijackNotSupported(t *testing.T) {
+	handler := Logging()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _, err := http.NewResponseController(w).Hijack()
+		assert.ErrorIs(t, err, http.ErrNotSupported)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+}
+
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+	readFrom bool
+}
+
+func (w *readerFromRecorder) ReadFrom(r io.Reader) (int64, error) {
+	w.readFrom = true
+
+	return io.Copy(w.ResponseRecorder, r)
+}
+
+func TestLoggingReadFrom(t *testing.T) {
+	var logBuffer bytes.Buffer
+	log.SetOutput(&logBuffer)
+
+	handler := Logging()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, err := io.Copy(w, io.LimitReader(strings.NewReader("body"), 10))
+		assert.NoError(t, err)
+	}))
+
+	w := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.True(t, w.readFrom)
+	assert.Equal(t, "body", w.Body.String())
+	assert.Contains(t, logBuffer.String(), " 202 ")
+
+	// Without io.ReaderFrom to pass through, the copy falls back to Write.
+	plain := httptest.NewRecorder()
+	handler.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Equal(t, "body", plain.Body.String())
+}
+
+func TestResponseWriterStatus(t *testing.T) {
+	rw := wrapResponseWriter(httptest.NewRecorder())
+	assert.Equal(t, http.StatusOK, rw.Status())
+
+	rw.WriteHeader(http.StatusEarlyHints)
+	rw.WriteHeader(http.StatusNotFound)
+	rw.WriteHeader(http.StatusInternalServerError)
+	assert.Equal(t, http.StatusNotFound, rw.Status())
+}
@@ -0,0 +1,155 @@
+// Package migrate applies versioned SQL migrations, embedded in the binary, to Postgres.
+//
+// Migrations are files named VERSION_NAME.up.sql, with an optional VERSION_NAME.down.sql
+// reverting them, such as 0001_create_users.up.sql. They are applied in the order
+// of their versions, each in a transaction recorded in the schema_migrations table:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	sub, _ := fs.Sub(migrations, "migrations")
+//	migrator, err := migrate.New(db, sub, migrate.WithLogger(log))
+//	...
+//	if err := migrator.Up(ctx); err != nil {
+//		log.Fatal("migration failed", "error", err)
+//	}
+//
+// An advisory lock serializes the migrators of the replicas starting together,
+// and the checksums of the applied migrations are verified, so a migration
+// edited after it was applied is reported rather than silently skipped.
+package migrate
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+var (
+	// ErrChecksumMismatch is returned when an applied migration was modified since.
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+
+	// ErrUnknownVersion is returned when the database has a migration applied
+	// that is not among the migration files, such as one of a newer release.
+	ErrUnknownVersion = errors.New("migrate: applied migration not found")
+
+	// ErrNoDown is returned when rolling back a migration without a down script.
+	ErrNoDown = errors.New("migrate: migration has no down script")
+)
+
+// Migration is a versioned schema change.
+type Migration struct {
+	// Version orders the migrations.
+	Version int64
+	// Name describes the migration, from its file name.
+	Name string
+	// Up applies the migration.
+	Up string
+	// Down reverts the migration; it is empty if the migration can't be reverted.
+	Down string
+}
+
+// Checksum returns the hex-encoded SHA-256 hash of the up script.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// fileName matches the names of migration files.
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// parseMigrations reads the migration files at the root of fsys, sorted by version.
+// Other files are ignored.
+func parseMigrations(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, name := range names {
+		match := fileName.FindStringSubmatch(path.Base(name))
+		if match == nil {
+			return nil, fmt.Errorf("migrate: invalid file name %q, expected VERSION_NAME.up.sql or .down.sql", name)
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: invalid version in %q: %w", name, err)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mig
+		}
+		if mig.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d is used by %q and %q", version, mig.Name, match[2])
+		}
+
+		script := &mig.Up
+		if match[3] == "down" {
+			script = &mig.Down
+		}
+		*script = string(data)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" {
+			return nil, fmt.Errorf("migrate: migration %d_%s has no up script", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// verify checks the applied migrations, by version, against the migration files.
+func verify(migrations []Migration, applied map[int64]appliedMigration) error {
+	var errs []error
+	for version, record := range applied {
+		idx, found := slices.BinarySearchFunc(migrations, version, func(m Migration, v int64) int {
+			return cmp.Compare(m.Version, v)
+		})
+		if !found {
+			errs = append(errs, fmt.Errorf("%w: %d_%s", ErrUnknownVersion, version, record.name))
+
+			continue
+		}
+
+		if mig := migrations[idx]; mig.Checksum() != record.checksum {
+			errs = append(errs, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, version, mig.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// pending returns the migrations not applied yet, in order.
+func pending(migrations []Migration, applied map[int64]appliedMigration) []Migration {
+	var todo []Migration
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			todo = append(todo, mig)
+		}
+	}
+
+	return todo
+}
@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"0002_add_email.up.sql":        {Data: []byte("ALTER TABLE users ADD email TEXT;")},
+		"0001_create_users.up.sql":     {Data: []byte("CREATE TABLE users (id BIGINT);")},
+		"0001_create_users.down.sql":   {Data: []byte("DROP TABLE users;")},
+		"0010_create_orders.up.sql":    {Data: []byte("CREATE TABLE orders (id BIGINT);")},
+		"0010_create_orders.down.sql":  {Data: []byte("DROP TABLE orders;")},
+		"README.md":                    {Data: []byte("ignored")},
+		"nested/0003_ignored.up.sql":   {Data: []byte("ignored")},
+		"0004_not_a_migration.sql.bak": {Data: []byte("ignored")},
+	}
+}
+
+func TestParseMigrations(t *testing.T) {
+	migrations, err := parseMigrations(testFS())
+	require.NoError(t, err)
+
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "create_users", Up: "CREATE TABLE users (id BIGINT);", Down: "DROP TABLE users;"},
+		{Version: 2, Name: "add_email", Up: "ALTER TABLE users ADD email TEXT;"},
+		{Version: 10, Name: "create_orders", Up: "CREATE TABLE orders (id BIGINT);", Down: "DROP TABLE orders;"},
+	}, migrations)
+}
+
+func TestParseMigrationsErrors(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"invalid file name": {"create_users.sql": {}},
+		"duplicate version": {
+			"0001_create_users.up.sql":  {Data: []byte("a")},
+			"0001_create_orders.up.sql": {Data: []byte("b")},
+		},
+		"down without up": {"0001_create_users.down.sql": {Data: []byte("a")}},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseMigrations(fsys)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	migrations, err := parseMigrations(testFS())
+	require.NoError(t, err)
+
+	applied := map[int64]appliedMigration{
+		1: {name: "create_users", checksum: migrations[0].Checksum()},
+		2: {name: "add_email", checksum: migrations[1].Checksum()},
+	}
+	require.NoError(t, verify(migrations, applied))
+	assert.Equal(t, []Migration{migrations[2]}, pending(migrations, applied))
+
+	applied[2] = appliedMigration{name: "add_email", checksum: "edited"}
+	err = verify(migrations, applied)
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.ErrorContains(t, err, "2_add_email")
+
+	applied[3] = appliedMigration{name: "from_newer_release"}
+	require.ErrorIs(t, verify(migrations, applied), ErrUnknownVersion)
+}
+
+func TestChecksum(t *testing.T) {
+	mig := Migration{Up: "CREATE TABLE users (id BIGINT);"}
+
+	assert.Len(t, mig.Checksum(), 64)
+	assert.Equal(t, mig.Checksum(), Migration{Up: mig.Up, Down: "changed"}.Checksum())
+	assert.NotEqual(t, mig.Checksum(), Migration{Up: mig.Up + " "}.Checksum())
+}
@@ -0,0 +1,297 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/ezex-io/gopkg/logger"
+)
+
+type options struct {
+	table  string
+	logger logger.Logger
+}
+
+// Option configures a Migrator.
+type Option func(*options)
+
+// WithTable sets the table recording the applied migrations, optionally qualified
+// with its schema. The default is "schema_migrations".
+func WithTable(table string) Option {
+	return func(opts *options) {
+		opts.table = table
+	}
+}
+
+// WithLogger sets the logger of the applied and reverted migrations.
+// The default is the global logger of the logger package.
+func WithLogger(log logger.Logger) Option {
+	return func(opts *options) {
+		opts.logger = log
+	}
+}
+
+// identifier matches the table names accepted by WithTable.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	table      string
+	logger     logger.Logger
+}
+
+// New returns a Migrator of db applying the migration files at the root of fsys.
+// It works with any database/sql driver for Postgres, such as pgx's stdlib driver.
+func New(db *sql.DB, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	cfg := options{table: "schema_migrations", logger: globalLogger{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !identifier.MatchString(cfg.table) {
+		return nil, fmt.Errorf("migrate: invalid table name %q", cfg.table)
+	}
+
+	migrations, err := parseMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		table:      cfg.table,
+		logger:     cfg.logger,
+	}, nil
+}
+
+// Migrations returns the migrations read from the files, in order.
+func (m *Migrator) Migrations() []Migration {
+	return slices.Clone(m.migrations)
+}
+
+// Up applies the pending migrations in order, after verifying the applied ones.
+//
+// Each migration is applied in a transaction. If ctx is done, such as on shutdown,
+// the migration in progress is rolled back and the following ones are left pending.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.locked(ctx, func(conn *sql.Conn, applied map[int64]appliedMigration) error {
+		for _, mig := range pending(m.migrations, applied) {
+			if err := m.up(ctx, conn, mig); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Down reverts the last steps applied migrations, latest first, after verifying
+// the applied ones. It returns an error wrapping ErrNoDown, before reverting any,
+// if one of them has no down script.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(conn *sql.Conn, applied map[int64]appliedMigration) error {
+		var revert []Migration
+		for _, mig := range slices.Backward(m.migrations) {
+			if len(revert) == steps {
+				break
+			}
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrNoDown, mig.Version, mig.Name)
+			}
+			revert = append(revert, mig)
+		}
+
+		for _, mig := range revert {
+			if err := m.down(ctx, conn, mig); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Status is the state of a migration in the database.
+type Status struct {
+	Migration
+
+	// Applied reports whether the migration is applied.
+	Applied bool
+	// AppliedAt is the time the migration was applied, if it is.
+	AppliedAt time.Time
+}
+
+// Status returns the state of the migrations, in order. It doesn't verify them.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.createTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		record, ok := applied[mig.Version]
+		statuses = append(statuses, Status{Migration: mig, Applied: ok, AppliedAt: record.appliedAt})
+	}
+
+	return statuses, nil
+}
+
+// appliedMigration is a row of the migrations table.
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// execQuerier is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// locked calls fn with a connection holding the advisory lock of the migrations table,
+// and the verified applied migrations.
+func (m *Migrator) locked(ctx context.Context,
+	fn func(conn *sql.Conn, applied map[int64]appliedMigration) error,
+) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	// Session-level advisory locks are held by the connection, so the lock
+	// and the migrations share it.
+	lockKey := "migrate:" + m.table
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock(hashtext($1))`, lockKey); err != nil {
+		return fmt.Errorf("migrate: failed to lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock(hashtext($1))`, lockKey)
+	}()
+
+	if err := m.createTable(ctx, conn); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	if err := verify(m.migrations, applied); err != nil {
+		return err
+	}
+
+	return fn(conn, applied)
+}
+
+func (m *Migrator) createTable(ctx context.Context, db execQuerier) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+m.table+` (
+		version    BIGINT      PRIMARY KEY,
+		name       TEXT        NOT NULL,
+		checksum   TEXT        NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("migrate: failed to create %s: %w", m.table, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, db execQuerier) (map[int64]appliedMigration, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM `+m.table)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	applied := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = record
+	}
+
+	return applied, rows.Err()
+}
+
+// up applies mig and records it, in a transaction.
+func (m *Migrator) up(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	return m.run(ctx, conn, mig, "up", mig.Up,
+		`INSERT INTO `+m.table+` (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)`,
+		mig.Version, mig.Name, mig.Checksum(), time.Now().UTC())
+}
+
+// down reverts mig and deletes its record, in a transaction.
+func (m *Migrator) down(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	return m.run(ctx, conn, mig, "down", mig.Down,
+		`DELETE FROM `+m.table+` WHERE version = $1`, mig.Version)
+}
+
+// run executes script, then the query updating the migrations table with args, in a transaction.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, mig Migration,
+	direction, script, query string, args ...any,
+) error {
+	start := time.Now()
+	err := m.inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, script); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, query, args...)
+
+		return err
+	})
+	if err != nil {
+		m.logger.Error("migration failed",
+			"version", mig.Version, "name", mig.Name, "direction", direction, "error", err)
+
+		return fmt.Errorf("migrate: %s %d_%s: %w", direction, mig.Version, mig.Name, err)
+	}
+
+	m.logger.Info("migration applied",
+		"version", mig.Version, "name", mig.Name, "direction", direction, "duration", time.Since(start))
+
+	return nil
+}
+
+func (*Migrator) inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// globalLogger logs with the global logger of the logger package.
+type globalLogger struct{}
+
+func (globalLogger) Debug(msg string, args ...any) { logger.Debug(msg, args...) }
+func (globalLogger) Info(msg string, args ...any)  { logger.Info(msg, args...) }
+func (globalLogger) Warn(msg string, args ...any)  { logger.Warn(msg, args...) }
+func (globalLogger) Error(msg string, args ...any) { logger.Error(msg, args...) }
+func (globalLogger) Fatal(msg string, args ...any) { logger.Fatal(msg, args...) }
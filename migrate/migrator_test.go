@@ -0,0 +1,65 @@
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInvalidTable(t *testing.T) {
+	_, err := New(nil, testFS(), WithTable("users; DROP TABLE users"))
+	require.ErrorContains(t, err, "invalid table name")
+
+	migrator, err := New(nil, testFS(), WithTable("app.schema_migrations"))
+	require.NoError(t, err)
+	assert.Len(t, migrator.Migrations(), 3)
+}
+
+// TestMigratorPostgres runs against the database in MIGRATE_POSTGRES_DSN, if set.
+func TestMigratorPostgres(t *testing.T) {
+	dsn := os.Getenv("MIGRATE_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("MIGRATE_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	suffix := fmt.Sprintf("_%d", time.Now().UnixNano())
+	table := "migrations" + suffix
+	fsys := fstest.MapFS{
+		"0001_create.up.sql":   {Data: []byte("CREATE TABLE users" + suffix + " (id BIGINT);")},
+		"0001_create.down.sql": {Data: []byte("DROP TABLE users" + suffix + ";")},
+		"0002_alter.up.sql":    {Data: []byte("ALTER TABLE users" + suffix + " ADD email TEXT;")},
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec("DROP TABLE IF EXISTS " + table + ", users" + suffix)
+	})
+
+	migrator, err := New(db, fsys, WithTable(table))
+	require.NoError(t, err)
+
+	require.NoError(t, migrator.Up(t.Context()))
+	require.NoError(t, migrator.Up(t.Context()), "Up is idempotent")
+
+	statuses, err := migrator.Status(t.Context())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.True(t, statuses[1].Applied)
+
+	require.ErrorIs(t, migrator.Down(t.Context(), 1), ErrNoDown)
+
+	fsys["0002_alter.up.sql"] = &fstest.MapFile{Data: []byte("ALTER TABLE users" + suffix + " ADD phone TEXT;")}
+	edited, err := New(db, fsys, WithTable(table))
+	require.NoError(t, err)
+	require.ErrorIs(t, edited.Up(t.Context()), ErrChecksumMismatch)
+}
@@ -0,0 +1,95 @@
+package pipeline
+
+import (
+	"context"
+	"iter"
+)
+
+// FromChannel returns a pipeline delivering the messages received from ch, so code
+// producing a plain channel can feed pipeline receivers.
+// The pipeline is closed, after delivering its buffered messages, once ch is closed.
+// Reading from ch stops when the pipeline is closed or ctx is done, leaving
+// the remaining messages in ch.
+//
+// Parameters:
+//   - ctx: The parent context for the pipeline
+//   - ch: The channel to read messages from
+//   - opts: Functional options to configure the pipeline
+//
+// Returns:
+//   - A pipeline delivering the messages of ch
+func FromChannel[T any](ctx context.Context, ch <-chan T, opts ...Option) Pipeline[T] {
+	out := New[T](ctx, opts...)
+
+	go func() {
+		for {
+			// Check first, so no message is taken from ch once the pipeline is closed.
+			select {
+			case <-out.Done():
+				return
+			default:
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-out.Done():
+				return
+			case data, ok := <-ch:
+				if !ok {
+					_ = out.CloseAndDrain(ctx)
+
+					return
+				}
+
+				out.Send(data)
+			}
+		}
+	}()
+
+	return out
+}
+
+// ToSeq returns an iterator over the messages of p, so a pipeline can be consumed
+// with a for-range loop or passed to code expecting an iter.Seq.
+// The iteration ends once p is closed and its buffered messages have been yielded,
+// or when the context of p is cancelled. Breaking out of the loop closes p,
+// so senders don't block on a pipeline nobody reads anymore.
+//
+// ToSeq takes over consumption of p: do not register receivers on it,
+// and range over the iterator only once.
+//
+// Parameters:
+//   - p: The pipeline to consume
+//
+// Returns:
+//   - An iterator yielding the messages of p
+func ToSeq[T any](p Pipeline[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ch := p.UnsafeGetChannel()
+		for {
+			select {
+			case data, ok := <-ch:
+				if !ok {
+					return
+				}
+				if !yield(data) {
+					p.Close()
+
+					return
+				}
+			case <-p.Done():
+				if p.IsClosed() {
+					// Closed by CloseAndDrain: yield what is left in the buffer.
+					for data := range ch {
+						if !yield(data) {
+							return
+						}
+					}
+				}
+
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package pipeline
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromChannel(t *testing.T) {
+	ch := make(chan int)
+	pipe := FromChannel(t.Context(), ch, WithName("from-channel"))
+
+	received := make(chan int, 3)
+	pipe.RegisterReceiver(func(data int) {
+		received <- data
+	})
+
+	for i := 1; i <= 3; i++ {
+		ch <- i
+	}
+	close(ch)
+
+	require.NoError(t, pipe.Wait(t.Context()))
+	assert.True(t, pipe.IsClosed())
+	assert.Equal(t, "from-channel", pipe.Name())
+
+	close(received)
+	var got []int
+	for data := range received {
+		got = append(got, data)
+	}
+	assert.Equal(t, []int{1, 2, 3}, got)
+}
+
+func TestFromChannelStopsReadingWhenClosed(t *testing.T) {
+	ch := make(chan int, 1)
+	pipe := FromChannel(t.Context(), ch)
+	pipe.Close()
+	require.NoError(t, pipe.Wait(t.Context()))
+
+	ch <- 1
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, ch, 1, "expected the closed pipeline to leave the channel alone")
+}
+
+func TestToSeq(t *testing.T) {
+	pipe := New[int](t.Context())
+	for i := 1; i <= 3; i++ {
+		pipe.Send(i)
+	}
+
+	go func() { _ = pipe.CloseAndDrain(t.Context()) }()
+
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(ToSeq(pipe)))
+}
+
+func TestToSeqBreakClosesPipeline(t *testing.T) {
+	pipe := New[int](t.Context())
+	for i := 1; i <= 3; i++ {
+		pipe.Send(i)
+	}
+
+	for data := range ToSeq(pipe) {
+		if data == 2 {
+			break
+		}
+	}
+
+	assert.True(t, pipe.IsClosed())
+	require.NoError(t, pipe.Wait(t.Context()))
+}
+
+func TestToSeqContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	pipe := New[int](ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range ToSeq(pipe) {
+			t.Error("expected no message")
+		}
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the iteration to end when the context is cancelled")
+	}
+}
+
+func TestChannelRoundTrip(t *testing.T) {
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for _, word := range []string{"a", "b", "c"} {
+			ch <- word
+		}
+	}()
+
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(ToSeq(FromChannel(t.Context(), ch))))
+}
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Batch collects messages from source and delivers them as slices to the returned pipeline.
+// A batch is delivered once it holds maxItems messages, or maxWait after its first message
+// arrived, whichever happens first. A non-positive maxWait disables the time threshold.
+// When source shuts down, the pending partial batch is delivered and the returned
+// pipeline is closed after delivering its buffered batches.
+//
+// Parameters:
+//   - ctx: The parent context for the batched pipeline
+//   - source: The pipeline to collect messages from
+//   - maxItems: The maximum number of messages per batch (at least 1)
+//   - maxWait: The maximum time a message waits before its batch is delivered
+//   - opts: Functional options to configure the batched pipeline
+//
+// Returns:
+//   - A pipeline delivering batches of messages
+func Batch[T any](ctx context.Context, source Pipeline[T],
+	maxItems int, maxWait time.Duration, opts ...Option,
+) Pipeline[[]T] {
+	out := New[[]T](ctx, opts...)
+
+	btc := &batcher[T]{
+		out:      out,
+		maxItems: max(maxItems, 1),
+		maxWait:  maxWait,
+	}
+	source.RegisterReceiver(btc.add)
+	source.OnClose(func() {
+		btc.flush()
+		go func() { _ = out.CloseAndDrain(ctx) }()
+	})
+
+	return out
+}
+
+// batcher accumulates messages until a size or time threshold is hit.
+type batcher[T any] struct {
+	sync.Mutex
+
+	out      Pipeline[[]T]
+	maxItems int
+	maxWait  time.Duration
+	items    []T
+	timer    *time.Timer
+	gen      uint64
+}
+
+func (b *batcher[T]) add(item T) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.items = append(b.items, item)
+	if len(b.items) >= b.maxItems {
+		b.flushLocked()
+
+		return
+	}
+
+	if len(b.items) == 1 && b.maxWait > 0 {
+		gen := b.gen
+		b.timer = time.AfterFunc(b.maxWait, func() {
+			b.flushGen(gen)
+		})
+	}
+}
+
+// flushGen flushes the batch started in generation gen,
+// unless it was already delivered because it became full.
+func (b *batcher[T]) flushGen(gen uint64) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.gen == gen {
+		b.flushLocked()
+	}
+}
+
+func (b *batcher[T]) flush() {
+	b.Lock()
+	defer b.Unlock()
+
+	b.flushLocked()
+}
+
+func (b *batcher[T]) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	b.gen++
+	if len(b.items) == 0 {
+		return
+	}
+
+	batch := b.items
+	b.items = make([]T, 0, b.maxItems)
+	b.out.Send(batch)
+}
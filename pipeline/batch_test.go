@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchBySize(t *testing.T) {
+	source := New[int](t.Context())
+	batched := Batch(t.Context(), source, 3, time.Hour)
+
+	received := make(chan []int, 2)
+	batched.RegisterReceiver(func(batch []int) {
+		received <- batch
+	})
+
+	for i := 1; i <= 6; i++ {
+		source.Send(i)
+	}
+
+	for _, expected := range [][]int{{1, 2, 3}, {4, 5, 6}} {
+		select {
+		case batch := <-received:
+			assert.Equal(t, expected, batch)
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for batch")
+		}
+	}
+}
+
+func TestBatchByTime(t *testing.T) {
+	source := New[string](t.Context())
+	batched := Batch(t.Context(), source, 100, 20*time.Millisecond)
+
+	received := make(chan []string, 1)
+	batched.RegisterReceiver(func(batch []string) {
+		received <- batch
+	})
+
+	source.Send("a")
+	source.Send("b")
+
+	select {
+	case batch := <-received:
+		assert.Equal(t, []string{"a", "b"}, batch)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for batch")
+	}
+}
+
+func TestBatchNoTimeLimit(t *testing.T) {
+	source := New[int](t.Context())
+	batched := Batch(t.Context(), source, 2, 0)
+
+	received := make(chan []int, 1)
+	batched.RegisterReceiver(func(batch []int) {
+		received <- batch
+	})
+
+	source.Send(1)
+
+	select {
+	case <-received:
+		t.Fatal("partial batch should not be delivered without a time threshold")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	source.Send(2)
+
+	select {
+	case batch := <-received:
+		assert.Equal(t, []int{1, 2}, batch)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for batch")
+	}
+}
+
+func TestBatchFlushesOnClose(t *testing.T) {
+	source := New[int](t.Context())
+	batched := Batch(t.Context(), source, 10, 0)
+
+	var received [][]int
+	batched.RegisterReceiver(func(batch []int) {
+		received = append(received, batch)
+	})
+
+	source.Send(1)
+	source.Send(2)
+	require.NoError(t, source.CloseAndDrain(t.Context()))
+	require.NoError(t, batched.Wait(t.Context()))
+
+	assert.Equal(t, [][]int{{1, 2}}, received)
+	assert.True(t, batched.IsClosed())
+}
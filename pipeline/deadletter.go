@@ -0,0 +1,67 @@
+package pipeline
+
+import (
+	"context"
+	"log"
+
+	"github.com/ezex-io/gopkg/errors"
+	"github.com/ezex-io/gopkg/retry"
+)
+
+// Sender is the send side of a pipeline.
+// Any Pipeline[T] can be used where a Sender[T] is expected.
+type Sender[T any] interface {
+	Send(T)
+}
+
+// Failed wraps a message that could not be processed by a receiver,
+// together with the last error returned for it.
+type Failed[T any] struct {
+	Message T
+	Err     error
+}
+
+// RegisterReceiverE registers a receiver that may fail.
+// Each message is attempted once, or according to opts when retry options are given.
+// Messages that still fail are forwarded to deadLetter, or logged when deadLetter is nil.
+//
+// Parameters:
+//   - receiver: The callback function that will process received data
+//   - deadLetter: The pipeline receiving messages that could not be processed
+//   - opts: Retry options applied to each failing message
+//
+// Note: This method is NOT thread-safe; register receivers before sending.
+func (p *pipeline[T]) RegisterReceiverE(receiver func(T) error,
+	deadLetter Sender[Failed[T]], opts ...retry.Options,
+) {
+	p.RegisterReceiver(withRetry(p.ctx, p.name, receiver, deadLetter, p.errs, opts...))
+}
+
+// withRetry adapts a failing receiver into a plain receiver that retries each message
+// and forwards the ones that still fail to deadLetter.
+func withRetry[T any](ctx context.Context, name string, receiver func(T) error,
+	deadLetter Sender[Failed[T]], errs Sender[*errors.Error], opts ...retry.Options,
+) func(T) {
+	// Single attempt unless the caller asks for retries.
+	opts = append([]retry.Options{retry.WithSyncMaxRetries(1)}, opts...)
+
+	return func(data T) {
+		err := retry.ExecuteSync(ctx, func() error {
+			return receiver(data)
+		}, opts...)
+		if err == nil {
+			return
+		}
+		if errs != nil {
+			errs.Send(stageError(CodeReceiverFailed, name, data, err))
+		}
+
+		if deadLetter == nil {
+			log.Printf("pipeline receiver failed: %s, error: %v", name, err)
+
+			return
+		}
+
+		deadLetter.Send(Failed[T]{Message: data, Err: err})
+	}
+}
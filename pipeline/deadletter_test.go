@@ -0,0 +1,102 @@
+package pipeline
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterReceiverEDeadLetter(t *testing.T) {
+	pipe := New[int](t.Context())
+	deadLetter := New[Failed[int]](t.Context())
+
+	failed := make(chan Failed[int], 1)
+	deadLetter.RegisterReceiver(func(f Failed[int]) {
+		failed <- f
+	})
+
+	errOdd := errors.New("odd number")
+	var attempts atomic.Int32
+	pipe.RegisterReceiverE(func(v int) error {
+		attempts.Add(1)
+		if v%2 == 1 {
+			return errOdd
+		}
+
+		return nil
+	}, deadLetter, retry.WithSyncMaxRetries(3), retry.WithSyncRetryDelay(time.Millisecond))
+
+	pipe.Send(2)
+	pipe.Send(3)
+
+	select {
+	case f := <-failed:
+		assert.Equal(t, 3, f.Message)
+		require.ErrorIs(t, f.Err, errOdd)
+	case <-time.After(time.Second):
+		t.Fatal("failed message was not forwarded to dead-letter pipeline")
+	}
+
+	// One attempt for 2 and three attempts for 3.
+	assert.Equal(t, int32(4), attempts.Load())
+}
+
+func TestRegisterReceiverERetrySucceeds(t *testing.T) {
+	pipe := New[string](t.Context())
+	deadLetter := New[Failed[string]](t.Context())
+
+	deadLetter.RegisterReceiver(func(Failed[string]) {
+		t.Error("message should not reach the dead-letter pipeline")
+	})
+
+	done := make(chan struct{})
+	var attempts atomic.Int32
+	pipe.RegisterReceiverE(func(string) error {
+		if attempts.Add(1) < 2 {
+			return errors.New("temporary error")
+		}
+		close(done)
+
+		return nil
+	}, deadLetter, retry.WithSyncMaxRetries(2), retry.WithSyncRetryDelay(time.Millisecond))
+
+	pipe.Send("event")
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("receiver did not succeed after retry")
+	}
+}
+
+func TestRegisterReceiverENoRetryByDefault(t *testing.T) {
+	pipe := New[int](t.Context())
+	deadLetter := New[Failed[int]](t.Context())
+
+	failed := make(chan Failed[int], 1)
+	deadLetter.RegisterReceiver(func(f Failed[int]) {
+		failed <- f
+	})
+
+	var attempts atomic.Int32
+	pipe.RegisterReceiverE(func(int) error {
+		attempts.Add(1)
+
+		return errors.New("failure")
+	}, deadLetter)
+
+	pipe.Send(1)
+
+	select {
+	case <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("failed message was not forwarded to dead-letter pipeline")
+	}
+
+	assert.Equal(t, int32(1), attempts.Load())
+}
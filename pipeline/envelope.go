@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// Envelope wraps a message with the context it was sent with,
+// the time it was sent, and key/value metadata such as trace IDs.
+type Envelope[T any] struct {
+	Ctx      context.Context
+	SentAt   time.Time
+	Metadata map[string]string
+	Message  T
+}
+
+type metadataKey struct{}
+
+// WithMetadata returns a copy of ctx carrying the key/value pair as envelope metadata.
+// Metadata travels with every message sent with the returned context.
+func WithMetadata(ctx context.Context, key, value string) context.Context {
+	metadata := maps.Clone(Metadata(ctx))
+	if metadata == nil {
+		metadata = make(map[string]string, 1)
+	}
+	metadata[key] = value
+
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// Metadata returns the envelope metadata carried by ctx.
+// The returned map must not be modified.
+func Metadata(ctx context.Context) map[string]string {
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+
+	return metadata
+}
+
+// Wrap puts the message in an envelope.
+// The envelope keeps the values of ctx but not its cancellation,
+// so receivers are not affected when the sender's context ends.
+func Wrap[T any](ctx context.Context, msg T) Envelope[T] {
+	return Envelope[T]{
+		Ctx:      context.WithoutCancel(ctx),
+		SentAt:   time.Now(),
+		Metadata: Metadata(ctx),
+		Message:  msg,
+	}
+}
+
+// SendWithContext wraps the message in an envelope and sends it to the pipeline.
+func SendWithContext[T any](ctx context.Context, pipe Sender[Envelope[T]], msg T) {
+	pipe.Send(Wrap(ctx, msg))
+}
+
+// RegisterContextReceiver registers a receiver that gets the context and message of each envelope.
+// Forwarding with the received context propagates metadata across pipeline hops.
+//
+// Note: This method is NOT thread-safe; register receivers before sending.
+func RegisterContextReceiver[T any](pipe Pipeline[Envelope[T]], receiver func(ctx context.Context, msg T)) {
+	pipe.RegisterReceiver(func(env Envelope[T]) {
+		receiver(env.Ctx, env.Message)
+	})
+}
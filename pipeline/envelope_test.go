@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopePropagatesMetadata(t *testing.T) {
+	first := New[Envelope[string]](t.Context())
+	second := New[Envelope[int]](t.Context())
+
+	// Forward to the second hop with the received context.
+	RegisterContextReceiver(first, func(ctx context.Context, msg string) {
+		SendWithContext(ctx, second, len(msg))
+	})
+
+	received := make(chan Envelope[int], 1)
+	second.RegisterReceiver(func(env Envelope[int]) {
+		received <- env
+	})
+
+	ctx := WithMetadata(t.Context(), "trace_id", "abc")
+	ctx = WithMetadata(ctx, "span_id", "def")
+	before := time.Now()
+	SendWithContext(ctx, first, "hello")
+
+	select {
+	case env := <-received:
+		assert.Equal(t, 5, env.Message)
+		assert.Equal(t, map[string]string{"trace_id": "abc", "span_id": "def"}, env.Metadata)
+		assert.Equal(t, "abc", Metadata(env.Ctx)["trace_id"])
+		assert.False(t, env.SentAt.Before(before))
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for envelope")
+	}
+}
+
+func TestEnvelopeDetachedFromCancellation(t *testing.T) {
+	pipe := New[Envelope[int]](t.Context())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	env := Wrap(WithMetadata(ctx, "request_id", "r1"), 1)
+	cancel()
+
+	require.NoError(t, env.Ctx.Err())
+	assert.Equal(t, "r1", env.Metadata["request_id"])
+
+	received := make(chan error, 1)
+	RegisterContextReceiver(pipe, func(ctx context.Context, _ int) {
+		received <- ctx.Err()
+	})
+	pipe.Send(env)
+
+	require.NoError(t, <-received)
+}
+
+func TestWithMetadataDoesNotMutateParent(t *testing.T) {
+	parent := WithMetadata(t.Context(), "key", "parent")
+	child := WithMetadata(parent, "key", "child")
+
+	assert.Equal(t, "parent", Metadata(parent)["key"])
+	assert.Equal(t, "child", Metadata(child)["key"])
+	assert.Nil(t, Metadata(t.Context()))
+}
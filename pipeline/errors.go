@@ -0,0 +1,66 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+const (
+	// CodeReceiverFailed is the code of the errors reported for a message
+	// a RegisterReceiverE receiver still failed to process after its retries.
+	CodeReceiverFailed = "pipeline_receiver_failed"
+
+	// CodeReceiverPanic is the code of the errors reported for a panicking receiver.
+	CodeReceiverPanic = "pipeline_receiver_panic"
+)
+
+// Errors creates a pipeline collecting the structured errors of the stages
+// created with WithErrors, so a single consumer can alert on a failure in any
+// stage of a composed pipeline:
+//
+//	errs := pipeline.Errors(ctx)
+//	errs.RegisterReceiver(alert)
+//
+//	orders := pipeline.New[Order](ctx, pipeline.WithName("orders"), pipeline.WithErrors(errs))
+//
+// Each error has the metadata "operation", the name of the failing stage,
+// and "fingerprint", a hash of the message, to group the failures of the same message.
+// It is named "errors" unless set with WithName.
+func Errors(ctx context.Context, opts ...Option) Pipeline[*errors.Error] {
+	return New[*errors.Error](ctx, append([]Option{WithName("errors")}, opts...)...)
+}
+
+// WithErrors makes the pipeline report to errs the panics of its receivers and the
+// messages its RegisterReceiverE receivers still fail to process after their retries,
+// as errors with the codes CodeReceiverPanic and CodeReceiverFailed, those of the panics
+// wrapping an errors.Panic error.
+// They are still handled by the panic handler and the dead letter pipeline.
+func WithErrors(errs Sender[*errors.Error]) Option {
+	return func(opt *options) {
+		opt.errs = errs
+	}
+}
+
+// stageError returns the error reported for the message data of the stage name.
+func stageError(code, name string, data any, cause error) *errors.Error {
+	message := "receiver failed"
+	if code == CodeReceiverPanic {
+		message = "receiver panicked"
+	}
+
+	return errors.Wrap(cause, code, message).
+		WithMeta("operation", name).
+		WithMeta("fingerprint", fingerprint(data))
+}
+
+// fingerprint returns a stable hash of the Go representation of data.
+func fingerprint(data any) string {
+	hash := fnv.New64a()
+	_, _ = fmt.Fprintf(hash, "%#v", data)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	errs := Errors(t.Context())
+	assert.Equal(t, "errors", errs.Name())
+
+	reported := make(chan *errors.Error, 2)
+	errs.RegisterReceiver(func(err *errors.Error) {
+		reported <- err
+	})
+
+	errInvalid := errors.New("invalid order")
+	orders := New[string](t.Context(), WithName("orders"), WithErrors(errs),
+		WithPanicHandler(func(string, any) {}))
+	orders.RegisterReceiverE(func(string) error { return errInvalid }, nil)
+
+	payments := New[int](t.Context(), WithName("payments"), WithErrors(errs),
+		WithPanicHandler(func(string, any) {}))
+	payments.RegisterReceiver(func(int) { panic("boom") })
+
+	orders.Send("order-1")
+	failed := receiveError(t, reported)
+	assert.Equal(t, CodeReceiverFailed, failed.Code)
+	assert.Equal(t, "orders", failed.Meta["operation"])
+	assert.Equal(t, fingerprint("order-1"), failed.Meta["fingerprint"])
+	require.ErrorIs(t, failed, errInvalid)
+
+	payments.Send(42)
+	panicked := receiveError(t, reported)
+	assert.Equal(t, CodeReceiverPanic, panicked.Code)
+	assert.Equal(t, "payments", panicked.Meta["operation"])
+	assert.Contains(t, panicked.Error(), "boom")
+	require.ErrorIs(t, panicked, errors.ErrPanic)
+	recovered, ok := errors.Recovered(panicked)
+	assert.True(t, ok)
+	assert.Equal(t, "boom", recovered)
+}
+
+func TestFingerprint(t *testing.T) {
+	type order struct {
+		ID    string
+		Total int
+	}
+
+	assert.Equal(t, fingerprint(order{"a", 1}), fingerprint(order{"a", 1}))
+	assert.NotEqual(t, fingerprint(order{"a", 1}), fingerprint(order{"a", 2}))
+	assert.Len(t, fingerprint(1), 16)
+}
+
+func receiveError(t *testing.T, reported <-chan *errors.Error) *errors.Error {
+	t.Helper()
+
+	select {
+	case err := <-reported:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("expected an error to be reported")
+
+		return nil
+	}
+}
@@ -2,7 +2,11 @@ module github.com/ezex-io/gopkg/pipeline
 
 go 1.25.1
 
-require github.com/stretchr/testify v1.11.1
+require (
+	github.com/ezex-io/gopkg/errors v0.0.0-20261016204707-b2f46bf9ce1b
+	github.com/ezex-io/gopkg/retry v0.0.0-20261016204715-9db6111cfe2e
+	github.com/stretchr/testify v1.11.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
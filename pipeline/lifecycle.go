@@ -0,0 +1,89 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// lifecycle tracks when a pipeline has shut down and runs its close callbacks.
+// It is shared by the pipeline implementations.
+type lifecycle struct {
+	name    string
+	onPanic PanicHandler
+	done    chan struct{}
+	once    sync.Once
+
+	mu        sync.Mutex
+	finished  bool
+	callbacks []func()
+}
+
+func newLifecycle(name string, onPanic PanicHandler) *lifecycle {
+	return &lifecycle{
+		name:    name,
+		onPanic: onPanic,
+		done:    make(chan struct{}),
+	}
+}
+
+// finish runs the close callbacks and then marks the pipeline as shut down,
+// so waiters observe the effects of the callbacks. Only the first call has an effect.
+func (l *lifecycle) finish() {
+	l.once.Do(func() {
+		defer close(l.done)
+
+		l.mu.Lock()
+		l.finished = true
+		callbacks := l.callbacks
+		l.callbacks = nil
+		l.mu.Unlock()
+
+		for _, callback := range callbacks {
+			l.run(callback)
+		}
+	})
+}
+
+func (l *lifecycle) run(callback func()) {
+	invoke(l.name, l.onPanic, nil, func(struct{}) { callback() }, struct{}{})
+}
+
+// Done returns a channel that is closed once the pipeline is closed, no receiver
+// callback is running anymore and the close callbacks have returned.
+func (l *lifecycle) Done() <-chan struct{} {
+	return l.done
+}
+
+// OnClose registers a callback to run once the pipeline has shut down, after the
+// receivers have returned. Callbacks run in registration order; a callback registered
+// after shutdown runs immediately. A panicking callback is reported like a panicking receiver.
+// Callbacks run before Done is closed, so they must not wait for the pipeline.
+func (l *lifecycle) OnClose(callback func()) {
+	l.mu.Lock()
+	if !l.finished {
+		l.callbacks = append(l.callbacks, callback)
+		l.mu.Unlock()
+
+		return
+	}
+	l.mu.Unlock()
+
+	l.run(callback)
+}
+
+// Wait blocks until the pipeline is closed and its receive loop has exited,
+// so every receiver callback has returned. It does not close the pipeline itself.
+//
+// Parameters:
+//   - ctx: Bounds how long to wait
+//
+// Returns:
+//   - nil once the receive loop has exited, or the context error
+func (l *lifecycle) Wait(ctx context.Context) error {
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnClose(t *testing.T) {
+	pipe := New[int](t.Context())
+
+	var order []string
+	pipe.RegisterReceiver(func(int) {
+		time.Sleep(20 * time.Millisecond)
+		order = append(order, "receiver")
+	})
+	pipe.OnClose(func() { order = append(order, "first") })
+	pipe.OnClose(func() { order = append(order, "second") })
+
+	pipe.Send(1)
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+	<-pipe.Done()
+
+	assert.Equal(t, []string{"receiver", "first", "second"}, order)
+
+	// Registered after shutdown: runs immediately.
+	ran := false
+	pipe.OnClose(func() { ran = true })
+	assert.True(t, ran)
+}
+
+func TestDone(t *testing.T) {
+	pipe := New[int](t.Context())
+
+	select {
+	case <-pipe.Done():
+		t.Fatal("done before close")
+	default:
+	}
+
+	pipe.Close()
+
+	select {
+	case <-pipe.Done():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for done")
+	}
+}
+
+func TestDone_ParentCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	pipe := New[int](ctx)
+	persistent, err := NewPersistent(ctx, t.TempDir(), JSONCodec[int]{})
+	require.NoError(t, err)
+
+	closed := make(chan struct{}, 2)
+	pipe.OnClose(func() { closed <- struct{}{} })
+	persistent.OnClose(func() { closed <- struct{}{} })
+
+	cancel()
+
+	for range 2 {
+		select {
+		case <-closed:
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the close callbacks")
+		}
+	}
+}
+
+func TestOnClosePanic(t *testing.T) {
+	rec := &panicRecorder{}
+	pipe := New[int](t.Context(), WithPanicHandler(rec.handle))
+
+	ran := make(chan struct{})
+	pipe.OnClose(func() { panic("cleanup failed") })
+	pipe.OnClose(func() { close(ran) })
+	pipe.Close()
+
+	<-ran
+	assert.Equal(t, []any{"", "cleanup failed"}, rec.recovered)
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Merge consumes every source pipeline and forwards their messages into a single pipeline.
+// The merged pipeline is closed, after delivering its buffered messages,
+// once all sources are closed or ctx is done.
+//
+// Merge takes over consumption of the sources: do not register receivers on them.
+//
+// Parameters:
+//   - ctx: The parent context for the merged pipeline
+//   - pipes: The source pipelines to merge
+//
+// Returns:
+//   - A pipeline delivering messages from all sources
+func Merge[T any](ctx context.Context, pipes ...Pipeline[T]) Pipeline[T] {
+	out := New[T](ctx)
+
+	var wg sync.WaitGroup
+	for _, pipe := range pipes {
+		wg.Go(func() {
+			forward(ctx, pipe.UnsafeGetChannel(), out)
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		_ = out.CloseAndDrain(ctx)
+	}()
+
+	return out
+}
+
+// forward sends every message of ch to out until ch is closed or ctx is done.
+func forward[T any](ctx context.Context, ch <-chan T, out Sender[T]) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case data, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			out.Send(data)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package pipeline
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	first := New[int](t.Context())
+	second := New[int](t.Context())
+
+	merged := Merge(t.Context(), first, second)
+
+	var mu sync.Mutex
+	var received []int
+	merged.RegisterReceiver(func(v int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, v)
+	})
+
+	first.Send(1)
+	second.Send(2)
+	first.Send(3)
+	second.Send(4)
+
+	first.Close()
+	assert.False(t, merged.IsClosed(), "merged pipeline should stay open while a source is open")
+
+	second.Close()
+	assert.Eventually(t, merged.IsClosed, time.Second, 5*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(received) == 4
+	}, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	slices.Sort(received)
+	assert.Equal(t, []int{1, 2, 3, 4}, received)
+}
+
+func TestMergeNoSources(t *testing.T) {
+	merged := Merge[int](t.Context())
+
+	assert.Eventually(t, merged.IsClosed, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"log"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+// PanicHandler is called with the name of the pipeline and the value recovered
+// from a panicking receiver, unchanged. errors.Panic turns it into an error
+// with the stack of the panic, if called from the handler.
+type PanicHandler func(name string, recovered any)
+
+// WithPanicHandler sets the handler called when a receiver panics.
+// The panic is recovered either way, so the other receivers and the following
+// messages are still delivered. By default the panic is logged as an errors.Panic
+// error, with its stack trace.
+func WithPanicHandler(handler PanicHandler) Option {
+	if handler == nil {
+		handler = logPanic
+	}
+
+	return func(opt *options) {
+		opt.onPanic = handler
+	}
+}
+
+// logPanic is the default PanicHandler.
+func logPanic(name string, recovered any) {
+	log.Printf("pipeline receiver panic: %s, error: %+v", name, errors.Panic(recovered))
+}
+
+// invoke calls handler with data and reports a panic to onPanic, and to errs
+// unless it is nil, instead of propagating it.
+func invoke[T any](name string, onPanic PanicHandler, errs Sender[*errors.Error], handler func(T), data T) {
+	defer func() {
+		if r := recover(); r != nil {
+			onPanic(name, r)
+			if errs != nil {
+				errs.Send(stageError(CodeReceiverPanic, name, data, errors.Panic(r)))
+			}
+		}
+	}()
+
+	handler(data)
+}
@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicRecorder struct {
+	sync.Mutex
+
+	recovered []any
+}
+
+func (r *panicRecorder) handle(name string, recovered any) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.recovered = append(r.recovered, name, recovered)
+}
+
+func TestReceiverPanicIsolated(t *testing.T) {
+	rec := &panicRecorder{}
+	pipe := New[int](t.Context(), WithName("numbers"), WithPanicHandler(rec.handle))
+
+	var received []int
+	pipe.RegisterReceiver(func(n int) {
+		if n == 2 {
+			panic("bad message")
+		}
+	})
+	pipe.RegisterReceiver(func(n int) {
+		received = append(received, n)
+	})
+
+	pipe.Send(1)
+	pipe.Send(2)
+	pipe.Send(3)
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+
+	assert.Equal(t, []int{1, 2, 3}, received)
+	assert.Equal(t, []any{"numbers", "bad message"}, rec.recovered)
+}
+
+func TestWorkerPanicIsolated(t *testing.T) {
+	rec := &panicRecorder{}
+	pipe := New[int](t.Context(), WithPanicHandler(rec.handle))
+
+	var mu sync.Mutex
+	handled := 0
+	pipe.RegisterWorkerPool(2, func(n int) {
+		if n%2 == 0 {
+			panic(n)
+		}
+		mu.Lock()
+		handled++
+		mu.Unlock()
+	})
+
+	for i := range 10 {
+		pipe.Send(i)
+	}
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+
+	assert.Equal(t, 5, handled)
+	assert.Len(t, rec.recovered, 10)
+}
+
+func TestPersistentReceiverPanicIsolated(t *testing.T) {
+	rec := &panicRecorder{}
+	pipe, err := NewPersistent(t.Context(), t.TempDir(), JSONCodec[int]{}, WithPanicHandler(rec.handle))
+	require.NoError(t, err)
+
+	var received []int
+	pipe.RegisterReceiver(func(n int) {
+		if n == 1 {
+			panic("bad message")
+		}
+		received = append(received, n)
+	})
+
+	pipe.Send(1)
+	pipe.Send(2)
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+
+	assert.Equal(t, []int{2}, received)
+	assert.Len(t, rec.recovered, 2)
+}
+
+func TestDefaultPanicHandler(t *testing.T) {
+	assert.NotPanics(t, func() {
+		invoke("test", logPanic, nil, func(int) { panic("boom") }, 1)
+	})
+
+	cfg := options{}
+	WithPanicHandler(nil)(&cfg)
+	assert.NotNil(t, cfg.onPanic)
+}
@@ -0,0 +1,452 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ezex-io/gopkg/errors"
+	"github.com/ezex-io/gopkg/retry"
+)
+
+var _ Pipeline[int] = &persistent[int]{}
+
+const (
+	spoolExt    = ".msg"
+	spoolTmpExt = ".tmp"
+)
+
+// Codec serializes messages of a persistent pipeline.
+type Codec[T any] interface {
+	Marshal(msg T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec encodes messages as JSON.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Marshal(msg T) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var msg T
+	err := json.Unmarshal(data, &msg)
+
+	return msg, err
+}
+
+// persistent implements the Pipeline interface on top of an on-disk spool.
+// Every message is stored in its own file, named after its sequence number,
+// and removed once all receivers have processed it.
+type persistent[T any] struct {
+	sync.RWMutex
+	*lifecycle
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	name      string
+	dir       string
+	codec     Codec[T]
+	closed    bool
+	started   bool
+	readSeq   uint64
+	writeSeq  uint64
+	notify    chan struct{}
+	ch        chan T
+	jobs      chan spooled[T]
+	workers   sync.WaitGroup
+	limiter   Limiter
+	onPanic   PanicHandler
+	errs      Sender[*errors.Error]
+	receivers []func(T)
+}
+
+// spooled is a decoded message handed to a worker, which removes it from disk once handled.
+type spooled[T any] struct {
+	seq  uint64
+	data T
+}
+
+// NewPersistent creates a pipeline that spools messages to files in dir.
+// Messages not yet processed when the process stops are delivered again, in order,
+// by the next pipeline created on the same directory (at-least-once delivery).
+//
+// Parameters:
+//   - parentCtx: The parent context for lifecycle management
+//   - dir: The spool directory, created if missing; it must not be shared by two pipelines
+//   - codec: Serializes the messages to disk
+//   - opts: Functional options to configure the name and rate limit; the buffer size is not used
+//
+// Returns:
+//   - A new pipeline instance ready for use, or an error if the spool can't be opened
+func NewPersistent[T any](parentCtx context.Context, dir string,
+	codec Codec[T], opts ...Option,
+) (Pipeline[T], error) {
+	cfg := options{onPanic: logPanic}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	readSeq, writeSeq, err := scanSpool(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(parentCtx)
+
+	pipe := &persistent[T]{
+		ctx:       ctx,
+		cancel:    cancel,
+		name:      cfg.name,
+		dir:       dir,
+		codec:     codec,
+		readSeq:   readSeq,
+		writeSeq:  writeSeq,
+		notify:    make(chan struct{}, 1),
+		ch:        make(chan T),
+		lifecycle: newLifecycle(cfg.name, cfg.onPanic),
+		limiter:   cfg.limiter,
+		onPanic:   cfg.onPanic,
+		errs:      cfg.errs,
+	}
+
+	// Without a receive loop, nothing else marks the pipeline as shut down
+	// when the parent context is cancelled.
+	go func() {
+		<-ctx.Done()
+		if !pipe.isStarted() {
+			pipe.finish()
+		}
+	}()
+
+	return pipe, nil
+}
+
+// scanSpool finds the range of pending sequence numbers and removes incomplete writes.
+func scanSpool(dir string) (readSeq, writeSeq uint64, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	first := true
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, spoolTmpExt) {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil {
+				return 0, 0, err
+			}
+
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolExt), 10, 64)
+		if err != nil || !strings.HasSuffix(name, spoolExt) {
+			continue
+		}
+
+		if first || seq < readSeq {
+			readSeq = seq
+		}
+		if first || seq >= writeSeq {
+			writeSeq = seq + 1
+		}
+		first = false
+	}
+
+	return readSeq, writeSeq, nil
+}
+
+// Name returns the identifier name of the pipeline.
+func (p *persistent[T]) Name() string {
+	return p.name
+}
+
+// Send writes data to the spool directory.
+// Encoding and disk errors are logged, since Send does not return an error.
+func (p *persistent[T]) Send(data T) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.closed || p.ctx.Err() != nil {
+		return
+	}
+
+	if err := p.write(p.writeSeq, data); err != nil {
+		log.Printf("pipeline spool error: %s, error: %v", p.name, err)
+
+		return
+	}
+	p.writeSeq++
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// write stores the message atomically: the file is synced under a temporary
+// name and then renamed, so a crash never leaves a partial message behind.
+func (p *persistent[T]) write(seq uint64, data T) error {
+	encoded, err := p.codec.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := p.path(seq, spoolTmpExt)
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(encoded); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, p.path(seq, spoolExt))
+}
+
+func (p *persistent[T]) path(seq uint64, ext string) string {
+	return filepath.Join(p.dir, fmt.Sprintf("%020d%s", seq, ext))
+}
+
+// RegisterReceiver registers a callback to receive every message (one-to-many fan-out).
+// A message is removed from disk once every receiver has returned.
+//
+// Note: This method is NOT thread-safe; register receivers before sending.
+func (p *persistent[T]) RegisterReceiver(receiver func(T)) {
+	p.receivers = append(p.receivers, receiver)
+	p.start()
+}
+
+// RegisterReceiverE registers a receiver that may fail.
+// Messages that still fail after the retries are forwarded to deadLetter.
+//
+// Note: This method is NOT thread-safe; register receivers before sending.
+func (p *persistent[T]) RegisterReceiverE(receiver func(T) error,
+	deadLetter Sender[Failed[T]], opts ...retry.Options,
+) {
+	p.RegisterReceiver(withRetry(p.ctx, p.name, receiver, deadLetter, p.errs, opts...))
+}
+
+// RegisterWorkerPool starts n workers (at least 1) that compete for messages.
+// Messages are handed over in order, and each is removed from disk once its worker
+// has returned, so messages in flight when the process stops are delivered again.
+//
+// Note: This method is NOT thread-safe; register the pool before sending.
+func (p *persistent[T]) RegisterWorkerPool(n int, handler func(T)) {
+	p.jobs = make(chan spooled[T])
+	for range max(n, 1) {
+		p.workers.Go(func() {
+			for job := range p.jobs {
+				invoke(p.name, p.onPanic, p.errs, handler, job.data)
+				p.remove(job.seq)
+			}
+		})
+	}
+	p.start()
+}
+
+// UnsafeGetChannel provides direct read access to the spooled messages.
+// A message is removed from disk as soon as it is read from the channel.
+// WARNING: Messages are only delivered here while no receiver is registered.
+func (p *persistent[T]) UnsafeGetChannel() <-chan T {
+	p.start()
+
+	return p.ch
+}
+
+func (p *persistent[T]) start() {
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.started {
+		p.started = true
+		go p.receiveLoop()
+	}
+}
+
+// receiveLoop delivers spooled messages in order until the pipeline is closed.
+func (p *persistent[T]) receiveLoop() {
+	defer func() {
+		if p.jobs != nil {
+			close(p.jobs)
+			p.workers.Wait()
+		}
+		p.finish()
+	}()
+
+	for {
+		seq, ok, drained := p.next()
+		if drained {
+			return
+		}
+
+		if !ok {
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-p.notify:
+				continue
+			}
+		}
+
+		if !pace(p.ctx, p.name, p.limiter) || !p.deliver(seq) {
+			return
+		}
+
+		p.Lock()
+		p.readSeq++
+		p.Unlock()
+	}
+}
+
+// next returns the sequence number of the oldest pending message.
+// drained is true once the pipeline is closed and no message is pending.
+func (p *persistent[T]) next() (seq uint64, ok, drained bool) {
+	p.RLock()
+	defer p.RUnlock()
+
+	if p.readSeq < p.writeSeq {
+		return p.readSeq, true, false
+	}
+
+	return 0, false, p.closed
+}
+
+// deliver hands the message over and removes it from disk.
+// It returns false if the pipeline was cancelled before the message was handed over.
+func (p *persistent[T]) deliver(seq uint64) bool {
+	path := p.path(seq, spoolExt)
+
+	encoded, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
+	if err != nil {
+		log.Printf("pipeline spool error: %s, error: %v", p.name, err)
+
+		return true
+	}
+
+	data, err := p.codec.Unmarshal(encoded)
+	if err != nil {
+		log.Printf("pipeline spool decode error: %s, seq: %d, error: %v", p.name, seq, err)
+	} else if len(p.receivers) > 0 {
+		for _, handler := range p.receivers {
+			invoke(p.name, p.onPanic, p.errs, handler, data)
+		}
+	} else if p.jobs != nil {
+		select {
+		case <-p.ctx.Done():
+			return false
+		case p.jobs <- spooled[T]{seq: seq, data: data}:
+			// The worker removes the message once handled.
+			return true
+		}
+	} else {
+		select {
+		case <-p.ctx.Done():
+			return false
+		case p.ch <- data:
+		}
+	}
+
+	p.remove(seq)
+
+	return true
+}
+
+func (p *persistent[T]) remove(seq uint64) {
+	if err := os.Remove(p.path(seq, spoolExt)); err != nil {
+		log.Printf("pipeline spool error: %s, error: %v", p.name, err)
+	}
+}
+
+// Close stops the pipeline. Messages still on disk are kept for the next pipeline
+// created on the same directory.
+// This method is idempotent - subsequent calls have no effect.
+func (p *persistent[T]) Close() {
+	p.Lock()
+	defer p.Unlock()
+
+	if !p.closed {
+		p.cancel()
+		p.closed = true
+	}
+}
+
+func (p *persistent[T]) isStarted() bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.started
+}
+
+// CloseAndDrain stops accepting new messages and waits until every spooled message
+// has been delivered. If ctx is done first, the pipeline is cancelled, the remaining
+// messages are kept on disk and the context error is returned.
+// Like Close, this method is idempotent.
+func (p *persistent[T]) CloseAndDrain(ctx context.Context) error {
+	p.Lock()
+	if p.closed {
+		p.Unlock()
+
+		return nil
+	}
+	p.closed = true
+	started := p.started
+	p.Unlock()
+
+	defer p.cancel()
+
+	if !started {
+		p.finish()
+
+		return nil
+	}
+
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsClosed checks if the pipeline has been closed.
+func (p *persistent[T]) IsClosed() bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.closed
+}
@@ -0,0 +1,174 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type notification struct {
+	UserID string `json:"user_id"`
+	Text   string `json:"text"`
+}
+
+func TestPersistentSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	pipe, err := NewPersistent(t.Context(), dir, JSONCodec[notification]{}, WithName("notifications"))
+	require.NoError(t, err)
+	assert.Equal(t, "notifications", pipe.Name())
+
+	pipe.Send(notification{UserID: "1", Text: "first"})
+	pipe.Send(notification{UserID: "2", Text: "second"})
+	pipe.Send(notification{UserID: "3", Text: "third"})
+
+	// No receiver yet: messages stay on disk after close.
+	pipe.Close()
+	assert.True(t, pipe.IsClosed())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+
+	restarted, err := NewPersistent(t.Context(), dir, JSONCodec[notification]{})
+	require.NoError(t, err)
+
+	var received []string
+	restarted.RegisterReceiver(func(n notification) {
+		received = append(received, n.Text)
+	})
+	restarted.Send(notification{UserID: "4", Text: "fourth"})
+
+	require.NoError(t, restarted.CloseAndDrain(t.Context()))
+	assert.Equal(t, []string{"first", "second", "third", "fourth"}, received)
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestPersistentRemovesIncompleteWrites(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000000.tmp"), []byte("{"), 0o600))
+
+	pipe, err := NewPersistent(t.Context(), dir, JSONCodec[notification]{})
+	require.NoError(t, err)
+	defer pipe.Close()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestPersistentSkipsUndecodableMessages(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000007.msg"), []byte("{"), 0o600))
+
+	pipe, err := NewPersistent(t.Context(), dir, JSONCodec[notification]{})
+	require.NoError(t, err)
+
+	received := make(chan notification, 1)
+	pipe.RegisterReceiver(func(n notification) {
+		received <- n
+	})
+	pipe.Send(notification{Text: "valid"})
+
+	select {
+	case n := <-received:
+		assert.Equal(t, "valid", n.Text)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+}
+
+func TestPersistentUnsafeGetChannel(t *testing.T) {
+	pipe, err := NewPersistent(t.Context(), t.TempDir(), JSONCodec[int]{})
+	require.NoError(t, err)
+	defer pipe.Close()
+
+	pipe.Send(42)
+
+	select {
+	case val := <-pipe.UnsafeGetChannel():
+		assert.Equal(t, 42, val)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for channel value")
+	}
+}
+
+func TestPersistentSendAfterClose(t *testing.T) {
+	dir := t.TempDir()
+
+	pipe, err := NewPersistent(t.Context(), dir, JSONCodec[int]{})
+	require.NoError(t, err)
+
+	pipe.Close()
+	pipe.Send(1)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestPersistentWait(t *testing.T) {
+	pipe, err := NewPersistent(t.Context(), t.TempDir(), JSONCodec[int]{})
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	pipe.RegisterReceiver(func(int) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+	})
+
+	pipe.Send(1)
+	<-started
+	pipe.Close()
+
+	require.NoError(t, pipe.Wait(t.Context()))
+	select {
+	case <-finished:
+	default:
+		assert.Fail(t, "receiver is still running")
+	}
+
+	idle, err := NewPersistent(t.Context(), t.TempDir(), JSONCodec[int]{})
+	require.NoError(t, err)
+	idle.Close()
+	require.NoError(t, idle.Wait(t.Context()))
+}
+
+func TestPersistentWorkerPool(t *testing.T) {
+	dir := t.TempDir()
+	pipe, err := NewPersistent(t.Context(), dir, JSONCodec[int]{})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var handled []int
+	pipe.RegisterWorkerPool(3, func(msg int) {
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		handled = append(handled, msg)
+		mu.Unlock()
+	})
+
+	for i := range 10 {
+		pipe.Send(i)
+	}
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+	require.NoError(t, pipe.Wait(t.Context()))
+
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, handled)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -14,6 +14,9 @@ import (
 	"context"
 	"log"
 	"sync"
+
+	"github.com/ezex-io/gopkg/errors"
+	"github.com/ezex-io/gopkg/retry"
 )
 
 var _ Pipeline[int] = &pipeline[int]{}
@@ -27,15 +30,37 @@ type Pipeline[T any] interface {
 	// Close initiates a graceful shutdown of the pipeline.
 	Close()
 
+	// CloseAndDrain stops accepting new messages and delivers everything
+	// already buffered to the receivers before shutting down.
+	CloseAndDrain(ctx context.Context) error
+
 	// IsClosed reports whether the pipeline has been closed.
 	IsClosed() bool
 
+	// Wait blocks until the pipeline is closed and no receiver callback is running anymore.
+	Wait(ctx context.Context) error
+
+	// Done returns a channel that is closed once the pipeline has shut down,
+	// either by Close, CloseAndDrain or the cancellation of its parent context.
+	Done() <-chan struct{}
+
+	// OnClose registers a callback to run once the pipeline has shut down.
+	OnClose(callback func())
+
 	// Send publishes a message to the pipeline (non-blocking).
 	Send(T)
 
 	// RegisterReceiver sets the handler function for incoming messages.
 	RegisterReceiver(func(T))
 
+	// RegisterReceiverE sets a handler that may fail; failed messages are
+	// retried according to the retry options and then sent to the dead-letter pipeline.
+	RegisterReceiverE(receiver func(T) error, deadLetter Sender[Failed[T]], opts ...retry.Options)
+
+	// RegisterWorkerPool consumes messages with n concurrent workers (competing consumers):
+	// each message is handled by exactly one worker. Don't combine it with RegisterReceiver.
+	RegisterWorkerPool(n int, handler func(T))
+
 	// UnsafeGetChannel provides direct read access to the underlying channel
 	// WARNING: This bypasses pipeline management and should be used with caution.
 	UnsafeGetChannel() <-chan T
@@ -45,12 +70,18 @@ type Pipeline[T any] interface {
 // and lifecycle management.
 type pipeline[T any] struct {
 	sync.RWMutex
+	*lifecycle
 
 	ctx       context.Context
 	cancel    context.CancelFunc
 	name      string
 	closed    bool
+	consuming bool
 	ch        chan T
+	consumers sync.WaitGroup
+	limiter   Limiter
+	onPanic   PanicHandler
+	errs      Sender[*errors.Error]
 	receivers []func(T)
 }
 
@@ -59,6 +90,9 @@ const defaultBufferSize = 64
 type options struct {
 	name       string
 	bufferSize int
+	limiter    Limiter
+	onPanic    PanicHandler
+	errs       Sender[*errors.Error]
 }
 
 // Option configures pipeline creation.
@@ -90,6 +124,7 @@ func New[T any](parentCtx context.Context, opts ...Option) Pipeline[T] {
 	cfg := options{
 		bufferSize: defaultBufferSize,
 		name:       "",
+		onPanic:    logPanic,
 	}
 
 	for _, opt := range opts {
@@ -99,13 +134,26 @@ func New[T any](parentCtx context.Context, opts ...Option) Pipeline[T] {
 	ctx, cancel := context.WithCancel(parentCtx)
 
 	pipe := &pipeline[T]{
-		ctx:    ctx,
-		cancel: cancel,
-		name:   cfg.name,
-		closed: false,
-		ch:     make(chan T, cfg.bufferSize),
+		ctx:       ctx,
+		cancel:    cancel,
+		name:      cfg.name,
+		closed:    false,
+		ch:        make(chan T, cfg.bufferSize),
+		lifecycle: newLifecycle(cfg.name, cfg.onPanic),
+		limiter:   cfg.limiter,
+		onPanic:   cfg.onPanic,
+		errs:      cfg.errs,
 	}
 
+	// Without consumers, nothing else marks the pipeline as shut down
+	// when the parent context is cancelled.
+	go func() {
+		<-ctx.Done()
+		if !pipe.isConsuming() {
+			pipe.finish()
+		}
+	}()
+
 	return pipe
 }
 
@@ -152,15 +200,58 @@ func (p *pipeline[T]) Send(data T) {
 // Note: This method is NOT thread-safe; register receivers before sending.
 func (p *pipeline[T]) RegisterReceiver(receiver func(T)) {
 	if len(p.receivers) == 0 {
-		go p.receiveLoop()
+		p.startConsumers(1, p.fanOut)
 	}
 
 	p.receivers = append(p.receivers, receiver)
 }
 
-// receiveLoop continuously listens for incoming data and fans out to all
-// registered receivers until the pipeline is closed.
-func (p *pipeline[T]) receiveLoop() {
+// RegisterWorkerPool starts n workers (at least 1) that compete for messages,
+// so each message is handled by a single worker, concurrently with the others.
+// Close, CloseAndDrain and Wait account for the workers like for receivers.
+//
+// Parameters:
+//   - n: The number of concurrent workers
+//   - handler: The callback function that will process each message
+//
+// Note: This method is NOT thread-safe; register the pool before sending.
+func (p *pipeline[T]) RegisterWorkerPool(n int, handler func(T)) {
+	p.startConsumers(max(n, 1), func(data T) {
+		invoke(p.name, p.onPanic, p.errs, handler, data)
+	})
+}
+
+// startConsumers runs n receive loops calling handle. The first call also starts
+// a watcher that marks the pipeline as finished once every loop has exited.
+func (p *pipeline[T]) startConsumers(n int, handle func(T)) {
+	p.Lock()
+	first := !p.consuming
+	p.consuming = true
+	p.Unlock()
+
+	for range n {
+		p.consumers.Go(func() { p.receiveLoop(handle) })
+	}
+
+	if first {
+		go func() {
+			p.consumers.Wait()
+			p.finish()
+		}()
+	}
+}
+
+// fanOut passes data to every registered receiver. A panicking receiver
+// doesn't prevent the others from receiving the message.
+func (p *pipeline[T]) fanOut(data T) {
+	for _, handler := range p.receivers {
+		invoke(p.name, p.onPanic, p.errs, handler, data)
+	}
+}
+
+// receiveLoop continuously listens for incoming data and passes it to handle
+// until the pipeline is closed.
+func (p *pipeline[T]) receiveLoop(handle func(T)) {
 	for {
 		select {
 		case <-p.ctx.Done():
@@ -172,9 +263,11 @@ func (p *pipeline[T]) receiveLoop() {
 				return
 			}
 
-			for _, handler := range p.receivers {
-				handler(data)
+			if !pace(p.ctx, p.name, p.limiter) {
+				return
 			}
+
+			handle(data)
 		}
 	}
 }
@@ -195,6 +288,56 @@ func (p *pipeline[T]) Close() {
 	}
 }
 
+func (p *pipeline[T]) isConsuming() bool {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.consuming
+}
+
+// CloseAndDrain shuts down the pipeline without losing buffered messages.
+// It stops accepting new sends, closes the channel and waits until the receive
+// loop has delivered every buffered message before cancelling the context.
+// If ctx is done before draining completes, the pipeline is cancelled and
+// the context error is returned.
+// Like Close, this method is idempotent.
+//
+// Parameters:
+//   - ctx: Bounds how long to wait for the buffered messages to be delivered
+//
+// Returns:
+//   - nil if all buffered messages were delivered, or the context error
+func (p *pipeline[T]) CloseAndDrain(ctx context.Context) error {
+	p.Lock()
+	if p.closed {
+		p.Unlock()
+
+		return nil
+	}
+
+	// Close the channel without cancelling, so the receive loop keeps going
+	// until it observes the closed channel.
+	close(p.ch)
+	p.closed = true
+	draining := p.consuming
+	p.Unlock()
+
+	defer p.cancel()
+
+	if !draining {
+		p.finish()
+
+		return nil
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // IsClosed checks if the pipeline has been closed.
 //
 // Returns:
@@ -2,10 +2,12 @@ package pipeline
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClosePipeline(t *testing.T) {
@@ -166,3 +168,130 @@ func TestOptionsConfigurePipeline(t *testing.T) {
 	assert.Equal(t, name, pipe.Name())
 	assert.Equal(t, buf, cap(pipeCh))
 }
+
+func TestCloseAndDrain(t *testing.T) {
+	pipe := New[int](t.Context(), WithBufferSize(10))
+
+	var received []int
+	pipe.RegisterReceiver(func(v int) {
+		time.Sleep(time.Millisecond)
+		received = append(received, v)
+	})
+
+	for i := 0; i < 10; i++ {
+		pipe.Send(i)
+	}
+
+	err := pipe.CloseAndDrain(t.Context())
+	require.NoError(t, err)
+
+	assert.True(t, pipe.IsClosed())
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, received)
+
+	// Second call should be no-op
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+}
+
+func TestCloseAndDrainTimeout(t *testing.T) {
+	pipe := New[int](t.Context(), WithBufferSize(10))
+
+	pipe.RegisterReceiver(func(int) {
+		time.Sleep(50 * time.Millisecond)
+	})
+
+	for i := 0; i < 10; i++ {
+		pipe.Send(i)
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	err := pipe.CloseAndDrain(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, pipe.IsClosed())
+}
+
+func TestWait(t *testing.T) {
+	pipe := New[int](t.Context())
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	pipe.RegisterReceiver(func(int) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(true)
+	})
+
+	pipe.Send(1)
+	<-started
+	pipe.Close()
+
+	require.NoError(t, pipe.Wait(t.Context()))
+	assert.True(t, finished.Load())
+}
+
+func TestWait_NoReceivers(t *testing.T) {
+	pipe := New[int](t.Context())
+	pipe.Close()
+
+	require.NoError(t, pipe.Wait(t.Context()))
+}
+
+func TestWait_Timeout(t *testing.T) {
+	pipe := New[int](t.Context())
+	pipe.RegisterReceiver(func(int) {})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, pipe.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestWorkerPool(t *testing.T) {
+	pipe := New[int](t.Context())
+
+	var handled, running, peak atomic.Int32
+	pipe.RegisterWorkerPool(4, func(int) {
+		current := running.Add(1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		handled.Add(1)
+	})
+
+	for i := range 20 {
+		pipe.Send(i)
+	}
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+	require.NoError(t, pipe.Wait(t.Context()))
+
+	// Each message is handled exactly once, by concurrent workers.
+	assert.Equal(t, int32(20), handled.Load())
+	assert.Equal(t, int32(4), peak.Load())
+}
+
+func TestWorkerPool_Close(t *testing.T) {
+	pipe := New[int](t.Context())
+
+	started := make(chan struct{}, 2)
+	var finished atomic.Int32
+	pipe.RegisterWorkerPool(2, func(int) {
+		started <- struct{}{}
+		time.Sleep(30 * time.Millisecond)
+		finished.Add(1)
+	})
+
+	pipe.Send(1)
+	pipe.Send(2)
+	<-started
+	<-started
+	pipe.Close()
+
+	require.NoError(t, pipe.Wait(t.Context()))
+	assert.Equal(t, int32(2), finished.Load())
+}
@@ -0,0 +1,153 @@
+package pipeline
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxQuarantineIDs is the number of failing messages a quarantine tracks
+// before it forgets those that failed least recently.
+const maxQuarantineIDs = 10000
+
+// Quarantined is a message set aside after failing too many times,
+// with the errors of its failed deliveries.
+type Quarantined[T any] struct {
+	Message       T
+	Failures      int
+	Errors        []error
+	FirstFailedAt time.Time
+	LastFailedAt  time.Time
+}
+
+// Quarantine counts delivery failures per message across retries and redeliveries,
+// and diverts poison messages to a quarantine pipeline, so a single malformed
+// message can't keep a redelivery loop busy forever.
+//
+// A message failing without being redelivered, such as one dead-lettered or dropped,
+// is tracked until it is forgotten: call Forget once its failure is final. Past
+// 10000 messages, the half that failed least recently is forgotten anyway, so
+// the tracking stays bounded under steady failures.
+type Quarantine[T any] struct {
+	mu sync.Mutex
+
+	idFunc      func(T) string
+	maxFailures int
+	maxIDs      int
+	quarantine  Sender[Quarantined[T]]
+	failures    map[string]*Quarantined[T]
+}
+
+// NewQuarantine creates a quarantine for messages identified by idFunc.
+// A message is quarantined when it fails more than maxFailures times.
+//
+// Parameters:
+//   - idFunc: Identifies a message across redeliveries
+//   - maxFailures: The number of failures tolerated before quarantine
+//   - quarantine: The pipeline receiving quarantined messages
+//
+// Returns:
+//   - A quarantine whose Wrap method decorates failing receivers
+func NewQuarantine[T any](idFunc func(T) string, maxFailures int,
+	quarantine Sender[Quarantined[T]],
+) *Quarantine[T] {
+	return &Quarantine[T]{
+		idFunc:      idFunc,
+		maxFailures: max(maxFailures, 0),
+		maxIDs:      maxQuarantineIDs,
+		quarantine:  quarantine,
+		failures:    make(map[string]*Quarantined[T]),
+	}
+}
+
+// Wrap decorates a receiver to count its failures. A success clears the count.
+// When a message fails more than maxFailures times, it is sent to the quarantine
+// pipeline and the wrapped receiver returns nil, which ends any retry or dead-lettering.
+// Use it with RegisterReceiverE, where each retry attempt counts as a failure.
+func (q *Quarantine[T]) Wrap(receiver func(T) error) func(T) error {
+	return func(msg T) error {
+		err := receiver(msg)
+		id := q.idFunc(msg)
+
+		if err == nil {
+			q.Forget(id)
+
+			return nil
+		}
+
+		quarantined, ok := q.record(id, msg, err)
+		if !ok {
+			return err
+		}
+
+		q.quarantine.Send(quarantined)
+
+		return nil
+	}
+}
+
+// record counts a failure, and returns the failure record if the message must be quarantined.
+func (q *Quarantine[T]) record(id string, msg T, err error) (Quarantined[T], bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := q.failures[id]
+	if !ok {
+		q.forgetOldest()
+		entry = &Quarantined[T]{FirstFailedAt: now}
+		q.failures[id] = entry
+	}
+	entry.Message = msg
+	entry.Failures++
+	entry.Errors = append(entry.Errors, err)
+	entry.LastFailedAt = now
+
+	if entry.Failures <= q.maxFailures {
+		return Quarantined[T]{}, false
+	}
+	delete(q.failures, id)
+
+	return *entry, true
+}
+
+// forgetOldest forgets the half of the messages that failed least recently once
+// the quarantine tracks maxIDs of them; those still redelivered fail again soon.
+func (q *Quarantine[T]) forgetOldest() {
+	if len(q.failures) < q.maxIDs {
+		return
+	}
+
+	ids := make([]string, 0, len(q.failures))
+	for id := range q.failures {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		return cmp.Compare(q.failures[a].LastFailedAt.UnixNano(), q.failures[b].LastFailedAt.UnixNano())
+	})
+	for _, id := range ids[:len(ids)-q.maxIDs/2] {
+		delete(q.failures, id)
+	}
+}
+
+// Failures returns the number of failures counted for the message id.
+func (q *Quarantine[T]) Failures(id string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if entry, ok := q.failures[id]; ok {
+		return entry.Failures
+	}
+
+	return 0
+}
+
+// Forget clears the failures counted for the message id, such as when
+// the message is dead-lettered and won't be redelivered.
+func (q *Quarantine[T]) Forget(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.failures, id)
+}
@@ -0,0 +1,153 @@
+package pipeline
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuarantine(t *testing.T) {
+	pipe := New[int](t.Context())
+	deadLetter := New[Failed[int]](t.Context())
+	quarantined := New[Quarantined[int]](t.Context())
+
+	// Failed messages are redelivered, which loops forever without quarantine.
+	deadLetter.RegisterReceiver(func(f Failed[int]) {
+		go pipe.Send(f.Message)
+	})
+
+	result := make(chan Quarantined[int], 1)
+	quarantined.RegisterReceiver(func(q Quarantined[int]) {
+		result <- q
+	})
+
+	errPoison := errors.New("malformed")
+	q := NewQuarantine(strconv.Itoa, 5, quarantined)
+	handled := make(chan int, 10)
+	pipe.RegisterReceiverE(q.Wrap(func(v int) error {
+		if v == 13 {
+			return errPoison
+		}
+		handled <- v
+
+		return nil
+	}), deadLetter, retry.WithSyncMaxRetries(2), retry.WithSyncRetryDelay(time.Millisecond))
+
+	pipe.Send(13)
+	pipe.Send(1)
+
+	select {
+	case got := <-result:
+		assert.Equal(t, 13, got.Message)
+		assert.Equal(t, 6, got.Failures)
+		require.Len(t, got.Errors, 6)
+		require.ErrorIs(t, got.Errors[0], errPoison)
+		assert.False(t, got.LastFailedAt.Before(got.FirstFailedAt))
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for quarantine")
+	}
+
+	assert.Equal(t, 1, <-handled)
+	assert.Zero(t, q.Failures("13"))
+}
+
+func TestQuarantineSuccessResets(t *testing.T) {
+	quarantined := New[Quarantined[string]](t.Context())
+	q := NewQuarantine(func(s string) string { return s }, 1, quarantined)
+
+	fail := true
+	receiver := q.Wrap(func(string) error {
+		if fail {
+			return errors.New("flaky")
+		}
+
+		return nil
+	})
+
+	require.Error(t, receiver("a"))
+	assert.Equal(t, 1, q.Failures("a"))
+
+	fail = false
+	require.NoError(t, receiver("a"))
+	assert.Zero(t, q.Failures("a"))
+
+	fail = true
+	require.Error(t, receiver("a"))
+	require.NoError(t, receiver("a"), "quarantined on the second failure")
+	assert.Zero(t, q.Failures("a"))
+
+	select {
+	case got := <-quarantined.UnsafeGetChannel():
+		assert.Equal(t, 2, got.Failures)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for quarantine")
+	}
+
+	require.Error(t, receiver("b"))
+	q.Forget("b")
+	assert.Zero(t, q.Failures("b"))
+}
+
+func TestQuarantineDeadLettered(t *testing.T) {
+	pipe := New[int](t.Context())
+	deadLetter := New[Failed[int]](t.Context())
+	quarantined := New[Quarantined[int]](t.Context())
+
+	q := NewQuarantine(strconv.Itoa, 5, quarantined)
+	q.maxIDs = 10
+
+	// Dead-lettered messages are dropped, never redelivered.
+	dropped := make(chan int, 100)
+	deadLetter.RegisterReceiver(func(f Failed[int]) {
+		dropped <- f.Message
+	})
+	pipe.RegisterReceiverE(q.Wrap(func(int) error {
+		return errors.New("unavailable")
+	}), deadLetter, retry.WithSyncMaxRetries(2), retry.WithSyncRetryDelay(0))
+
+	for i := range 100 {
+		pipe.Send(i)
+	}
+	for range 100 {
+		select {
+		case <-dropped:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for the dead letters")
+		}
+	}
+
+	q.mu.Lock()
+	tracked := len(q.failures)
+	q.mu.Unlock()
+	assert.LessOrEqual(t, tracked, 10, "the messages failing least recently are forgotten")
+	assert.Positive(t, tracked)
+}
+
+func TestQuarantineForgetDeadLettered(t *testing.T) {
+	pipe := New[int](t.Context())
+	deadLetter := New[Failed[int]](t.Context())
+	quarantined := New[Quarantined[int]](t.Context())
+
+	q := NewQuarantine(strconv.Itoa, 5, quarantined)
+	forgotten := make(chan int, 1)
+	deadLetter.RegisterReceiver(func(f Failed[int]) {
+		q.Forget(strconv.Itoa(f.Message))
+		forgotten <- f.Message
+	})
+	pipe.RegisterReceiverE(q.Wrap(func(int) error {
+		return errors.New("unavailable")
+	}), deadLetter, retry.WithSyncMaxRetries(2), retry.WithSyncRetryDelay(0))
+
+	pipe.Send(7)
+	select {
+	case <-forgotten:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the dead letter")
+	}
+	assert.Zero(t, q.Failures("7"))
+}
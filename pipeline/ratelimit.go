@@ -0,0 +1,175 @@
+package pipeline
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Limiter paces the deliveries of a pipeline. Wait blocks until a delivery is allowed,
+// or returns an error if ctx is done first. *rate.Limiter of golang.org/x/time/rate
+// implements it, as do the limiters returned by NewTokenBucket.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithConsumeRate paces the deliveries of the pipeline with limiter, such as to call
+// an external API at most 50 times per second, instead of receivers sleeping
+// and backing up the channel:
+//
+//	notifications := pipeline.New[Notification](ctx,
+//		pipeline.WithConsumeRate(pipeline.NewTokenBucket(50, 10)))
+//
+// Like with WithDeliveryRateLimit, which it replaces, excess messages stay buffered,
+// and the limit applies to the pipeline as a whole. A limiter shared by several
+// pipelines paces them together. A nil limiter disables the limit.
+func WithConsumeRate(limiter Limiter) Option {
+	return func(opt *options) {
+		opt.limiter = limiter
+	}
+}
+
+// WithDeliveryRateLimit invokes the receivers for at most n messages in any window of
+// the given duration. Excess messages stay buffered in the pipeline, so senders block
+// once the buffer is full. A non-positive n or per disables the limit.
+// It replaces WithConsumeRate.
+//
+// The limit applies to the pipeline as a whole: with several receivers, each of them
+// is invoked at most n times per window, and a worker pool shares the limit between workers.
+func WithDeliveryRateLimit(n int, per time.Duration) Option {
+	return func(opt *options) {
+		opt.limiter = nil
+		if limiter := newRateLimiter(n, per); limiter != nil {
+			opt.limiter = limiter
+		}
+	}
+}
+
+// rateLimiter allows at most len(times) events in any window of per.
+// It remembers the time of the last events in a ring. Concurrent callers of wait
+// are served one at a time.
+type rateLimiter struct {
+	sync.Mutex
+
+	per   time.Duration
+	times []time.Time
+	next  int
+}
+
+// newRateLimiter returns nil if the limit is disabled.
+func newRateLimiter(n int, per time.Duration) *rateLimiter {
+	if n <= 0 || per <= 0 {
+		return nil
+	}
+
+	return &rateLimiter{
+		per:   per,
+		times: make([]time.Time, n),
+	}
+}
+
+// Wait implements Limiter.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if !l.wait(ctx) {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// wait blocks until another event is allowed, and records it.
+// It returns false if ctx is done first. A nil limiter never waits.
+func (l *rateLimiter) wait(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	oldest := l.times[l.next]
+	if !oldest.IsZero() {
+		if delay := time.Until(oldest.Add(l.per)); delay > 0 {
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			select {
+			case <-ctx.Done():
+				return false
+			case <-timer.C:
+			}
+		}
+	}
+
+	l.times[l.next] = time.Now()
+	l.next = (l.next + 1) % len(l.times)
+
+	return true
+}
+
+// tokenBucket is a Limiter allowing bursts, implemented with the generic cell
+// rate algorithm: tat is the time the bucket will be full again.
+type tokenBucket struct {
+	sync.Mutex
+
+	interval  time.Duration
+	tolerance time.Duration
+	tat       time.Time
+}
+
+// NewTokenBucket returns a Limiter allowing perSecond deliveries per second on average,
+// and bursts of up to burst deliveries (at least 1) after an idle period.
+// Concurrent callers of Wait are served one at a time. It returns nil, which disables
+// the limit, if perSecond is not positive.
+func NewTokenBucket(perSecond float64, burst int) Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	interval := time.Duration(float64(time.Second) / perSecond)
+
+	return &tokenBucket{
+		interval:  interval,
+		tolerance: time.Duration(max(burst, 1)-1) * interval,
+	}
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	b.Lock()
+	defer b.Unlock()
+
+	tat := b.tat
+	if now := time.Now(); tat.Before(now) {
+		tat = now
+	}
+
+	if delay := time.Until(tat.Add(-b.tolerance)); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	b.tat = tat.Add(b.interval)
+
+	return nil
+}
+
+// pace waits for limiter, if any, and reports whether ctx is still alive.
+// A limiter failing for another reason is logged and doesn't hold deliveries back.
+func pace(ctx context.Context, name string, limiter Limiter) bool {
+	if limiter == nil {
+		return true
+	}
+
+	if err := limiter.Wait(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("pipeline rate limiter error: %s, error: %v", name, err)
+	}
+
+	return ctx.Err() == nil
+}
@@ -0,0 +1,152 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryRateLimit(t *testing.T) {
+	pipe := New[int](t.Context(), WithDeliveryRateLimit(2, 100*time.Millisecond))
+
+	var mu sync.Mutex
+	var times []time.Time
+	pipe.RegisterReceiver(func(int) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+	})
+
+	start := time.Now()
+	for i := range 6 {
+		pipe.Send(i)
+	}
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+
+	// Receivers observe the times slightly after the limiter recorded them.
+	const slack = 5 * time.Millisecond
+	require.Len(t, times, 6)
+	// Two messages per window: the 3rd waits one window, the 5th two windows.
+	assert.GreaterOrEqual(t, times[2].Sub(start), 100*time.Millisecond-slack)
+	assert.GreaterOrEqual(t, times[4].Sub(start), 200*time.Millisecond-slack)
+	for i := 2; i < len(times); i++ {
+		assert.GreaterOrEqual(t, times[i].Sub(times[i-2]), 100*time.Millisecond-slack)
+	}
+}
+
+func TestDeliveryRateLimit_Disabled(t *testing.T) {
+	assert.Nil(t, newRateLimiter(0, time.Second))
+	assert.Nil(t, newRateLimiter(1, 0))
+
+	var limiter *rateLimiter
+	assert.True(t, limiter.wait(t.Context()))
+}
+
+func TestDeliveryRateLimit_Cancel(t *testing.T) {
+	limiter := newRateLimiter(1, time.Hour)
+	assert.True(t, limiter.wait(t.Context()))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	assert.False(t, limiter.wait(ctx))
+}
+
+func TestDeliveryRateLimit_Persistent(t *testing.T) {
+	pipe, err := NewPersistent[int](t.Context(), t.TempDir(), JSONCodec[int]{},
+		WithDeliveryRateLimit(1, 50*time.Millisecond))
+	require.NoError(t, err)
+
+	received := make(chan time.Time, 3)
+	pipe.RegisterReceiver(func(int) { received <- time.Now() })
+
+	for i := range 3 {
+		pipe.Send(i)
+	}
+	require.NoError(t, pipe.CloseAndDrain(t.Context()))
+
+	const slack = 5 * time.Millisecond
+	first, second, third := <-received, <-received, <-received
+	assert.GreaterOrEqual(t, second.Sub(first), 50*time.Millisecond-slack)
+	assert.GreaterOrEqual(t, third.Sub(second), 50*time.Millisecond-slack)
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(20, 3) // one every 50ms, bursts of 3
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, bucket.Wait(t.Context()))
+	}
+	assert.Less(t, time.Since(start), 25*time.Millisecond, "the burst is immediate")
+
+	require.NoError(t, bucket.Wait(t.Context()))
+	require.NoError(t, bucket.Wait(t.Context()))
+	assert.GreaterOrEqual(t, time.Since(start), 95*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	require.ErrorIs(t, bucket.Wait(ctx), context.Canceled)
+
+	assert.Nil(t, NewTokenBucket(0, 1))
+}
+
+func TestConsumeRate(t *testing.T) {
+	// Two pipelines sharing a limiter are paced together.
+	limiter := NewTokenBucket(20, 1)
+	first := New[int](t.Context(), WithConsumeRate(limiter))
+	second := New[int](t.Context(), WithConsumeRate(limiter))
+
+	var mu sync.Mutex
+	var times []time.Time
+	record := func(int) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+	}
+	first.RegisterReceiver(record)
+	second.RegisterReceiver(record)
+
+	start := time.Now()
+	for i := range 3 {
+		first.Send(i)
+		second.Send(i)
+	}
+	require.NoError(t, first.CloseAndDrain(t.Context()))
+	require.NoError(t, second.CloseAndDrain(t.Context()))
+
+	require.Len(t, times, 6)
+	assert.GreaterOrEqual(t, time.Since(start), 245*time.Millisecond)
+}
+
+// failingLimiter fails without the context being done.
+type failingLimiter struct{}
+
+func (failingLimiter) Wait(context.Context) error { return context.DeadlineExceeded }
+
+func TestConsumeRate_LimiterError(t *testing.T) {
+	pipe := New[int](t.Context(), WithConsumeRate(failingLimiter{}))
+
+	received := make(chan int, 1)
+	pipe.RegisterReceiver(func(i int) { received <- i })
+	pipe.Send(1)
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("a failing limiter held the delivery back")
+	}
+}
+
+func TestConsumeRate_ReplacesDeliveryRateLimit(t *testing.T) {
+	cfg := options{}
+	WithDeliveryRateLimit(1, time.Hour)(&cfg)
+	WithConsumeRate(nil)(&cfg)
+	assert.Nil(t, cfg.limiter)
+
+	WithDeliveryRateLimit(0, time.Hour)(&cfg)
+	assert.Nil(t, cfg.limiter, "a disabled limit is a nil Limiter")
+}
@@ -0,0 +1,140 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// KeyFunc extracts the routing key of a message.
+type KeyFunc[K comparable, T any] func(T) K
+
+// Router dispatches messages to per-key sub-pipelines.
+// Messages sharing a key are delivered in order by a single sub-pipeline,
+// while messages with different keys are processed in parallel.
+// Sub-pipelines are created on the first message for a key.
+type Router[K comparable, T any] struct {
+	sync.Mutex
+
+	ctx      context.Context
+	keyFunc  KeyFunc[K, T]
+	receiver func(K, T)
+	opts     []Option
+	name     string
+	closed   bool
+	routes   map[K]Pipeline[T]
+}
+
+// NewRouter creates a router that delivers each message to receiver,
+// preserving ordering per key.
+//
+// Parameters:
+//   - ctx: The parent context of every sub-pipeline
+//   - keyFunc: Extracts the routing key of a message
+//   - receiver: The callback invoked with the key and the message
+//   - opts: Functional options applied to every sub-pipeline
+//
+// Returns:
+//   - A new router ready for use
+func NewRouter[K comparable, T any](ctx context.Context,
+	keyFunc KeyFunc[K, T], receiver func(K, T), opts ...Option,
+) *Router[K, T] {
+	cfg := options{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Router[K, T]{
+		ctx:      ctx,
+		keyFunc:  keyFunc,
+		receiver: receiver,
+		opts:     opts,
+		name:     cfg.name,
+		routes:   make(map[K]Pipeline[T]),
+	}
+}
+
+// Send routes the message to the sub-pipeline of its key.
+// It can be registered as a receiver of another pipeline to route its messages.
+func (r *Router[K, T]) Send(msg T) {
+	route := r.route(r.keyFunc(msg))
+	if route == nil {
+		// send on closed router
+		return
+	}
+
+	route.Send(msg)
+}
+
+// Remove closes the sub-pipeline of the key and forgets it.
+// A later message with the same key creates a new sub-pipeline.
+func (r *Router[K, T]) Remove(key K) {
+	r.Lock()
+	route, ok := r.routes[key]
+	delete(r.routes, key)
+	r.Unlock()
+
+	if ok {
+		route.Close()
+	}
+}
+
+// Len returns the number of active sub-pipelines.
+func (r *Router[K, T]) Len() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return len(r.routes)
+}
+
+// Close closes every sub-pipeline. Subsequent sends are ignored.
+func (r *Router[K, T]) Close() {
+	for _, route := range r.shutdown() {
+		route.Close()
+	}
+}
+
+// CloseAndDrain closes every sub-pipeline after delivering its buffered messages.
+// It returns the context error if ctx is done before all of them are drained.
+func (r *Router[K, T]) CloseAndDrain(ctx context.Context) error {
+	var err error
+	for _, route := range r.shutdown() {
+		if drainErr := route.CloseAndDrain(ctx); drainErr != nil {
+			err = drainErr
+		}
+	}
+
+	return err
+}
+
+func (r *Router[K, T]) shutdown() map[K]Pipeline[T] {
+	r.Lock()
+	defer r.Unlock()
+
+	routes := r.routes
+	r.routes = make(map[K]Pipeline[T])
+	r.closed = true
+
+	return routes
+}
+
+func (r *Router[K, T]) route(key K) Pipeline[T] {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	route, ok := r.routes[key]
+	if !ok {
+		opts := append(r.opts[:len(r.opts):len(r.opts)], WithName(fmt.Sprintf("%s/%v", r.name, key)))
+		route = New[T](r.ctx, opts...)
+		route.RegisterReceiver(func(msg T) {
+			r.receiver(key, msg)
+		})
+		r.routes[key] = route
+	}
+
+	return route
+}
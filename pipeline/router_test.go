@@ -0,0 +1,113 @@
+package pipeline
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type accountEvent struct {
+	account string
+	seq     int
+}
+
+func TestRouterPreservesOrderPerKey(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]int{}
+
+	router := NewRouter(t.Context(), func(e accountEvent) string {
+		return e.account
+	}, func(account string, e accountEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received[account] = append(received[account], e.seq)
+	}, WithName("accounts"))
+
+	for i := 0; i < 50; i++ {
+		router.Send(accountEvent{account: "alice", seq: i})
+		router.Send(accountEvent{account: "bob", seq: i})
+	}
+
+	assert.Equal(t, 2, router.Len())
+	require.NoError(t, router.CloseAndDrain(t.Context()))
+
+	expected := make([]int, 50)
+	for i := range expected {
+		expected[i] = i
+	}
+	assert.Equal(t, expected, received["alice"])
+	assert.Equal(t, expected, received["bob"])
+}
+
+func TestRouterParallelAcrossKeys(t *testing.T) {
+	release := make(chan struct{})
+	done := make(chan string, 2)
+
+	router := NewRouter(t.Context(), func(e accountEvent) string {
+		return e.account
+	}, func(account string, _ accountEvent) {
+		if account == "slow" {
+			<-release
+		}
+		done <- account
+	})
+
+	router.Send(accountEvent{account: "slow"})
+	router.Send(accountEvent{account: "fast"})
+
+	select {
+	case account := <-done:
+		assert.Equal(t, "fast", account)
+	case <-time.After(time.Second):
+		t.Fatal("fast key was blocked by slow key")
+	}
+
+	close(release)
+	assert.Equal(t, "slow", <-done)
+}
+
+func TestRouterAsReceiver(t *testing.T) {
+	source := New[accountEvent](t.Context())
+
+	received := make(chan accountEvent, 1)
+	router := NewRouter(t.Context(), func(e accountEvent) string {
+		return e.account
+	}, func(_ string, e accountEvent) {
+		received <- e
+	})
+	source.RegisterReceiver(router.Send)
+
+	source.Send(accountEvent{account: "alice", seq: 7})
+
+	select {
+	case e := <-received:
+		assert.Equal(t, 7, e.seq)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for routed message")
+	}
+}
+
+func TestRouterRemoveAndClose(t *testing.T) {
+	router := NewRouter(t.Context(), func(v int) int {
+		return v % 2
+	}, func(int, int) {})
+
+	router.Send(1)
+	router.Send(2)
+	assert.Equal(t, 2, router.Len())
+
+	router.Remove(1)
+	assert.Equal(t, 1, router.Len())
+
+	router.Close()
+	assert.Equal(t, 0, router.Len())
+
+	assert.NotPanics(t, func() {
+		router.Send(3)
+	})
+	assert.Equal(t, 0, router.Len())
+}
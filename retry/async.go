@@ -3,6 +3,8 @@ package retry
 import (
 	"context"
 	"time"
+
+	"github.com/ezex-io/gopkg/errors"
 )
 
 type (
@@ -14,13 +16,9 @@ type AsyncOptions func(*asyncOptions)
 type asyncOptions struct {
 	maxRetries int
 	retryDelay time.Duration
-}
-
-func defaultAsyncOpts() *asyncOptions {
-	return &asyncOptions{
-		maxRetries: 3,
-		retryDelay: 2 * time.Second,
-	}
+	dryRun     bool
+	observer   Observer
+	name       string
 }
 
 func WithAsyncMaxRetries(maxRetries int) AsyncOptions {
@@ -36,8 +34,9 @@ func WithAsyncRetryDelay(retryDelay time.Duration) AsyncOptions {
 }
 
 // ExecuteAsync executes a function asynchronously with retry logic
-// It respects context cancellation and timeout
+// It respects context cancellation and timeout, reported as errors.ErrCanceled or errors.ErrTimeout
 // onSuccess and onFailure callbacks will be called exactly once.
+// An invalid configuration is reported to onFailure as a *ConfigError without running the task.
 func ExecuteAsync(
 	ctx context.Context,
 	task SyncTask,
@@ -49,19 +48,36 @@ func ExecuteAsync(
 		opt(conf)
 	}
 
+	if err := conf.config().Validate(); err != nil {
+		if onFailure != nil {
+			onFailure(err)
+		}
+
+		return
+	}
+
 	go func() {
 		var err error
 		for attempt := 0; attempt < conf.maxRetries; attempt++ {
 			err = task()
 			if err == nil {
+				observeHealth(conf.name, nil)
+
 				return
 			}
 
+			if conf.dryRun {
+				observeDryRun(conf.observer, err, conf.maxRetries, conf.retryDelay)
+
+				break
+			}
+
 			if attempt < conf.maxRetries-1 {
 				select {
 				case <-ctx.Done():
+					observeHealth(conf.name, errors.FromContext(err, ctx.Err()))
 					if onFailure != nil {
-						onFailure(ctx.Err())
+						onFailure(errors.FromContext(err, ctx.Err()))
 					}
 
 					return
@@ -72,6 +88,7 @@ func ExecuteAsync(
 		}
 
 		// All retries exhausted
+		observeHealth(conf.name, err)
 		onFailure(err)
 	}()
 }
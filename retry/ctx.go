@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+type (
+	SyncTaskCtx         func(ctx context.Context) error
+	SyncTaskCtxT[T any] func(ctx context.Context) (T, error)
+)
+
+// WithSyncAttemptTimeout bounds the context of each attempt of ExecuteSyncCtx
+// and ExecuteSyncCtxT, so a hung attempt is abandoned and retried instead of
+// holding up the whole retry loop. Zero, the default, leaves attempts unbounded.
+func WithSyncAttemptTimeout(timeout time.Duration) Options {
+	return func(o *syncOptions) {
+		o.attemptTimeout = timeout
+	}
+}
+
+// ExecuteSyncCtx is like ExecuteSync, but passes each attempt its own context,
+// derived from ctx and bounded by WithSyncAttemptTimeout if set:
+//
+//	err := retry.ExecuteSyncCtx(ctx, func(ctx context.Context) error {
+//		for _, batch := range batches {
+//			if err := retry.CheckCtx(ctx); err != nil {
+//				return err // stop between batches, not after the last one
+//			}
+//			if err := send(ctx, batch); err != nil {
+//				return err
+//			}
+//		}
+//
+//		return nil
+//	}, retry.WithSyncAttemptTimeout(30*time.Second))
+func ExecuteSyncCtx(ctx context.Context, task SyncTaskCtx, opts ...Options) error {
+	_, err := ExecuteSyncCtxT(ctx, func(ctx context.Context) (any, error) {
+		return nil, task(ctx)
+	}, opts...)
+
+	return err
+}
+
+// CheckCtx returns nil while ctx is live, and an error wrapping errors.ErrCanceled
+// or errors.ErrTimeout once it is done. Long-running tasks call it at safe points,
+// such as between batches, to stop cooperatively when their attempt is abandoned.
+func CheckCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.FromContext(nil, err)
+	}
+
+	return nil
+}
+
+// runAttempt runs one attempt of task with its own context, bounded by timeout if positive.
+func runAttempt[T any](ctx context.Context, timeout time.Duration, task SyncTaskCtxT[T]) (T, error) {
+	if timeout <= 0 {
+		return task(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return task(attemptCtx)
+}
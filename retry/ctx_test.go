@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ezerrors "github.com/ezex-io/gopkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCtx(t *testing.T) {
+	require.NoError(t, CheckCtx(t.Context()))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	err := CheckCtx(ctx)
+	require.ErrorIs(t, err, ezerrors.ErrCanceled)
+	require.ErrorIs(t, err, context.Canceled)
+
+	ctx, cancel = context.WithTimeout(t.Context(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	require.ErrorIs(t, CheckCtx(ctx), ezerrors.ErrTimeout)
+}
+
+func TestExecuteSyncCtxAttemptTimeout(t *testing.T) {
+	attempts := 0
+	err := ExecuteSyncCtx(t.Context(), func(ctx context.Context) error {
+		attempts++
+		if attempts == 1 {
+			// A hung first attempt is abandoned when its own context times out.
+			<-ctx.Done()
+		}
+
+		return CheckCtx(ctx)
+	}, WithSyncMaxRetries(3), WithSyncRetryDelay(time.Millisecond), WithSyncAttemptTimeout(10*time.Millisecond))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestExecuteSyncCtxT(t *testing.T) {
+	got, err := ExecuteSyncCtxT(t.Context(), func(ctx context.Context) (int, error) {
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline, "expected no attempt deadline by default")
+
+		return 42, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 42, got)
+}
+
+func TestExecuteSyncCtxCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+
+	attempts := 0
+	err := ExecuteSyncCtx(ctx, func(ctx context.Context) error {
+		attempts++
+		cancel()
+
+		return CheckCtx(ctx)
+	}, WithSyncMaxRetries(3), WithSyncRetryDelay(time.Second))
+
+	require.ErrorIs(t, err, ezerrors.ErrCanceled)
+	assert.Equal(t, 1, attempts, "expected no retry once the parent context is canceled")
+}
+
+func TestAttemptTimeoutValidation(t *testing.T) {
+	err := ExecuteSyncCtx(t.Context(), func(context.Context) error {
+		t.Error("expected the task not to run")
+
+		return nil
+	}, WithSyncAttemptTimeout(-time.Second))
+
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "attemptTimeout", configErr.Field)
+	require.Error(t, ValidateSync(WithSyncAttemptTimeout(-time.Second)))
+}
@@ -0,0 +1,103 @@
+package retry
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Process-wide defaults set by SetDefaults and SetAsyncDefaults.
+var (
+	defaultsMu    sync.RWMutex
+	syncDefaults  []Options
+	asyncDefaults []AsyncOptions
+)
+
+// SetDefaults replaces the defaults of ExecuteSync and ExecuteSyncT for the whole process,
+// so a service can set its policy once at startup and every call site without options
+// inherits it. Options given to a call still take precedence.
+// Invalid options are rejected with a *ConfigError and the defaults are left unchanged.
+func SetDefaults(opts ...Options) error {
+	conf := builtinSyncOpts()
+	for _, opt := range opts {
+		opt(conf)
+	}
+	if err := conf.config().Validate(); err != nil {
+		return err
+	}
+
+	defaultsMu.Lock()
+	syncDefaults = slices.Clone(opts)
+	defaultsMu.Unlock()
+
+	return nil
+}
+
+// SetAsyncDefaults replaces the defaults of ExecuteAsync for the whole process, like SetDefaults.
+func SetAsyncDefaults(opts ...AsyncOptions) error {
+	conf := builtinAsyncOpts()
+	for _, opt := range opts {
+		opt(conf)
+	}
+	if err := conf.config().Validate(); err != nil {
+		return err
+	}
+
+	defaultsMu.Lock()
+	asyncDefaults = slices.Clone(opts)
+	defaultsMu.Unlock()
+
+	return nil
+}
+
+// ResetDefaults restores the built-in defaults, typically in test cleanups:
+//
+//	t.Cleanup(retry.ResetDefaults)
+func ResetDefaults() {
+	defaultsMu.Lock()
+	syncDefaults = nil
+	asyncDefaults = nil
+	defaultsMu.Unlock()
+}
+
+func builtinSyncOpts() *syncOptions {
+	return &syncOptions{
+		maxRetries: 3,
+		retryDelay: 2 * time.Second,
+		observer:   LogObserver,
+	}
+}
+
+func builtinAsyncOpts() *asyncOptions {
+	return &asyncOptions{
+		maxRetries: 3,
+		retryDelay: 2 * time.Second,
+		observer:   LogObserver,
+	}
+}
+
+func defaultSyncOpts() *syncOptions {
+	conf := builtinSyncOpts()
+
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+
+	for _, opt := range syncDefaults {
+		opt(conf)
+	}
+
+	return conf
+}
+
+func defaultAsyncOpts() *asyncOptions {
+	conf := builtinAsyncOpts()
+
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+
+	for _, opt := range asyncDefaults {
+		opt(conf)
+	}
+
+	return conf
+}
@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDefaults(t *testing.T) {
+	t.Cleanup(ResetDefaults)
+
+	require.NoError(t, SetDefaults(WithSyncMaxRetries(5), WithSyncRetryDelay(time.Millisecond)))
+
+	calls := 0
+	err := ExecuteSync(t.Context(), func() error {
+		calls++
+
+		return errors.New("fail")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 5, calls, "call sites without options inherit the defaults")
+
+	calls = 0
+	_ = ExecuteSync(t.Context(), func() error {
+		calls++
+
+		return errors.New("fail")
+	}, WithSyncMaxRetries(2))
+	assert.Equal(t, 2, calls, "call site options take precedence")
+}
+
+func TestSetDefaults_Invalid(t *testing.T) {
+	t.Cleanup(ResetDefaults)
+
+	require.NoError(t, SetDefaults(WithSyncMaxRetries(4)))
+
+	err := SetDefaults(WithSyncMaxRetries(0))
+	require.ErrorIs(t, err, ErrInvalidConfig)
+
+	conf := defaultSyncOpts()
+	assert.Equal(t, 4, conf.maxRetries, "invalid defaults leave the previous ones in place")
+}
+
+func TestSetAsyncDefaults(t *testing.T) {
+	t.Cleanup(ResetDefaults)
+
+	require.NoError(t, SetAsyncDefaults(WithAsyncMaxRetries(4), WithAsyncRetryDelay(time.Millisecond)))
+	require.ErrorIs(t, SetAsyncDefaults(WithAsyncRetryDelay(-time.Second)), ErrInvalidConfig)
+
+	calls := 0
+	done := make(chan error, 1)
+	ExecuteAsync(t.Context(), func() error {
+		calls++
+
+		return errors.New("fail")
+	}, func(err error) { done <- err })
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.Equal(t, 4, calls)
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for onFailure")
+	}
+
+	assert.Equal(t, 3, builtinSyncOpts().maxRetries, "sync defaults are independent")
+	assert.Equal(t, 3, defaultSyncOpts().maxRetries)
+}
+
+func TestResetDefaults(t *testing.T) {
+	require.NoError(t, SetDefaults(WithSyncMaxRetries(7)))
+	require.NoError(t, SetAsyncDefaults(WithAsyncMaxRetries(7)))
+
+	ResetDefaults()
+
+	assert.Equal(t, 3, defaultSyncOpts().maxRetries)
+	assert.Equal(t, 3, defaultAsyncOpts().maxRetries)
+}
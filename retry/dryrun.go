@@ -0,0 +1,105 @@
+package retry
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// DryRunPlan describes what the retry policy would have done after a failed first attempt.
+type DryRunPlan struct {
+	// Err is the error of the first attempt.
+	Err error
+
+	// Attempts is the total number of attempts the policy allows.
+	Attempts int
+
+	// Waits are the delays the policy would wait before each retry.
+	Waits []time.Duration
+
+	// GiveUpAfter is the total waiting time before the last attempt.
+	GiveUpAfter time.Duration
+
+	// GiveUpAt is when the last attempt would have started, ignoring the task duration.
+	GiveUpAt time.Time
+}
+
+func (p DryRunPlan) String() string {
+	return fmt.Sprintf("first attempt failed: %v; would retry %d more time(s) every %v, giving up after %v at %s",
+		p.Err, p.Attempts-1, p.retryDelay(), p.GiveUpAfter, p.GiveUpAt.Format(time.RFC3339))
+}
+
+func (p DryRunPlan) retryDelay() time.Duration {
+	if len(p.Waits) == 0 {
+		return 0
+	}
+
+	return p.Waits[0]
+}
+
+// Observer is notified of what a retry policy in dry-run mode would have done.
+type Observer interface {
+	OnDryRun(plan DryRunPlan)
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc func(plan DryRunPlan)
+
+// OnDryRun calls f(plan).
+func (f ObserverFunc) OnDryRun(plan DryRunPlan) {
+	f(plan)
+}
+
+// LogObserver logs dry-run plans with the standard logger. It is the default observer.
+var LogObserver Observer = ObserverFunc(func(plan DryRunPlan) {
+	log.Printf("retry dry-run: %s", plan)
+})
+
+// WithDryRun executes the first attempt only. If it fails, the observer is told what
+// the policy would have done, and the error is returned without retrying.
+// Use it to validate a new retry configuration in production before enabling it.
+func WithDryRun() Options {
+	return func(o *syncOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithObserver sets the observer notified in dry-run mode. Defaults to LogObserver;
+// a nil observer fails validation in dry-run mode.
+func WithObserver(observer Observer) Options {
+	return func(o *syncOptions) {
+		o.observer = observer
+	}
+}
+
+// WithAsyncDryRun is WithDryRun for ExecuteAsync.
+func WithAsyncDryRun() AsyncOptions {
+	return func(o *asyncOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithAsyncObserver is WithObserver for ExecuteAsync.
+func WithAsyncObserver(observer Observer) AsyncOptions {
+	return func(o *asyncOptions) {
+		o.observer = observer
+	}
+}
+
+// observeDryRun reports the plan of a policy whose first attempt failed with err.
+func observeDryRun(observer Observer, err error, maxRetries int, retryDelay time.Duration) {
+	waits := make([]time.Duration, 0, max(maxRetries-1, 0))
+	var total time.Duration
+	for attempt := 1; attempt < maxRetries; attempt++ {
+		waits = append(waits, retryDelay)
+		total += retryDelay
+	}
+
+	observer.OnDryRun(DryRunPlan{
+		Err:         err,
+		Attempts:    maxRetries,
+		Waits:       waits,
+		GiveUpAfter: total,
+		GiveUpAt:    time.Now().Add(total),
+	})
+}
@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteSync_DryRunFailure(t *testing.T) {
+	taskErr := errors.New("boom")
+	callCount := 0
+	var plans []DryRunPlan
+
+	start := time.Now()
+	err := ExecuteSync(t.Context(), func() error {
+		callCount++
+
+		return taskErr
+	},
+		WithSyncMaxRetries(4),
+		WithSyncRetryDelay(time.Second),
+		WithDryRun(),
+		WithObserver(ObserverFunc(func(plan DryRunPlan) {
+			plans = append(plans, plan)
+		})),
+	)
+
+	require.ErrorIs(t, err, taskErr)
+	assert.Equal(t, 1, callCount)
+	assert.Less(t, time.Since(start), time.Second, "dry run must not wait")
+
+	require.Len(t, plans, 1)
+	plan := plans[0]
+	assert.ErrorIs(t, plan.Err, taskErr)
+	assert.Equal(t, 4, plan.Attempts)
+	assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second}, plan.Waits)
+	assert.Equal(t, 3*time.Second, plan.GiveUpAfter)
+	assert.WithinDuration(t, start.Add(3*time.Second), plan.GiveUpAt, time.Second)
+	assert.Contains(t, plan.String(), "would retry 3 more time(s)")
+}
+
+func TestExecuteSync_DryRunSuccess(t *testing.T) {
+	observed := false
+
+	err := ExecuteSync(t.Context(), func() error {
+		return nil
+	},
+		WithDryRun(),
+		WithObserver(ObserverFunc(func(DryRunPlan) {
+			observed = true
+		})),
+	)
+
+	require.NoError(t, err)
+	assert.False(t, observed)
+}
+
+func TestExecuteSync_ObserverWithoutDryRun(t *testing.T) {
+	observed := false
+	callCount := 0
+
+	err := ExecuteSync(t.Context(), func() error {
+		callCount++
+
+		return errors.New("boom")
+	},
+		WithSyncMaxRetries(2),
+		WithSyncRetryDelay(time.Millisecond),
+		WithObserver(ObserverFunc(func(DryRunPlan) {
+			observed = true
+		})),
+	)
+
+	require.Error(t, err)
+	assert.Equal(t, 2, callCount)
+	assert.False(t, observed)
+}
+
+func TestExecuteAsync_DryRun(t *testing.T) {
+	taskErr := errors.New("boom")
+	var wg sync.WaitGroup
+	var gotErr error
+	var plan DryRunPlan
+	callCount := 0
+
+	wg.Add(1)
+	ExecuteAsync(t.Context(), func() error {
+		callCount++
+
+		return taskErr
+	}, func(err error) {
+		gotErr = err
+		wg.Done()
+	},
+		WithAsyncMaxRetries(3),
+		WithAsyncRetryDelay(time.Second),
+		WithAsyncDryRun(),
+		WithAsyncObserver(ObserverFunc(func(p DryRunPlan) {
+			plan = p
+		})),
+	)
+	wg.Wait()
+
+	require.ErrorIs(t, gotErr, taskErr)
+	assert.Equal(t, 1, callCount)
+	assert.Equal(t, 3, plan.Attempts)
+	assert.Equal(t, 2*time.Second, plan.GiveUpAfter)
+}
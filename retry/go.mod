@@ -2,7 +2,10 @@ module github.com/ezex-io/gopkg/retry
 
 go 1.25.1
 
-require github.com/stretchr/testify v1.11.1
+require (
+	github.com/ezex-io/gopkg/errors v0.0.0-20261016203821-268b83c0bbf8
+	github.com/stretchr/testify v1.11.1
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,162 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+const (
+	// healthWindow is the number of recent calls a health check looks at.
+	healthWindow = 100
+	// healthMinCalls is the number of calls below which the failure rate isn't judged.
+	healthMinCalls = 10
+)
+
+// ErrUnhealthy is wrapped by the errors of the checks returned by HealthCheck.
+var ErrUnhealthy = errors.New("retry: unhealthy")
+
+// health records the outcomes of the calls of a named policy.
+type health struct {
+	mu       sync.Mutex
+	outcomes [healthWindow]bool // true for a failure
+	next     int
+	count    int
+	// failingSince is when the current run of failed calls started, zero after a success.
+	failingSince time.Time
+}
+
+var (
+	healthMu sync.Mutex
+	healths  = map[string]*health{}
+)
+
+// WithSyncName names the policy, so the outcomes of its calls are tracked for HealthCheck.
+// Calls with the same name share their outcomes.
+func WithSyncName(name string) Options {
+	return func(o *syncOptions) {
+		o.name = name
+	}
+}
+
+// WithAsyncName names the policy like WithSyncName.
+func WithAsyncName(name string) AsyncOptions {
+	return func(o *asyncOptions) {
+		o.name = name
+	}
+}
+
+type healthOptions struct {
+	maxFailureRate float64
+	failingFor     time.Duration
+}
+
+// HealthOption configures a check returned by HealthCheck.
+type HealthOption func(*healthOptions)
+
+// WithMaxFailureRate fails the check when more than rate of the recent calls failed,
+// 0.5 by default. The rate is only judged after a few calls.
+func WithMaxFailureRate(rate float64) HealthOption {
+	return func(o *healthOptions) {
+		o.maxFailureRate = rate
+	}
+}
+
+// WithFailingFor fails the check when every call has failed for longer than d,
+// however few they are. Zero, the default, disables it.
+func WithFailingFor(d time.Duration) HealthOption {
+	return func(o *healthOptions) {
+		o.failingFor = d
+	}
+}
+
+// HealthCheck returns a check of the calls of the policy named name with WithSyncName
+// or WithAsyncName, for a readiness probe to flip when a critical dependency keeps failing:
+//
+//	ready.Register("payments", retry.HealthCheck("payments", retry.WithFailingFor(time.Minute)))
+//
+// The check returns an error wrapping ErrUnhealthy, or nil. A call counts once,
+// after its retries, and calls canceled by their caller are not counted.
+func HealthCheck(name string, opts ...HealthOption) func(context.Context) error {
+	conf := &healthOptions{maxFailureRate: 0.5}
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	return func(context.Context) error {
+		return healthOf(name).check(name, conf)
+	}
+}
+
+// ResetHealth forgets the outcomes of every named policy, typically in test cleanups.
+func ResetHealth() {
+	healthMu.Lock()
+	clear(healths)
+	healthMu.Unlock()
+}
+
+func healthOf(name string) *health {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+
+	h, ok := healths[name]
+	if !ok {
+		h = &health{}
+		healths[name] = h
+	}
+
+	return h
+}
+
+// observeHealth records the outcome of a call of the policy named name, if any.
+func observeHealth(name string, err error) {
+	if name == "" || errors.Is(err, errors.ErrCanceled) {
+		return
+	}
+
+	h := healthOf(name)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	failed := err != nil
+	h.outcomes[h.next] = failed
+	h.next = (h.next + 1) % healthWindow
+	h.count = min(h.count+1, healthWindow)
+
+	switch {
+	case !failed:
+		h.failingSince = time.Time{}
+	case h.failingSince.IsZero():
+		h.failingSince = time.Now()
+	}
+}
+
+func (h *health) check(name string, conf *healthOptions) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if conf.failingFor > 0 && !h.failingSince.IsZero() {
+		if failing := time.Since(h.failingSince); failing > conf.failingFor {
+			return fmt.Errorf("%w: %s: every call failed for %s", ErrUnhealthy, name, failing.Round(time.Second))
+		}
+	}
+
+	if h.count < healthMinCalls {
+		return nil
+	}
+
+	failures := 0
+	for _, failed := range h.outcomes[:h.count] {
+		if failed {
+			failures++
+		}
+	}
+	if rate := float64(failures) / float64(h.count); rate > conf.maxFailureRate {
+		return fmt.Errorf("%w: %s: %d of the last %d calls failed", ErrUnhealthy, name, failures, h.count)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthCheck_FailureRate(t *testing.T) {
+	t.Cleanup(ResetHealth)
+
+	check := HealthCheck("payments", WithMaxFailureRate(0.5))
+	failing := errors.New("unavailable")
+	run := func(err error) {
+		_ = ExecuteSync(t.Context(), func() error { return err },
+			WithSyncName("payments"), WithSyncMaxRetries(1), WithSyncRetryDelay(0))
+	}
+
+	for range healthMinCalls - 1 {
+		run(failing)
+	}
+	require.NoError(t, check(t.Context()), "too few calls to judge")
+
+	run(nil)
+	require.ErrorIs(t, check(t.Context()), ErrUnhealthy)
+
+	for range healthMinCalls {
+		run(nil)
+	}
+	require.NoError(t, check(t.Context()))
+}
+
+func TestHealthCheck_RateAboveMax(t *testing.T) {
+	t.Cleanup(ResetHealth)
+
+	for i := range healthMinCalls {
+		var err error
+		if i%4 != 0 {
+			err = errors.New("unavailable")
+		}
+		observeHealth("db", err)
+	}
+
+	err := HealthCheck("db")(t.Context())
+	require.ErrorIs(t, err, ErrUnhealthy)
+	assert.Contains(t, err.Error(), "7 of the last 10 calls failed")
+
+	require.NoError(t, HealthCheck("db", WithMaxFailureRate(0.8))(t.Context()))
+	require.NoError(t, HealthCheck("other")(t.Context()))
+}
+
+func TestHealthCheck_Window(t *testing.T) {
+	t.Cleanup(ResetHealth)
+
+	for range healthWindow {
+		observeHealth("db", errors.New("unavailable"))
+	}
+	require.ErrorIs(t, HealthCheck("db")(t.Context()), ErrUnhealthy)
+
+	for range healthWindow / 2 {
+		observeHealth("db", nil)
+	}
+	require.NoError(t, HealthCheck("db")(t.Context()), "half of the window recovered")
+}
+
+func TestHealthCheck_FailingFor(t *testing.T) {
+	t.Cleanup(ResetHealth)
+
+	check := HealthCheck("db", WithFailingFor(10*time.Millisecond))
+	observeHealth("db", errors.New("unavailable"))
+	require.NoError(t, check(t.Context()))
+
+	time.Sleep(20 * time.Millisecond)
+	require.ErrorIs(t, check(t.Context()), ErrUnhealthy)
+
+	observeHealth("db", nil)
+	require.NoError(t, check(t.Context()))
+}
+
+func TestHealthCheck_IgnoresCanceled(t *testing.T) {
+	t.Cleanup(ResetHealth)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	for range healthMinCalls {
+		_ = ExecuteSync(ctx, func() error { return errors.New("unavailable") },
+			WithSyncName("db"), WithSyncMaxRetries(2), WithSyncRetryDelay(time.Millisecond))
+	}
+
+	require.NoError(t, HealthCheck("db", WithMaxFailureRate(0))(t.Context()))
+}
+
+func TestHealthCheck_Async(t *testing.T) {
+	t.Cleanup(ResetHealth)
+
+	done := make(chan error, healthMinCalls)
+	for range healthMinCalls {
+		ExecuteAsync(t.Context(), func() error { return errors.New("unavailable") },
+			func(err error) { done <- err }, WithAsyncName("queue"), WithAsyncMaxRetries(1), WithAsyncRetryDelay(0))
+	}
+	for range healthMinCalls {
+		<-done
+	}
+
+	require.ErrorIs(t, HealthCheck("queue")(t.Context()), ErrUnhealthy)
+}
@@ -3,6 +3,8 @@ package retry
 import (
 	"context"
 	"time"
+
+	"github.com/ezex-io/gopkg/errors"
 )
 
 type (
@@ -13,15 +15,12 @@ type (
 type Options func(*syncOptions)
 
 type syncOptions struct {
-	maxRetries int
-	retryDelay time.Duration
-}
-
-func defaultSyncOpts() *syncOptions {
-	return &syncOptions{
-		maxRetries: 3,
-		retryDelay: 2 * time.Second,
-	}
+	maxRetries     int
+	retryDelay     time.Duration
+	attemptTimeout time.Duration
+	dryRun         bool
+	observer       Observer
+	name           string
 }
 
 func WithSyncMaxRetries(maxRetries int) Options {
@@ -37,8 +36,9 @@ func WithSyncRetryDelay(retryDelay time.Duration) Options {
 }
 
 // ExecuteSync executes a function synchronously with retry logic
-// It respects context cancellation and timeout
+// It respects context cancellation and timeout, reported as errors.ErrCanceled or errors.ErrTimeout
 // Returns nil if the function succeeds, or the last error if all retries are exhausted.
+// An invalid configuration is reported as a *ConfigError without running the task.
 func ExecuteSync(ctx context.Context,
 	task SyncTask,
 	opts ...Options,
@@ -51,30 +51,61 @@ func ExecuteSync(ctx context.Context,
 }
 
 // ExecuteSyncT executes a function synchronously with retry logic and returns a result
-// It respects context cancellation and timeout
+// It respects context cancellation and timeout, reported as errors.ErrCanceled or errors.ErrTimeout
 // Returns the result if the function succeeds, or the last error if all retries are exhausted.
+// An invalid configuration is reported as a *ConfigError without running the task.
 func ExecuteSyncT[T any](ctx context.Context,
 	task SyncTaskT[T], opts ...Options,
+) (T, error) {
+	return ExecuteSyncCtxT(ctx, func(context.Context) (T, error) {
+		return task()
+	}, opts...)
+}
+
+// ExecuteSyncCtxT is like ExecuteSyncT, but passes each attempt its own context,
+// derived from ctx and bounded by WithSyncAttemptTimeout if set, so the task can
+// give up on a slow attempt and check for cancellation with CheckCtx.
+func ExecuteSyncCtxT[T any](ctx context.Context,
+	task SyncTaskCtxT[T], opts ...Options,
 ) (T, error) {
 	conf := defaultSyncOpts()
 	for _, opt := range opts {
 		opt(conf)
 	}
 
+	if err := conf.config().Validate(); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	result, err := executeSync(ctx, conf, task)
+	observeHealth(conf.name, err)
+
+	return result, err
+}
+
+func executeSync[T any](ctx context.Context, conf *syncOptions, task SyncTaskCtxT[T]) (T, error) {
 	var result T
 	var err error
 	for attempt := 0; attempt < conf.maxRetries; attempt++ {
-		result, err = task()
+		result, err = runAttempt(ctx, conf.attemptTimeout, task)
 		if err == nil {
 			return result, nil
 		}
 
+		if conf.dryRun {
+			observeDryRun(conf.observer, err, conf.maxRetries, conf.retryDelay)
+
+			return result, err
+		}
+
 		// Don't wait after the last attempt
 		if attempt < conf.maxRetries-1 {
 			// Wait before retry, but respect context cancellation
 			select {
 			case <-ctx.Done():
-				return result, ctx.Err()
+				return result, errors.FromContext(err, ctx.Err())
 
 			case <-time.After(conf.retryDelay):
 				// Continue to next retry
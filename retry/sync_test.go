@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	ezerrors "github.com/ezex-io/gopkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -76,6 +77,7 @@ func TestExecuteSync_ContextCancellationDuringRetry(t *testing.T) {
 
 	require.Error(t, err)
 	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, ezerrors.ErrCanceled)
 	assert.GreaterOrEqual(t, callCount, 1, "Should be called at least once")
 	assert.Less(t, callCount, 5, "Should not complete all retries")
 }
@@ -97,3 +99,17 @@ func TestExecuteSync_ConcurrentCalls(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestExecuteSync_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	errTask := errors.New("temporary error")
+	err := ExecuteSync(ctx, func() error {
+		return errTask
+	}, WithSyncMaxRetries(5), WithSyncRetryDelay(100*time.Millisecond))
+
+	require.ErrorIs(t, err, ezerrors.ErrTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errTask)
+}
@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxRetryDelay is the upper bound accepted for the delay between attempts.
+// Anything larger is almost certainly a unit mistake (e.g. nanoseconds vs seconds).
+const MaxRetryDelay = time.Hour
+
+// ErrInvalidConfig is the sentinel wrapped by every ConfigError.
+var ErrInvalidConfig = errors.New("retry: invalid config")
+
+// ConfigError describes a single invalid retry option.
+type ConfigError struct {
+	Field  string
+	Value  any
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("retry: invalid %s %v: %s", e.Field, e.Value, e.Reason)
+}
+
+func (*ConfigError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// Config is a retry policy as plain values, such as built from configuration
+// at startup, checked with Validate and turned into options with Options:
+//
+//	cfg := retry.Config{MaxRetries: 5, RetryDelay: time.Second, AttemptTimeout: 10 * time.Second}
+//	if err := cfg.Validate(); err != nil {
+//		return err
+//	}
+//	err = retry.ExecuteSyncCtx(ctx, task, cfg.Options()...)
+type Config struct {
+	// MaxRetries is the number of attempts, at least one.
+	MaxRetries int
+	// RetryDelay is the wait before each retry, at most MaxRetryDelay.
+	RetryDelay time.Duration
+	// AttemptTimeout bounds each attempt if positive, and is then at least RetryDelay.
+	AttemptTimeout time.Duration
+	// DryRun runs the first attempt only, telling Observer what the policy would have done.
+	DryRun bool
+	// Observer is notified in dry-run mode, and must be set then.
+	Observer Observer
+}
+
+// Validate reports the first invalid setting of c as a *ConfigError,
+// including settings conflicting with each other.
+func (c Config) Validate() error {
+	if c.MaxRetries <= 0 {
+		return &ConfigError{Field: "maxRetries", Value: c.MaxRetries, Reason: "must be greater than zero"}
+	}
+
+	if c.RetryDelay < 0 {
+		return &ConfigError{Field: "retryDelay", Value: c.RetryDelay, Reason: "must not be negative"}
+	}
+
+	if c.RetryDelay > MaxRetryDelay {
+		return &ConfigError{Field: "retryDelay", Value: c.RetryDelay, Reason: "exceeds " + MaxRetryDelay.String()}
+	}
+
+	if c.AttemptTimeout < 0 {
+		return &ConfigError{Field: "attemptTimeout", Value: c.AttemptTimeout, Reason: "must not be negative"}
+	}
+
+	// An attempt timing out before the first retry could even start
+	// is almost certainly a mix-up of the two settings.
+	if c.AttemptTimeout > 0 && c.AttemptTimeout < c.RetryDelay {
+		return &ConfigError{
+			Field: "attemptTimeout", Value: c.AttemptTimeout,
+			Reason: "is shorter than the retry delay " + c.RetryDelay.String(),
+		}
+	}
+
+	if c.DryRun && isNilObserver(c.Observer) {
+		return &ConfigError{Field: "observer", Value: c.Observer, Reason: "must be set in dry-run mode"}
+	}
+
+	return nil
+}
+
+// Options returns the options applying c, replacing the defaults.
+func (c Config) Options() []Options {
+	return []Options{func(o *syncOptions) {
+		o.maxRetries = c.MaxRetries
+		o.retryDelay = c.RetryDelay
+		o.attemptTimeout = c.AttemptTimeout
+		o.dryRun = c.DryRun
+		o.observer = c.Observer
+	}}
+}
+
+// isNilObserver reports whether observer is nil, or a nil ObserverFunc.
+func isNilObserver(observer Observer) bool {
+	if f, ok := observer.(ObserverFunc); ok {
+		return f == nil
+	}
+
+	return observer == nil
+}
+
+// ValidateSync applies the options on top of the defaults and reports
+// the first invalid setting, so configurations can be checked at startup.
+func ValidateSync(opts ...Options) error {
+	conf := defaultSyncOpts()
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	return conf.config().Validate()
+}
+
+// ValidateAsync applies the options on top of the defaults and reports
+// the first invalid setting, so configurations can be checked at startup.
+func ValidateAsync(opts ...AsyncOptions) error {
+	conf := defaultAsyncOpts()
+	for _, opt := range opts {
+		opt(conf)
+	}
+
+	return conf.config().Validate()
+}
+
+func (o *syncOptions) config() Config {
+	return Config{
+		MaxRetries:     o.maxRetries,
+		RetryDelay:     o.retryDelay,
+		AttemptTimeout: o.attemptTimeout,
+		DryRun:         o.dryRun,
+		Observer:       o.observer,
+	}
+}
+
+func (o *asyncOptions) config() Config {
+	return Config{
+		MaxRetries: o.maxRetries,
+		RetryDelay: o.retryDelay,
+		DryRun:     o.dryRun,
+		Observer:   o.observer,
+	}
+}
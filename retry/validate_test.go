@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, ValidateSync())
+	require.NoError(t, ValidateAsync())
+	require.NoError(t, ValidateSync(WithSyncRetryDelay(0)))
+
+	tests := []struct {
+		name  string
+		opts  []Options
+		field string
+	}{
+		{"zero retries", []Options{WithSyncMaxRetries(0)}, "maxRetries"},
+		{"negative retries", []Options{WithSyncMaxRetries(-1)}, "maxRetries"},
+		{"negative delay", []Options{WithSyncRetryDelay(-time.Second)}, "retryDelay"},
+		{"absurd delay", []Options{WithSyncRetryDelay(MaxRetryDelay + time.Second)}, "retryDelay"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSync(tt.opts...)
+			require.ErrorIs(t, err, ErrInvalidConfig)
+
+			var cfgErr *ConfigError
+			require.ErrorAs(t, err, &cfgErr)
+			assert.Equal(t, tt.field, cfgErr.Field)
+		})
+	}
+
+	require.ErrorIs(t, ValidateAsync(WithAsyncMaxRetries(0)), ErrInvalidConfig)
+}
+
+func TestExecuteWithInvalidConfig(t *testing.T) {
+	called := false
+	err := ExecuteSync(t.Context(), func() error {
+		called = true
+
+		return nil
+	}, WithSyncMaxRetries(0))
+
+	require.ErrorIs(t, err, ErrInvalidConfig)
+	assert.False(t, called, "task should not run with an invalid config")
+
+	failure := make(chan error, 1)
+	ExecuteAsync(t.Context(), func() error {
+		called = true
+
+		return nil
+	}, func(err error) {
+		failure <- err
+	}, WithAsyncRetryDelay(-time.Second))
+
+	require.ErrorIs(t, <-failure, ErrInvalidConfig)
+	assert.False(t, called, "task should not run with an invalid config")
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, Config{MaxRetries: 1}.Validate())
+	require.NoError(t, Config{MaxRetries: 3, RetryDelay: time.Second, AttemptTimeout: time.Second}.Validate())
+	require.NoError(t, Config{MaxRetries: 1, DryRun: true, Observer: LogObserver}.Validate())
+
+	tests := []struct {
+		name  string
+		cfg   Config
+		field string
+	}{
+		{"zero retries", Config{}, "maxRetries"},
+		{"negative delay", Config{MaxRetries: 1, RetryDelay: -time.Second}, "retryDelay"},
+		{"absurd delay", Config{MaxRetries: 1, RetryDelay: MaxRetryDelay + time.Second}, "retryDelay"},
+		{"negative attempt timeout", Config{MaxRetries: 1, AttemptTimeout: -time.Second}, "attemptTimeout"},
+		{
+			"attempt timeout shorter than the delay",
+			Config{MaxRetries: 3, RetryDelay: time.Second, AttemptTimeout: 100 * time.Millisecond},
+			"attemptTimeout",
+		},
+		{"dry run without observer", Config{MaxRetries: 1, DryRun: true}, "observer"},
+		{"dry run with nil func", Config{MaxRetries: 1, DryRun: true, Observer: ObserverFunc(nil)}, "observer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			require.ErrorIs(t, err, ErrInvalidConfig)
+
+			var cfgErr *ConfigError
+			require.ErrorAs(t, err, &cfgErr)
+			assert.Equal(t, tt.field, cfgErr.Field)
+		})
+	}
+}
+
+func TestValidateConflicts(t *testing.T) {
+	err := ValidateSync(WithSyncAttemptTimeout(time.Second))
+	require.ErrorIs(t, err, ErrInvalidConfig, "the default delay is longer than the attempt timeout")
+	require.NoError(t, ValidateSync(WithSyncRetryDelay(time.Millisecond), WithSyncAttemptTimeout(time.Second)))
+
+	require.ErrorIs(t, ValidateSync(WithDryRun(), WithObserver(nil)), ErrInvalidConfig)
+	require.ErrorIs(t, ValidateAsync(WithAsyncDryRun(), WithAsyncObserver(nil)), ErrInvalidConfig)
+	require.NoError(t, ValidateSync(WithObserver(nil)), "the observer is only used in dry-run mode")
+}
+
+func TestConfigOptions(t *testing.T) {
+	attempts := 0
+	cfg := Config{MaxRetries: 2, RetryDelay: time.Millisecond, AttemptTimeout: time.Second}
+	require.NoError(t, cfg.Validate())
+
+	err := ExecuteSyncCtx(t.Context(), func(ctx context.Context) error {
+		attempts++
+		_, ok := ctx.Deadline()
+		assert.True(t, ok, "the attempt timeout applies")
+
+		return errors.New("failed")
+	}, cfg.Options()...)
+
+	require.Error(t, err)
+	assert.Equal(t, 2, attempts)
+}
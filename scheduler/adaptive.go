@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+// AdaptiveJob is a job that decides how long to wait before its next run,
+// so polling jobs can back off when idle and speed up under load.
+// A non-positive delay means the default interval.
+type AdaptiveJob interface {
+	Run(ctx context.Context) (time.Duration, error)
+}
+
+// AdaptiveFunc adapts a function to the AdaptiveJob interface.
+type AdaptiveFunc func(ctx context.Context) (time.Duration, error)
+
+// Run calls f(ctx).
+func (f AdaptiveFunc) Run(ctx context.Context) (time.Duration, error) {
+	return f(ctx)
+}
+
+type AdaptiveBuilder struct {
+	interval time.Duration
+	minDelay time.Duration
+	maxDelay time.Duration
+}
+
+// Adaptive schedules a job that runs after the given default interval,
+// and then after the delay returned by each run.
+func Adaptive(interval time.Duration) AdaptiveBuilder {
+	return AdaptiveBuilder{interval: interval}
+}
+
+// Between bounds the delays returned by the job. A zero bound is ignored.
+func (b AdaptiveBuilder) Between(minDelay, maxDelay time.Duration) AdaptiveBuilder {
+	b.minDelay = minDelay
+	b.maxDelay = maxDelay
+
+	return b
+}
+
+// Do runs the job until ctx is done. Errors are logged and panics recovered, logged
+// as errors.Panic errors; the job still decides the next delay when it returns an error.
+func (b AdaptiveBuilder) Do(ctx context.Context, job AdaptiveJob) {
+	go func() {
+		timer := time.NewTimer(b.interval)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				timer.Reset(b.nextDelay(runAdaptive(ctx, job)))
+			}
+		}
+	}()
+}
+
+func runAdaptive(ctx context.Context, job AdaptiveJob) (delay time.Duration) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("scheduler: panic in job: %+v", errors.Panic(r))
+			delay = 0
+		}
+	}()
+
+	delay, err := job.Run(ctx)
+	if err != nil {
+		log.Printf("job failed: %v", err)
+	}
+
+	return delay
+}
+
+// nextDelay applies the default interval and the bounds to the delay returned by the job.
+func (b AdaptiveBuilder) nextDelay(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		delay = b.interval
+	}
+	if b.minDelay > 0 && delay < b.minDelay {
+		delay = b.minDelay
+	}
+	if b.maxDelay > 0 && delay > b.maxDelay {
+		delay = b.maxDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,105 @@
+package scheduler_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/scheduler"
+)
+
+func TestAdaptiveDelays(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	delays := []time.Duration{30 * time.Millisecond, 0, time.Hour}
+	var mu sync.Mutex
+	var runs []time.Time
+	done := make(chan struct{})
+
+	start := time.Now()
+	scheduler.Adaptive(10*time.Millisecond).Between(0, 50*time.Millisecond).Do(ctx,
+		scheduler.AdaptiveFunc(func(context.Context) (time.Duration, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			runs = append(runs, time.Now())
+			if len(runs) == 4 {
+				close(done)
+			}
+			if len(runs) > len(delays) {
+				return time.Hour, nil
+			}
+
+			return delays[len(runs)-1], errors.New("ignored")
+		}))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the adaptive job")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Initial interval, then the returned delay, the default interval for 0,
+	// and the upper bound for an hour.
+	expected := []time.Duration{10, 30, 10, 50}
+	prev := start
+	for i, run := range runs[:4] {
+		if gap := run.Sub(prev); gap < expected[i]*time.Millisecond {
+			t.Fatalf("run %d: expected a gap of at least %dms, got %v", i, expected[i], gap)
+		}
+		prev = run
+	}
+}
+
+func TestAdaptiveRecoversPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var counter atomic.Int32
+	scheduler.Adaptive(time.Millisecond).Do(ctx,
+		scheduler.AdaptiveFunc(func(context.Context) (time.Duration, error) {
+			if counter.Add(1) == 1 {
+				panic("boom")
+			}
+
+			return time.Millisecond, nil
+		}))
+
+	deadline := time.After(time.Second)
+	for counter.Load() < 3 {
+		select {
+		case <-deadline:
+			t.Fatal("adaptive job stopped after a panic")
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestSchedulerAdaptiveJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	ran := make(chan struct{})
+	var once sync.Once
+
+	s := scheduler.NewScheduler()
+	s.AddAdaptiveJob(scheduler.AdaptiveFunc(func(context.Context) (time.Duration, error) {
+		once.Do(func() { close(ran) })
+
+		return 0, nil
+	}))
+	s.Start(ctx, time.Millisecond)
+
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the adaptive job")
+	}
+}
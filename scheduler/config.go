@@ -0,0 +1,217 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+// ErrUnknownJob is returned by FromConfig for a spec naming no registered job.
+var ErrUnknownJob = errors.New("scheduler: unknown job")
+
+// Overlap is what happens when a job is due while its previous run is still going.
+type Overlap string
+
+const (
+	// OverlapSkip skips the activation, the default.
+	OverlapSkip Overlap = "skip"
+	// OverlapAllow starts another run alongside the previous one.
+	OverlapAllow Overlap = "allow"
+)
+
+// JobSpec schedules a registered job. It decodes from JSON, or YAML through
+// env.GetEnvJSON, with the timeout as a duration string:
+//
+//	{"cron": "*/5 * * * *", "timeout": "30s", "overlap": "skip"}
+//
+// The zero value of the other fields runs the job, so a spec built in Go needs only its Cron.
+type JobSpec struct {
+	// Cron is the cron expression of the activations, as ParseCron reads it.
+	Cron string `json:"cron"`
+	// Timeout bounds each run, if positive.
+	Timeout time.Duration `json:"timeout"`
+	// Overlap defaults to OverlapSkip.
+	Overlap Overlap `json:"overlap"`
+	// Disabled skips the job. JSON may also set it with "enabled": false.
+	Disabled bool `json:"disabled"`
+}
+
+// UnmarshalJSON decodes the spec, reading the timeout as a duration string
+// such as "30s" and disabling the job if "disabled" is true or "enabled" is false.
+func (s *JobSpec) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Cron     string          `json:"cron"`
+		Timeout  json.RawMessage `json:"timeout"`
+		Overlap  Overlap         `json:"overlap"`
+		Disabled bool            `json:"disabled"`
+		Enabled  *bool           `json:"enabled"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	disabled := raw.Disabled || (raw.Enabled != nil && !*raw.Enabled)
+	spec := JobSpec{Cron: raw.Cron, Overlap: raw.Overlap, Disabled: disabled}
+	if len(raw.Timeout) > 0 && string(raw.Timeout) != "null" {
+		var timeout string
+		if err := json.Unmarshal(raw.Timeout, &timeout); err != nil {
+			return fmt.Errorf("scheduler: timeout must be a duration string: %w", err)
+		}
+
+		var err error
+		if spec.Timeout, err = time.ParseDuration(timeout); err != nil {
+			return fmt.Errorf("scheduler: invalid timeout: %w", err)
+		}
+	}
+	*s = spec
+
+	return nil
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Job{}
+)
+
+// Register makes job available to FromConfig under name, typically from an init function
+// or at startup. It panics if name is already registered or job is nil.
+func Register(name string, job Job) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if job == nil {
+		panic("scheduler: Register job is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("scheduler: Register called twice for job " + name)
+	}
+	registry[name] = job
+}
+
+// Unregister removes the job registered under name, typically in test cleanups.
+func Unregister(name string) {
+	registryMu.Lock()
+	delete(registry, name)
+	registryMu.Unlock()
+}
+
+// FromConfig runs the registered jobs on the schedules of cfg, keyed by job name,
+// until ctx is done, so schedules can change per environment without code changes:
+//
+//	scheduler.Register("cleanup", cleanupJob)
+//	specs, err := env.GetEnvJSON[map[string]scheduler.JobSpec]("SCHEDULES")
+//	...
+//	err = scheduler.FromConfig(ctx, specs)
+//
+// Disabled specs are skipped, and registered jobs without a spec don't run.
+// FromConfig checks every spec before starting any job, and returns their errors joined,
+// those of names not registered wrapping ErrUnknownJob. Errors and panics of runs are logged.
+func FromConfig(ctx context.Context, cfg map[string]JobSpec) error {
+	type scheduled struct {
+		name     string
+		job      Job
+		spec     JobSpec
+		schedule CronSchedule
+	}
+
+	var jobs []scheduled
+	var errs []error
+	for name, spec := range cfg {
+		if spec.Disabled {
+			continue
+		}
+
+		registryMu.RLock()
+		job, ok := registry[name]
+		registryMu.RUnlock()
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownJob, name))
+
+			continue
+		}
+
+		schedule, err := ParseCron(spec.Cron)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scheduler: job %s: %w", name, err))
+
+			continue
+		}
+
+		switch spec.Overlap {
+		case "", OverlapSkip, OverlapAllow:
+		default:
+			errs = append(errs, fmt.Errorf("scheduler: job %s: unknown overlap policy %q", name, spec.Overlap))
+
+			continue
+		}
+
+		jobs = append(jobs, scheduled{name: name, job: job, spec: spec, schedule: schedule})
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	for _, s := range jobs {
+		go runScheduled(ctx, s.name, s.job, s.spec, s.schedule)
+	}
+
+	return nil
+}
+
+func runScheduled(ctx context.Context, name string, job Job, spec JobSpec, schedule CronSchedule) {
+	var running atomic.Int32
+	next := schedule.Next(time.Now())
+	for {
+		if next.IsZero() {
+			log.Printf("scheduler: job %s has no next activation, stopping", name)
+
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+		next = schedule.Next(time.Now())
+
+		if spec.Overlap != OverlapAllow && running.Load() > 0 {
+			log.Printf("scheduler: job %s still running, skipping activation", name)
+
+			continue
+		}
+
+		running.Add(1)
+		go func() {
+			defer running.Add(-1)
+			runSpec(ctx, name, job, spec.Timeout)
+		}()
+	}
+}
+
+func runSpec(ctx context.Context, name string, job Job, timeout time.Duration) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("scheduler: panic in job %s: %+v", name, errors.Panic(r))
+		}
+	}()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err := job.Run(ctx); err != nil {
+		log.Printf("scheduler: job %s: %v", name, err)
+	}
+}
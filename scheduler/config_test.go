@@ -0,0 +1,175 @@
+package scheduler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/scheduler"
+)
+
+func register(t *testing.T, name string, job scheduler.Job) {
+	t.Helper()
+
+	scheduler.Register(name, job)
+	t.Cleanup(func() { scheduler.Unregister(name) })
+}
+
+type jobFunc func(ctx context.Context) error
+
+func (f jobFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestJobSpecUnmarshal(t *testing.T) {
+	var specs map[string]scheduler.JobSpec
+	data := `{"cleanup": {"cron": "@hourly", "timeout": "30s", "overlap": "allow"},
+		"report": {"cron": "0 6 * * *", "enabled": false}}`
+	if err := json.Unmarshal([]byte(data), &specs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := scheduler.JobSpec{
+		Cron: "@hourly", Timeout: 30 * time.Second, Overlap: scheduler.OverlapAllow,
+	}
+	if specs["cleanup"] != want {
+		t.Fatalf("expected %+v, got %+v", want, specs["cleanup"])
+	}
+	if !specs["report"].Disabled {
+		t.Fatal("expected report to be disabled")
+	}
+
+	var disabled scheduler.JobSpec
+	err := json.Unmarshal([]byte(`{"cron": "@hourly", "disabled": true}`), &disabled)
+	if err != nil || !disabled.Disabled {
+		t.Fatalf("expected a disabled spec, got %+v, %v", disabled, err)
+	}
+
+	var spec scheduler.JobSpec
+	if err := json.Unmarshal([]byte(`{"timeout": 30}`), &spec); err == nil {
+		t.Fatal("expected an error for a numeric timeout")
+	}
+}
+
+func TestFromConfigErrors(t *testing.T) {
+	register(t, "config-errors", jobFunc(func(context.Context) error { return nil }))
+
+	err := scheduler.FromConfig(t.Context(), map[string]scheduler.JobSpec{
+		"config-missing": {Cron: "@hourly"},
+		"config-errors":  {Cron: "every minute"},
+		"config-off":     {Cron: "invalid", Disabled: true},
+	})
+	if !errors.Is(err, scheduler.ErrUnknownJob) {
+		t.Fatalf("expected ErrUnknownJob, got %v", err)
+	}
+
+	err = scheduler.FromConfig(t.Context(), map[string]scheduler.JobSpec{
+		"config-errors": {Cron: "@hourly", Overlap: "queue"},
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unknown overlap policy")
+	}
+}
+
+func TestFromConfigRuns(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	// Runs last longer than the interval, so skipped activations show.
+	var skipped, allowed atomic.Int32
+	slow := func(count *atomic.Int32) scheduler.Job {
+		return jobFunc(func(ctx context.Context) error {
+			count.Add(1)
+			<-ctx.Done()
+
+			return ctx.Err()
+		})
+	}
+	register(t, "config-skip", slow(&skipped))
+	register(t, "config-allow", slow(&allowed))
+
+	var deadline atomic.Bool
+	register(t, "config-timeout", jobFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		deadline.Store(errors.Is(ctx.Err(), context.DeadlineExceeded))
+
+		return nil
+	}))
+
+	err := scheduler.FromConfig(ctx, map[string]scheduler.JobSpec{
+		"config-skip":    {Cron: "@every 1s"},
+		"config-allow":   {Cron: "@every 1s", Overlap: scheduler.OverlapAllow},
+		"config-timeout": {Cron: "@every 1s", Timeout: 10 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+
+	if n := skipped.Load(); n != 1 {
+		t.Fatalf("expected 1 run with overlaps skipped, got %d", n)
+	}
+	// Activations fall on whole seconds, so there are 2 or 3 of them.
+	if n := allowed.Load(); n < 2 {
+		t.Fatalf("expected overlapping runs, got %d", n)
+	}
+	if !deadline.Load() {
+		t.Fatal("expected the run to time out")
+	}
+}
+
+func TestFromConfigSpecInCode(t *testing.T) {
+	var ran, skipped atomic.Int32
+	register(t, "config-code", jobFunc(func(context.Context) error {
+		ran.Add(1)
+
+		return nil
+	}))
+	register(t, "config-disabled", jobFunc(func(context.Context) error {
+		skipped.Add(1)
+
+		return nil
+	}))
+
+	err := scheduler.FromConfig(t.Context(), map[string]scheduler.JobSpec{
+		"config-code":     {Cron: "@every 1s"},
+		"config-disabled": {Cron: "@every 1s", Disabled: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if ran.Load() == 0 {
+		t.Fatal("expected a spec built in code to run")
+	}
+	if n := skipped.Load(); n != 0 {
+		t.Fatalf("expected the disabled spec to be skipped, got %d runs", n)
+	}
+}
+
+func TestFromConfigImpossibleDate(t *testing.T) {
+	var runs atomic.Int32
+	register(t, "config-never", jobFunc(func(context.Context) error {
+		runs.Add(1)
+
+		return nil
+	}))
+
+	err := scheduler.FromConfig(t.Context(), map[string]scheduler.JobSpec{
+		"config-never": {Cron: "0 0 30 2 *", Overlap: scheduler.OverlapAllow},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a schedule that never matches")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if n := runs.Load(); n != 0 {
+		t.Fatalf("expected no run, got %d", n)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var cronParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// CronSchedule computes activation times from a cron expression.
+type CronSchedule struct {
+	expr     string
+	schedule cron.Schedule
+}
+
+// ParseCron parses a standard five-field cron expression
+// ("minute hour day-of-month month day-of-week") or a descriptor such as "@hourly".
+// Expressions that never match, such as "0 0 30 2 *", are rejected.
+func ParseCron(expr string) (CronSchedule, error) {
+	schedule, err := cronParser.Parse(expr)
+	if err != nil {
+		return CronSchedule{}, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	if schedule.Next(time.Now()).IsZero() {
+		return CronSchedule{}, fmt.Errorf("invalid cron expression %q: it never matches", expr)
+	}
+
+	return CronSchedule{expr: expr, schedule: schedule}, nil
+}
+
+// CronExpr is a cron expression read from configuration, such as with the env module,
+// checked with ValidateCron:
+//
+//	expr := env.GetEnv[scheduler.CronExpr]("CLEANUP_CRON", env.WithValidator(scheduler.ValidateCron))
+//	schedule, err := expr.Schedule()
+type CronExpr string
+
+// Schedule parses the expression with ParseCron.
+func (c CronExpr) Schedule() (CronSchedule, error) {
+	return ParseCron(string(c))
+}
+
+// ValidateCron reports whether expr is a cron expression ParseCron accepts.
+func ValidateCron(expr string) error {
+	_, err := ParseCron(expr)
+
+	return err
+}
+
+// Next returns the next activation time strictly after t,
+// or the zero time if there is none in the following five years.
+func (c CronSchedule) Next(t time.Time) time.Time {
+	return c.schedule.Next(t)
+}
+
+// String returns the original cron expression.
+func (c CronSchedule) String() string {
+	return c.expr
+}
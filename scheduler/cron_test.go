@@ -0,0 +1,59 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/scheduler"
+)
+
+func TestParseCron(t *testing.T) {
+	schedule, err := scheduler.ParseCron("30 14 * * *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	from := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	expected := time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+	if next := schedule.Next(from); !next.Equal(expected) {
+		t.Fatalf("expected next activation %v, got %v", expected, next)
+	}
+
+	if schedule.String() != "30 14 * * *" {
+		t.Fatalf("unexpected expression %q", schedule.String())
+	}
+
+	if _, err := scheduler.ParseCron("@hourly"); err != nil {
+		t.Fatalf("unexpected error for descriptor: %v", err)
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{"", "* * *", "61 * * * *", "every minute", "0 0 30 2 *"} {
+		if _, err := scheduler.ParseCron(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestCronExpr(t *testing.T) {
+	schedule, err := scheduler.CronExpr("*/5 * * * *").Schedule()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if schedule.String() != "*/5 * * * *" {
+		t.Fatalf("unexpected expression %q", schedule.String())
+	}
+
+	if err := scheduler.ValidateCron("@daily"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, expr := range []string{"every five minutes", "0 0 30 2 *"} {
+		if err := scheduler.ValidateCron(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+		if _, err := scheduler.CronExpr(expr).Schedule(); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
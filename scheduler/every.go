@@ -3,8 +3,9 @@ package scheduler
 import (
 	"context"
 	"log"
-	"runtime/debug"
 	"time"
+
+	"github.com/ezex-io/gopkg/errors"
 )
 
 type EveryBuilder struct {
@@ -31,11 +32,7 @@ func (b EveryBuilder) Do(ctx context.Context, callback func(ctx context.Context)
 				func() {
 					defer func() {
 						if r := recover(); r != nil {
-							log.Printf(
-								"scheduler: panic in job: %v\n%s",
-								r,
-								debug.Stack(),
-							)
+							log.Printf("scheduler: panic in job: %+v", errors.Panic(r))
 						}
 					}()
 					callback(ctx)
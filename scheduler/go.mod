@@ -2,4 +2,8 @@ module github.com/ezex-io/gopkg/scheduler
 
 go 1.25.1
 
-require golang.org/x/sync v0.19.0
+require (
+	github.com/ezex-io/gopkg/errors v0.0.0-20261016204619-a45bd06deca2
+	github.com/robfig/cron/v3 v3.0.1
+	golang.org/x/sync v0.19.0
+)
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Priority is the class of a job. Each class runs on its own ticker and worker pool,
+// so slow jobs of one class never delay the jobs of another.
+type Priority int
+
+const (
+	// PriorityNormal is the class of jobs added with AddJob. Only these jobs count
+	// toward the success callback.
+	PriorityNormal Priority = iota
+	// PriorityCritical is for small jobs that must keep running, such as heartbeats.
+	PriorityCritical
+	// PriorityBatch is for heavy jobs, such as reconciliations.
+	PriorityBatch
+
+	numPriorities
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityNormal:
+		return "normal"
+	case PriorityCritical:
+		return "critical"
+	case PriorityBatch:
+		return "batch"
+	default:
+		return "unknown"
+	}
+}
+
+// class holds the jobs and the pool settings of a priority.
+type class struct {
+	jobs    []Job
+	workers int
+	share   float64
+}
+
+// WithPoolSize limits the jobs of the priority running at the same time to workers.
+// Zero, the default, runs all the jobs of the class at once.
+func WithPoolSize(priority Priority, workers int) Option {
+	return func(s *Scheduler) {
+		if priority >= 0 && priority < numPriorities {
+			s.classes[priority].workers = workers
+		}
+	}
+}
+
+// WithTimeShare caps the share of time, between 0 and 1, each worker of the priority
+// spends running jobs: after a job that ran for d, its worker rests d×(1-share)/share
+// before taking the next one. Run time stands for CPU time, so a batch class with
+// a share of 0.25 leaves at least three quarters of its workers' time to other work.
+// Zero or one, the default, disables the cap.
+func WithTimeShare(priority Priority, share float64) Option {
+	return func(s *Scheduler) {
+		if priority >= 0 && priority < numPriorities {
+			s.classes[priority].share = share
+		}
+	}
+}
+
+// AddJobWithPriority adds a job to the class of the given priority.
+func (s *Scheduler) AddJobWithPriority(job Job, priority Priority) {
+	if priority < 0 || priority >= numPriorities {
+		priority = PriorityNormal
+	}
+	s.classes[priority].jobs = append(s.classes[priority].jobs, job)
+}
+
+// run runs the jobs of the class once, in its worker pool, and returns the first error.
+func (c *class) run(ctx context.Context) error {
+	group, _ := errgroup.WithContext(ctx)
+	if c.workers > 0 {
+		group.SetLimit(c.workers)
+	}
+
+	for _, j := range c.jobs {
+		job := j
+		group.Go(func() error {
+			start := time.Now()
+			err := job.Run(ctx)
+			if err != nil {
+				log.Printf("job failed: %v", err)
+			}
+
+			c.rest(ctx, time.Since(start))
+
+			return err
+		})
+	}
+
+	return group.Wait()
+}
+
+// rest holds the worker for the time the share requires after a job that ran for elapsed.
+func (c *class) rest(ctx context.Context, elapsed time.Duration) {
+	if c.share <= 0 || c.share >= 1 {
+		return
+	}
+
+	timer := time.NewTimer(time.Duration(float64(elapsed) * (1 - c.share) / c.share))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,140 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/scheduler"
+)
+
+type funcJob func(ctx context.Context) error
+
+func (f funcJob) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestBatchJobDoesNotStarveCritical(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var heartbeats atomic.Int32
+	s := scheduler.NewScheduler()
+	s.AddJobWithPriority(funcJob(func(ctx context.Context) error {
+		<-ctx.Done() // A reconciliation that never finishes.
+
+		return nil
+	}), scheduler.PriorityBatch)
+	s.AddJobWithPriority(funcJob(func(context.Context) error {
+		heartbeats.Add(1)
+
+		return nil
+	}), scheduler.PriorityCritical)
+
+	s.Start(ctx, 5*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	if n := heartbeats.Load(); n < 5 {
+		t.Fatalf("expected the critical job to keep running, got %d runs", n)
+	}
+}
+
+func TestOnSuccessIgnoresOtherClasses(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var counter atomic.Int32
+	s := scheduler.NewScheduler()
+	s.AddJob(testJob{counter: &counter})
+	s.AddJobWithPriority(funcJob(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}), scheduler.PriorityBatch)
+
+	done := make(chan struct{})
+	var once sync.Once
+	s.Start(ctx, time.Millisecond, scheduler.WithOnSuccess(func() {
+		once.Do(func() { close(done) })
+	}))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a blocked batch job must not hold back the success callback")
+	}
+}
+
+func TestWithPoolSize(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var running, peak, runs atomic.Int32
+	job := funcJob(func(context.Context) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		runs.Add(1)
+
+		return nil
+	})
+
+	s := scheduler.NewScheduler()
+	for range 4 {
+		s.AddJobWithPriority(job, scheduler.PriorityBatch)
+	}
+
+	s.Start(ctx, time.Millisecond, scheduler.WithPoolSize(scheduler.PriorityBatch, 2))
+	time.Sleep(60 * time.Millisecond)
+
+	if runs.Load() == 0 {
+		t.Fatal("expected batch jobs to run")
+	}
+	if p := peak.Load(); p > 2 {
+		t.Fatalf("expected at most 2 concurrent batch jobs, got %d", p)
+	}
+}
+
+func TestWithTimeShare(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var mu sync.Mutex
+	var starts []time.Time
+	job := funcJob(func(context.Context) error {
+		mu.Lock()
+		starts = append(starts, time.Now())
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+
+		return nil
+	})
+
+	s := scheduler.NewScheduler()
+	s.AddJobWithPriority(job, scheduler.PriorityBatch)
+	s.AddJobWithPriority(job, scheduler.PriorityBatch)
+
+	s.Start(ctx, time.Millisecond,
+		scheduler.WithPoolSize(scheduler.PriorityBatch, 1),
+		scheduler.WithTimeShare(scheduler.PriorityBatch, 0.5))
+	time.Sleep(70 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(starts) < 2 {
+		t.Fatalf("expected two batch runs, got %d", len(starts))
+	}
+	// A 20ms run at a 50% share rests 20ms before the next job.
+	if gap := starts[1].Sub(starts[0]); gap < 40*time.Millisecond {
+		t.Fatalf("expected the worker to rest, next job started after %v", gap)
+	}
+}
@@ -2,23 +2,19 @@ package scheduler
 
 import (
 	"context"
-	"log"
 	"time"
-
-	"golang.org/x/sync/errgroup"
 )
 
 type Scheduler struct {
-	jobs      []Job
-	onSuccess func()
+	classes      [numPriorities]class
+	adaptiveJobs []AdaptiveJob
+	onSuccess    func()
 }
 
 type Option func(*Scheduler)
 
 func NewScheduler() Scheduler {
-	return Scheduler{
-		jobs: make([]Job, 0),
-	}
+	return Scheduler{}
 }
 
 // WithOnSuccess registers a callback to run after all jobs succeed in a tick.
@@ -28,39 +24,41 @@ func WithOnSuccess(cb func()) Option {
 	}
 }
 
+// AddJob adds a job with the normal priority.
 func (s *Scheduler) AddJob(job Job) {
-	s.jobs = append(s.jobs, job)
+	s.AddJobWithPriority(job, PriorityNormal)
+}
+
+// AddAdaptiveJob adds a job that runs on its own schedule, waiting the delay
+// returned by each run. The interval given to Start is used as the default delay.
+func (s *Scheduler) AddAdaptiveJob(job AdaptiveJob) {
+	s.adaptiveJobs = append(s.adaptiveJobs, job)
 }
 
 // Start starts the scheduler and runs the jobs on the given interval.
+// Each priority class ticks on its own, so a slow batch job never delays critical jobs.
+// Adaptive jobs and jobs of the critical and batch classes run independently
+// and don't count toward the success callback.
 func (s *Scheduler) Start(ctx context.Context, interval time.Duration, opts ...Option) {
 	for _, opt := range opts {
 		opt(s)
 	}
 
-	Every(interval).Do(ctx, func(ctx context.Context) {
-		s.runJobs(ctx)
-	})
-}
-
-func (s *Scheduler) runJobs(ctx context.Context) {
-	group, _ := errgroup.WithContext(ctx)
+	for _, job := range s.adaptiveJobs {
+		Adaptive(interval).Do(ctx, job)
+	}
 
-	for _, j := range s.jobs {
-		job := j
-		group.Go(func() error {
-			if err := job.Run(ctx); err != nil {
-				log.Printf("job failed: %v", err)
+	for priority := range s.classes {
+		cls := &s.classes[priority]
+		if Priority(priority) != PriorityNormal && len(cls.jobs) == 0 {
+			continue
+		}
 
-				return err
+		Every(interval).Do(ctx, func(ctx context.Context) {
+			err := cls.run(ctx)
+			if Priority(priority) == PriorityNormal && err == nil && s.onSuccess != nil {
+				s.onSuccess()
 			}
-
-			return nil
 		})
 	}
-
-	err := group.Wait()
-	if err == nil && s.onSuccess != nil {
-		s.onSuccess()
-	}
 }
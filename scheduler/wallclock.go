@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ezex-io/gopkg/errors"
+)
+
+// WallClockBuilder schedules a callback at a time of day, every day or once a week.
+type WallClockBuilder struct {
+	hour, minute, second int
+	weekly               bool
+	weekday              time.Weekday
+	loc                  *time.Location
+}
+
+// DailyAt schedules a callback every day at clock, given as "15:04" or "15:04:05",
+// in loc (time.Local when nil). It panics if clock is malformed.
+func DailyAt(clock string, loc *time.Location) WallClockBuilder {
+	hour, minute, second := parseClock(clock)
+
+	return WallClockBuilder{hour: hour, minute: minute, second: second, loc: loc}
+}
+
+// WeeklyOn schedules a callback every week on day at clock, given as "15:04"
+// or "15:04:05", in time.Local unless set with In. It panics if clock is malformed.
+func WeeklyOn(day time.Weekday, clock string) WallClockBuilder {
+	hour, minute, second := parseClock(clock)
+
+	return WallClockBuilder{hour: hour, minute: minute, second: second, weekly: true, weekday: day}
+}
+
+func parseClock(clock string) (hour, minute, second int) {
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, clock); err == nil {
+			return t.Hour(), t.Minute(), t.Second()
+		}
+	}
+
+	panic(fmt.Sprintf("scheduler: invalid time of day %q, expected HH:MM or HH:MM:SS", clock))
+}
+
+// In returns a copy of the builder using the time zone loc.
+func (b WallClockBuilder) In(loc *time.Location) WallClockBuilder {
+	b.loc = loc
+
+	return b
+}
+
+// Next returns the next activation time strictly after t.
+// It follows the calendar of the time zone, so the activation stays at the same
+// wall-clock time across daylight saving changes. A time skipped by a change
+// runs at the time it normalizes to, such as 03:30 for 02:30.
+func (b WallClockBuilder) Next(t time.Time) time.Time {
+	loc := b.loc
+	if loc == nil {
+		loc = time.Local
+	}
+
+	t = t.In(loc)
+	year, month, day := t.Date()
+	for offset := 0; ; offset++ {
+		next := time.Date(year, month, day+offset, b.hour, b.minute, b.second, 0, loc)
+		if !next.After(t) {
+			continue
+		}
+		if b.weekly && time.Date(year, month, day+offset, 0, 0, 0, 0, loc).Weekday() != b.weekday {
+			continue
+		}
+
+		return next
+	}
+}
+
+// Do registers the callback to run at every activation time until ctx is done.
+// The next activation is computed again after each run, so a run lasting past
+// an activation time skips it instead of running twice in a row.
+// The scheduler passes the builder's context to the callback for cancellation-aware work.
+func (b WallClockBuilder) Do(ctx context.Context, callback func(ctx context.Context)) {
+	go func() {
+		for {
+			timer := time.NewTimer(time.Until(b.Next(time.Now())))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return
+			case <-timer.C:
+				func() {
+					defer func() {
+						if r := recover(); r != nil {
+							log.Printf("scheduler: panic in job: %+v", errors.Panic(r))
+						}
+					}()
+					callback(ctx)
+				}()
+			}
+		}
+	}()
+}
@@ -0,0 +1,105 @@
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ezex-io/gopkg/scheduler"
+)
+
+func TestDailyAtNext(t *testing.T) {
+	daily := scheduler.DailyAt("14:30", time.UTC)
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		from time.Time
+		want time.Time
+	}{
+		{"later today", date(1, 1, 9, 0), date(1, 1, 14, 30)},
+		{"tomorrow", date(1, 1, 15, 0), date(1, 2, 14, 30)},
+		{"strictly after", date(1, 1, 14, 30), date(1, 2, 14, 30)},
+		{"end of month", date(1, 31, 20, 0), date(2, 1, 14, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := daily.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyAtDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	daily := scheduler.DailyAt("09:00:00", loc)
+
+	// Clocks move forward on 30 March 2025 in Berlin.
+	first := daily.Next(time.Date(2025, 3, 29, 8, 0, 0, 0, loc))
+	second := daily.Next(first)
+	if first.Hour() != 9 || second.Hour() != 9 {
+		t.Errorf("expected 09:00 on both days, got %v and %v", first, second)
+	}
+	if elapsed := second.Sub(first); elapsed != 23*time.Hour {
+		t.Errorf("expected the DST day to last 23 hours, got %v", elapsed)
+	}
+
+	// 02:30 doesn't exist on that day.
+	skipped := scheduler.DailyAt("02:30", loc).Next(time.Date(2025, 3, 30, 0, 0, 0, 0, loc))
+	if want := time.Date(2025, 3, 30, 3, 30, 0, 0, loc); !skipped.Equal(want) {
+		t.Errorf("expected the skipped time to run at %v, got %v", want, skipped)
+	}
+}
+
+func TestWeeklyOnNext(t *testing.T) {
+	weekly := scheduler.WeeklyOn(time.Monday, "09:00").In(time.UTC)
+
+	// 1 January 2025 is a Wednesday.
+	from := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	want := time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC)
+	next := weekly.Next(from)
+	if !next.Equal(want) {
+		t.Fatalf("Next(%v) = %v, want %v", from, next, want)
+	}
+
+	if after := weekly.Next(next); !after.Equal(want.AddDate(0, 0, 7)) {
+		t.Errorf("expected a week later, got %v", after)
+	}
+}
+
+func TestDailyAtInvalid(t *testing.T) {
+	for _, clock := range []string{"", "25:00", "9", "noon"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected %q to panic", clock)
+				}
+			}()
+			scheduler.DailyAt(clock, time.UTC)
+		}()
+	}
+}
+
+func TestDailyAtDo(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+
+	done := make(chan struct{})
+	next := time.Now().Add(time.Second)
+	scheduler.DailyAt(next.Format("15:04:05"), time.Local).Do(ctx, func(context.Context) {
+		close(done)
+	})
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for DailyAt to run")
+	}
+}
@@ -0,0 +1,107 @@
+package sealed
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// MasterKey wraps the data keys of sealed values, such as a key held by a KMS.
+// Its implementations must be safe for concurrent use.
+type MasterKey interface {
+	// ID identifies the key in the sealed values, so they can be opened
+	// after the key is rotated. It is at most 255 bytes long.
+	ID() string
+
+	// Wrap encrypts a data key.
+	Wrap(dataKey []byte) ([]byte, error)
+
+	// Unwrap decrypts a data key wrapped by Wrap.
+	Unwrap(wrapped []byte) ([]byte, error)
+}
+
+// aesKey is a MasterKey held in memory, wrapping data keys with AES-256-GCM.
+type aesKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+// NewAESKey returns a MasterKey wrapping data keys with AES-256-GCM under key,
+// which must be 32 bytes long.
+func NewAESKey(id string, key []byte) (MasterKey, error) {
+	if id == "" || len(id) > maxKeyIDLen {
+		return nil, fmt.Errorf("sealed: invalid key ID %q", id)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("sealed: master key must be %d bytes, got %d", dataKeySize, len(key))
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesKey{id: id, aead: aead}, nil
+}
+
+// MasterKeyFromEnv returns an AES MasterKey read from the environment variable name,
+// holding 32 bytes encoded in base64, as generated by:
+//
+//	openssl rand -base64 32
+func MasterKeyFromEnv(id, name string) (MasterKey, error) {
+	encoded := os.Getenv(name)
+	if encoded == "" {
+		return nil, fmt.Errorf("sealed: master key variable %s is not set", name)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("sealed: invalid master key in %s: %w", name, err)
+	}
+
+	return NewAESKey(id, key)
+}
+
+func (k *aesKey) ID() string {
+	return k.id
+}
+
+func (k *aesKey) Wrap(dataKey []byte) ([]byte, error) {
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return k.aead.Seal(nonce, nonce, dataKey, []byte(k.id)), nil
+}
+
+func (k *aesKey) Unwrap(wrapped []byte) ([]byte, error) {
+	return openGCM(k.aead, wrapped, []byte(k.id))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// openGCM opens data sealed with a random nonce prepended to it.
+func openGCM(aead cipher.AEAD, data, additional []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrInvalid
+	}
+
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	return plaintext, nil
+}
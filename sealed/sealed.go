@@ -0,0 +1,247 @@
+// Package sealed encrypts secrets stored in databases with envelope encryption.
+//
+// Each value is encrypted with its own random data key, itself wrapped by a master key,
+// such as one held by a KMS or read from the environment. Rotating the master key
+// only rewraps the data keys, and the values sealed under the previous master keys
+// can still be opened as long as their keys are in the Keyring:
+//
+//	current, _ := sealed.MasterKeyFromEnv("2026-10", "SEALED_MASTER_KEY")
+//	previous, _ := sealed.MasterKeyFromEnv("2026-01", "SEALED_PREVIOUS_MASTER_KEY")
+//	sealed.SetDefault(sealed.NewKeyring(current, previous))
+//
+// String and Bytes hold a secret in memory and seal it when stored in SQL or
+// marshaled to JSON, so it stays encrypted at rest.
+package sealed
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// Format of the sealed values, version 1:
+//
+//	version (1 byte) = 1
+//	key ID length (1 byte), key ID
+//	wrapped data key length (2 bytes, big endian), wrapped data key
+//	nonce (12 bytes), AES-256-GCM ciphertext and tag of the plaintext
+//
+// The header is not authenticated with the ciphertext, so rotating the master key
+// rewraps the data key without sealing the plaintext again: the wrapped data key
+// is authenticated by the master key, and the ciphertext by the data key.
+const (
+	formatV1    byte = 1
+	dataKeySize      = 32
+	maxKeyIDLen      = 255
+)
+
+var (
+	// ErrInvalid is returned for a sealed value that is malformed or was tampered with.
+	ErrInvalid = errors.New("sealed: invalid sealed value")
+
+	// ErrUnknownKey is returned for a value sealed under a master key missing from the Keyring.
+	ErrUnknownKey = errors.New("sealed: unknown master key")
+
+	// ErrNoKeyring is returned when sealing or opening a String or a Bytes
+	// before the default Keyring is set with SetDefault.
+	ErrNoKeyring = errors.New("sealed: default keyring not set")
+)
+
+// Keyring seals values under its primary master key, and opens those sealed
+// under any of its master keys. It is safe for concurrent use.
+type Keyring struct {
+	primary MasterKey
+	keys    map[string]MasterKey
+}
+
+// NewKeyring returns a Keyring sealing under primary and opening values sealed
+// under primary or one of the previous master keys.
+func NewKeyring(primary MasterKey, previous ...MasterKey) *Keyring {
+	keys := make(map[string]MasterKey, len(previous)+1)
+	for _, key := range previous {
+		keys[key.ID()] = key
+	}
+	keys[primary.ID()] = primary
+
+	return &Keyring{primary: primary, keys: keys}
+}
+
+// Seal encrypts plaintext with a new data key wrapped by the primary master key.
+// additional is authenticated but not encrypted; the same must be given to Open,
+// such as the ID of the row holding the value, so it can't be moved to another row.
+func (k *Keyring) Seal(plaintext, additional []byte) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := k.primary.Wrap(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("sealed: failed to wrap data key: %w", err)
+	}
+
+	header, err := encodeHeader(k.primary.ID(), wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	sealed := append(header, nonce...)
+
+	return aead.Seal(sealed, nonce, plaintext, additional), nil
+}
+
+// Open decrypts a value sealed by Seal with the same additional data.
+func (k *Keyring) Open(sealed, additional []byte) ([]byte, error) {
+	env, err := decodeEnvelope(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := k.unwrap(env)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return openGCM(aead, env.payload, additional)
+}
+
+// NeedsRotation reports whether sealed is not sealed under the primary master key.
+func (k *Keyring) NeedsRotation(sealed []byte) (bool, error) {
+	env, err := decodeEnvelope(sealed)
+	if err != nil {
+		return false, err
+	}
+
+	return env.keyID != k.primary.ID(), nil
+}
+
+// Rotate returns sealed with its data key rewrapped by the primary master key,
+// without decrypting the value, so it needs no additional data. It returns sealed
+// unchanged if it already is under the primary master key.
+//
+// To rotate a master key, add a new primary one to the Keyring, keeping the previous one,
+// rotate the stored values, then drop the previous key.
+func (k *Keyring) Rotate(sealed []byte) ([]byte, error) {
+	env, err := decodeEnvelope(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if env.keyID == k.primary.ID() {
+		return sealed, nil
+	}
+
+	dataKey, err := k.unwrap(env)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped, err := k.primary.Wrap(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("sealed: failed to wrap data key: %w", err)
+	}
+
+	header, err := encodeHeader(k.primary.ID(), wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(header, env.payload...), nil
+}
+
+func (k *Keyring) unwrap(env envelope) ([]byte, error) {
+	key, ok := k.keys[env.keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, env.keyID)
+	}
+
+	dataKey, err := key.Unwrap(env.wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to unwrap data key: %w", ErrInvalid, err)
+	}
+	if len(dataKey) != dataKeySize {
+		return nil, fmt.Errorf("%w: data key of %d bytes", ErrInvalid, len(dataKey))
+	}
+
+	return dataKey, nil
+}
+
+// envelope is a decoded sealed value.
+type envelope struct {
+	keyID   string
+	wrapped []byte
+	payload []byte
+}
+
+func encodeHeader(keyID string, wrapped []byte) ([]byte, error) {
+	if len(keyID) == 0 || len(keyID) > maxKeyIDLen {
+		return nil, fmt.Errorf("sealed: invalid key ID %q", keyID)
+	}
+	if len(wrapped) > 0xFFFF {
+		return nil, fmt.Errorf("sealed: wrapped data key of %d bytes is too long", len(wrapped))
+	}
+
+	header := make([]byte, 0, 4+len(keyID)+len(wrapped))
+	header = append(header, formatV1, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrapped)))
+
+	return append(header, wrapped...), nil
+}
+
+func decodeEnvelope(sealed []byte) (envelope, error) {
+	if len(sealed) < 2 {
+		return envelope{}, ErrInvalid
+	}
+	if sealed[0] != formatV1 {
+		return envelope{}, fmt.Errorf("%w: unknown format version %d", ErrInvalid, sealed[0])
+	}
+
+	rest := sealed[2:]
+	idLen := int(sealed[1])
+	if len(rest) < idLen+2 {
+		return envelope{}, ErrInvalid
+	}
+	keyID, rest := string(rest[:idLen]), rest[idLen:]
+
+	wrappedLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < wrappedLen {
+		return envelope{}, ErrInvalid
+	}
+	wrapped, payload := rest[:wrappedLen], rest[wrappedLen:]
+
+	return envelope{
+		keyID:   keyID,
+		wrapped: wrapped,
+		payload: payload,
+	}, nil
+}
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+// SetDefault sets the Keyring used by String and Bytes, typically at startup.
+func SetDefault(k *Keyring) {
+	defaultKeyring.Store(k)
+}
+
+// Default returns the Keyring set with SetDefault, or nil.
+func Default() *Keyring {
+	return defaultKeyring.Load()
+}
@@ -0,0 +1,117 @@
+package sealed
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(t *testing.T, id string, b byte) MasterKey {
+	t.Helper()
+
+	key, err := NewAESKey(id, bytes.Repeat([]byte{b}, dataKeySize))
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestKeyringSealOpen(t *testing.T) {
+	keyring := NewKeyring(testKey(t, "k1", 1))
+
+	sealed, err := keyring.Seal([]byte("secret"), []byte("row-1"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "secret")
+
+	again, err := keyring.Seal([]byte("secret"), []byte("row-1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "each value has its own data key and nonce")
+
+	opened, err := keyring.Open(sealed, []byte("row-1"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), opened)
+
+	_, err = keyring.Open(sealed, []byte("row-2"))
+	require.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestKeyringTampered(t *testing.T) {
+	keyring := NewKeyring(testKey(t, "k1", 1))
+	sealed, err := keyring.Seal([]byte("secret"), nil)
+	require.NoError(t, err)
+
+	for i := range sealed {
+		tampered := bytes.Clone(sealed)
+		tampered[i] ^= 0x01
+
+		_, err := keyring.Open(tampered, nil)
+		require.Error(t, err, "byte %d", i)
+	}
+
+	for _, invalid := range [][]byte{nil, {formatV1}, {2, 0}, sealed[:10]} {
+		_, err := keyring.Open(invalid, nil)
+		require.ErrorIs(t, err, ErrInvalid)
+	}
+}
+
+func TestKeyringRotate(t *testing.T) {
+	old := NewKeyring(testKey(t, "k1", 1))
+	sealed, err := old.Seal([]byte("secret"), []byte("row-1"))
+	require.NoError(t, err)
+
+	keyring := NewKeyring(testKey(t, "k2", 2), testKey(t, "k1", 1))
+	needs, err := keyring.NeedsRotation(sealed)
+	require.NoError(t, err)
+	assert.True(t, needs)
+
+	opened, err := keyring.Open(sealed, []byte("row-1"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), opened)
+
+	rotated, err := keyring.Rotate(sealed)
+	require.NoError(t, err)
+	needs, err = keyring.NeedsRotation(rotated)
+	require.NoError(t, err)
+	assert.False(t, needs)
+
+	again, err := keyring.Rotate(rotated)
+	require.NoError(t, err)
+	assert.Equal(t, rotated, again)
+
+	current := NewKeyring(testKey(t, "k2", 2))
+	opened, err = current.Open(rotated, []byte("row-1"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), opened)
+
+	_, err = current.Open(sealed, []byte("row-1"))
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewAESKeyInvalid(t *testing.T) {
+	_, err := NewAESKey("k1", []byte("short"))
+	require.Error(t, err)
+
+	_, err = NewAESKey("", make([]byte, dataKeySize))
+	require.Error(t, err)
+}
+
+func TestMasterKeyFromEnv(t *testing.T) {
+	t.Setenv("SEALED_TEST_KEY", "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=")
+
+	key, err := MasterKeyFromEnv("k1", "SEALED_TEST_KEY")
+	require.NoError(t, err)
+
+	sealed, err := NewKeyring(key).Seal([]byte("secret"), nil)
+	require.NoError(t, err)
+	opened, err := NewKeyring(testKey(t, "k1", 1)).Open(sealed, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), opened)
+
+	_, err = MasterKeyFromEnv("k1", "SEALED_TEST_MISSING")
+	require.Error(t, err)
+
+	t.Setenv("SEALED_TEST_KEY", "not base64!")
+	_, err = MasterKeyFromEnv("k1", "SEALED_TEST_KEY")
+	require.Error(t, err)
+}
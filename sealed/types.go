@@ -0,0 +1,164 @@
+package sealed
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+)
+
+// redacted is how String and Bytes format, so secrets don't leak in logs.
+const redacted = "[sealed]"
+
+// String is a secret string, sealed with the default Keyring when stored in SQL
+// or marshaled to JSON, and opened when scanned or unmarshaled:
+//
+//	type Account struct {
+//		ID     int64
+//		APIKey sealed.String // stored in a BYTEA column
+//	}
+//
+// It formats and logs as "[sealed]"; Reveal returns the secret.
+type String struct {
+	secret string
+}
+
+// NewString returns a String holding secret.
+func NewString(secret string) String {
+	return String{secret: secret}
+}
+
+// Reveal returns the secret.
+func (s String) Reveal() string {
+	return s.secret
+}
+
+func (String) String() string       { return redacted }
+func (String) GoString() string     { return redacted }
+func (String) LogValue() slog.Value { return slog.StringValue(redacted) }
+
+// Value returns the sealed secret, to be stored in a BYTEA column.
+func (s String) Value() (driver.Value, error) {
+	return seal([]byte(s.secret))
+}
+
+// Scan opens a value stored by Value. NULL scans as an empty secret.
+func (s *String) Scan(src any) error {
+	secret, err := scanSealed(src)
+	s.secret = string(secret)
+
+	return err
+}
+
+// MarshalJSON returns the sealed secret as a base64 JSON string.
+func (s String) MarshalJSON() ([]byte, error) {
+	return marshalSealed([]byte(s.secret))
+}
+
+// UnmarshalJSON opens a value marshaled by MarshalJSON. null unmarshals as an empty secret.
+func (s *String) UnmarshalJSON(data []byte) error {
+	secret, err := unmarshalSealed(data)
+	s.secret = string(secret)
+
+	return err
+}
+
+// Bytes is a secret byte slice, sealed like String.
+type Bytes struct {
+	secret []byte
+}
+
+// NewBytes returns a Bytes holding a copy of secret.
+func NewBytes(secret []byte) Bytes {
+	return Bytes{secret: slices.Clone(secret)}
+}
+
+// Reveal returns the secret. It must not be modified.
+func (b Bytes) Reveal() []byte {
+	return b.secret
+}
+
+func (Bytes) String() string       { return redacted }
+func (Bytes) GoString() string     { return redacted }
+func (Bytes) LogValue() slog.Value { return slog.StringValue(redacted) }
+
+// Value returns the sealed secret, to be stored in a BYTEA column.
+func (b Bytes) Value() (driver.Value, error) {
+	return seal(b.secret)
+}
+
+// Scan opens a value stored by Value. NULL scans as an empty secret.
+func (b *Bytes) Scan(src any) error {
+	secret, err := scanSealed(src)
+	b.secret = secret
+
+	return err
+}
+
+// MarshalJSON returns the sealed secret as a base64 JSON string.
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return marshalSealed(b.secret)
+}
+
+// UnmarshalJSON opens a value marshaled by MarshalJSON. null unmarshals as an empty secret.
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	secret, err := unmarshalSealed(data)
+	b.secret = secret
+
+	return err
+}
+
+func seal(plaintext []byte) ([]byte, error) {
+	keyring := Default()
+	if keyring == nil {
+		return nil, ErrNoKeyring
+	}
+
+	return keyring.Seal(plaintext, nil)
+}
+
+func open(sealed []byte) ([]byte, error) {
+	keyring := Default()
+	if keyring == nil {
+		return nil, ErrNoKeyring
+	}
+
+	return keyring.Open(sealed, nil)
+}
+
+func scanSealed(src any) ([]byte, error) {
+	switch src := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return open(src)
+	case string:
+		return open([]byte(src))
+	default:
+		return nil, fmt.Errorf("sealed: can't scan %T", src)
+	}
+}
+
+func marshalSealed(plaintext []byte) ([]byte, error) {
+	sealed, err := seal(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(sealed)
+}
+
+func unmarshalSealed(data []byte) ([]byte, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	var sealed []byte
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("sealed: %w", err)
+	}
+
+	return open(sealed)
+}
@@ -0,0 +1,87 @@
+package sealed
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setTestDefault(t *testing.T) {
+	t.Helper()
+
+	previous := Default()
+	SetDefault(NewKeyring(testKey(t, "k1", 1)))
+	t.Cleanup(func() { SetDefault(previous) })
+}
+
+func TestStringSQL(t *testing.T) {
+	setTestDefault(t)
+
+	val, err := NewString("api-key").Value()
+	require.NoError(t, err)
+	stored, ok := val.([]byte)
+	require.True(t, ok)
+	assert.NotContains(t, string(stored), "api-key")
+
+	var scanned String
+	require.NoError(t, scanned.Scan(stored))
+	assert.Equal(t, "api-key", scanned.Reveal())
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Empty(t, scanned.Reveal())
+
+	require.Error(t, scanned.Scan(42))
+}
+
+func TestBytesJSON(t *testing.T) {
+	setTestDefault(t)
+
+	type account struct {
+		Key Bytes `json:"key"`
+	}
+
+	data, err := json.Marshal(account{Key: NewBytes([]byte{1, 2, 3})})
+	require.NoError(t, err)
+
+	var decoded account
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []byte{1, 2, 3}, decoded.Key.Reveal())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"key":null}`), &decoded))
+	assert.Empty(t, decoded.Key.Reveal())
+
+	require.Error(t, json.Unmarshal([]byte(`{"key":"AQID"}`), &decoded))
+}
+
+func TestRedacted(t *testing.T) {
+	secret := NewString("api-key")
+
+	for _, formatted := range []string{
+		fmt.Sprint(secret),
+		fmt.Sprintf("%v %+v %#v %s", secret, secret, secret, secret),
+		fmt.Sprintf("%v", struct{ Key Bytes }{NewBytes([]byte("api-key"))}),
+	} {
+		assert.NotContains(t, formatted, "api-key")
+	}
+
+	var buf strings.Builder
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("loaded", "key", secret)
+	assert.Contains(t, buf.String(), "key=[sealed]")
+}
+
+func TestNoKeyring(t *testing.T) {
+	previous := Default()
+	SetDefault(nil)
+	t.Cleanup(func() { SetDefault(previous) })
+
+	_, err := NewString("api-key").Value()
+	require.ErrorIs(t, err, ErrNoKeyring)
+
+	_, err = json.Marshal(NewBytes([]byte("api-key")))
+	require.ErrorIs(t, err, ErrNoKeyring)
+}
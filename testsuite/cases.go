@@ -0,0 +1,122 @@
+package testsuite
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+type runCasesConfig struct {
+	sequential bool
+	seed       int64
+	seeded     bool
+}
+
+// RunCasesOption is a functional option for configuring RunCases.
+type RunCasesOption func(*runCasesConfig)
+
+// WithSequential runs the cases one after the other instead of in parallel,
+// for cases sharing state.
+func WithSequential() RunCasesOption {
+	return func(c *runCasesConfig) {
+		c.sequential = true
+	}
+}
+
+// WithSeed sets the suite seed the case seeds are derived from,
+// to reproduce a failed run.
+func WithSeed(seed int64) RunCasesOption {
+	return func(c *runCasesConfig) {
+		c.seed = seed
+		c.seeded = true
+	}
+}
+
+// RunCases runs fn for each case of a table test in a subtest, in parallel by default.
+//
+// Subtests are named after the `name` or `Name` string field of the case,
+// or "case_<index>" when there is none. Each case gets its own TestSuite, seeded
+// from the logged suite seed and the case index, so a case draws the same random
+// values whatever the order the cases run in. The seed of a failed case is logged.
+// A panic fails the case with its name and stack instead of aborting the whole test.
+//
+//	type lenCase struct {
+//		name string
+//		in   string
+//		want int
+//	}
+//
+//	testsuite.RunCases(t, []lenCase{
+//		{"empty", "", 0},
+//		{"word", "abc", 3},
+//	}, func(t *testing.T, ts *testsuite.TestSuite, tt lenCase) {
+//		assert.Equal(t, tt.want, len(tt.in))
+//	})
+func RunCases[C any](t *testing.T, cases []C, fn func(t *testing.T, ts *TestSuite, tc C), opts ...RunCasesOption) {
+	t.Helper()
+
+	cfg := runCasesConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !cfg.seeded {
+		cfg.seed = GenerateSeed()
+	}
+	t.Logf("%v seed is %v", t.Name(), cfg.seed)
+
+	for i, tc := range cases {
+		name := caseName(tc, i)
+		seed := caseSeed(cfg.seed, i)
+
+		t.Run(name, func(t *testing.T) {
+			if !cfg.sequential {
+				t.Parallel()
+			}
+
+			t.Cleanup(func() {
+				if t.Failed() {
+					t.Logf("case %q seed is %v", name, seed)
+				}
+			})
+
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("case %q panicked: %v\n%s", name, r, debug.Stack())
+				}
+			}()
+
+			fn(t, NewTestSuiteFromSeed(t, seed), tc)
+		})
+	}
+}
+
+// caseName returns the `name` or `Name` string field of tc, or "case_<index>".
+func caseName(tc any, index int) string {
+	value := reflect.ValueOf(tc)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Struct {
+		field := value.FieldByNameFunc(func(name string) bool {
+			return strings.EqualFold(name, "name")
+		})
+		if field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			return field.String()
+		}
+	}
+
+	return fmt.Sprintf("case_%d", index)
+}
+
+// caseSeed derives the seed of the case at index from the suite seed,
+// mixing them with SplitMix64 so neighbouring cases get unrelated seeds.
+func caseSeed(seed int64, index int) int64 {
+	z := uint64(seed) + uint64(index+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+
+	return int64(z ^ (z >> 31))
+}
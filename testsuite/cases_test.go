@@ -0,0 +1,60 @@
+package testsuite
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCases(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"word", "abc", 3},
+	}
+
+	var mu sync.Mutex
+	ran := make(map[string]int64)
+	t.Run("group", func(t *testing.T) {
+		RunCases(t, tests, func(t *testing.T, ts *TestSuite, tt struct {
+			name string
+			in   string
+			want int
+		},
+		) {
+			assert.Equal(t, tt.want, len(tt.in))
+
+			mu.Lock()
+			ran[t.Name()] = ts.Seed
+			mu.Unlock()
+		}, WithSeed(42))
+	})
+
+	assert.Len(t, ran, 2)
+	assert.Equal(t, caseSeed(42, 0), ran["TestRunCases/group/empty"])
+	assert.Equal(t, caseSeed(42, 1), ran["TestRunCases/group/word"])
+	assert.NotEqual(t, ran["TestRunCases/group/empty"], ran["TestRunCases/group/word"])
+}
+
+func TestRunCasesSequential(t *testing.T) {
+	var order []int
+	RunCases(t, []int{1, 2, 3}, func(_ *testing.T, _ *TestSuite, tc int) {
+		order = append(order, tc)
+	}, WithSequential())
+
+	assert.Equal(t, []int{1, 2, 3}, order)
+}
+
+func TestCaseName(t *testing.T) {
+	type exported struct{ Name string }
+
+	assert.Equal(t, "lower", caseName(struct{ name string }{"lower"}, 0))
+	assert.Equal(t, "upper", caseName(exported{"upper"}, 0))
+	assert.Equal(t, "pointer", caseName(&exported{"pointer"}, 0))
+	assert.Equal(t, "case_1", caseName(exported{}, 1))
+	assert.Equal(t, "case_2", caseName(7, 2))
+}
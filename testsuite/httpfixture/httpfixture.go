@@ -0,0 +1,284 @@
+// Package httpfixture records HTTP interactions to fixture files and replays them in tests,
+// so tests of API clients don't depend on live endpoints.
+//
+// A Recorder is an http.RoundTripper. In record mode it forwards requests to the real
+// transport and saves the scrubbed interactions when the test ends; in replay mode it
+// answers requests from the fixture file and never touches the network.
+//
+//	rec := httpfixture.New(t, "testdata/node.json")
+//	client := rec.Client()
+//
+// The mode defaults to replay, and to record when HTTPFIXTURE_RECORD is set to a non-empty value.
+package httpfixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// RecordEnv is the environment variable that switches the default mode to record.
+const RecordEnv = "HTTPFIXTURE_RECORD"
+
+// Redacted replaces scrubbed secrets in fixtures.
+const Redacted = "[REDACTED]"
+
+// ErrNoInteraction is returned in replay mode when no recorded interaction matches a request.
+var ErrNoInteraction = errors.New("httpfixture: no recorded interaction matches the request")
+
+// Mode selects whether a Recorder records or replays.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the fixture file.
+	ModeReplay Mode = iota
+
+	// ModeRecord forwards requests to the real transport and saves them to the fixture file.
+	ModeRecord
+)
+
+// Request is the recorded part of an HTTP request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is the recorded part of an HTTP response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a recorded request with its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder records or replays HTTP interactions.
+type Recorder struct {
+	mu sync.Mutex
+
+	path         string
+	mode         Mode
+	transport    http.RoundTripper
+	matcher      Matcher
+	scrubbers    []Scrubber
+	interactions []Interaction
+	used         []bool
+}
+
+// New creates a recorder for the fixture file at path.
+// In replay mode the fixture is loaded now and the test fails if it can't be read.
+// In record mode the fixture is written when the test and its subtests complete.
+func New(t testing.TB, path string, opts ...Option) *Recorder {
+	t.Helper()
+
+	cfg := defaultOptions()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	rec := &Recorder{
+		path:      path,
+		mode:      cfg.mode,
+		transport: cfg.transport,
+		matcher:   cfg.matcher,
+		scrubbers: cfg.scrubbers,
+	}
+
+	switch rec.mode {
+	case ModeReplay:
+		if err := rec.load(); err != nil {
+			t.Fatalf("httpfixture: loading %s: %v (set %s=1 to record it)", path, err, RecordEnv)
+		}
+	case ModeRecord:
+		t.Cleanup(func() {
+			if err := rec.Save(); err != nil {
+				t.Errorf("httpfixture: saving %s: %v", path, err)
+			}
+		})
+	}
+
+	return rec
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client using the recorder as transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns a copy of the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := r.scrubRequest(Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   string(reqBody),
+	})
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	return r.record(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       string(respBody),
+		},
+	}
+	for _, scrub := range r.scrubbers {
+		scrub(&interaction)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, interaction)
+	r.used = append(r.used, true)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay answers with the first unused matching interaction, so repeated identical
+// requests get their responses in recorded order. Once all matches are used,
+// the last one is served again.
+func (r *Recorder) replay(req *http.Request, actual Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, interaction := range r.interactions {
+		if !r.matcher(actual, interaction.Request) {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, actual.Method, actual.URL)
+	}
+	r.used[found] = true
+
+	recorded := r.interactions[found].Response
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewBufferString(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Recorder) scrubRequest(req Request) Request {
+	interaction := Interaction{Request: req}
+	for _, scrub := range r.scrubbers {
+		scrub(&interaction)
+	}
+
+	return interaction.Request
+}
+
+func (r *Recorder) load() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	var fix fixture
+	if err := json.Unmarshal(data, &fix); err != nil {
+		return err
+	}
+
+	r.interactions = fix.Interactions
+	r.used = make([]bool, len(fix.Interactions))
+
+	return nil
+}
+
+// Save writes the recorded interactions to the fixture file.
+// It is called automatically at the end of the test in record mode.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(fixture{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(data, '\n'), 0o600)
+}
+
+// readBody reads the body and replaces it with a reader over the same bytes.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	_ = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
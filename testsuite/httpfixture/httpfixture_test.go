@@ -0,0 +1,132 @@
+package httpfixture
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret-session")
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, r.Method+" "+r.URL.Path+" "+string(body)+" #"+string(rune('0'+calls)))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func get(t *testing.T, client *http.Client, req *http.Request) string {
+	t.Helper()
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := newServer(t)
+	path := filepath.Join(t.TempDir(), "fixtures", "api.json")
+
+	newRequests := func() []*http.Request {
+		first, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/v1/items?apikey=abc123", nil)
+		first.Header.Set("Authorization", "Bearer token")
+		second, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, server.URL+"/v1/items",
+			strings.NewReader(`{"name":"abc123"}`))
+
+		return []*http.Request{first, second}
+	}
+
+	// Record in a subtest, so the fixture is saved by its cleanup.
+	var recorded []string
+	t.Run("record", func(t *testing.T) {
+		rec := New(t, path, WithMode(ModeRecord),
+			WithScrubber(ScrubQueryParams("apikey")), WithScrubber(ScrubString("abc123")))
+		assert.Equal(t, ModeRecord, rec.Mode())
+
+		for _, req := range newRequests() {
+			recorded = append(recorded, get(t, rec.Client(), req))
+		}
+	})
+	assert.Equal(t, []string{"GET /v1/items  #1", `POST /v1/items {"name":"abc123"} #2`}, recorded)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "abc123")
+	assert.NotContains(t, string(data), "Bearer token")
+	assert.NotContains(t, string(data), "secret-session")
+	assert.Contains(t, string(data), Redacted)
+
+	server.Close()
+
+	rec := New(t, path, WithMode(ModeReplay),
+		WithScrubber(ScrubQueryParams("apikey")), WithScrubber(ScrubString("abc123")))
+	assert.Len(t, rec.Interactions(), 2)
+
+	var replayed []string
+	for _, req := range newRequests() {
+		replayed = append(replayed, get(t, rec.Client(), req))
+	}
+	assert.Equal(t, []string{"GET /v1/items  #1", `POST /v1/items {"name":"[REDACTED]"} #2`}, replayed)
+}
+
+func TestReplayOrderAndMatchers(t *testing.T) {
+	server := newServer(t)
+	path := filepath.Join(t.TempDir(), "api.json")
+
+	t.Run("record", func(t *testing.T) {
+		rec := New(t, path, WithMode(ModeRecord))
+		for range 2 {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+"/poll", nil)
+			get(t, rec.Client(), req)
+		}
+	})
+
+	rec := New(t, path, WithMode(ModeReplay), WithMatcher(MatchAll(MatchMethod, MatchPath)))
+	client := rec.Client()
+
+	// Another host and query still match by path; responses come back in recorded order,
+	// then the last one repeats.
+	for _, expected := range []string{"GET /poll  #1", "GET /poll  #2", "GET /poll  #2"} {
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.invalid/poll?x=1", nil)
+		assert.Equal(t, expected, get(t, client, req))
+	}
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodDelete, "http://example.invalid/poll", nil)
+	_, err := client.Do(req)
+	require.ErrorIs(t, err, ErrNoInteraction)
+}
+
+func TestMissingFixture(t *testing.T) {
+	fake := &fakeTB{TB: t}
+	New(fake, filepath.Join(t.TempDir(), "missing.json"), WithMode(ModeReplay))
+	assert.True(t, fake.fatal)
+}
+
+type fakeTB struct {
+	testing.TB
+
+	fatal bool
+}
+
+func (f *fakeTB) Fatalf(string, ...any) {
+	f.fatal = true
+}
@@ -0,0 +1,184 @@
+package httpfixture
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+type options struct {
+	mode      Mode
+	transport http.RoundTripper
+	matcher   Matcher
+	scrubbers []Scrubber
+}
+
+// Option configures a Recorder.
+type Option func(*options)
+
+// defaultHeaders are the headers scrubbed by default.
+var defaultHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+func defaultOptions() options {
+	mode := ModeReplay
+	if os.Getenv(RecordEnv) != "" {
+		mode = ModeRecord
+	}
+
+	return options{
+		mode:      mode,
+		transport: http.DefaultTransport,
+		matcher:   MatchAll(MatchMethod, MatchURL, MatchBody),
+		scrubbers: []Scrubber{ScrubHeaders(defaultHeaders...)},
+	}
+}
+
+// WithMode overrides the mode chosen from the environment.
+func WithMode(mode Mode) Option {
+	return func(opt *options) {
+		opt.mode = mode
+	}
+}
+
+// WithTransport sets the transport used in record mode. Defaults to http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(opt *options) {
+		opt.transport = transport
+	}
+}
+
+// WithMatcher sets how requests are matched to recorded interactions in replay mode.
+// Defaults to MatchAll(MatchMethod, MatchURL, MatchBody).
+func WithMatcher(matcher Matcher) Option {
+	return func(opt *options) {
+		opt.matcher = matcher
+	}
+}
+
+// WithScrubber adds a scrubber, applied after the default header scrubber.
+func WithScrubber(scrubber Scrubber) Option {
+	return func(opt *options) {
+		opt.scrubbers = append(opt.scrubbers, scrubber)
+	}
+}
+
+// Matcher reports whether an incoming request, already scrubbed, matches a recorded one.
+type Matcher func(actual, recorded Request) bool
+
+// MatchMethod matches requests with the same method.
+func MatchMethod(actual, recorded Request) bool {
+	return actual.Method == recorded.Method
+}
+
+// MatchURL matches requests with the same URL, including the query.
+func MatchURL(actual, recorded Request) bool {
+	return actual.URL == recorded.URL
+}
+
+// MatchPath matches requests with the same URL path, ignoring host and query.
+func MatchPath(actual, recorded Request) bool {
+	actualURL, err := url.Parse(actual.URL)
+	if err != nil {
+		return false
+	}
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+
+	return actualURL.Path == recordedURL.Path
+}
+
+// MatchBody matches requests with the same body.
+func MatchBody(actual, recorded Request) bool {
+	return actual.Body == recorded.Body
+}
+
+// MatchAll matches requests that all the matchers match.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(actual, recorded Request) bool {
+		for _, match := range matchers {
+			if !match(actual, recorded) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Scrubber removes secrets from an interaction before it is saved.
+// Scrubbers are also applied to incoming requests before matching,
+// so a request still matches its scrubbed recording.
+type Scrubber func(*Interaction)
+
+// ScrubHeaders redacts the given request and response headers.
+func ScrubHeaders(names ...string) Scrubber {
+	return func(interaction *Interaction) {
+		for _, name := range names {
+			redactHeader(interaction.Request.Header, name)
+			redactHeader(interaction.Response.Header, name)
+		}
+	}
+}
+
+// ScrubQueryParams redacts the given query parameters of the request URL.
+func ScrubQueryParams(names ...string) Scrubber {
+	return func(interaction *Interaction) {
+		parsed, err := url.Parse(interaction.Request.URL)
+		if err != nil {
+			return
+		}
+
+		query := parsed.Query()
+		changed := false
+		for _, name := range names {
+			if query.Has(name) {
+				query.Set(name, Redacted)
+				changed = true
+			}
+		}
+
+		if changed {
+			parsed.RawQuery = query.Encode()
+			interaction.Request.URL = parsed.String()
+		}
+	}
+}
+
+func redactHeader(header http.Header, name string) {
+	if header.Get(name) != "" {
+		header.Set(name, Redacted)
+	}
+}
+
+// ScrubString redacts every occurrence of secret in the URL, headers and bodies.
+// An empty secret is ignored, so it is safe to pass an unset environment variable.
+func ScrubString(secret string) Scrubber {
+	return func(interaction *Interaction) {
+		if secret == "" {
+			return
+		}
+
+		interaction.Request.URL = strings.ReplaceAll(interaction.Request.URL, secret, Redacted)
+		interaction.Request.Body = strings.ReplaceAll(interaction.Request.Body, secret, Redacted)
+		interaction.Response.Body = strings.ReplaceAll(interaction.Response.Body, secret, Redacted)
+		replaceInHeader(interaction.Request.Header, secret)
+		replaceInHeader(interaction.Response.Header, secret)
+	}
+}
+
+func replaceInHeader(header http.Header, secret string) {
+	for _, values := range header {
+		for i, value := range values {
+			values[i] = strings.ReplaceAll(value, secret, Redacted)
+		}
+	}
+}